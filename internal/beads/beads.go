@@ -1497,6 +1497,29 @@ func (b *Beads) AddGateWaiter(gateID, waiter string) error {
 	return nil
 }
 
+// CreateGate creates a gate bead awaiting the given condition (bd await
+// syntax, e.g. "timer:30m" or "human:deploy-approval").
+func (b *Beads) CreateGate(await, title string) (*Issue, error) {
+	out, err := b.run("gate", "create", "--await", await, "--title", title, "--json")
+	if err != nil {
+		return nil, fmt.Errorf("creating gate: %w", err)
+	}
+	var issue Issue
+	if err := json.Unmarshal(out, &issue); err != nil {
+		return nil, fmt.Errorf("parsing bd gate create output: %w", err)
+	}
+	return &issue, nil
+}
+
+// CloseGate closes a gate with a reason. Waiters are woken separately by
+// gt gate wake.
+func (b *Beads) CloseGate(gateID, reason string) error {
+	if _, err := b.run("gate", "close", gateID, "--reason", reason); err != nil {
+		return fmt.Errorf("closing gate: %w", err)
+	}
+	return nil
+}
+
 // ===== Merge Slot Functions (serialized conflict resolution) =====
 
 // MergeSlotStatus represents the result of checking a merge slot.
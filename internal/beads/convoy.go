@@ -0,0 +1,260 @@
+package beads
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConvoyFields holds a convoy's dependencies, staging, due date and epic. They are stored
+// as "key: value" lines in the convoy bead's description, next to the
+// Notify and Molecule lines written by gt convoy create.
+type ConvoyFields struct {
+	// Needs lists convoys that must land (close) before this convoy
+	// dispatches anything.
+	Needs []string
+
+	// Stages lists tracked issues in dispatch order: stage N+1 is slung
+	// once every issue in stage N is closed. Tracked issues not in any
+	// stage belong to stage 1.
+	Stages [][]string
+
+	// Target is the rig released stages are slung to; empty routes each
+	// issue with gt sling --auto.
+	Target string
+
+	// Released is the highest stage released for dispatch: 0 while the
+	// convoy waits on Needs.
+	Released int
+
+	// Due is the date (YYYY-MM-DD) the convoy should land by. gt convoy
+	// check alerts when the forecast slips past it.
+	Due string
+
+	// SlipAlerted is the projected landing date last alerted on, so a
+	// slip is reported once, and again only if it slips further.
+	SlipAlerted string
+
+	// Epic is the epic whose child issues the convoy tracks. gt convoy
+	// check adds children as they appear.
+	Epic string
+
+	// Rigs are the rigs the convoy's work is scoped to. Empty means the
+	// Target rig, or every rig.
+	Rigs []string
+
+	// OutOfScope lists tracked issues that no rig in scope can take. They
+	// are marked in status and never dispatched by the convoy.
+	OutOfScope []string
+}
+
+// ParseConvoyFields extracts convoy fields from an issue's description.
+// Returns nil if the convoy has no dependency, staging, due date or epic fields.
+func ParseConvoyFields(issue *Issue) *ConvoyFields {
+	if issue == nil || issue.Description == "" {
+		return nil
+	}
+
+	fields := &ConvoyFields{}
+	hasFields := false
+	stages := make(map[int][]string)
+
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := convoyFieldLine(line)
+		if !ok || value == "" {
+			continue
+		}
+
+		switch {
+		case key == "needs":
+			fields.Needs = splitConvoyList(value)
+		case key == "target":
+			fields.Target = value
+		case key == "released_stage":
+			fields.Released, _ = strconv.Atoi(value)
+		case key == "due":
+			fields.Due = value
+		case key == "slip_alerted":
+			fields.SlipAlerted = value
+		case key == "epic":
+			fields.Epic = value
+		case key == "rigs":
+			fields.Rigs = splitConvoyList(value)
+		case key == "out_of_scope":
+			fields.OutOfScope = splitConvoyList(value)
+		default:
+			n, _ := strconv.Atoi(strings.TrimPrefix(key, "stage_"))
+			stages[n] = splitConvoyList(value)
+		}
+		hasFields = true
+	}
+
+	if !hasFields {
+		return nil
+	}
+
+	// Stage numbers are positions: a missing stage_1 (a convoy that only
+	// waits on others) leaves stage 1 empty rather than renumbering
+	for n, ids := range stages {
+		for len(fields.Stages) < n {
+			fields.Stages = append(fields.Stages, nil)
+		}
+		fields.Stages[n-1] = ids
+	}
+	return fields
+}
+
+// convoyFieldLine splits a description line into a convoy field key and
+// value. ok is false for lines that aren't convoy fields.
+func convoyFieldLine(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	colonIdx := strings.Index(line, ":")
+	if colonIdx == -1 {
+		return "", "", false
+	}
+	key = strings.ToLower(strings.TrimSpace(line[:colonIdx]))
+	value = strings.TrimSpace(line[colonIdx+1:])
+	switch key {
+	case "needs", "target", "released_stage", "due", "slip_alerted", "epic", "rigs", "out_of_scope":
+		return key, value, true
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(key, "stage_")); err == nil && n > 0 && strings.HasPrefix(key, "stage_") {
+		return key, value, true
+	}
+	return "", "", false
+}
+
+func splitConvoyList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// FormatConvoyFields formats ConvoyFields as description lines.
+// Only non-empty fields are included.
+func FormatConvoyFields(fields *ConvoyFields) string {
+	if fields == nil {
+		return ""
+	}
+
+	var lines []string
+	if len(fields.Needs) > 0 {
+		lines = append(lines, "needs: "+strings.Join(fields.Needs, ", "))
+	}
+	for i, stage := range fields.Stages {
+		if len(stage) > 0 {
+			lines = append(lines, fmt.Sprintf("stage_%d: %s", i+1, strings.Join(stage, ", ")))
+		}
+	}
+	if fields.Target != "" {
+		lines = append(lines, "target: "+fields.Target)
+	}
+	if fields.Released > 0 {
+		lines = append(lines, fmt.Sprintf("released_stage: %d", fields.Released))
+	}
+	if fields.Due != "" {
+		lines = append(lines, "due: "+fields.Due)
+	}
+	if fields.SlipAlerted != "" {
+		lines = append(lines, "slip_alerted: "+fields.SlipAlerted)
+	}
+	if fields.Epic != "" {
+		lines = append(lines, "epic: "+fields.Epic)
+	}
+	if len(fields.Rigs) > 0 {
+		lines = append(lines, "rigs: "+strings.Join(fields.Rigs, ", "))
+	}
+	if len(fields.OutOfScope) > 0 {
+		lines = append(lines, "out_of_scope: "+strings.Join(fields.OutOfScope, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetConvoyFields updates an issue's description with the given convoy
+// fields. Existing convoy field lines are replaced; other content is
+// preserved. Returns the new description string.
+func SetConvoyFields(issue *Issue, fields *ConvoyFields) string {
+	var otherLines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			if _, _, ok := convoyFieldLine(line); ok {
+				continue
+			}
+			otherLines = append(otherLines, line)
+		}
+	}
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+
+	formatted := FormatConvoyFields(fields)
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+	return strings.Join(otherLines, "\n") + "\n" + formatted
+}
+
+// Staged reports whether the convoy has dependencies or stages, so its
+// dispatch is managed by gt convoy check.
+func (f *ConvoyFields) Staged() bool {
+	return f != nil && (len(f.Needs) > 0 || len(f.Stages) > 0)
+}
+
+// StageOf returns the 1-based stage of a tracked issue. Issues not listed
+// in any stage are in stage 1.
+func (f *ConvoyFields) StageOf(issueID string) int {
+	for i, stage := range f.Stages {
+		for _, id := range stage {
+			if id == issueID {
+				return i + 1
+			}
+		}
+	}
+	return 1
+}
+
+// StageCount returns the number of stages, at least 1.
+func (f *ConvoyFields) StageCount() int {
+	if len(f.Stages) == 0 {
+		return 1
+	}
+	return len(f.Stages)
+}
+
+// ScopeRigs returns the rigs the convoy's work is scoped to: Rigs, else
+// Target. Empty means every rig.
+func (f *ConvoyFields) ScopeRigs() []string {
+	if f == nil {
+		return nil
+	}
+	if len(f.Rigs) > 0 {
+		return f.Rigs
+	}
+	if f.Target != "" {
+		return []string{f.Target}
+	}
+	return nil
+}
+
+// InScope reports whether a tracked issue can be taken by a rig in scope.
+func (f *ConvoyFields) InScope(issueID string) bool {
+	if f == nil {
+		return true
+	}
+	for _, id := range f.OutOfScope {
+		if id == issueID {
+			return false
+		}
+	}
+	return true
+}
+
+// Held reports whether a tracked issue is waiting for its stage (or the
+// convoy's needs) and must not be dispatched yet.
+func (f *ConvoyFields) Held(issueID string) bool {
+	if !f.Staged() {
+		return false
+	}
+	return f.StageOf(issueID) > f.Released
+}
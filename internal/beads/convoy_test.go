@@ -0,0 +1,129 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConvoyFields(t *testing.T) {
+	issue := &Issue{Description: `Convoy tracking 4 issues
+Notify: mayor/
+needs: hq-cv-aaa, hq-cv-bbb
+stage_2: gt-c, gt-d
+stage_1: gt-a gt-b
+target: gastown
+released_stage: 1`}
+
+	fields := ParseConvoyFields(issue)
+	if fields == nil {
+		t.Fatal("ParseConvoyFields returned nil")
+	}
+	want := &ConvoyFields{
+		Needs:    []string{"hq-cv-aaa", "hq-cv-bbb"},
+		Stages:   [][]string{{"gt-a", "gt-b"}, {"gt-c", "gt-d"}},
+		Target:   "gastown",
+		Released: 1,
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ParseConvoyFields = %+v, want %+v", fields, want)
+	}
+
+	if got := ParseConvoyFields(&Issue{Description: "Convoy tracking 2 issues\nNotify: mayor/"}); got != nil {
+		t.Errorf("ParseConvoyFields without convoy fields = %+v, want nil", got)
+	}
+}
+
+func TestSetConvoyFieldsRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Convoy tracking 3 issues\nNotify: mayor/\nstage_1: gt-a\nreleased_stage: 1"}
+	fields := ParseConvoyFields(issue)
+	fields.Stages = append(fields.Stages, []string{"gt-b", "gt-c"})
+	fields.Released = 2
+
+	desc := SetConvoyFields(issue, fields)
+	want := "Convoy tracking 3 issues\nNotify: mayor/\nstage_1: gt-a\nstage_2: gt-b, gt-c\nreleased_stage: 2"
+	if desc != want {
+		t.Errorf("SetConvoyFields =\n%s\nwant\n%s", desc, want)
+	}
+	if got := ParseConvoyFields(&Issue{Description: desc}); !reflect.DeepEqual(got, fields) {
+		t.Errorf("round trip = %+v, want %+v", got, fields)
+	}
+}
+
+func TestConvoyFieldsStages(t *testing.T) {
+	fields := &ConvoyFields{
+		Stages:   [][]string{{"gt-a"}, {"gt-b"}, {"gt-c"}},
+		Released: 2,
+	}
+	tests := []struct {
+		id    string
+		stage int
+		held  bool
+	}{
+		{"gt-a", 1, false},
+		{"gt-b", 2, false},
+		{"gt-c", 3, true},
+		{"gt-unstaged", 1, false},
+	}
+	for _, tt := range tests {
+		if got := fields.StageOf(tt.id); got != tt.stage {
+			t.Errorf("StageOf(%s) = %d, want %d", tt.id, got, tt.stage)
+		}
+		if got := fields.Held(tt.id); got != tt.held {
+			t.Errorf("Held(%s) = %v, want %v", tt.id, got, tt.held)
+		}
+	}
+
+	var none *ConvoyFields
+	if none.Held("gt-a") {
+		t.Error("nil fields should hold nothing")
+	}
+	if got := (&ConvoyFields{}).StageCount(); got != 1 {
+		t.Errorf("StageCount without stages = %d, want 1", got)
+	}
+}
+
+func TestParseConvoyFieldsDueAndGaps(t *testing.T) {
+	issue := &Issue{Description: "Convoy tracking 1 issues\nneeds: hq-cv-aaa\nstage_2: gt-b\ndue: 2026-11-01\nslip_alerted: 2026-11-03"}
+	fields := ParseConvoyFields(issue)
+	if fields == nil {
+		t.Fatal("ParseConvoyFields returned nil")
+	}
+	if fields.Due != "2026-11-01" || fields.SlipAlerted != "2026-11-03" {
+		t.Errorf("due fields = %q, %q", fields.Due, fields.SlipAlerted)
+	}
+	// A convoy with no stage 1 issues keeps its stage numbers
+	if len(fields.Stages) != 2 || len(fields.Stages[0]) != 0 || fields.StageOf("gt-b") != 2 {
+		t.Errorf("Stages = %v, want stage 1 empty and gt-b in stage 2", fields.Stages)
+	}
+
+	dueOnly := ParseConvoyFields(&Issue{Description: "Convoy tracking 2 issues\ndue: 2026-11-01"})
+	if dueOnly == nil || dueOnly.Staged() || dueOnly.Held("gt-a") {
+		t.Errorf("due-only convoy = %+v, want unstaged", dueOnly)
+	}
+}
+
+func TestConvoyFieldsEpicScope(t *testing.T) {
+	issue := &Issue{Description: "Convoy tracking 3 issues\nepic: gt-epic\nrigs: gastown, beads\nout_of_scope: gt-c"}
+	fields := ParseConvoyFields(issue)
+	want := &ConvoyFields{Epic: "gt-epic", Rigs: []string{"gastown", "beads"}, OutOfScope: []string{"gt-c"}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("ParseConvoyFields = %+v, want %+v", fields, want)
+	}
+	if fields.Staged() {
+		t.Error("epic convoy without needs or stages should not be staged")
+	}
+	if !fields.InScope("gt-a") || fields.InScope("gt-c") {
+		t.Errorf("InScope: gt-a = %v, gt-c = %v", fields.InScope("gt-a"), fields.InScope("gt-c"))
+	}
+	if got := fields.ScopeRigs(); !reflect.DeepEqual(got, []string{"gastown", "beads"}) {
+		t.Errorf("ScopeRigs = %v", got)
+	}
+	if got := (&ConvoyFields{Target: "gastown"}).ScopeRigs(); !reflect.DeepEqual(got, []string{"gastown"}) {
+		t.Errorf("ScopeRigs with only a target = %v", got)
+	}
+
+	desc := SetConvoyFields(issue, fields)
+	if got := ParseConvoyFields(&Issue{Description: desc}); !reflect.DeepEqual(got, fields) {
+		t.Errorf("round trip = %+v, want %+v", got, fields)
+	}
+}
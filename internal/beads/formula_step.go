@@ -0,0 +1,116 @@
+package beads
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormulaStepFields tie a bead to the workflow formula step it runs, and
+// hold the output of the step's latest attempt for when and until
+// conditions. They are stored as "key: value" lines in the step bead's
+// description, next to the step's policy fields.
+type FormulaStepFields struct {
+	Step      string // formula step ID, e.g. "test" or "review-file.2"
+	Iteration int    // loop iteration the bead is on, from 1
+	Output    string // output of the latest attempt; one line
+}
+
+// formulaStepKeys are the description keys of FormulaStepFields.
+var formulaStepKeys = map[string]bool{
+	"formula_step":      true,
+	"formula_iteration": true,
+	"step_output":       true,
+}
+
+// ParseFormulaStepFields extracts formula step fields from an issue's
+// description. Returns nil if the bead doesn't run a formula step.
+func ParseFormulaStepFields(issue *Issue) *FormulaStepFields {
+	if issue == nil || issue.Description == "" {
+		return nil
+	}
+
+	fields := &FormulaStepFields{}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := formulaStepFieldLine(line)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "formula_step":
+			fields.Step = value
+		case "formula_iteration":
+			fields.Iteration, _ = strconv.Atoi(value)
+		case "step_output":
+			fields.Output = value
+		}
+	}
+
+	if fields.Step == "" {
+		return nil
+	}
+	return fields
+}
+
+// formulaStepFieldLine splits a description line into a formula step
+// field key and value. ok is false for lines that aren't formula step fields.
+func formulaStepFieldLine(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	colonIdx := strings.Index(line, ":")
+	if colonIdx == -1 {
+		return "", "", false
+	}
+	key = strings.ToLower(strings.TrimSpace(line[:colonIdx]))
+	if !formulaStepKeys[key] {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(line[colonIdx+1:]), true
+}
+
+// FormatFormulaStepFields formats FormulaStepFields as description lines.
+// Only non-empty fields are included.
+func FormatFormulaStepFields(fields *FormulaStepFields) string {
+	if fields == nil {
+		return ""
+	}
+
+	var lines []string
+	if fields.Step != "" {
+		lines = append(lines, "formula_step: "+fields.Step)
+	}
+	if fields.Iteration > 0 {
+		lines = append(lines, fmt.Sprintf("formula_iteration: %d", fields.Iteration))
+	}
+	if fields.Output != "" {
+		// Keep the output on one line so it parses back
+		lines = append(lines, "step_output: "+strings.Join(strings.Fields(fields.Output), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetFormulaStepFields updates an issue's description with the given
+// formula step fields. Existing formula step lines are replaced; other
+// content is preserved. Returns the new description string.
+func SetFormulaStepFields(issue *Issue, fields *FormulaStepFields) string {
+	var otherLines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			if _, _, ok := formulaStepFieldLine(line); ok {
+				continue
+			}
+			otherLines = append(otherLines, line)
+		}
+	}
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+
+	formatted := FormatFormulaStepFields(fields)
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+	return strings.Join(otherLines, "\n") + "\n" + formatted
+}
@@ -0,0 +1,24 @@
+package beads
+
+import "testing"
+
+func TestFormulaStepFields_RoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Run the tests.\n\nformula_step: old\nstep_on_failure: retry"}
+	desc := SetFormulaStepFields(issue, &FormulaStepFields{Step: "test", Iteration: 2, Output: "ok\nPASS"})
+	want := "Run the tests.\n\nstep_on_failure: retry\nformula_step: test\nformula_iteration: 2\nstep_output: ok PASS"
+	if desc != want {
+		t.Errorf("SetFormulaStepFields =\n%s\nwant\n%s", desc, want)
+	}
+
+	got := ParseFormulaStepFields(&Issue{Description: desc})
+	if got == nil || got.Step != "test" || got.Iteration != 2 || got.Output != "ok PASS" {
+		t.Errorf("parsed = %+v", got)
+	}
+	if policy := ParseStepPolicyFields(&Issue{Description: desc}); policy == nil || policy.OnFailure != OnFailureRetry {
+		t.Errorf("policy fields lost: %+v", policy)
+	}
+
+	if ParseFormulaStepFields(&Issue{Description: "step_output: PASS"}) != nil {
+		t.Error("expected nil for a bead without formula_step")
+	}
+}
@@ -0,0 +1,150 @@
+package beads
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Gate kinds evaluated by gt gate eval. Gates without a kind open only by
+// hand (bd gate close, bd gate approve) or by bd's own await types.
+const (
+	GateKindTimer   = "timer"   // opens at a time
+	GateKindBead    = "bead"    // opens when a bead closes or gets a label
+	GateKindCommand = "command" // opens when a local command exits 0
+	GateKindMRQueue = "mrqueue" // opens when a merge request merges
+)
+
+// GateKinds lists the gate kinds gt evaluates.
+var GateKinds = []string{GateKindTimer, GateKindBead, GateKindCommand, GateKindMRQueue}
+
+// GateFields holds the condition of a gate opened by gt gate eval. They are
+// stored as "key: value" lines in the gate bead's description.
+type GateFields struct {
+	Kind    string // one of GateKinds
+	At      string // timer: RFC3339 time the gate opens
+	Bead    string // bead: bead to watch
+	Label   string // bead: label that opens the gate; empty waits for close
+	Command string // command: shell command to run
+	Dir     string // command: directory to run it in
+	MR      string // mrqueue: MR ID or source issue to wait on
+}
+
+// ParseGateFields extracts gate condition fields from an issue's
+// description. Returns nil if the gate has no kind.
+func ParseGateFields(issue *Issue) *GateFields {
+	if issue == nil || issue.Description == "" {
+		return nil
+	}
+
+	fields := &GateFields{}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := gateFieldLine(line)
+		if !ok || value == "" {
+			continue
+		}
+		switch key {
+		case "gate_kind":
+			fields.Kind = value
+		case "gate_at":
+			fields.At = value
+		case "gate_bead":
+			fields.Bead = value
+		case "gate_label":
+			fields.Label = value
+		case "gate_command":
+			fields.Command = value
+		case "gate_dir":
+			fields.Dir = value
+		case "gate_mr":
+			fields.MR = value
+		}
+	}
+	if fields.Kind == "" {
+		return nil
+	}
+	return fields
+}
+
+// gateFieldLine splits a description line into a gate field key and value.
+// ok is false for lines that aren't gate fields.
+func gateFieldLine(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	colonIdx := strings.Index(line, ":")
+	if colonIdx == -1 {
+		return "", "", false
+	}
+	key = strings.ToLower(strings.TrimSpace(line[:colonIdx]))
+	switch key {
+	case "gate_kind", "gate_at", "gate_bead", "gate_label", "gate_command", "gate_dir", "gate_mr":
+		return key, strings.TrimSpace(line[colonIdx+1:]), true
+	}
+	return "", "", false
+}
+
+// FormatGateFields formats GateFields as description lines.
+// Only non-empty fields are included.
+func FormatGateFields(fields *GateFields) string {
+	if fields == nil {
+		return ""
+	}
+
+	var lines []string
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, key+": "+value)
+		}
+	}
+	add("gate_kind", fields.Kind)
+	add("gate_at", fields.At)
+	add("gate_bead", fields.Bead)
+	add("gate_label", fields.Label)
+	add("gate_command", fields.Command)
+	add("gate_dir", fields.Dir)
+	add("gate_mr", fields.MR)
+	return strings.Join(lines, "\n")
+}
+
+// SetGateFields updates an issue's description with the given gate fields.
+// Existing gate field lines are replaced; other content is preserved.
+// Returns the new description string.
+func SetGateFields(issue *Issue, fields *GateFields) string {
+	var otherLines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			if _, _, ok := gateFieldLine(line); ok {
+				continue
+			}
+			otherLines = append(otherLines, line)
+		}
+	}
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+
+	formatted := FormatGateFields(fields)
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+	return strings.Join(otherLines, "\n") + "\n" + formatted
+}
+
+// Describe summarizes the gate's condition, e.g. "gt-abc closes".
+func (f *GateFields) Describe() string {
+	switch f.Kind {
+	case GateKindTimer:
+		return "at " + f.At
+	case GateKindBead:
+		if f.Label != "" {
+			return fmt.Sprintf("%s gets label %s", f.Bead, f.Label)
+		}
+		return f.Bead + " closes"
+	case GateKindCommand:
+		return fmt.Sprintf("`%s` exits 0", f.Command)
+	case GateKindMRQueue:
+		return f.MR + " merges"
+	}
+	return f.Kind
+}
@@ -0,0 +1,45 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGateFieldsRoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Waiting for CI\ngate_kind: command\ngate_command: make test\ngate_dir: /tmp/rig"}
+	fields := ParseGateFields(issue)
+	want := &GateFields{Kind: GateKindCommand, Command: "make test", Dir: "/tmp/rig"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("ParseGateFields = %+v, want %+v", fields, want)
+	}
+
+	timer := &GateFields{Kind: GateKindTimer, At: "2026-10-20T15:00:00Z"}
+	desc := SetGateFields(issue, timer)
+	if wantDesc := "Waiting for CI\ngate_kind: timer\ngate_at: 2026-10-20T15:00:00Z"; desc != wantDesc {
+		t.Errorf("SetGateFields =\n%s\nwant\n%s", desc, wantDesc)
+	}
+	if got := ParseGateFields(&Issue{Description: desc}); !reflect.DeepEqual(got, timer) {
+		t.Errorf("round trip = %+v, want %+v", got, timer)
+	}
+
+	if got := ParseGateFields(&Issue{Description: "gate_bead: gt-abc"}); got != nil {
+		t.Errorf("ParseGateFields without a kind = %+v, want nil", got)
+	}
+}
+
+func TestGateFieldsDescribe(t *testing.T) {
+	tests := []struct {
+		fields GateFields
+		want   string
+	}{
+		{GateFields{Kind: GateKindBead, Bead: "gt-abc"}, "gt-abc closes"},
+		{GateFields{Kind: GateKindBead, Bead: "gt-abc", Label: "approved"}, "gt-abc gets label approved"},
+		{GateFields{Kind: GateKindCommand, Command: "true"}, "`true` exits 0"},
+		{GateFields{Kind: GateKindMRQueue, MR: "mr-123"}, "mr-123 merges"},
+	}
+	for _, tt := range tests {
+		if got := tt.fields.Describe(); got != tt.want {
+			t.Errorf("Describe(%+v) = %q, want %q", tt.fields, got, tt.want)
+		}
+	}
+}
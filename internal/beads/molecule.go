@@ -18,6 +18,9 @@ type MoleculeStep struct {
 	Tier         string         // Optional tier hint: haiku, sonnet, opus
 	Type         string         // Step type: "task" (default), "wait", etc.
 	Backoff      *BackoffConfig // Backoff configuration for wait-type steps
+	Timeout      string         // Optional time limit per attempt (e.g., "30m")
+	MaxAttempts  int            // Optional attempts allowed with OnFailure retry
+	OnFailure    string         // Optional failure policy: retry, skip, escalate, burn
 }
 
 // BackoffConfig defines exponential backoff parameters for wait-type steps.
@@ -49,6 +52,15 @@ var typeLineRegex = regexp.MustCompile(`(?i)^Type:\s*(\w+)\s*$`)
 // Parses backoff configuration for wait-type steps.
 var backoffLineRegex = regexp.MustCompile(`(?i)^Backoff:\s*(.+)$`)
 
+// timeoutLineRegex matches "Timeout: 30m" lines.
+var timeoutLineRegex = regexp.MustCompile(`(?i)^Timeout:\s*(\S+)\s*$`)
+
+// maxAttemptsLineRegex matches "MaxAttempts: 3" lines.
+var maxAttemptsLineRegex = regexp.MustCompile(`(?i)^MaxAttempts:\s*(\d+)\s*$`)
+
+// onFailureLineRegex matches "OnFailure: retry|skip|escalate|burn" lines.
+var onFailureLineRegex = regexp.MustCompile(`(?i)^OnFailure:\s*(retry|skip|escalate|burn)\s*$`)
+
 // templateVarRegex matches {{variable}} placeholders.
 var templateVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
 
@@ -62,6 +74,9 @@ var templateVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
 //	Tier: haiku|sonnet|opus  # optional
 //	Type: task|wait  # optional, default is "task"
 //	Backoff: base=30s, multiplier=2, max=10m  # optional, for wait-type steps
+//	Timeout: 30m  # optional, time limit per attempt
+//	MaxAttempts: 3  # optional, attempts allowed with OnFailure: retry
+//	OnFailure: retry|skip|escalate|burn  # optional, see StepPolicyFields
 //
 // Returns an empty slice if no steps are found.
 func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
@@ -127,6 +142,20 @@ func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
 				continue
 			}
 
+			// Check for failure policy lines
+			if matches := timeoutLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.Timeout = matches[1]
+				continue
+			}
+			if matches := maxAttemptsLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.MaxAttempts, _ = strconv.Atoi(matches[1])
+				continue
+			}
+			if matches := onFailureLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.OnFailure = strings.ToLower(matches[1])
+				continue
+			}
+
 			// Regular instruction line
 			instructionLines = append(instructionLines, line)
 		}
@@ -174,6 +203,14 @@ func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
 	return steps, nil
 }
 
+// Policy returns the step's failure policy, or nil if it declares none.
+func (s *MoleculeStep) Policy() *StepPolicyFields {
+	if s.Timeout == "" && s.MaxAttempts == 0 && s.OnFailure == "" {
+		return nil
+	}
+	return &StepPolicyFields{Timeout: s.Timeout, MaxAttempts: s.MaxAttempts, OnFailure: s.OnFailure}
+}
+
 // parseBackoffConfig parses a backoff configuration string.
 // Expected format: "base=30s, multiplier=2, max=10m"
 // Returns nil if parsing fails.
@@ -406,6 +443,9 @@ func (b *Beads) instantiateFromMarkdown(mol *Issue, parent *Issue, opts Instanti
 		if step.Tier != "" {
 			description += fmt.Sprintf("\ntier: %s", step.Tier)
 		}
+		if policy := FormatStepPolicyFields(step.Policy()); policy != "" {
+			description += "\n" + policy
+		}
 
 		// Create the child issue
 		childOpts := CreateOptions{
@@ -787,3 +787,38 @@ Needs: step1`
 		t.Errorf("step[1].Type = %q, want task", steps[1].Type)
 	}
 }
+
+func TestParseMoleculeSteps_WithPolicy(t *testing.T) {
+	desc := `## Step: run-tests
+Run the test suite.
+Timeout: 30m
+MaxAttempts: 3
+OnFailure: Retry
+
+## Step: report
+Report results.
+Needs: run-tests`
+
+	steps, err := ParseMoleculeSteps(desc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(steps))
+	}
+
+	policy := steps[0].Policy()
+	if policy == nil {
+		t.Fatal("step[0].Policy() is nil")
+	}
+	if policy.Timeout != "30m" || policy.MaxAttempts != 3 || policy.OnFailure != OnFailureRetry {
+		t.Errorf("step[0].Policy() = %+v", policy)
+	}
+	if strings.Contains(steps[0].Instructions, "Timeout") {
+		t.Errorf("Instructions contain policy lines: %q", steps[0].Instructions)
+	}
+
+	if steps[1].Policy() != nil {
+		t.Errorf("step[1].Policy() = %+v, want nil", steps[1].Policy())
+	}
+}
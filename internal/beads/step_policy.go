@@ -0,0 +1,235 @@
+package beads
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Failure policies for molecule steps (on_failure).
+const (
+	OnFailureRetry    = "retry"    // reopen the step until max_attempts is used up, then escalate
+	OnFailureSkip     = "skip"     // close the step so the molecule continues without it
+	OnFailureEscalate = "escalate" // leave the step open and escalate to the overseer
+	OnFailureBurn     = "burn"     // abandon the whole molecule
+)
+
+// ValidOnFailure returns true if action is a recognized on_failure policy.
+func ValidOnFailure(action string) bool {
+	switch action {
+	case OnFailureRetry, OnFailureSkip, OnFailureEscalate, OnFailureBurn:
+		return true
+	}
+	return false
+}
+
+// StepPolicyFields holds a molecule step's failure policy and the state of
+// its current attempt. They are stored as "step_key: value" lines in the
+// step bead's description, and enforced by patrols (gt mol step check).
+// The step_ prefix keeps instructions like "timeout: 5m" in a step's own
+// text from being read as policy. Attempts count tries of one run of the
+// step; a workflow loop's iterations are tracked separately, in the
+// formula_iteration line of FormulaStepFields.
+type StepPolicyFields struct {
+	// Policy, from the formula or molecule step
+	Timeout     string // Go duration, e.g. "30m"; empty for no limit
+	MaxAttempts int    // attempts allowed with on_failure retry; 0 means 1
+	OnFailure   string // retry, skip, escalate or burn; empty means escalate
+
+	// Tracking
+	Attempts    int    // attempts started so far
+	StartedAt   string // RFC 3339 start of the current attempt
+	LastFailure string // why the last attempt failed
+	EscalatedAt string // RFC 3339 time the step was escalated, if it was
+}
+
+// stepPolicyKeys are the description keys of StepPolicyFields.
+var stepPolicyKeys = map[string]bool{
+	"step_timeout":            true,
+	"step_max_attempts":       true,
+	"step_on_failure":         true,
+	"step_attempts":           true,
+	"step_attempt_started_at": true,
+	"step_last_failure":       true,
+	"step_escalated_at":       true,
+}
+
+// ParseStepPolicyFields extracts step policy fields from an issue's
+// description. Returns nil if the step has no policy fields.
+func ParseStepPolicyFields(issue *Issue) *StepPolicyFields {
+	if issue == nil || issue.Description == "" {
+		return nil
+	}
+
+	fields := &StepPolicyFields{}
+	hasFields := false
+
+	for _, line := range strings.Split(issue.Description, "\n") {
+		line = strings.TrimSpace(line)
+		colonIdx := strings.Index(line, ":")
+		if colonIdx == -1 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[:colonIdx]))
+		value := strings.TrimSpace(line[colonIdx+1:])
+		if value == "" || !stepPolicyKeys[key] {
+			continue
+		}
+
+		switch key {
+		case "step_timeout":
+			fields.Timeout = value
+		case "step_max_attempts":
+			fields.MaxAttempts, _ = strconv.Atoi(value)
+		case "step_on_failure":
+			fields.OnFailure = strings.ToLower(value)
+		case "step_attempts":
+			fields.Attempts, _ = strconv.Atoi(value)
+		case "step_attempt_started_at":
+			fields.StartedAt = value
+		case "step_last_failure":
+			fields.LastFailure = value
+		case "step_escalated_at":
+			fields.EscalatedAt = value
+		}
+		hasFields = true
+	}
+
+	if !hasFields {
+		return nil
+	}
+	return fields
+}
+
+// FormatStepPolicyFields formats StepPolicyFields as description lines.
+// Only non-empty fields are included.
+func FormatStepPolicyFields(fields *StepPolicyFields) string {
+	if fields == nil {
+		return ""
+	}
+
+	var lines []string
+	if fields.Timeout != "" {
+		lines = append(lines, "step_timeout: "+fields.Timeout)
+	}
+	if fields.MaxAttempts > 0 {
+		lines = append(lines, fmt.Sprintf("step_max_attempts: %d", fields.MaxAttempts))
+	}
+	if fields.OnFailure != "" {
+		lines = append(lines, "step_on_failure: "+fields.OnFailure)
+	}
+	if fields.Attempts > 0 {
+		lines = append(lines, fmt.Sprintf("step_attempts: %d", fields.Attempts))
+	}
+	if fields.StartedAt != "" {
+		lines = append(lines, "step_attempt_started_at: "+fields.StartedAt)
+	}
+	if fields.LastFailure != "" {
+		// Keep the reason on one line so it parses back
+		lines = append(lines, "step_last_failure: "+strings.Join(strings.Fields(fields.LastFailure), " "))
+	}
+	if fields.EscalatedAt != "" {
+		lines = append(lines, "step_escalated_at: "+fields.EscalatedAt)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SetStepPolicyFields updates an issue's description with the given step
+// policy fields. Existing policy lines are replaced; other content is
+// preserved. Returns the new description string.
+func SetStepPolicyFields(issue *Issue, fields *StepPolicyFields) string {
+	var otherLines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if colonIdx := strings.Index(trimmed, ":"); colonIdx != -1 {
+				key := strings.ToLower(strings.TrimSpace(trimmed[:colonIdx]))
+				if stepPolicyKeys[key] {
+					continue
+				}
+			}
+			otherLines = append(otherLines, line)
+		}
+	}
+
+	// Trim leading and trailing blank lines from other content
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[0]) == "" {
+		otherLines = otherLines[1:]
+	}
+
+	formatted := FormatStepPolicyFields(fields)
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+
+	// Policy goes last: the step's instructions stay on top for the agent
+	return strings.Join(otherLines, "\n") + "\n\n" + formatted
+}
+
+// Limit returns the parsed timeout, or 0 if the step has none.
+func (f *StepPolicyFields) Limit() time.Duration {
+	d, err := time.ParseDuration(f.Timeout)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// Remaining returns the time left in the current attempt. ok is false if
+// the step has no timeout or no attempt has started.
+func (f *StepPolicyFields) Remaining(now time.Time) (time.Duration, bool) {
+	limit := f.Limit()
+	if limit == 0 || f.StartedAt == "" {
+		return 0, false
+	}
+	started, err := time.Parse(time.RFC3339, f.StartedAt)
+	if err != nil {
+		return 0, false
+	}
+	return started.Add(limit).Sub(now), true
+}
+
+// TimedOut reports whether the current attempt has run past the timeout.
+func (f *StepPolicyFields) TimedOut(now time.Time) bool {
+	remaining, ok := f.Remaining(now)
+	return ok && remaining <= 0
+}
+
+// AttemptLimit returns the number of attempts allowed, at least 1.
+func (f *StepPolicyFields) AttemptLimit() int {
+	if f.MaxAttempts < 1 {
+		return 1
+	}
+	return f.MaxAttempts
+}
+
+// StartAttempt starts the clock on a new attempt.
+func (f *StepPolicyFields) StartAttempt(now time.Time) {
+	f.Attempts++
+	f.StartedAt = now.UTC().Format(time.RFC3339)
+}
+
+// FailureAction returns what to do now that the current attempt failed:
+// on_failure, except that retry escalates once attempts are used up and
+// an unset policy escalates.
+func (f *StepPolicyFields) FailureAction() string {
+	switch f.OnFailure {
+	case OnFailureRetry:
+		if f.Attempts < f.AttemptLimit() {
+			return OnFailureRetry
+		}
+		return OnFailureEscalate
+	case OnFailureSkip, OnFailureBurn:
+		return f.OnFailure
+	}
+	return OnFailureEscalate
+}
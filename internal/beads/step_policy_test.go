@@ -0,0 +1,89 @@
+package beads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStepPolicyFields_RoundTrip(t *testing.T) {
+	issue := &Issue{Description: "Run the tests.\n\nstep_attempts: 1\n"}
+	fields := &StepPolicyFields{
+		Timeout:     "30m",
+		MaxAttempts: 3,
+		OnFailure:   OnFailureRetry,
+		Attempts:    2,
+		StartedAt:   "2026-01-02T15:04:05Z",
+		LastFailure: "timed out\nafter 30m",
+	}
+
+	desc := SetStepPolicyFields(issue, fields)
+	want := "Run the tests.\n\nstep_timeout: 30m\nstep_max_attempts: 3\nstep_on_failure: retry\nstep_attempts: 2\n" +
+		"step_attempt_started_at: 2026-01-02T15:04:05Z\nstep_last_failure: timed out after 30m"
+	if desc != want {
+		t.Errorf("SetStepPolicyFields =\n%s\nwant\n%s", desc, want)
+	}
+
+	got := ParseStepPolicyFields(&Issue{Description: desc})
+	if got == nil {
+		t.Fatal("ParseStepPolicyFields returned nil")
+	}
+	if got.Timeout != "30m" || got.MaxAttempts != 3 || got.OnFailure != OnFailureRetry || got.Attempts != 2 {
+		t.Errorf("parsed policy = %+v", got)
+	}
+	if got.LastFailure != "timed out after 30m" {
+		t.Errorf("LastFailure = %q", got.LastFailure)
+	}
+
+	if ParseStepPolicyFields(&Issue{Description: "Run the tests."}) != nil {
+		t.Error("expected nil for a description without policy fields")
+	}
+	// Unprefixed keys are the step's own instructions, not policy
+	if ParseStepPolicyFields(&Issue{Description: "Poll the build.\ntimeout: 5m\nattempts: 3"}) != nil {
+		t.Error("unprefixed keys parsed as policy")
+	}
+}
+
+func TestStepPolicyFields_Remaining(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	f := &StepPolicyFields{Timeout: "30m"}
+	if _, ok := f.Remaining(now); ok {
+		t.Error("Remaining ok before the attempt started")
+	}
+
+	f.StartAttempt(now.Add(-20 * time.Minute))
+	if f.Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1", f.Attempts)
+	}
+	if left, ok := f.Remaining(now); !ok || left != 10*time.Minute {
+		t.Errorf("Remaining = %v, %v; want 10m", left, ok)
+	}
+	if f.TimedOut(now) {
+		t.Error("TimedOut with 10m left")
+	}
+	if !f.TimedOut(now.Add(10 * time.Minute)) {
+		t.Error("not TimedOut at the deadline")
+	}
+
+	if (&StepPolicyFields{OnFailure: OnFailureSkip}).TimedOut(now) {
+		t.Error("TimedOut without a timeout")
+	}
+}
+
+func TestStepPolicyFields_FailureAction(t *testing.T) {
+	tests := []struct {
+		fields StepPolicyFields
+		want   string
+	}{
+		{StepPolicyFields{}, OnFailureEscalate},
+		{StepPolicyFields{OnFailure: OnFailureRetry, MaxAttempts: 3, Attempts: 2}, OnFailureRetry},
+		{StepPolicyFields{OnFailure: OnFailureRetry, MaxAttempts: 3, Attempts: 3}, OnFailureEscalate},
+		{StepPolicyFields{OnFailure: OnFailureSkip, Attempts: 1}, OnFailureSkip},
+		{StepPolicyFields{OnFailure: OnFailureBurn, Attempts: 1}, OnFailureBurn},
+		{StepPolicyFields{OnFailure: OnFailureEscalate}, OnFailureEscalate},
+	}
+	for _, tt := range tests {
+		if got := tt.fields.FailureAction(); got != tt.want {
+			t.Errorf("%+v FailureAction() = %q, want %q", tt.fields, got, tt.want)
+		}
+	}
+}
@@ -116,6 +116,9 @@ func runCLIList(cmd *cobra.Command, args []string) error {
 
 // saveTownSettings saves town settings to a file.
 func saveTownSettings(path string, settings *config.TownSettings) error {
+	if err := config.ValidateTownSettings(settings); err != nil {
+		return fmt.Errorf("invalid town settings: %w", err)
+	}
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("creating directory: %w", err)
 	}
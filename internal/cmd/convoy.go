@@ -16,6 +16,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -69,6 +70,13 @@ var (
 	convoyListTree     bool
 	convoyInteractive  bool
 	convoyStrandedJSON bool
+	convoyAfter        []string
+	convoyStages       []string
+	convoyTarget       string
+	convoyAddStage     int
+	convoyDue          string
+	convoyFromEpic     string
+	convoyRigs         []string
 )
 
 var convoyCmd = &cobra.Command{
@@ -103,15 +111,30 @@ TRACKING SEMANTICS:
   - Cross-prefix capable (convoy in hq-* tracks issues in gt-*, bd-*)
   - Landed: all tracked issues closed → notification sent to subscribers
 
+DEPENDENCIES AND STAGES:
+  - --after <convoy>: dispatch nothing until that convoy lands
+  - --stage <issues>: ordered stages; stage N+1 starts when stage N closes
+  - 'gt convoy check' slings each stage as it is released (to --target,
+    or routed with gt sling --auto)
+
+EPICS:
+  - --from-epic <id>: track every issue under an epic, recursively
+  - 'gt convoy check' tracks children added to the epic later
+  - Issues no rig in scope (--rigs, or --target) can take are marked
+    out of scope
+
 COMMANDS:
   create    Create a convoy tracking specified issues
   add       Add issues to an existing convoy (reopens if closed)
   status    Show convoy progress, tracked issues, and active workers
-  list      List convoys (the dashboard view)`,
+  list      List convoys (the dashboard view)
+  check     Sync epics, auto-close landed convoys, release stages, alert on slips
+  due       Set or clear the date a convoy should land by
+  stranded  Find convoys with ready work but no workers`,
 }
 
 var convoyCreateCmd = &cobra.Command{
-	Use:   "create <name> [issues...]",
+	Use:   "create [name] [issues...]",
 	Short: "Create a new convoy",
 	Long: `Create a new convoy that tracks the specified issues.
 
@@ -122,8 +145,40 @@ Examples:
   gt convoy create "Deploy v2.0" gt-abc bd-xyz
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
   gt convoy create "Release prep" gt-abc --notify ops/      # notify ops/
-  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release`,
-	Args: cobra.MinimumNArgs(1),
+  gt convoy create "Release prep" gt-abc --due 2026-11-01   # alert if the ETA slips past it
+  gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
+
+Dependencies and stages:
+  # Schema first, then API, then UI; stages start as the previous one closes
+  gt convoy create "Billing" gt-schema --stage gt-api1,gt-api2 --stage gt-ui --target gastown
+
+  # Don't start until another convoy lands
+  gt convoy create "Billing docs" gt-docs --after hq-cv-abc
+
+Positional issues are stage 1. A convoy with --after, or stages beyond 1,
+is dispatched by 'gt convoy check': when its needs land it slings stage 1,
+and each later stage once the one before is closed. Stage 1 of a convoy
+without --after is yours to sling, as usual.
+
+From an epic:
+  gt convoy create --from-epic gt-epic                     # named after the epic
+  gt convoy create "Billing" --from-epic gt-epic --rigs gastown,beads
+
+The convoy tracks every issue under the epic, recursively (nested epics
+are walked, not tracked). 'gt convoy check' tracks children added later,
+reopening the convoy if it had landed. Open issues needing capabilities
+(lang:, area:, cli:, cap: labels) that no rig in scope has are marked out
+of scope: tracked, but not dispatched or reported stranded. The scope is
+--rigs, else --target, else every rig.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if convoyFromEpic != "" {
+			if len(args) > 1 {
+				return fmt.Errorf("--from-epic tracks the epic's issues: give at most a name (add others with 'gt convoy add')")
+			}
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: runConvoyCreate,
 }
 
@@ -132,7 +187,15 @@ var convoyStatusCmd = &cobra.Command{
 	Short: "Show convoy status",
 	Long: `Show detailed status for a convoy.
 
-Displays convoy metadata, tracked issues, and completion progress.
+Displays convoy metadata, tracked issues, and completion progress, with a
+burndown of open issues since the convoy was created and a forecast.
+
+The forecast (ETA) comes from how long issues took to close across the
+town over the last 30 days (beads close times, and merge times from the
+merge queue), and how many workers are on the convoy now. It gives an
+early–late range and a likely date. With a due date (--due), it flags a
+convoy projected to slip.
+
 Without an ID, shows status of all active convoys.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runConvoyStatus,
@@ -161,7 +224,8 @@ If the convoy is closed, it will be automatically reopened.
 
 Examples:
   gt convoy add hq-cv-abc gt-new-issue
-  gt convoy add hq-cv-abc gt-issue1 gt-issue2 gt-issue3`,
+  gt convoy add hq-cv-abc gt-issue1 gt-issue2 gt-issue3
+  gt convoy add hq-cv-abc gt-followup --stage 3    # Add to a staged convoy`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runConvoyAdd,
 }
@@ -174,6 +238,23 @@ var convoyCheckCmd = &cobra.Command{
 This handles cross-rig convoy completion: convoys in town beads tracking issues
 in rig beads won't auto-close via bd close alone. This command bridges that gap.
 
+First it syncs epic convoys (--from-epic): issues added under the epic are
+tracked, reopening a landed convoy if they are open, and open issues no
+rig in the convoy's scope can take are marked out of scope.
+
+Workflow formula runs (gt formula run) are advanced next: steps whose needs
+are done and whose when condition holds are created and slung, loop steps
+whose until condition doesn't hold yet are reopened, and loops that ran
+out of iterations are escalated.
+
+It also mails an alert (to the convoy's Notify address, or the mayor) when
+a convoy's likely landing slips past its due date.
+
+It then advances dependent and staged convoys: a convoy whose --after
+convoys have landed releases stage 1, and a convoy whose current stage is
+closed releases the next. Open, unassigned issues in a released stage are
+slung to the convoy's target rig (or with gt sling --auto).
+
 Can be run manually or by deacon patrol to ensure convoys close promptly.`,
 	RunE: runConvoyCheck,
 }
@@ -190,6 +271,10 @@ A convoy is "stranded" when:
   - not blocked (all dependencies met)
   - no assignee OR assignee session is dead
 
+Issues in stages not yet released, and convoys still waiting on the
+convoys they need, are held rather than stranded: 'gt convoy check'
+releases them.
+
 Use this to detect convoys that need feeding. The Deacon patrol runs this
 periodically and dispatches dogs to feed stranded convoys.
 
@@ -199,11 +284,35 @@ Examples:
 	RunE: runConvoyStranded,
 }
 
+var convoyDueCmd = &cobra.Command{
+	Use:   "due <convoy-id> <YYYY-MM-DD|none>",
+	Short: "Set the date a convoy should land by",
+	Long: `Set or clear a convoy's due date.
+
+'gt convoy status' shows whether the forecast lands by the due date, and
+'gt convoy check' mails an alert when the likely landing slips past it.
+
+Examples:
+  gt convoy due hq-cv-abc 2026-11-01
+  gt convoy due hq-cv-abc none`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConvoyDue,
+}
+
 func init() {
 	// Create flags
 	convoyCreateCmd.Flags().StringVar(&convoyMolecule, "molecule", "", "Associated molecule ID")
 	convoyCreateCmd.Flags().StringVar(&convoyNotify, "notify", "", "Address to notify on completion (default: mayor/ if flag used without value)")
 	convoyCreateCmd.Flags().Lookup("notify").NoOptDefVal = "mayor/"
+	convoyCreateCmd.Flags().StringArrayVar(&convoyAfter, "after", nil, "Convoy that must land before this one dispatches (repeatable)")
+	convoyCreateCmd.Flags().StringArrayVar(&convoyStages, "stage", nil, "Comma-separated issues for the next stage (repeatable, in order)")
+	convoyCreateCmd.Flags().StringVar(&convoyTarget, "target", "", "Rig to sling released stages to (default: gt sling --auto)")
+	convoyCreateCmd.Flags().StringVar(&convoyDue, "due", "", "Date the convoy should land by (YYYY-MM-DD); slips are alerted")
+	convoyCreateCmd.Flags().StringVar(&convoyFromEpic, "from-epic", "", "Track every issue under this epic, kept in sync by 'gt convoy check'")
+	convoyCreateCmd.Flags().StringSliceVar(&convoyRigs, "rigs", nil, "Rigs an epic convoy is scoped to (comma-separated; default: --target, or every rig)")
+
+	// Add flags
+	convoyAddCmd.Flags().IntVar(&convoyAddStage, "stage", 0, "Stage to add the issues to (staged convoys)")
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
@@ -227,6 +336,7 @@ func init() {
 	convoyCmd.AddCommand(convoyAddCmd)
 	convoyCmd.AddCommand(convoyCheckCmd)
 	convoyCmd.AddCommand(convoyStrandedCmd)
+	convoyCmd.AddCommand(convoyDueCmd)
 
 	rootCmd.AddCommand(convoyCmd)
 }
@@ -241,12 +351,16 @@ func getTownBeadsDir() (string, error) {
 }
 
 func runConvoyCreate(cmd *cobra.Command, args []string) error {
-	name := args[0]
-	trackedIssues := args[1:]
+	var name string
+	var trackedIssues []string
+	if len(args) > 0 {
+		name = args[0]
+		trackedIssues = args[1:]
+	}
 
 	// If first arg looks like an issue ID (has beads prefix), treat all args as issues
 	// and auto-generate a name from the first issue's title
-	if looksLikeIssueID(name) {
+	if convoyFromEpic == "" && looksLikeIssueID(name) {
 		trackedIssues = args // All args are issue IDs
 		// Get the first issue's title to use as convoy name
 		if details := getIssueDetails(args[0]); details != nil && details.Title != "" {
@@ -261,6 +375,33 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Dependencies and stages: positional issues are stage 1
+	fields, err := convoyCreateFields(townBeads, trackedIssues)
+	if err != nil {
+		return err
+	}
+	if fields.Staged() {
+		trackedIssues = nil
+		for _, stage := range fields.Stages {
+			trackedIssues = append(trackedIssues, stage...)
+		}
+	}
+
+	// Epic convoys track the epic's issues
+	if convoyFromEpic != "" {
+		issues, epicTitle, err := loadEpicMembers(filepath.Dir(townBeads), fields)
+		if err != nil {
+			return err
+		}
+		trackedIssues = issues
+		if name == "" {
+			name = epicTitle
+		}
+		if name == "" {
+			name = fmt.Sprintf("Epic %s", convoyFromEpic)
+		}
+	}
+
 	// Create convoy issue in town beads
 	description := fmt.Sprintf("Convoy tracking %d issues", len(trackedIssues))
 	if convoyNotify != "" {
@@ -269,6 +410,9 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		description += fmt.Sprintf("\nMolecule: %s", convoyMolecule)
 	}
+	if fields != nil {
+		description += "\n" + beads.FormatConvoyFields(fields)
+	}
 
 	// Generate convoy ID with cv- prefix
 	convoyID := fmt.Sprintf("hq-cv-%s", generateShortID())
@@ -323,12 +467,126 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyMolecule != "" {
 		fmt.Printf("  Molecule: %s\n", convoyMolecule)
 	}
+	if fields != nil && fields.Due != "" {
+		fmt.Printf("  Due:      %s\n", fields.Due)
+	}
+	if fields != nil && fields.Epic != "" {
+		fmt.Printf("  Epic:     %s\n", fields.Epic)
+		if len(fields.OutOfScope) > 0 {
+			scope := "any rig"
+			if rigs := fields.ScopeRigs(); len(rigs) > 0 {
+				scope = strings.Join(rigs, ", ")
+			}
+			fmt.Printf("  %s\n", style.Warning.Render(fmt.Sprintf("Out of scope for %s: %s", scope, strings.Join(fields.OutOfScope, ", "))))
+		}
+	}
+	if fields.Staged() {
+		if len(fields.Needs) > 0 {
+			fmt.Printf("  After:    %s\n", strings.Join(fields.Needs, ", "))
+		}
+		if len(fields.Stages) > 1 {
+			for i, stage := range fields.Stages {
+				fmt.Printf("  Stage %d:  %s\n", i+1, strings.Join(stage, ", "))
+			}
+		}
+		target := fields.Target
+		if target == "" {
+			target = "auto (gt sling --auto)"
+		}
+		fmt.Printf("  Target:   %s\n", target)
+	}
 
 	fmt.Printf("\n  %s\n", style.Dim.Render("Convoy auto-closes when all tracked issues complete"))
+	if fields != nil && fields.Epic != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("'gt convoy check' tracks issues added to the epic"))
+	}
+	if fields.Staged() {
+		if fields.Released == 0 {
+			fmt.Printf("  %s\n", style.Dim.Render("Stage 1 is slung by 'gt convoy check' once the convoys it needs land"))
+		} else {
+			fmt.Printf("  %s\n", style.Dim.Render("Sling stage 1 now; 'gt convoy check' slings later stages as each one closes"))
+		}
+	}
 
 	return nil
 }
 
+// convoyCreateFields builds the dependency, stage, due date and epic
+// fields for a new convoy from --after, --stage, --target, --due,
+// --from-epic and --rigs. Returns nil if none were given.
+func convoyCreateFields(townBeads string, stage1 []string) (*beads.ConvoyFields, error) {
+	if convoyDue != "" {
+		if _, err := parseConvoyDue(convoyDue); err != nil {
+			return nil, err
+		}
+	}
+	if convoyFromEpic == "" && len(convoyRigs) > 0 {
+		return nil, fmt.Errorf("--rigs applies to convoys created with --from-epic")
+	}
+	if convoyFromEpic != "" && len(convoyStages) > 0 {
+		return nil, fmt.Errorf("--stage can't be combined with --from-epic (stage issues later with 'gt convoy add --stage')")
+	}
+	for _, name := range append([]string{convoyTarget}, convoyRigs...) {
+		if name == "" {
+			continue
+		}
+		if _, isRig := IsRigName(name); !isRig {
+			return nil, fmt.Errorf("'%s' is not a rig", name)
+		}
+	}
+	if len(convoyAfter) == 0 && len(convoyStages) == 0 {
+		if convoyTarget != "" && convoyFromEpic == "" {
+			return nil, fmt.Errorf("--target applies to convoys with --after, --stage or --from-epic")
+		}
+		if convoyDue == "" && convoyFromEpic == "" {
+			return nil, nil
+		}
+		return &beads.ConvoyFields{Due: convoyDue, Target: convoyTarget, Epic: convoyFromEpic, Rigs: convoyRigs}, nil
+	}
+
+	b := beads.New(townBeads)
+	for _, id := range convoyAfter {
+		needed, err := b.Show(id)
+		if err != nil {
+			return nil, fmt.Errorf("convoy '%s' not found", id)
+		}
+		if needed.Type != "convoy" {
+			return nil, fmt.Errorf("--after '%s' is not a convoy (type: %s)", id, needed.Type)
+		}
+	}
+
+	fields := &beads.ConvoyFields{Needs: convoyAfter, Target: convoyTarget, Due: convoyDue, Epic: convoyFromEpic, Rigs: convoyRigs}
+	fields.Stages = append(fields.Stages, stage1)
+	seen := make(map[string]bool)
+	for _, id := range stage1 {
+		seen[id] = true
+	}
+	for i, spec := range convoyStages {
+		var stage []string
+		for _, id := range strings.Split(spec, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			if seen[id] {
+				return nil, fmt.Errorf("%s is in more than one stage", id)
+			}
+			seen[id] = true
+			stage = append(stage, id)
+		}
+		if len(stage) == 0 {
+			return nil, fmt.Errorf("--stage %d is empty", i+2)
+		}
+		fields.Stages = append(fields.Stages, stage)
+	}
+
+	// Stage 1 of a convoy with no needs is dispatched by hand, as before
+	if len(fields.Needs) == 0 {
+		fields.Released = 1
+	}
+	return fields, nil
+}
+
 func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	convoyID := args[0]
 	issuesToAdd := args[1:]
@@ -350,10 +608,11 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	var convoys []struct {
-		ID     string `json:"id"`
-		Title  string `json:"title"`
-		Status string `json:"status"`
-		Type   string `json:"issue_type"`
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Status      string `json:"status"`
+		Type        string `json:"issue_type"`
+		Description string `json:"description"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
 		return fmt.Errorf("parsing convoy data: %w", err)
@@ -370,6 +629,29 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("'%s' is not a convoy (type: %s)", convoyID, convoy.Type)
 	}
 
+	// Staged convoys: record which stage the new issues belong to
+	if convoyAddStage > 0 {
+		issue := &beads.Issue{ID: convoy.ID, Description: convoy.Description}
+		fields := beads.ParseConvoyFields(issue)
+		if !fields.Staged() {
+			return fmt.Errorf("convoy '%s' has no stages (create it with --stage)", convoyID)
+		}
+		if convoyAddStage > len(fields.Stages)+1 {
+			return fmt.Errorf("convoy '%s' has %d stages: --stage can be at most %d", convoyID, len(fields.Stages), len(fields.Stages)+1)
+		}
+		if convoyAddStage > len(fields.Stages) {
+			fields.Stages = append(fields.Stages, nil)
+		}
+		fields.Stages[convoyAddStage-1] = append(fields.Stages[convoyAddStage-1], issuesToAdd...)
+		description := beads.SetConvoyFields(issue, fields)
+		if err := beads.New(townBeads).Update(convoyID, beads.UpdateOptions{Description: &description}); err != nil {
+			return fmt.Errorf("updating convoy stages: %w", err)
+		}
+		if convoyAddStage <= fields.Released {
+			fmt.Printf("%s Stage %d is already released: sling the new issues yourself\n", style.WarningPrefix, convoyAddStage)
+		}
+	}
+
 	// If convoy is closed, reopen it
 	reopened := false
 	if convoy.Status == "closed" {
@@ -409,12 +691,95 @@ func runConvoyAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConvoyDue(cmd *cobra.Command, args []string) error {
+	convoyID, due := args[0], args[1]
+	if due == "none" {
+		due = ""
+	} else if _, err := parseConvoyDue(due); err != nil {
+		return err
+	}
+
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	b := beads.New(townBeads)
+	convoy, err := b.Show(convoyID)
+	if err != nil {
+		return fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	if convoy.Type != "convoy" {
+		return fmt.Errorf("'%s' is not a convoy (type: %s)", convoyID, convoy.Type)
+	}
+
+	fields := beads.ParseConvoyFields(convoy)
+	if fields == nil {
+		fields = &beads.ConvoyFields{}
+	}
+	fields.Due = due
+	fields.SlipAlerted = "" // New date, new slip alerts
+	description := beads.SetConvoyFields(convoy, fields)
+	if err := b.Update(convoyID, beads.UpdateOptions{Description: &description}); err != nil {
+		return fmt.Errorf("updating convoy: %w", err)
+	}
+
+	if due == "" {
+		fmt.Printf("%s Cleared due date of 🚚 %s\n", style.Bold.Render("✓"), convoyID)
+	} else {
+		fmt.Printf("%s 🚚 %s is due %s\n", style.Bold.Render("✓"), convoyID, due)
+	}
+	return nil
+}
+
 func runConvoyCheck(cmd *cobra.Command, args []string) error {
 	townBeads, err := getTownBeadsDir()
 	if err != nil {
 		return err
 	}
 
+	// Track new epic children first so a convoy doesn't close under them
+	syncs, err := syncEpicConvoys(townBeads)
+	if err != nil {
+		return err
+	}
+	if len(syncs) > 0 {
+		fmt.Printf("%s Synced %d epic convoy(s):\n", style.Bold.Render("↻"), len(syncs))
+		for _, sy := range syncs {
+			line := fmt.Sprintf("  🚚 %s: %s", sy.ID, sy.Title)
+			if sy.Reopened {
+				line += " (reopened)"
+			}
+			fmt.Println(line)
+			if len(sy.Added) > 0 {
+				fmt.Printf("     Added:        %s\n", strings.Join(sy.Added, ", "))
+			}
+			if len(sy.OutOfScope) > 0 {
+				fmt.Printf("     %s\n", style.Warning.Render("Out of scope: "+strings.Join(sy.OutOfScope, ", ")))
+			}
+		}
+	}
+
+	// Create and rerun workflow formula steps before the runs are judged done
+	advances, err := advanceFormulaRuns(townBeads)
+	if err != nil {
+		return err
+	}
+	if len(advances) > 0 {
+		fmt.Printf("%s Advanced %d formula run(s):\n", style.Bold.Render("▶"), len(advances))
+		for _, a := range advances {
+			fmt.Printf("  🚚 %s: %s\n", a.ID, a.Title)
+			if len(a.Dispatched) > 0 {
+				fmt.Printf("     Dispatched: %s\n", strings.Join(a.Dispatched, ", "))
+			}
+			if len(a.Rerun) > 0 {
+				fmt.Printf("     Rerun:      %s\n", strings.Join(a.Rerun, ", "))
+			}
+			if len(a.Failed) > 0 {
+				fmt.Printf("     %s\n", style.Warning.Render("Escalated:  "+strings.Join(a.Failed, ", ")))
+			}
+		}
+	}
+
 	closed, err := checkAndCloseCompletedConvoys(townBeads)
 	if err != nil {
 		return err
@@ -429,6 +794,35 @@ func runConvoyCheck(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	slips, err := checkConvoySlips(townBeads, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(slips) > 0 {
+		fmt.Printf("%s %d convoy(s) projected to slip:\n", style.Warning.Render("⚠"), len(slips))
+		for _, sl := range slips {
+			fmt.Printf("  🚚 %s: %s — due %s, likely %s\n", sl.ID, sl.Title, sl.Due, sl.Likely.Format(convoyDueLayout))
+		}
+	}
+
+	// Closing convoys can unblock the convoys that need them
+	releases, err := advanceConvoyStages(townBeads)
+	if err != nil {
+		return err
+	}
+	if len(releases) > 0 {
+		fmt.Printf("%s Released %d stage(s):\n", style.Bold.Render("▶"), len(releases))
+		for _, r := range releases {
+			fmt.Printf("  🚚 %s: %s — stage %d/%d\n", r.ID, r.Title, r.Stage, r.Stages)
+			if len(r.Slung) > 0 {
+				fmt.Printf("     Slung:   %s\n", strings.Join(r.Slung, ", "))
+			}
+			if len(r.Skipped) > 0 {
+				fmt.Printf("     %s\n", style.Dim.Render("Skipped: "+strings.Join(r.Skipped, ", ")))
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -438,6 +832,7 @@ type strandedConvoyInfo struct {
 	Title       string   `json:"title"`
 	ReadyCount  int      `json:"ready_count"`
 	ReadyIssues []string `json:"ready_issues"`
+	Stage       string   `json:"stage,omitempty"` // e.g. "stage 2/3" for staged convoys
 }
 
 // readyIssueInfo holds info about a ready (stranded) issue.
@@ -472,6 +867,9 @@ func runConvoyStranded(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%s Found %d stranded convoy(s):\n\n", style.Warning.Render("⚠"), len(stranded))
 	for _, s := range stranded {
 		fmt.Printf("  🚚 %s: %s\n", s.ID, s.Title)
+		if s.Stage != "" {
+			fmt.Printf("     Stage: %s\n", s.Stage)
+		}
 		fmt.Printf("     Ready issues: %d\n", s.ReadyCount)
 		for _, issueID := range s.ReadyIssues {
 			fmt.Printf("       • %s\n", issueID)
@@ -506,8 +904,9 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 	}
 
 	var convoys []struct {
-		ID    string `json:"id"`
-		Title string `json:"title"`
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &convoys); err != nil {
 		return nil, fmt.Errorf("parsing convoy list: %w", err)
@@ -515,26 +914,39 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 
 	// Check each convoy for stranded state
 	for _, convoy := range convoys {
+		// Convoys waiting on other convoys are held, not stranded
+		fields := beads.ParseConvoyFields(&beads.Issue{Description: convoy.Description})
+		if fields.Staged() && fields.Released == 0 {
+			continue
+		}
+
 		tracked := getTrackedIssues(townBeads, convoy.ID)
 		if len(tracked) == 0 {
 			continue
 		}
 
-		// Find ready issues (open, not blocked, no live assignee)
+		// Find ready issues (open, not blocked, no live assignee, stage released)
 		var readyIssues []string
 		for _, t := range tracked {
+			if fields.Held(t.ID) || !fields.InScope(t.ID) {
+				continue
+			}
 			if isReadyIssue(t, blockedIssues) {
 				readyIssues = append(readyIssues, t.ID)
 			}
 		}
 
 		if len(readyIssues) > 0 {
-			stranded = append(stranded, strandedConvoyInfo{
+			info := strandedConvoyInfo{
 				ID:          convoy.ID,
 				Title:       convoy.Title,
 				ReadyCount:  len(readyIssues),
 				ReadyIssues: readyIssues,
-			})
+			}
+			if fields.Staged() {
+				info.Stage = formatConvoyStage(fields, nil)
+			}
+			stranded = append(stranded, info)
 		}
 	}
 
@@ -687,20 +1099,13 @@ func notifyConvoyCompletion(townBeads, convoyID, title string) {
 	}
 
 	// Parse notify address from description
-	desc := convoys[0].Description
-	for _, line := range strings.Split(desc, "\n") {
-		if strings.HasPrefix(line, "Notify: ") {
-			addr := strings.TrimPrefix(line, "Notify: ")
-			if addr != "" {
-				// Send notification via gt mail
-				mailArgs := []string{"mail", "send", addr,
-					"-s", fmt.Sprintf("🚚 Convoy landed: %s", title),
-					"-m", fmt.Sprintf("Convoy %s has completed.\n\nAll tracked issues are now closed.", convoyID)}
-				mailCmd := exec.Command("gt", mailArgs...)
-				_ = mailCmd.Run() // Best effort, ignore errors
-			}
-			break
-		}
+	if addr := convoyNotifyAddress(convoys[0].Description); addr != "" {
+		// Send notification via gt mail
+		mailArgs := []string{"mail", "send", addr,
+			"-s", fmt.Sprintf("🚚 Convoy landed: %s", title),
+			"-m", fmt.Sprintf("Convoy %s has completed.\n\nAll tracked issues are now closed.", convoyID)}
+		mailCmd := exec.Command("gt", mailArgs...)
+		_ = mailCmd.Run() // Best effort, ignore errors
 	}
 }
 
@@ -769,6 +1174,17 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 
 	tracked := getTrackedIssues(townBeads, convoyID)
 
+	// Dependencies and stages, if the convoy has them
+	fields := beads.ParseConvoyFields(&beads.Issue{Description: convoy.Description})
+	stage := ""
+	if fields.Staged() {
+		var waiting []string
+		if fields.Released == 0 {
+			_, waiting = convoyNeedsLanded(beads.New(townBeads), fields.Needs)
+		}
+		stage = formatConvoyStage(fields, waiting)
+	}
+
 	// Count completed
 	completed := 0
 	for _, t := range tracked {
@@ -777,14 +1193,30 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Burndown and forecast (history only matters while work remains)
+	now := time.Now()
+	var history []time.Duration
+	if convoy.Status != "closed" && completed < len(tracked) {
+		history = convoyCloseHistory(filepath.Dir(townBeads), now)
+	}
+	outlook := forecastConvoy(convoy.CreatedAt, tracked, history, now)
+	if fields != nil {
+		outlook.checkDue(fields.Due)
+	}
+
 	if convoyStatusJSON {
 		type jsonStatus struct {
-			ID        string             `json:"id"`
-			Title     string             `json:"title"`
-			Status    string             `json:"status"`
-			Tracked   []trackedIssueInfo `json:"tracked"`
-			Completed int                `json:"completed"`
-			Total     int                `json:"total"`
+			ID         string             `json:"id"`
+			Title      string             `json:"title"`
+			Status     string             `json:"status"`
+			Tracked    []trackedIssueInfo `json:"tracked"`
+			Completed  int                `json:"completed"`
+			Total      int                `json:"total"`
+			Stage      string             `json:"stage,omitempty"`
+			Needs      []string           `json:"needs,omitempty"`
+			Epic       string             `json:"epic,omitempty"`
+			OutOfScope []string           `json:"out_of_scope,omitempty"`
+			*convoyForecastInfo
 		}
 		out := jsonStatus{
 			ID:        convoy.ID,
@@ -793,6 +1225,13 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Tracked:   tracked,
 			Completed: completed,
 			Total:     len(tracked),
+			Stage:     stage,
+		}
+		out.convoyForecastInfo = outlook
+		if fields != nil {
+			out.Needs = fields.Needs
+			out.Epic = fields.Epic
+			out.OutOfScope = fields.OutOfScope
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
@@ -803,6 +1242,20 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 	fmt.Printf("🚚 %s %s\n\n", style.Bold.Render(convoy.ID+":"), convoy.Title)
 	fmt.Printf("  Status:    %s\n", formatConvoyStatus(convoy.Status))
 	fmt.Printf("  Progress:  %d/%d completed\n", completed, len(tracked))
+	printConvoyForecast(outlook, len(history), now)
+	if fields.Staged() {
+		fmt.Printf("  Stage:     %s\n", stage)
+		if len(fields.Needs) > 0 {
+			fmt.Printf("  After:     %s\n", strings.Join(fields.Needs, ", "))
+		}
+	}
+	if fields != nil && fields.Epic != "" {
+		epicLine := fields.Epic
+		if rigs := fields.ScopeRigs(); len(rigs) > 0 {
+			epicLine += "  " + style.Dim.Render("(scoped to "+strings.Join(rigs, ", ")+")")
+		}
+		fmt.Printf("  Epic:      %s\n", epicLine)
+	}
 	fmt.Printf("  Created:   %s\n", convoy.CreatedAt)
 	if convoy.ClosedAt != "" {
 		fmt.Printf("  Closed:    %s\n", convoy.ClosedAt)
@@ -829,7 +1282,26 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 				bracketContent = "unassigned"
 			}
 
+			held := t.Status != "closed" && fields.Held(t.ID)
+			if held {
+				status = "◌"
+			}
+			outOfScope := t.Status != "closed" && !fields.InScope(t.ID)
+			if outOfScope {
+				status = "⊘"
+			}
+
 			line := fmt.Sprintf("    %s %s: %s [%s]", status, t.ID, t.Title, bracketContent)
+			if fields.Staged() && len(fields.Stages) > 1 {
+				stageNote := fmt.Sprintf("stage %d", fields.StageOf(t.ID))
+				if held {
+					stageNote += ", held"
+				}
+				line += "  " + style.Dim.Render("("+stageNote+")")
+			}
+			if outOfScope {
+				line += "  " + style.Warning.Render("(out of scope)")
+			}
 			if t.Worker != "" {
 				workerDisplay := "@" + t.Worker
 				if t.WorkerAge != "" {
@@ -1025,6 +1497,8 @@ type trackedIssueInfo struct {
 	Assignee  string `json:"assignee,omitempty"`   // Assigned agent (e.g., gastown/polecats/goose)
 	Worker    string `json:"worker,omitempty"`     // Worker currently assigned (e.g., gastown/nux)
 	WorkerAge string `json:"worker_age,omitempty"` // How long worker has been on this issue
+	CreatedAt string `json:"created_at,omitempty"`
+	ClosedAt  string `json:"closed_at,omitempty"`
 }
 
 // getTrackedIssues queries SQLite directly to get issues tracked by a convoy.
@@ -1096,6 +1570,8 @@ func getTrackedIssues(townBeads, convoyID string) []trackedIssueInfo {
 			info.Status = details.Status
 			info.IssueType = details.IssueType
 			info.Assignee = details.Assignee
+			info.CreatedAt = details.CreatedAt
+			info.ClosedAt = details.ClosedAt
 		} else {
 			info.Title = "(external)"
 			info.Status = "unknown"
@@ -1120,6 +1596,8 @@ type issueDetails struct {
 	Status    string
 	IssueType string
 	Assignee  string
+	CreatedAt string
+	ClosedAt  string
 }
 
 // getIssueDetailsBatch fetches details for multiple issues in a single bd show call.
@@ -1155,6 +1633,8 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 		Status    string `json:"status"`
 		IssueType string `json:"issue_type"`
 		Assignee  string `json:"assignee"`
+		CreatedAt string `json:"created_at"`
+		ClosedAt  string `json:"closed_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
 		return result
@@ -1167,6 +1647,8 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 			Status:    issue.Status,
 			IssueType: issue.IssueType,
 			Assignee:  issue.Assignee,
+			CreatedAt: issue.CreatedAt,
+			ClosedAt:  issue.ClosedAt,
 		}
 	}
 
@@ -1191,6 +1673,8 @@ func getIssueDetails(issueID string) *issueDetails {
 		Status    string `json:"status"`
 		IssueType string `json:"issue_type"`
 		Assignee  string `json:"assignee"`
+		CreatedAt string `json:"created_at"`
+		ClosedAt  string `json:"closed_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil || len(issues) == 0 {
 		return nil
@@ -1202,6 +1686,8 @@ func getIssueDetails(issueID string) *issueDetails {
 		Status:    issues[0].Status,
 		IssueType: issues[0].IssueType,
 		Assignee:  issues[0].Assignee,
+		CreatedAt: issues[0].CreatedAt,
+		ClosedAt:  issues[0].ClosedAt,
 	}
 }
 
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Epic convoys.
+//
+// gt convoy create --from-epic tracks every issue under an epic,
+// recursively. gt convoy check keeps the membership in sync: children
+// added to the epic are tracked (reopening a convoy that had landed), and
+// closed children count toward landing as usual. Open issues that no rig
+// in the convoy's scope can take (by capability labels, as gt sling --auto
+// matches them) are marked out of scope: still tracked, but never
+// dispatched or reported stranded by the convoy.
+
+// epicSync records what gt convoy check changed in an epic convoy.
+type epicSync struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Added      []string `json:"added,omitempty"`
+	OutOfScope []string `json:"out_of_scope,omitempty"`
+	Reopened   bool     `json:"reopened,omitempty"`
+}
+
+// routedBeads returns the beads database an issue ID routes to by prefix,
+// falling back to town beads.
+func routedBeads(townRoot, issueID string) *beads.Beads {
+	routes, _ := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	for _, r := range routes {
+		if strings.HasPrefix(issueID, r.Prefix) {
+			return beads.New(filepath.Join(townRoot, r.Path))
+		}
+	}
+	return beads.New(townRoot)
+}
+
+// epicDescendants returns every issue under an epic, recursively. Nested
+// epics are walked but not returned: they only group work.
+func epicDescendants(b *beads.Beads, epicID string) ([]*beads.Issue, error) {
+	var issues []*beads.Issue
+	seen := map[string]bool{epicID: true}
+	queue := []string{epicID}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+
+		children, err := b.List(beads.ListOptions{Parent: parent, Status: "all", Priority: -1})
+		if err != nil {
+			return nil, fmt.Errorf("listing children of %s: %w", parent, err)
+		}
+		for _, child := range children {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			if child.Type == "epic" || len(child.Children) > 0 {
+				queue = append(queue, child.ID)
+			}
+			if child.Type != "epic" {
+				issues = append(issues, child)
+			}
+		}
+	}
+	return issues, nil
+}
+
+// convoyScopeCandidates returns the rigs a convoy's work is scoped to,
+// with their capabilities. An empty scope is every rig.
+func convoyScopeCandidates(townRoot string, scope []string) ([]slingCandidate, error) {
+	cands, err := gatherSlingCandidates(townRoot, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(scope) == 0 {
+		return cands, nil
+	}
+	var inScope []slingCandidate
+	for _, c := range cands {
+		if slices.Contains(scope, c.Rig) {
+			inScope = append(inScope, c)
+		}
+	}
+	return inScope, nil
+}
+
+// planEpicSync compares an epic's descendants with a convoy's tracked
+// issues. It returns the descendants to start tracking, and the open
+// descendants no rig in cands can take, in epic order.
+func planEpicSync(descendants []*beads.Issue, tracked map[string]bool, cands []slingCandidate) (add, outOfScope []string) {
+	for _, issue := range descendants {
+		if !tracked[issue.ID] {
+			add = append(add, issue.ID)
+		}
+		if issueLanded(issue.Status) {
+			continue
+		}
+		if !rigCanTake(requiredCapabilities(issue.Labels), cands) {
+			outOfScope = append(outOfScope, issue.ID)
+		}
+	}
+	return add, outOfScope
+}
+
+// rigCanTake reports whether any of the rigs has every required tag.
+func rigCanTake(required []string, cands []slingCandidate) bool {
+	for _, c := range cands {
+		missing := false
+		for _, tag := range required {
+			if !slices.Contains(c.Capabilities, tag) {
+				missing = true
+				break
+			}
+		}
+		if !missing {
+			return true
+		}
+	}
+	return false
+}
+
+// loadEpicMembers resolves a new epic convoy's issues: every descendant of
+// fields.Epic. It records the out-of-scope ones in fields and returns the
+// issue IDs and the epic's title.
+func loadEpicMembers(townRoot string, fields *beads.ConvoyFields) ([]string, string, error) {
+	b := routedBeads(townRoot, fields.Epic)
+	epic, err := b.Show(fields.Epic)
+	if err != nil {
+		return nil, "", fmt.Errorf("epic '%s' not found", fields.Epic)
+	}
+	if epic.Type != "epic" {
+		return nil, "", fmt.Errorf("--from-epic '%s' is not an epic (type: %s)", fields.Epic, epic.Type)
+	}
+	descendants, err := epicDescendants(b, fields.Epic)
+	if err != nil {
+		return nil, "", err
+	}
+	cands, err := convoyScopeCandidates(townRoot, fields.ScopeRigs())
+	if err != nil {
+		return nil, "", err
+	}
+
+	ids, outOfScope := planEpicSync(descendants, nil, cands)
+	fields.OutOfScope = outOfScope
+	return ids, epic.Title, nil
+}
+
+// syncEpicConvoys brings every epic convoy's membership up to date with its
+// epic: new children are tracked, and the out-of-scope list is recomputed.
+// A landed convoy whose epic gains open children is reopened; one whose
+// epic is closed too is left alone.
+func syncEpicConvoys(townBeads string) ([]epicSync, error) {
+	townRoot := filepath.Dir(townBeads)
+	b := beads.New(townBeads)
+	convoys, err := b.List(beads.ListOptions{Type: "convoy", Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
+	}
+
+	var syncs []epicSync
+	for _, convoy := range convoys {
+		fields := beads.ParseConvoyFields(convoy)
+		if fields == nil || fields.Epic == "" {
+			continue
+		}
+		epicBeads := routedBeads(townRoot, fields.Epic)
+		if issueLanded(convoy.Status) {
+			if epic, err := epicBeads.Show(fields.Epic); err != nil || issueLanded(epic.Status) {
+				continue
+			}
+		}
+
+		descendants, err := epicDescendants(epicBeads, fields.Epic)
+		if err != nil {
+			style.PrintWarning("couldn't read epic %s of convoy %s: %v", fields.Epic, convoy.ID, err)
+			continue
+		}
+		cands, err := convoyScopeCandidates(townRoot, fields.ScopeRigs())
+		if err != nil {
+			style.PrintWarning("couldn't sync convoy %s: %v", convoy.ID, err)
+			continue
+		}
+
+		tracked := make(map[string]bool)
+		for _, t := range getTrackedIssues(townBeads, convoy.ID) {
+			tracked[t.ID] = true
+		}
+		add, outOfScope := planEpicSync(descendants, tracked, cands)
+		sync := epicSync{ID: convoy.ID, Title: convoy.Title}
+
+		if len(add) > 0 && issueLanded(convoy.Status) && epicHasOpen(descendants, add) {
+			open := "open"
+			if err := b.Update(convoy.ID, beads.UpdateOptions{Status: &open}); err != nil {
+				style.PrintWarning("couldn't reopen convoy %s: %v", convoy.ID, err)
+				continue
+			}
+			sync.Reopened = true
+		}
+		for _, id := range add {
+			depCmd := exec.Command("bd", "dep", "add", convoy.ID, id, "--type=tracks")
+			depCmd.Dir = townBeads
+			if err := depCmd.Run(); err != nil {
+				style.PrintWarning("couldn't track %s in convoy %s: %v", id, convoy.ID, err)
+				continue
+			}
+			sync.Added = append(sync.Added, id)
+		}
+
+		if !slices.Equal(outOfScope, fields.OutOfScope) {
+			for _, id := range outOfScope {
+				if !slices.Contains(fields.OutOfScope, id) {
+					sync.OutOfScope = append(sync.OutOfScope, id)
+				}
+			}
+			fields.OutOfScope = outOfScope
+			description := beads.SetConvoyFields(convoy, fields)
+			if err := b.Update(convoy.ID, beads.UpdateOptions{Description: &description}); err != nil {
+				style.PrintWarning("couldn't record out-of-scope issues for %s: %v", convoy.ID, err)
+			}
+		}
+
+		if len(sync.Added) > 0 || len(sync.OutOfScope) > 0 || sync.Reopened {
+			syncs = append(syncs, sync)
+		}
+	}
+	return syncs, nil
+}
+
+// epicHasOpen reports whether any of the given descendants is still open.
+func epicHasOpen(descendants []*beads.Issue, ids []string) bool {
+	for _, issue := range descendants {
+		if slices.Contains(ids, issue.ID) && !issueLanded(issue.Status) {
+			return true
+		}
+	}
+	return false
+}
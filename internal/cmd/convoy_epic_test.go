@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestPlanEpicSync(t *testing.T) {
+	descendants := []*beads.Issue{
+		{ID: "gt-a", Status: "closed", Labels: []string{"lang:rust"}},
+		{ID: "gt-b", Status: "open", Labels: []string{"lang:go"}},
+		{ID: "gt-c", Status: "open", Labels: []string{"lang:rust", "triage"}},
+		{ID: "gt-d", Status: "in_progress"},
+	}
+	cands := []slingCandidate{
+		{Rig: "gastown", Capabilities: []string{"lang:go"}},
+		{Rig: "beads", Capabilities: []string{"lang:go", "area:cli"}},
+	}
+
+	add, outOfScope := planEpicSync(descendants, map[string]bool{"gt-a": true, "gt-b": true}, cands)
+	if want := []string{"gt-c", "gt-d"}; !reflect.DeepEqual(add, want) {
+		t.Errorf("add = %v, want %v", add, want)
+	}
+	// Closed issues are never out of scope; untagged issues fit any rig
+	if want := []string{"gt-c"}; !reflect.DeepEqual(outOfScope, want) {
+		t.Errorf("outOfScope = %v, want %v", outOfScope, want)
+	}
+
+	if _, outOfScope := planEpicSync(descendants, nil, nil); !reflect.DeepEqual(outOfScope, []string{"gt-b", "gt-c", "gt-d"}) {
+		t.Errorf("with no rigs in scope, outOfScope = %v, want every open issue", outOfScope)
+	}
+}
+
+func TestRigCanTake(t *testing.T) {
+	cands := []slingCandidate{
+		{Rig: "gastown", Capabilities: []string{"lang:go"}},
+		{Rig: "beads", Capabilities: []string{"area:cli"}},
+	}
+	tests := []struct {
+		required []string
+		want     bool
+	}{
+		{nil, true},
+		{[]string{"lang:go"}, true},
+		{[]string{"area:cli"}, true},
+		{[]string{"lang:go", "area:cli"}, false}, // No single rig has both
+		{[]string{"lang:rust"}, false},
+	}
+	for _, tt := range tests {
+		if got := rigCanTake(tt.required, cands); got != tt.want {
+			t.Errorf("rigCanTake(%v) = %v, want %v", tt.required, got, tt.want)
+		}
+	}
+}
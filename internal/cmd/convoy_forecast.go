@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/forecast"
+	"github.com/steveyegge/gastown/internal/mrqueue"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// convoyHistoryWindow is how far back closed issues feed the forecast.
+const convoyHistoryWindow = 30 * 24 * time.Hour
+
+// convoyBurndownPoints is the length of the burndown series.
+const convoyBurndownPoints = 24
+
+// convoyDueLayout is the format of convoy due dates.
+const convoyDueLayout = "2006-01-02"
+
+// forecastIssueTypes are the issue types whose close times predict
+// convoy work. Convoys, epics, molecules and agent beads are left out.
+var forecastIssueTypes = map[string]bool{
+	"task": true, "bug": true, "feature": true, "chore": true,
+}
+
+// convoyForecastInfo is a convoy's burndown and projected landing.
+type convoyForecastInfo struct {
+	Burndown []forecast.Point   `json:"burndown"`
+	Forecast *forecast.Forecast `json:"forecast,omitempty"`
+	Due      string             `json:"due,omitempty"`
+	Slipping bool               `json:"slipping,omitempty"`
+}
+
+// parseBeadTime parses a bead timestamp. Returns the zero time if unset.
+func parseBeadTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	if t, err := time.Parse("2006-01-02 15:04", s); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// parseConvoyDue parses a YYYY-MM-DD due date as the end of that day.
+func parseConvoyDue(s string) (time.Time, error) {
+	day, err := time.ParseInLocation(convoyDueLayout, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid due date %q (want YYYY-MM-DD)", s)
+	}
+	return day.Add(24*time.Hour - time.Second), nil
+}
+
+// convoyCloseHistory returns how long recently closed issues took to
+// close, across every rig. The merge queue's merged event, when there is
+// one, marks when the issue landed.
+func convoyCloseHistory(townRoot string, now time.Time) []time.Duration {
+	routes, _ := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+
+	var issues []*beads.Issue
+	merged := make(map[string]time.Time)
+	seen := make(map[string]bool)
+	for _, r := range routes {
+		rigName := strings.Split(filepath.ToSlash(r.Path), "/")[0]
+		if r.Path == "" || r.Path == "." || seen[r.Path] {
+			continue
+		}
+		seen[r.Path] = true
+
+		closed, err := beads.New(filepath.Join(townRoot, r.Path)).List(beads.ListOptions{Status: "closed", Priority: -1})
+		if err == nil {
+			issues = append(issues, closed...)
+		}
+		events, _ := mrqueue.NewEventLoggerFromRig(filepath.Join(townRoot, rigName)).ReadEvents()
+		for _, e := range events {
+			if e.Type == mrqueue.EventMerged && e.SourceIssue != "" {
+				merged[e.SourceIssue] = e.Timestamp
+			}
+		}
+	}
+	return closeDurations(issues, merged, now.Add(-convoyHistoryWindow))
+}
+
+// closeDurations returns open-to-land durations of work issues that landed
+// since the given time. merged maps issue IDs to their merge time.
+func closeDurations(issues []*beads.Issue, merged map[string]time.Time, since time.Time) []time.Duration {
+	var durations []time.Duration
+	for _, issue := range issues {
+		if !forecastIssueTypes[issue.Type] {
+			continue
+		}
+		opened := parseBeadTime(issue.CreatedAt)
+		landed := parseBeadTime(issue.ClosedAt)
+		if t, ok := merged[issue.ID]; ok {
+			landed = t
+		}
+		if opened.IsZero() || landed.IsZero() || landed.Before(since) || !landed.After(opened) {
+			continue
+		}
+		durations = append(durations, landed.Sub(opened))
+	}
+	return durations
+}
+
+// forecastConvoy builds a convoy's burndown since it was created and, if
+// work remains, its projected landing with the workers on it now.
+func forecastConvoy(createdAt string, tracked []trackedIssueInfo, history []time.Duration, now time.Time) *convoyForecastInfo {
+	start := parseBeadTime(createdAt)
+	if start.IsZero() || start.After(now) {
+		start = now
+	}
+
+	var spans []forecast.Span
+	remaining := 0
+	workers := make(map[string]bool)
+	for _, t := range tracked {
+		span := forecast.Span{Opened: parseBeadTime(t.CreatedAt)}
+		if issueLanded(t.Status) {
+			span.Closed = parseBeadTime(t.ClosedAt)
+			if span.Closed.IsZero() {
+				span.Closed = start // Closed, time unknown
+			}
+		} else {
+			remaining++
+			if t.Worker != "" {
+				workers[t.Worker] = true
+			}
+		}
+		spans = append(spans, span)
+	}
+
+	info := &convoyForecastInfo{Burndown: forecast.Burndown(spans, start, now, convoyBurndownPoints)}
+	if remaining > 0 {
+		info.Forecast, _ = forecast.New(history, remaining, len(workers), now)
+	}
+	return info
+}
+
+// checkDue fills in the due date and whether the forecast misses it.
+func (info *convoyForecastInfo) checkDue(due string) {
+	info.Due = due
+	if due == "" || info.Forecast == nil {
+		return
+	}
+	if dueAt, err := parseConvoyDue(due); err == nil {
+		info.Slipping = info.Forecast.Slips(dueAt)
+	}
+}
+
+// formatETADay formats a projected landing time for humans.
+func formatETADay(t, now time.Time) string {
+	if t.Year() != now.Year() {
+		return t.Format("Jan 2 2006")
+	}
+	if t.Sub(now) < 24*time.Hour {
+		return t.Format("Jan 2 15:04")
+	}
+	return t.Format("Jan 2")
+}
+
+// printConvoyForecast prints the burndown, ETA and due date lines of gt
+// convoy status.
+func printConvoyForecast(info *convoyForecastInfo, history int, now time.Time) {
+	if len(info.Burndown) > 1 {
+		first, last := info.Burndown[0], info.Burndown[len(info.Burndown)-1]
+		fmt.Printf("  Burndown:  %s  %s\n", forecast.Sparkline(info.Burndown),
+			style.Dim.Render(fmt.Sprintf("%d → %d open over %s", first.Remaining, last.Remaining, formatDuration(last.Time.Sub(first.Time)))))
+	}
+
+	f := info.Forecast
+	switch {
+	case f != nil:
+		workers := fmt.Sprintf("%d worker(s)", f.Workers)
+		if f.AssumedWorkers {
+			workers = "assuming 1 worker (none active)"
+		}
+		fmt.Printf("  ETA:       %s – %s (likely %s)  %s\n",
+			formatETADay(f.Early, now), formatETADay(f.Late, now), formatETADay(f.Likely, now),
+			style.Dim.Render(fmt.Sprintf("%d open, %s, %d closes in the last %d days", f.Remaining, workers, f.Samples, int(convoyHistoryWindow.Hours()/24))))
+	case len(info.Burndown) > 0 && info.Burndown[len(info.Burndown)-1].Remaining > 0:
+		fmt.Printf("  ETA:       %s\n", style.Dim.Render(fmt.Sprintf("unknown (%d closes in the last %d days; need %d)", history, int(convoyHistoryWindow.Hours()/24), forecast.MinSamples)))
+	}
+
+	if info.Due != "" {
+		line := fmt.Sprintf("  Due:       %s", info.Due)
+		if info.Slipping {
+			line += "  " + style.Warning.Render("⚠ projected to slip (likely "+formatETADay(f.Likely, now)+")")
+		}
+		fmt.Println(line)
+	}
+}
+
+// convoySlip is a convoy whose projected landing moved past its due date.
+type convoySlip struct {
+	ID     string
+	Title  string
+	Due    string
+	Likely time.Time
+}
+
+// checkConvoySlips alerts on open convoys whose likely landing has slipped
+// past their due date. Each slip is alerted once, and again only if the
+// forecast slips further; a convoy back on track is re-armed.
+func checkConvoySlips(townBeads string, now time.Time) ([]convoySlip, error) {
+	b := beads.New(townBeads)
+	convoys, err := b.List(beads.ListOptions{Type: "convoy", Status: "open", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
+	}
+
+	var history []time.Duration
+	historyLoaded := false
+	var slips []convoySlip
+	for _, convoy := range convoys {
+		fields := beads.ParseConvoyFields(convoy)
+		if fields == nil || fields.Due == "" {
+			continue
+		}
+		if !historyLoaded {
+			history = convoyCloseHistory(filepath.Dir(townBeads), now)
+			historyLoaded = true
+		}
+
+		info := forecastConvoy(convoy.CreatedAt, getTrackedIssues(townBeads, convoy.ID), history, now)
+		info.checkDue(fields.Due)
+
+		alerted := fields.SlipAlerted
+		switch {
+		case info.Slipping:
+			likelyDay := info.Forecast.Likely.Format(convoyDueLayout)
+			if alerted != "" && likelyDay <= alerted {
+				continue // Already alerted at this slip or worse
+			}
+			fields.SlipAlerted = likelyDay
+			slip := convoySlip{ID: convoy.ID, Title: convoy.Title, Due: fields.Due, Likely: info.Forecast.Likely}
+			notifyConvoySlip(convoy, slip, info.Forecast)
+			slips = append(slips, slip)
+		case alerted != "" && info.Forecast != nil:
+			fields.SlipAlerted = "" // Back on track
+		default:
+			continue
+		}
+
+		description := beads.SetConvoyFields(convoy, fields)
+		if err := b.Update(convoy.ID, beads.UpdateOptions{Description: &description}); err != nil {
+			style.PrintWarning("couldn't record slip alert for %s: %v", convoy.ID, err)
+		}
+	}
+	return slips, nil
+}
+
+// notifyConvoySlip mails the convoy's notify address (or the mayor) that
+// it is projected to miss its due date.
+func notifyConvoySlip(convoy *beads.Issue, slip convoySlip, f *forecast.Forecast) {
+	addr := convoyNotifyAddress(convoy.Description)
+	if addr == "" {
+		addr = "mayor/"
+	}
+	body := fmt.Sprintf(`Convoy %s is projected to land after its due date.
+
+Due:        %s
+Likely:     %s
+Range:      %s – %s
+Remaining:  %d open issue(s), %d worker(s)
+
+Add workers, cut scope, or move the date with:
+  gt convoy due %s <YYYY-MM-DD>`,
+		slip.ID, slip.Due,
+		f.Likely.Format(convoyDueLayout), f.Early.Format(convoyDueLayout), f.Late.Format(convoyDueLayout),
+		f.Remaining, f.Workers, slip.ID)
+	mailCmd := exec.Command("gt", "mail", "send", addr,
+		"-s", fmt.Sprintf("⚠ Convoy slipping: %s", slip.Title),
+		"-m", body)
+	_ = mailCmd.Run() // Best effort, like landing notifications
+}
+
+// convoyNotifyAddress returns the Notify address from a convoy description.
+func convoyNotifyAddress(description string) string {
+	for _, line := range strings.Split(description, "\n") {
+		if strings.HasPrefix(line, "Notify: ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "Notify: "))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestCloseDurations(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ts := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+
+	issues := []*beads.Issue{
+		{ID: "gt-a", Type: "task", CreatedAt: ts(-10 * time.Hour), ClosedAt: ts(-6 * time.Hour)},
+		{ID: "gt-b", Type: "bug", CreatedAt: ts(-5 * time.Hour), ClosedAt: ts(-1 * time.Hour)},
+		{ID: "gt-old", Type: "task", CreatedAt: ts(-60 * 24 * time.Hour), ClosedAt: ts(-50 * 24 * time.Hour)},
+		{ID: "hq-cv-x", Type: "convoy", CreatedAt: ts(-10 * time.Hour), ClosedAt: ts(-1 * time.Hour)},
+		{ID: "gt-noclose", Type: "task", CreatedAt: ts(-3 * time.Hour)},
+	}
+	merged := map[string]time.Time{"gt-b": now.Add(-3 * time.Hour)}
+
+	got := closeDurations(issues, merged, now.Add(-convoyHistoryWindow))
+	want := []time.Duration{4 * time.Hour, 2 * time.Hour}
+	if len(got) != len(want) {
+		t.Fatalf("closeDurations = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("closeDurations[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestForecastConvoy(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ts := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+
+	tracked := []trackedIssueInfo{
+		{ID: "gt-a", Status: "closed", CreatedAt: ts(-48 * time.Hour), ClosedAt: ts(-24 * time.Hour)},
+		{ID: "gt-b", Status: "in_progress", CreatedAt: ts(-48 * time.Hour), Worker: "gastown/nux"},
+		{ID: "gt-c", Status: "open", CreatedAt: ts(-48 * time.Hour)},
+	}
+	history := []time.Duration{2 * time.Hour, 4 * time.Hour, 6 * time.Hour}
+
+	info := forecastConvoy(ts(-48*time.Hour), tracked, history, now)
+	if len(info.Burndown) != convoyBurndownPoints {
+		t.Fatalf("burndown has %d points, want %d", len(info.Burndown), convoyBurndownPoints)
+	}
+	if first, last := info.Burndown[0].Remaining, info.Burndown[len(info.Burndown)-1].Remaining; first != 3 || last != 2 {
+		t.Errorf("burndown runs %d → %d, want 3 → 2", first, last)
+	}
+	f := info.Forecast
+	if f == nil {
+		t.Fatal("no forecast with enough history")
+	}
+	// Two open issues, one worker: two waves of the 4h median
+	if f.Remaining != 2 || f.Workers != 1 || !f.Likely.Equal(now.Add(8*time.Hour)) {
+		t.Errorf("forecast = %+v, want 2 remaining, 1 worker, likely in 8h", f)
+	}
+
+	info.checkDue(now.Format(convoyDueLayout))
+	if info.Slipping {
+		t.Error("landing later today should not slip a due date of today")
+	}
+	info.checkDue(now.Add(-24 * time.Hour).Format(convoyDueLayout))
+	if !info.Slipping {
+		t.Error("landing after the due date should slip")
+	}
+
+	if done := forecastConvoy(ts(-48*time.Hour), tracked[:1], history, now); done.Forecast != nil {
+		t.Error("a landed convoy should have no forecast")
+	}
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Convoy dependencies and stages.
+//
+// A convoy can need other convoys (gt convoy create --after): it dispatches
+// nothing until they land. Its tracked issues can be split into ordered
+// stages (--stage): stage N+1 is slung once every issue in stage N is
+// closed. gt convoy check releases stages and slings them; gt convoy
+// stranded only reports issues in released stages.
+
+// convoyRelease records a stage released by gt convoy check.
+type convoyRelease struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Stage   int      `json:"stage"`
+	Stages  int      `json:"stages"`
+	Slung   []string `json:"slung,omitempty"`
+	Skipped []string `json:"skipped,omitempty"` // already assigned, not open or out of scope
+}
+
+// nextConvoyStage returns the stage to release next, or 0 if the convoy
+// is still waiting. needsLanded reports whether every convoy it needs has
+// closed; status maps tracked issue IDs to their status.
+func nextConvoyStage(fields *beads.ConvoyFields, needsLanded bool, status map[string]string) int {
+	if fields.Released == 0 {
+		if needsLanded {
+			return 1
+		}
+		return 0
+	}
+	if fields.Released >= fields.StageCount() {
+		return 0
+	}
+	for id, s := range status {
+		if fields.StageOf(id) <= fields.Released && !issueLanded(s) {
+			return 0
+		}
+	}
+	return fields.Released + 1
+}
+
+// issueLanded reports whether a tracked issue's status counts as done.
+func issueLanded(status string) bool {
+	return status == "closed" || status == "tombstone"
+}
+
+// convoyNeedsLanded reports whether every convoy in needs has closed, and
+// lists the ones still open.
+func convoyNeedsLanded(b *beads.Beads, needs []string) (bool, []string) {
+	var waiting []string
+	for _, id := range needs {
+		issue, err := b.Show(id)
+		if err != nil || !issueLanded(issue.Status) {
+			waiting = append(waiting, id)
+		}
+	}
+	return len(waiting) == 0, waiting
+}
+
+// convoyStageStatus maps a convoy's tracked issues to their status.
+func convoyStageStatus(tracked []trackedIssueInfo) map[string]string {
+	status := make(map[string]string, len(tracked))
+	for _, t := range tracked {
+		status[t.ID] = t.Status
+	}
+	return status
+}
+
+// advanceConvoyStages releases the next stage of every open convoy whose
+// needs have landed and whose current stage is done, slinging the issues
+// in each released stage. Stages are released one at a time per convoy,
+// repeatedly, so empty or already-finished stages are passed through.
+func advanceConvoyStages(townBeads string) ([]convoyRelease, error) {
+	b := beads.New(townBeads)
+	convoys, err := b.List(beads.ListOptions{Type: "convoy", Status: "open", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
+	}
+
+	var releases []convoyRelease
+	for _, convoy := range convoys {
+		fields := beads.ParseConvoyFields(convoy)
+		if !fields.Staged() {
+			continue
+		}
+		needsLanded, _ := convoyNeedsLanded(b, fields.Needs)
+		tracked := getTrackedIssues(townBeads, convoy.ID)
+		status := convoyStageStatus(tracked)
+
+		changed := false
+		for {
+			stage := nextConvoyStage(fields, needsLanded, status)
+			if stage == 0 {
+				break
+			}
+			fields.Released = stage
+			changed = true
+
+			release := convoyRelease{ID: convoy.ID, Title: convoy.Title, Stage: stage, Stages: fields.StageCount()}
+			for _, t := range tracked {
+				if fields.StageOf(t.ID) != stage {
+					continue
+				}
+				if t.Status != "open" || t.Assignee != "" || !fields.InScope(t.ID) {
+					release.Skipped = append(release.Skipped, t.ID)
+					continue
+				}
+				if err := slingConvoyIssue(filepath.Dir(townBeads), t.ID, fields.Target); err != nil {
+					style.PrintWarning("couldn't sling %s for convoy %s: %v", t.ID, convoy.ID, err)
+					release.Skipped = append(release.Skipped, t.ID)
+					continue
+				}
+				release.Slung = append(release.Slung, t.ID)
+			}
+			releases = append(releases, release)
+		}
+
+		if changed {
+			description := beads.SetConvoyFields(convoy, fields)
+			if err := b.Update(convoy.ID, beads.UpdateOptions{Description: &description}); err != nil {
+				style.PrintWarning("couldn't record released stage for %s: %v", convoy.ID, err)
+			}
+		}
+	}
+	return releases, nil
+}
+
+// slingConvoyIssue slings a released issue to the convoy's target rig, or
+// routes it with --auto. Full rigs queue it in the dispatch queue.
+func slingConvoyIssue(townRoot, issueID, target string) error {
+	args := []string{"sling", issueID}
+	if target != "" {
+		args = append(args, target)
+	} else {
+		args = append(args, "--auto")
+	}
+	// The convoy already tracks the issue
+	args = append(args, "--no-convoy")
+
+	c := exec.Command("gt", args...)
+	c.Dir = townRoot
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", lastOutputLine(out, err))
+	}
+	return nil
+}
+
+// formatConvoyStage describes where a staged convoy is, e.g. "stage 2/3"
+// or "waiting on hq-cv-abc".
+func formatConvoyStage(fields *beads.ConvoyFields, waiting []string) string {
+	if fields.Released == 0 {
+		if len(waiting) > 0 {
+			return "waiting on " + strings.Join(waiting, ", ")
+		}
+		return "waiting to start"
+	}
+	return fmt.Sprintf("stage %d/%d", fields.Released, fields.StageCount())
+}
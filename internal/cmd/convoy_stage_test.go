@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestNextConvoyStage(t *testing.T) {
+	stages := [][]string{{"gt-a", "gt-b"}, {"gt-c"}, {"gt-d"}}
+	tests := []struct {
+		name        string
+		released    int
+		needsLanded bool
+		status      map[string]string
+		want        int
+	}{
+		{
+			name:   "waiting on needs",
+			status: map[string]string{"gt-a": "open"},
+			want:   0,
+		},
+		{
+			name:        "needs landed releases stage 1",
+			needsLanded: true,
+			status:      map[string]string{"gt-a": "open"},
+			want:        1,
+		},
+		{
+			name:        "stage 1 still open",
+			released:    1,
+			needsLanded: true,
+			status:      map[string]string{"gt-a": "closed", "gt-b": "in_progress", "gt-c": "open"},
+			want:        0,
+		},
+		{
+			name:        "stage 1 closed releases stage 2",
+			released:    1,
+			needsLanded: true,
+			status:      map[string]string{"gt-a": "closed", "gt-b": "tombstone", "gt-c": "open"},
+			want:        2,
+		},
+		{
+			name:        "unstaged issues count as stage 1",
+			released:    1,
+			needsLanded: true,
+			status:      map[string]string{"gt-a": "closed", "gt-b": "closed", "gt-extra": "open"},
+			want:        0,
+		},
+		{
+			name:        "last stage released",
+			released:    3,
+			needsLanded: true,
+			status:      map[string]string{"gt-a": "closed", "gt-b": "closed", "gt-c": "closed", "gt-d": "open"},
+			want:        0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := &beads.ConvoyFields{Stages: stages, Released: tt.released}
+			if got := nextConvoyStage(fields, tt.needsLanded, tt.status); got != tt.want {
+				t.Errorf("nextConvoyStage = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
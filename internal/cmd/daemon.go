@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -25,7 +26,10 @@ The daemon is a simple Go process that:
 - Processes lifecycle requests (cycle, restart, shutdown)
 - Restarts sessions when agents request cycling
 
-The daemon is a "dumb scheduler" - all intelligence is in agents.`,
+The daemon is a "dumb scheduler" - all intelligence is in agents.
+
+Set daemon.metrics_addr in settings/config.json (e.g. "127.0.0.1:9464")
+to expose town health on /metrics for Prometheus.`,
 }
 
 var daemonStartCmd = &cobra.Command{
@@ -33,7 +37,12 @@ var daemonStartCmd = &cobra.Command{
 	Short: "Start the daemon",
 	Long: `Start the Gas Town daemon in the background.
 
-The daemon will run until stopped with 'gt daemon stop'.`,
+The daemon will run until stopped with 'gt daemon stop'.
+
+With --foreground the daemon runs in the current process for a process
+supervisor such as systemd: logs go to stdout (JSON by default), readiness
+and watchdog pings are sent via sd_notify, SIGTERM shuts down and SIGHUP
+reloads configuration. See 'gt daemon install-unit'.`,
 	RunE: runDaemonStart,
 }
 
@@ -47,8 +56,12 @@ var daemonStopCmd = &cobra.Command{
 var daemonStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show daemon status",
-	Long:  `Show the current status of the Gas Town daemon.`,
-	RunE:  runDaemonStatus,
+	Long: `Show the current status of the Gas Town daemon.
+
+When the daemon is reachable on its control socket, status is answered
+live (heartbeat schedule, paused rigs). Otherwise it falls back to the
+state file written by the daemon.`,
+	RunE: runDaemonStatus,
 }
 
 var daemonLogsCmd = &cobra.Command{
@@ -68,6 +81,9 @@ var daemonRunCmd = &cobra.Command{
 var (
 	daemonLogLines int
 	daemonLogFollow bool
+	daemonStatusJSON bool
+	daemonForeground bool
+	daemonLogFormat string
 )
 
 func init() {
@@ -77,8 +93,11 @@ func init() {
 	daemonCmd.AddCommand(daemonLogsCmd)
 	daemonCmd.AddCommand(daemonRunCmd)
 
+	daemonStartCmd.Flags().BoolVar(&daemonForeground, "foreground", false, "Run in the foreground under a process supervisor")
+	daemonStartCmd.Flags().StringVar(&daemonLogFormat, "log-format", daemon.LogFormatJSON, "Foreground log format: json or text")
 	daemonLogsCmd.Flags().IntVarP(&daemonLogLines, "lines", "n", 50, "Number of lines to show")
 	daemonLogsCmd.Flags().BoolVarP(&daemonLogFollow, "follow", "f", false, "Follow log output")
+	daemonStatusCmd.Flags().BoolVar(&daemonStatusJSON, "json", false, "Output as JSON")
 
 	rootCmd.AddCommand(daemonCmd)
 }
@@ -98,6 +117,10 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("daemon already running (PID %d)", pid)
 	}
 
+	if daemonForeground {
+		return runDaemonForeground(townRoot)
+	}
+
 	// Start daemon in background
 	// We use 'gt daemon run' as the actual daemon process
 	gtPath, err := os.Executable()
@@ -170,11 +193,30 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// Prefer a live answer from the control socket
+	live, err := daemon.NewClient(townRoot).Status()
+	if err == nil {
+		return printLiveDaemonStatus(live)
+	}
+	if !errors.Is(err, daemon.ErrNotRunning) {
+		fmt.Printf("%s control socket: %v (showing state file)\n", style.Dim.Render("⚠"), err)
+	}
+
 	running, pid, err := daemon.IsRunning(townRoot)
 	if err != nil {
 		return fmt.Errorf("checking daemon status: %w", err)
 	}
 
+	if daemonStatusJSON {
+		state, _ := daemon.LoadState(townRoot)
+		return printDaemonJSON(map[string]interface{}{
+			"running": running,
+			"pid":     pid,
+			"state":   state,
+			"live":    false,
+		})
+	}
+
 	if running {
 		fmt.Printf("%s Daemon is %s (PID %d)\n",
 			style.Bold.Render("●"),
@@ -233,7 +275,8 @@ func runDaemonLogs(cmd *cobra.Command, args []string) error {
 	logFile := filepath.Join(townRoot, "daemon", "daemon.log")
 
 	if _, err := os.Stat(logFile); os.IsNotExist(err) {
-		return fmt.Errorf("no log file found at %s", logFile)
+		return fmt.Errorf("no log file found at %s (a foreground daemon logs to stdout; under systemd use 'journalctl --user -u %s')",
+			logFile, daemonUnitName(townRoot))
 	}
 
 	if daemonLogFollow {
@@ -265,3 +308,20 @@ func runDaemonRun(cmd *cobra.Command, args []string) error {
 
 	return d.Run()
 }
+
+// runDaemonForeground runs the daemon in this process for a supervisor.
+func runDaemonForeground(townRoot string) error {
+	if daemonLogFormat != daemon.LogFormatJSON && daemonLogFormat != daemon.LogFormatText {
+		return fmt.Errorf("invalid --log-format %q: must be json or text", daemonLogFormat)
+	}
+
+	config := daemon.DefaultConfig(townRoot)
+	config.Foreground = true
+	config.LogFormat = daemonLogFormat
+	d, err := daemon.New(config)
+	if err != nil {
+		return fmt.Errorf("creating daemon: %w", err)
+	}
+
+	return d.Run()
+}
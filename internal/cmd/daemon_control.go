@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var daemonHeartbeatCmd = &cobra.Command{
+	Use:   "heartbeat",
+	Short: "Force an immediate daemon heartbeat",
+	Long: `Ask the running daemon to run a heartbeat now instead of waiting for
+the next recovery interval. Waits for the heartbeat to finish.`,
+	RunE: runDaemonHeartbeat,
+}
+
+var daemonReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload daemon configuration",
+	Long: `Ask the running daemon to re-read its configuration now.
+
+The daemon already watches settings/config.json, config/messaging.json,
+mayor/rigs.json and each rig's settings/config.json, and applies edits
+within a few seconds (daemon.config_poll_interval in town settings). A
+daemon block in mayor/config.json is still honored, with a warning, when
+town settings have none. Files that
+fail validation are rejected and the daemon keeps their last good version.`,
+	RunE: runDaemonReload,
+}
+
+var daemonPauseCmd = &cobra.Command{
+	Use:   "pause <rig>",
+	Short: "Pause daemon supervision of a rig",
+	Long: `Stop the daemon restarting the witness, refinery and polecats of a rig.
+
+Useful while doing maintenance on a rig by hand. The pause survives daemon
+restarts; undo it with 'gt daemon resume <rig>'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDaemonPause,
+}
+
+var daemonResumeCmd = &cobra.Command{
+	Use:   "resume <rig>",
+	Short: "Resume daemon supervision of a rig",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDaemonResume,
+}
+
+var daemonSessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "List sessions managed by the daemon",
+	Long:  `List the Gas Town tmux sessions the daemon currently sees, with their roles.`,
+	RunE:  runDaemonSessions,
+}
+
+var daemonHealthStateCmd = &cobra.Command{
+	Use:   "health-state",
+	Short: "Show restart backoff and crash-loop state",
+	Long: `Show the daemon's restart bookkeeping for the agents it supervises.
+
+Each automatic restart of a deacon, witness, refinery or polecat session
+doubles the wait before the next one (1m, 2m, 4m ... up to 30m). An agent
+restarted 5 times within an hour is in a crash loop: the daemon stops
+restarting it and escalates to the overseer. Restarts older than an hour
+are forgotten.
+
+Use --clear <session> once the cause is fixed to lift the backoff or crash
+loop so the next heartbeat restarts the agent.
+
+When the daemon isn't running, the last saved state is shown.`,
+	RunE: runDaemonHealthState,
+}
+
+var (
+	daemonPauseReason      string
+	daemonSessionsJSON     bool
+	daemonHealthStateJSON  bool
+	daemonHealthStateClear string
+)
+
+func init() {
+	daemonCmd.AddCommand(daemonHeartbeatCmd)
+	daemonCmd.AddCommand(daemonReloadCmd)
+	daemonCmd.AddCommand(daemonPauseCmd)
+	daemonCmd.AddCommand(daemonResumeCmd)
+	daemonCmd.AddCommand(daemonSessionsCmd)
+	daemonCmd.AddCommand(daemonHealthStateCmd)
+
+	daemonPauseCmd.Flags().StringVarP(&daemonPauseReason, "reason", "r", "", "Why supervision is paused")
+	daemonSessionsCmd.Flags().BoolVar(&daemonSessionsJSON, "json", false, "Output as JSON")
+	daemonHealthStateCmd.Flags().BoolVar(&daemonHealthStateJSON, "json", false, "Output as JSON")
+	daemonHealthStateCmd.Flags().StringVar(&daemonHealthStateClear, "clear", "", "Clear restart history for a session")
+}
+
+// daemonClient returns a control client for the current town.
+func daemonClient() (*daemon.Client, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return daemon.NewClient(townRoot), nil
+}
+
+// daemonCallError adds a start hint when the daemon isn't running.
+func daemonCallError(err error) error {
+	if errors.Is(err, daemon.ErrNotRunning) {
+		return fmt.Errorf("%w (start with 'gt daemon start')", err)
+	}
+	return err
+}
+
+func runDaemonHeartbeat(cmd *cobra.Command, args []string) error {
+	client, err := daemonClient()
+	if err != nil {
+		return err
+	}
+	res, err := client.Heartbeat()
+	if err != nil {
+		return daemonCallError(err)
+	}
+	fmt.Printf("%s Heartbeat #%d complete (%s)\n", style.Bold.Render("✓"), res.HeartbeatCount, formatDuration(res.Duration))
+	return nil
+}
+
+func runDaemonReload(cmd *cobra.Command, args []string) error {
+	client, err := daemonClient()
+	if err != nil {
+		return err
+	}
+	res, err := client.Reload()
+	if err != nil {
+		return daemonCallError(err)
+	}
+	fmt.Printf("%s Configuration reloaded (%d files, %d rigs)\n", style.Bold.Render("✓"), len(res.Files), len(res.Rigs))
+	for _, c := range res.Changes {
+		fmt.Printf("  %s\n", c)
+	}
+	if len(res.Changes) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("no changes"))
+	}
+	for _, r := range res.Rejected {
+		fmt.Printf("  %s %s: %s\n", style.Bold.Render("✗"), r.Path, r.Error)
+	}
+	if len(res.Rejected) > 0 {
+		return fmt.Errorf("%d config file(s) rejected; daemon kept the last good version", len(res.Rejected))
+	}
+	return nil
+}
+
+func runDaemonPause(cmd *cobra.Command, args []string) error {
+	client, err := daemonClient()
+	if err != nil {
+		return err
+	}
+	if _, err := client.PauseRig(args[0], daemonPauseReason); err != nil {
+		return daemonCallError(err)
+	}
+	fmt.Printf("%s Supervision paused for %s\n", style.Bold.Render("⏸"), style.Bold.Render(args[0]))
+	fmt.Printf("  Resume with: %s\n", style.Dim.Render("gt daemon resume "+args[0]))
+	return nil
+}
+
+func runDaemonResume(cmd *cobra.Command, args []string) error {
+	client, err := daemonClient()
+	if err != nil {
+		return err
+	}
+	if _, err := client.ResumeRig(args[0]); err != nil {
+		return daemonCallError(err)
+	}
+	fmt.Printf("%s Supervision resumed for %s\n", style.Bold.Render("✓"), style.Bold.Render(args[0]))
+	return nil
+}
+
+func runDaemonSessions(cmd *cobra.Command, args []string) error {
+	client, err := daemonClient()
+	if err != nil {
+		return err
+	}
+	sessions, err := client.Sessions()
+	if err != nil {
+		return daemonCallError(err)
+	}
+
+	if daemonSessionsJSON {
+		return printDaemonJSON(sessions)
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No Gas Town sessions running")
+		return nil
+	}
+	for _, s := range sessions {
+		marker := "○"
+		if s.Attached {
+			marker = "●"
+		}
+		line := fmt.Sprintf("%s %-28s %-9s %s", marker, s.Name, s.Role, s.Address)
+		if s.Paused {
+			line += " " + style.Dim.Render("(rig paused)")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runDaemonHealthState(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	client := daemon.NewClient(townRoot)
+
+	if daemonHealthStateClear != "" {
+		if _, err := client.ClearBackoff(daemonHealthStateClear); err != nil {
+			return daemonCallError(err)
+		}
+		fmt.Printf("%s Restart history cleared for %s\n", style.Bold.Render("✓"), style.Bold.Render(daemonHealthStateClear))
+		return nil
+	}
+
+	live := true
+	hs, err := client.HealthState()
+	if errors.Is(err, daemon.ErrNotRunning) {
+		live = false
+		hs, err = daemon.LoadHealthState(townRoot)
+	}
+	if err != nil {
+		return err
+	}
+
+	if daemonHealthStateJSON {
+		return printDaemonJSON(hs)
+	}
+
+	if !live {
+		fmt.Printf("%s\n", style.Dim.Render("Daemon not running; showing last saved state"))
+	}
+	if len(hs.Agents) == 0 {
+		fmt.Println("No recent automatic restarts")
+		return nil
+	}
+	for _, h := range hs.Agents {
+		var status string
+		switch {
+		case h.CrashLoop:
+			status = style.Bold.Render("✗ crash loop") + " since " + h.CrashLoopSince.Format("15:04:05")
+		case hs.At.Before(h.NextAllowed):
+			status = "⏳ backoff " + formatDuration(h.NextAllowed.Sub(hs.At).Round(time.Second))
+		default:
+			status = "✓ restart allowed"
+		}
+		fmt.Printf("%-28s %-9s %d restart(s)  %s\n", h.Session, h.Role, len(h.Restarts), status)
+		if h.LastError != "" {
+			fmt.Printf("  %s\n", style.Dim.Render("last error: "+h.LastError))
+		}
+		if h.CrashLoop {
+			fmt.Printf("  %s\n", style.Dim.Render("clear with: gt daemon health-state --clear "+h.Session))
+		}
+	}
+	return nil
+}
+
+// printLiveDaemonStatus prints status answered over the control socket.
+func printLiveDaemonStatus(s *daemon.Status) error {
+	if daemonStatusJSON {
+		return printDaemonJSON(map[string]interface{}{
+			"running": true,
+			"pid":     s.PID,
+			"status":  s,
+			"live":    true,
+		})
+	}
+
+	fmt.Printf("%s Daemon is %s (PID %d)\n",
+		style.Bold.Render("●"),
+		style.Bold.Render("running"),
+		s.PID)
+	fmt.Printf("  Started: %s\n", s.StartedAt.Format("2006-01-02 15:04:05"))
+	switch {
+	case s.InHeartbeat:
+		fmt.Printf("  Heartbeat: in progress (#%d done)\n", s.HeartbeatCount)
+	case !s.LastHeartbeat.IsZero():
+		fmt.Printf("  Last heartbeat: %s (#%d)\n", s.LastHeartbeat.Format("15:04:05"), s.HeartbeatCount)
+	}
+	if !s.NextHeartbeat.IsZero() && !s.InHeartbeat {
+		fmt.Printf("  Next heartbeat: in %s\n", formatDuration(time.Until(s.NextHeartbeat).Round(time.Second)))
+	}
+	if !s.LastReload.IsZero() {
+		fmt.Printf("  Config reloaded: %s\n", s.LastReload.Format("2006-01-02 15:04:05"))
+	}
+	if s.MetricsURL != "" {
+		fmt.Printf("  Metrics: %s\n", s.MetricsURL)
+	}
+
+	for _, r := range s.Rigs {
+		if !r.Paused {
+			continue
+		}
+		reason := ""
+		if r.Reason != "" {
+			reason = ": " + r.Reason
+		}
+		fmt.Printf("  %s Rig %s paused since %s%s\n",
+			style.Bold.Render("⏸"), r.Name, r.PausedAt.Format("2006-01-02 15:04"), reason)
+	}
+
+	if binaryModTime, err := getBinaryModTime(); err == nil && binaryModTime.After(s.StartedAt) {
+		fmt.Printf("  %s Binary is newer than process - consider '%s'\n",
+			style.Bold.Render("⚠"),
+			style.Dim.Render("gt daemon stop && gt daemon start"))
+	}
+	return nil
+}
+
+func printDaemonJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var daemonInstallUnitCmd = &cobra.Command{
+	Use:   "install-unit",
+	Short: "Write a systemd user unit for the town's daemon",
+	Long: `Write a systemd user service that runs this town's daemon under systemd.
+
+The unit runs 'gt daemon start --foreground' with Type=notify, so systemd
+knows when the daemon is ready, restarts it if it exits or stops answering
+the watchdog, and sends SIGHUP on 'systemctl --user reload'. Logs go to the
+journal.
+
+The unit is written to ~/.config/systemd/user/gastown-<town>.service. Stop
+any daemon started with 'gt daemon start' before enabling it.
+
+Examples:
+  gt daemon install-unit
+  gt daemon install-unit --print > my.service`,
+	RunE: runDaemonInstallUnit,
+}
+
+var (
+	daemonUnitPrint bool
+	daemonUnitForce bool
+)
+
+func init() {
+	daemonCmd.AddCommand(daemonInstallUnitCmd)
+
+	daemonInstallUnitCmd.Flags().BoolVar(&daemonUnitPrint, "print", false, "Print the unit instead of writing it")
+	daemonInstallUnitCmd.Flags().BoolVarP(&daemonUnitForce, "force", "f", false, "Overwrite an existing unit file")
+}
+
+// daemonWatchdogSec is the systemd watchdog timeout for the daemon unit.
+// The daemon pings at half this and holds pings while a heartbeat overruns.
+const daemonWatchdogSec = "5min"
+
+var unitNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// daemonUnitName returns the systemd unit name for a town's daemon.
+func daemonUnitName(townRoot string) string {
+	name := unitNameUnsafe.ReplaceAllString(filepath.Base(townRoot), "-")
+	name = strings.Trim(name, "-.")
+	if name == "" {
+		name = "town"
+	}
+	return "gastown-" + name + ".service"
+}
+
+// renderDaemonUnit returns the systemd unit for a town's daemon.
+func renderDaemonUnit(townRoot, gtPath, path string) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=Gas Town daemon (%s)\n", townRoot)
+	b.WriteString("Documentation=https://github.com/steveyegge/gastown\n")
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=notify\n")
+	b.WriteString("NotifyAccess=main\n")
+	fmt.Fprintf(&b, "WorkingDirectory=%s\n", systemdQuote(townRoot))
+	fmt.Fprintf(&b, "ExecStart=%s daemon start --foreground\n", systemdQuote(gtPath))
+	b.WriteString("ExecReload=/bin/kill -HUP $MAINPID\n")
+	b.WriteString("KillMode=process\n") // agent tmux sessions outlive the daemon
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=10s\n")
+	fmt.Fprintf(&b, "WatchdogSec=%s\n", daemonWatchdogSec)
+	if path != "" {
+		// tmux, bd, git and claude are found via PATH
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote("PATH="+path))
+	}
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=default.target\n")
+	return b.String()
+}
+
+// systemdQuote quotes a unit file value when it contains whitespace or quotes.
+func systemdQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func runDaemonInstallUnit(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(gtPath); err == nil {
+		gtPath = resolved
+	}
+
+	unit := renderDaemonUnit(townRoot, gtPath, os.Getenv("PATH"))
+	if daemonUnitPrint {
+		fmt.Print(unit)
+		return nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return fmt.Errorf("finding config directory: %w", err)
+	}
+	unitDir := filepath.Join(configDir, "systemd", "user")
+	unitName := daemonUnitName(townRoot)
+	unitPath := filepath.Join(unitDir, unitName)
+
+	if _, err := os.Stat(unitPath); err == nil && !daemonUnitForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", unitPath)
+	}
+	if err := os.MkdirAll(unitDir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", unitDir, err)
+	}
+	if err := os.WriteFile(unitPath, []byte(unit), 0644); err != nil {
+		return fmt.Errorf("writing unit: %w", err)
+	}
+
+	fmt.Printf("%s Wrote %s\n", style.Bold.Render("✓"), unitPath)
+	fmt.Println()
+	fmt.Println("Enable it with:")
+	fmt.Printf("  %s\n", style.Dim.Render("gt daemon stop  # if running"))
+	fmt.Printf("  %s\n", style.Dim.Render("systemctl --user daemon-reload"))
+	fmt.Printf("  %s\n", style.Dim.Render("systemctl --user enable --now "+unitName))
+	fmt.Println()
+	fmt.Printf("Logs: %s\n", style.Dim.Render("journalctl --user -u "+unitName+" -f"))
+	return nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDaemonUnitName(t *testing.T) {
+	tests := map[string]string{
+		"/home/me/gt":         "gastown-gt.service",
+		"/home/me/my town":    "gastown-my-town.service",
+		"/srv/towns/alpha.v2": "gastown-alpha.v2.service",
+		"/":                   "gastown-town.service",
+	}
+	for root, want := range tests {
+		if got := daemonUnitName(root); got != want {
+			t.Errorf("daemonUnitName(%q) = %q, want %q", root, got, want)
+		}
+	}
+}
+
+func TestRenderDaemonUnit(t *testing.T) {
+	unit := renderDaemonUnit("/home/me/my town", "/usr/local/bin/gt", "/usr/bin:/bin")
+	for _, want := range []string{
+		"Type=notify\n",
+		`WorkingDirectory="/home/me/my town"` + "\n",
+		"ExecStart=/usr/local/bin/gt daemon start --foreground\n",
+		"ExecReload=/bin/kill -HUP $MAINPID\n",
+		"KillMode=process\n",
+		"WatchdogSec=5min\n",
+		"Environment=PATH=/usr/bin:/bin\n",
+		"WantedBy=default.target\n",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit missing %q:\n%s", want, unit)
+		}
+	}
+}
@@ -181,6 +181,7 @@ var deaconHealthStateCmd = &cobra.Command{
 - Consecutive failure counts
 - Last ping and response times
 - Force-kill history and cooldowns
+- Pane state and remediations from 'gt deacon pane-check'
 
 This helps the Deacon understand which agents may need attention.`,
 	RunE: runDeaconHealthState,
@@ -811,6 +812,17 @@ func runDeaconHealthState(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Consecutive failures: %d\n", agentState.ConsecutiveFailures)
 		fmt.Printf("  Total force-kills: %d\n", agentState.ForceKillCount)
 
+		if agentState.PaneState != "" {
+			fmt.Printf("  Pane: %s for %s\n", agentState.PaneState, time.Since(agentState.PaneStateSince).Round(time.Second))
+			if agentState.PaneEvidence != "" {
+				fmt.Printf("    %s\n", style.Dim.Render(agentState.PaneEvidence))
+			}
+			if agentState.LastRemediation != deacon.RemedyNone {
+				fmt.Printf("  Last remediation: %s %s ago (%d this state)\n", agentState.LastRemediation,
+					time.Since(agentState.LastRemediationTime).Round(time.Second), agentState.PaneRemediations)
+			}
+		}
+
 		if !agentState.LastForceKillTime.IsZero() {
 			fmt.Printf("  Last force-kill: %s ago\n", time.Since(agentState.LastForceKillTime).Round(time.Second))
 			if agentState.IsInCooldown(healthCheckCooldown) {
@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/notify"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var deaconPaneCheckCmd = &cobra.Command{
+	Use:   "pane-check [agent...]",
+	Short: "Classify agent panes and remediate stuck states",
+	Long: `Capture each agent's tmux pane and classify what it shows:
+
+  working            busy, or output still changing
+  idle               sitting at the prompt with nothing hooked
+  idle_with_work     sitting at the prompt with work on its hook
+  error_loop         the same error repeated in the scrollback
+  context_limit      context nearly exhausted
+  permission_prompt  blocked on a permission dialog
+
+Pane history is kept in the health check state, so idleness is judged
+over successive checks (see 'gt deacon health-state').
+
+With --remediate, the Deacon acts on stuck autonomous agents (polecats,
+witnesses, refineries, the deacon): it escalates permission prompts to the
+overseer, nudges idle or looping agents, and asks agents near their
+context limit - or still stuck after repeated nudges - to hand off. Crew
+and mayor sessions and sessions a human is attached to are only reported.
+
+A permission prompt is only recognized when the pane ends with the dialog
+itself. Add --accept-prompts to confirm such dialogs instead of escalating
+them; this approves whatever the agent asked to run.
+
+With no arguments, all Gas Town agent sessions are checked.
+
+Examples:
+  gt deacon pane-check
+  gt deacon pane-check gastown/polecats/max --json
+  gt deacon pane-check --remediate
+  gt deacon pane-check --remediate --accept-prompts`,
+	RunE: runDeaconPaneCheck,
+}
+
+var (
+	paneCheckRemediate bool
+	paneCheckAccept    bool
+	paneCheckJSON      bool
+	paneCheckLines     int
+	paneCheckIdle      time.Duration
+)
+
+func init() {
+	deaconCmd.AddCommand(deaconPaneCheckCmd)
+
+	deaconPaneCheckCmd.Flags().BoolVar(&paneCheckRemediate, "remediate", false,
+		"Apply remediations (escalate prompt, nudge, handoff)")
+	deaconPaneCheckCmd.Flags().BoolVar(&paneCheckAccept, "accept-prompts", false,
+		"With --remediate, confirm permission prompts instead of escalating them")
+	deaconPaneCheckCmd.Flags().BoolVar(&paneCheckJSON, "json", false, "Output as JSON")
+	deaconPaneCheckCmd.Flags().IntVar(&paneCheckLines, "lines", 200,
+		"Pane lines to capture per agent")
+	deaconPaneCheckCmd.Flags().DurationVar(&paneCheckIdle, "idle-threshold", deacon.DefaultIdleThreshold,
+		"How long an unchanged prompt must sit before the agent counts as idle")
+}
+
+// paneCheckResult is one agent's pane analysis and what was done about it.
+type paneCheckResult struct {
+	*deacon.PaneAnalysis
+	Session    string `json:"session"`
+	HookBead   string `json:"hook_bead,omitempty"`
+	Applied    bool   `json:"applied,omitempty"`
+	SkipReason string `json:"skip_reason,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func runDeaconPaneCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	state, err := deacon.LoadHealthCheckState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading health check state: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	targets, err := paneCheckTargets(t, args)
+	if err != nil {
+		return err
+	}
+
+	cfg := deacon.DefaultPaneConfig()
+	cfg.IdleThreshold = paneCheckIdle
+	cfg.AutoAcceptPrompts = paneCheckAccept
+	b := beads.New(townRoot)
+
+	var results []*paneCheckResult
+	for _, target := range targets {
+		content, err := t.CapturePane(target.session, paneCheckLines)
+		if err != nil {
+			results = append(results, &paneCheckResult{
+				PaneAnalysis: &deacon.PaneAnalysis{AgentID: target.address},
+				Session:      target.session,
+				Error:        fmt.Sprintf("capturing pane: %v", err),
+			})
+			continue
+		}
+
+		hookBead := agentHookBead(b, target.beadID)
+		agentState := state.GetAgentState(target.address)
+		now := time.Now().UTC()
+		res := &paneCheckResult{
+			PaneAnalysis: agentState.ObservePane(content, hookBead != "", now, cfg),
+			Session:      target.session,
+			HookBead:     hookBead,
+		}
+
+		if res.Remediation != deacon.RemedyNone {
+			switch {
+			case !paneCheckRemediate:
+				res.SkipReason = "report only (use --remediate)"
+			case !target.autonomous:
+				res.SkipReason = "human-driven session"
+			case sessionAttached(t, target.session):
+				res.SkipReason = "session attached"
+			default:
+				if err := applyRemediation(t, target.session, res.PaneAnalysis); err != nil {
+					res.Error = err.Error()
+				} else {
+					res.Applied = true
+					agentState.RecordRemediation(res.Remediation, now)
+				}
+			}
+		}
+		results = append(results, res)
+	}
+
+	if err := deacon.SaveHealthCheckState(townRoot, state); err != nil {
+		style.PrintWarning("failed to save health check state: %v", err)
+	}
+
+	if paneCheckJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("%s No agent sessions running\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, r := range results {
+		printPaneCheckResult(r)
+	}
+	return nil
+}
+
+// paneCheckTarget is an agent session to analyze.
+type paneCheckTarget struct {
+	address    string
+	session    string
+	beadID     string
+	autonomous bool
+}
+
+// paneCheckTargets resolves agent addresses, or all running agent sessions.
+func paneCheckTargets(t *tmux.Tmux, addresses []string) ([]paneCheckTarget, error) {
+	var targets []paneCheckTarget
+	if len(addresses) > 0 {
+		for _, addr := range addresses {
+			beadID, sessionName, err := agentAddressToIDs(addr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid agent address %q: %w", addr, err)
+			}
+			identity, err := session.ParseSessionName(sessionName)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, paneCheckTarget{
+				address:    addr,
+				session:    sessionName,
+				beadID:     beadID,
+				autonomous: isAutonomousRole(identity.Role),
+			})
+		}
+		return targets, nil
+	}
+
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	sort.Strings(sessions)
+	for _, name := range sessions {
+		if name == boot.SessionName {
+			continue // Boot is a fresh-each-tick triage runner
+		}
+		identity, err := session.ParseSessionName(name)
+		if err != nil {
+			continue // not a Gas Town agent
+		}
+		addr := identity.Address()
+		beadID, _, err := agentAddressToIDs(addr)
+		if err != nil {
+			continue
+		}
+		targets = append(targets, paneCheckTarget{
+			address:    addr,
+			session:    name,
+			beadID:     beadID,
+			autonomous: isAutonomousRole(identity.Role),
+		})
+	}
+	return targets, nil
+}
+
+// isAutonomousRole reports whether the Deacon may act on a role's session
+// without a human in the loop. Crew and mayor sessions are human-driven.
+func isAutonomousRole(role session.Role) bool {
+	switch role {
+	case session.RolePolecat, session.RoleWitness, session.RoleRefinery, session.RoleDeacon:
+		return true
+	}
+	return false
+}
+
+// agentHookBead returns the work hooked to an agent bead, or "".
+func agentHookBead(b *beads.Beads, beadID string) string {
+	issue, err := b.Show(beadID)
+	if err != nil || issue == nil {
+		return ""
+	}
+	return issue.HookBead
+}
+
+func sessionAttached(t *tmux.Tmux, sessionName string) bool {
+	info, err := t.GetSessionInfo(sessionName)
+	return err == nil && info.Attached
+}
+
+// applyRemediation carries out a pane remediation on a session.
+func applyRemediation(t *tmux.Tmux, sessionName string, a *deacon.PaneAnalysis) error {
+	switch a.Remediation {
+	case deacon.RemedyAcceptPrompt:
+		// Enter confirms the highlighted option, which defaults to "Yes"
+		if err := t.SendKeysRaw(sessionName, "Enter"); err != nil {
+			return fmt.Errorf("accepting prompt: %w", err)
+		}
+		return nil
+	case deacon.RemedyEscalate:
+		topic := fmt.Sprintf("Permission prompt: %s", a.AgentID)
+		body := fmt.Sprintf("Agent: %s\nSession: %s\nWaiting since: %s\nDialog: %s\n\nAnswer it with: tmux attach -t %s",
+			a.AgentID, sessionName, a.Since.Format(time.RFC3339), a.Evidence, sessionName)
+		escCmd := exec.Command("gt", "escalate", topic, "--severity", notify.SeverityHigh, "--message", body) //nolint:gosec // G204: args are constructed internally
+		if out, err := escCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("escalating: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	if err := t.NudgeSession(sessionName, deacon.RemediationMessage(a)); err != nil {
+		return fmt.Errorf("nudging: %w", err)
+	}
+	return nil
+}
+
+func printPaneCheckResult(r *paneCheckResult) {
+	marker := style.Dim.Render("○")
+	switch r.State {
+	case deacon.PaneWorking:
+		marker = style.Bold.Render("●")
+	case deacon.PaneIdleWithWork, deacon.PaneErrorLoop, deacon.PaneContextLimit, deacon.PanePermissionPrompt:
+		marker = style.Bold.Render("⚠")
+	}
+	if r.Error != "" && r.State == "" {
+		fmt.Printf("%s %-28s %s\n", style.Bold.Render("✗"), r.AgentID, r.Error)
+		return
+	}
+
+	line := fmt.Sprintf("%s %-28s %-17s", marker, r.AgentID, r.State)
+	if r.State != deacon.PaneWorking && !r.Since.IsZero() {
+		line += style.Dim.Render(fmt.Sprintf(" for %s", time.Since(r.Since).Round(time.Second)))
+	}
+	fmt.Println(line)
+	if r.Evidence != "" {
+		fmt.Printf("    %s\n", style.Dim.Render(r.Evidence))
+	}
+	if r.Remediation == deacon.RemedyNone {
+		return
+	}
+	switch {
+	case r.Applied:
+		fmt.Printf("    %s %s\n", style.Bold.Render("→"), r.Remediation)
+	case r.Error != "":
+		fmt.Printf("    %s %s failed: %s\n", style.Bold.Render("✗"), r.Remediation, r.Error)
+	default:
+		fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("would %s (%s)", r.Remediation, r.SkipReason)))
+	}
+}
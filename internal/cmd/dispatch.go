@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/dispatch"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	dispatchListJSON  bool
+	dispatchRunDryRun bool
+)
+
+var dispatchCmd = &cobra.Command{
+	Use:     "dispatch",
+	GroupID: GroupWork,
+	Short:   "Manage the dispatch queue of slings waiting for capacity",
+	RunE:    requireSubcommand,
+	Long: `Manage the town dispatch queue.
+
+When a rig is at its max_polecats (or the town at its max_polecats),
+'gt sling' queues the work here instead of spawning another polecat.
+'gt sling --queue' queues on purpose. The daemon runs 'gt dispatch run'
+every heartbeat, starting queued work in order as polecats finish.
+
+Queue order: promoted items first, then bead priority, then oldest first.
+An item that fails to start ` + fmt.Sprint(dispatch.MaxAttempts) + ` times stalls until promoted.
+
+Commands:
+  gt dispatch list              Show the queue
+  gt dispatch promote <id>      Move an item to the front
+  gt dispatch cancel <id>       Remove an item
+  gt dispatch run               Start whatever fits now (daemon)
+
+Items can be named by queue ID (dq-...) or bead ID.`,
+}
+
+var dispatchListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "Show queued slings in dispatch order",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDispatchList,
+}
+
+var dispatchCancelCmd = &cobra.Command{
+	Use:          "cancel <id|bead>",
+	Short:        "Remove a sling from the dispatch queue",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDispatchCancel,
+}
+
+var dispatchPromoteCmd = &cobra.Command{
+	Use:   "promote <id|bead>",
+	Short: "Move a sling to the front of the dispatch queue",
+	Long: `Move a queued sling to the front of the dispatch queue.
+
+The most recently promoted item goes first. Promoting a stalled item
+clears its failed attempts so the daemon retries it.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runDispatchPromote,
+}
+
+var dispatchRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Start queued slings that have capacity (called by the daemon)",
+	Long: `Start queued slings, in queue order, while polecat capacity allows.
+
+Items whose rig is full are skipped, so work for other rigs is not held up.
+Auto-routed items start if any rig that can take them has a free slot.
+Nothing starts while the town is at its max_polecats.
+
+Each item is started with 'gt sling'. It stays in the queue, marked as
+starting, until the sling succeeds. If that fails, the item goes back in
+the queue with the error; after ` + fmt.Sprint(dispatch.MaxAttempts) + ` failures it stalls. An item
+still starting after ` + dispatch.StartTimeout.String() + ` was left by a run that died, and counts
+as a failed start.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDispatchRun,
+}
+
+func init() {
+	dispatchListCmd.Flags().BoolVar(&dispatchListJSON, "json", false, "Output as JSON")
+	dispatchRunCmd.Flags().BoolVarP(&dispatchRunDryRun, "dry-run", "n", false, "Show what would start")
+
+	dispatchCmd.AddCommand(dispatchListCmd)
+	dispatchCmd.AddCommand(dispatchCancelCmd)
+	dispatchCmd.AddCommand(dispatchPromoteCmd)
+	dispatchCmd.AddCommand(dispatchRunCmd)
+	rootCmd.AddCommand(dispatchCmd)
+}
+
+// dispatchQueue returns the current town's dispatch queue.
+func dispatchQueue() (*dispatch.Queue, string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return dispatch.New(townRoot), townRoot, nil
+}
+
+func runDispatchList(cmd *cobra.Command, args []string) error {
+	q, _, err := dispatchQueue()
+	if err != nil {
+		return err
+	}
+	items, err := q.List()
+	if err != nil {
+		return err
+	}
+
+	if dispatchListJSON {
+		if items == nil {
+			items = []*dispatch.Item{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Printf("%s Dispatch queue is empty\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Dispatch queue (%d)\n\n", style.Bold.Render("⏳"), len(items))
+	for i, item := range items {
+		mark := style.Dim.Render("○")
+		switch {
+		case item.Stalled():
+			mark = style.ErrorPrefix
+		case item.PromotedAt != nil:
+			mark = style.Warning.Render("↑")
+		}
+		title := item.Title
+		if item.Formula != "" {
+			title = fmt.Sprintf("%s (%s)", title, item.Formula)
+		}
+		fmt.Printf("%2d. %s %s  %s  %s\n", i+1, mark, item.BeadID, title, style.Dim.Render(item.ID))
+		fmt.Printf("      → %s  P%d  queued %s", item.Target(), item.Priority, formatAge(item.EnqueuedAt))
+		if item.Starting() {
+			fmt.Printf(", starting since %s", formatAge(*item.StartingAt))
+		}
+		if item.EnqueuedBy != "" {
+			fmt.Printf(" by %s", item.EnqueuedBy)
+		}
+		fmt.Println()
+		if item.LastError != "" {
+			status := fmt.Sprintf("failed %d/%d", item.Attempts, dispatch.MaxAttempts)
+			if item.Stalled() {
+				status = "stalled (gt dispatch promote to retry)"
+			}
+			fmt.Printf("      %s %s\n", style.Error.Render(status+":"), item.LastError)
+		}
+	}
+	return nil
+}
+
+// findDispatchItem looks up an item by queue ID or bead ID.
+func findDispatchItem(q *dispatch.Queue, ref string) (*dispatch.Item, error) {
+	item, err := q.Find(ref)
+	if errors.Is(err, dispatch.ErrNotFound) {
+		return nil, fmt.Errorf("%s is not in the dispatch queue", ref)
+	}
+	return item, err
+}
+
+func runDispatchCancel(cmd *cobra.Command, args []string) error {
+	q, _, err := dispatchQueue()
+	if err != nil {
+		return err
+	}
+	item, err := findDispatchItem(q, args[0])
+	if err != nil {
+		return err
+	}
+	if err := q.Remove(item.ID); err != nil {
+		return fmt.Errorf("removing %s: %w", item.ID, err)
+	}
+	fmt.Printf("%s Cancelled %s (%s → %s)\n", style.SuccessPrefix, item.ID, item.BeadID, item.Target())
+	return nil
+}
+
+func runDispatchPromote(cmd *cobra.Command, args []string) error {
+	q, _, err := dispatchQueue()
+	if err != nil {
+		return err
+	}
+	item, err := findDispatchItem(q, args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := q.Promote(item.ID); err != nil {
+		return fmt.Errorf("promoting %s: %w", item.ID, err)
+	}
+	fmt.Printf("%s Promoted %s (%s → %s) to the front of the queue\n", style.SuccessPrefix, item.ID, item.BeadID, item.Target())
+	return nil
+}
+
+func runDispatchRun(cmd *cobra.Command, args []string) error {
+	q, townRoot, err := dispatchQueue()
+	if err != nil {
+		return err
+	}
+	items, err := q.List()
+	if err != nil {
+		return err
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+
+	started, failed := 0, 0
+	for _, item := range items {
+		if item.Interrupted(time.Now()) && !dispatchRunDryRun {
+			// The run starting it died mid-sling: count it as a failed start
+			failedItem, err := q.Fail(item.ID, "start interrupted")
+			if err != nil {
+				return fmt.Errorf("requeueing %s: %w", item.ID, err)
+			}
+			item = failedItem
+			fmt.Printf("%s %s start was interrupted (%d/%d)\n", style.ErrorPrefix, item.BeadID, item.Attempts, dispatch.MaxAttempts)
+		}
+		if item.Stalled() || item.Starting() {
+			continue
+		}
+		if active, limit := townPolecatLoad(townRoot); limit > 0 && active >= limit {
+			fmt.Printf("%s Town is at capacity (%d/%d polecats working)\n", style.Dim.Render("○"), active, limit)
+			break
+		}
+		if reason, ok := dispatchCanStart(townRoot, item); !ok {
+			fmt.Printf("%s %s waiting: %s\n", style.Dim.Render("○"), item.BeadID, reason)
+			continue
+		}
+
+		slingArgs := append([]string{"sling"}, item.SlingArgs()...)
+		if dispatchRunDryRun {
+			fmt.Printf("Would start %s: gt %s\n", item.ID, strings.Join(slingArgs, " "))
+			continue
+		}
+
+		// Keep the item queued, marked in flight, until the sling succeeds
+		if _, err := q.Start(item.ID); err != nil {
+			if errors.Is(err, dispatch.ErrNotFound) || errors.Is(err, dispatch.ErrStarting) {
+				continue // cancelled, or another run is starting it
+			}
+			return fmt.Errorf("starting %s: %w", item.ID, err)
+		}
+		c := exec.Command(gtPath, slingArgs...)
+		c.Dir = townRoot
+		out, err := c.CombinedOutput()
+		if err != nil {
+			failedItem, failErr := q.Fail(item.ID, lastOutputLine(out, err))
+			if failErr != nil {
+				return fmt.Errorf("requeueing %s: %w", item.ID, failErr)
+			}
+			fmt.Printf("%s %s failed to start (%d/%d): %s\n", style.ErrorPrefix, item.BeadID, failedItem.Attempts, dispatch.MaxAttempts, failedItem.LastError)
+			failed++
+			continue
+		}
+		requeued, err := q.Started(item.ID)
+		if err != nil {
+			return fmt.Errorf("removing %s: %w", item.ID, err)
+		}
+		if requeued {
+			fmt.Printf("%s %s went back in the queue: no capacity when slung\n", style.Dim.Render("○"), item.BeadID)
+			continue
+		}
+		fmt.Printf("%s Started %s → %s (queued %s)\n", style.SuccessPrefix, item.BeadID, item.Target(), formatAge(item.EnqueuedAt))
+		started++
+	}
+
+	if !dispatchRunDryRun && (started > 0 || failed > 0) {
+		fmt.Printf("\nStarted %d, failed %d, %d still queued\n", started, failed, q.Count())
+	}
+	return nil
+}
+
+// dispatchCanStart reports whether an item has a free polecat slot, and
+// why not when it doesn't.
+func dispatchCanStart(townRoot string, item *dispatch.Item) (string, bool) {
+	if item.Rig != "" {
+		_, r, err := getRig(item.Rig)
+		if err != nil {
+			return "", true // Let the sling report it
+		}
+		reason, full := spawnCapacityFull(townRoot, r)
+		return reason, !full
+	}
+
+	info, err := getBeadInfo(item.BeadID)
+	if err != nil {
+		return "", true
+	}
+	cands, err := gatherSlingCandidates(townRoot, homeRigForBead(townRoot, item.BeadID))
+	if err != nil {
+		return "", true
+	}
+	if _, ok := rankSlingCandidates(requiredCapabilities(info.Labels), cands); !ok {
+		return errNoFreeRig.Error(), false
+	}
+	return "", true
+}
+
+// lastOutputLine returns the last non-empty line of command output, or the
+// error when there was none.
+func lastOutputLine(out []byte, err error) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
+	}
+	return err.Error()
+}
@@ -1,34 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
-// Escalation severity levels.
-// These map to mail priorities and indicate urgency for human attention.
-const (
-	// SeverityCritical (P0) - System-threatening issues requiring immediate human attention.
-	// Examples: data corruption, security breach, complete system failure.
-	SeverityCritical = "CRITICAL"
-
-	// SeverityHigh (P1) - Important blockers that need human attention soon.
-	// Examples: unresolvable merge conflicts, critical blocking bugs, ambiguous requirements.
-	SeverityHigh = "HIGH"
-
-	// SeverityMedium (P2) - Standard escalations for human attention at convenience.
-	// Examples: unclear requirements, design decisions needed, non-blocking issues.
-	SeverityMedium = "MEDIUM"
-)
-
 var escalateCmd = &cobra.Command{
 	Use:     "escalate <topic>",
 	GroupID: GroupComm,
@@ -50,6 +38,10 @@ The escalation creates an audit trail bead and sends mail to the overseer
 with appropriate priority. All molecular algebra edge cases should escalate
 here rather than failing silently.
 
+Notifiers configured in town settings (webhook, email, desktop, ntfy) are
+also triggered by severity so the overseer hears about escalations away
+from the terminal. See 'gt notifiers'.
+
 Examples:
   gt escalate "Database migration failed"
   gt escalate -s CRITICAL "Data corruption detected in user table"
@@ -66,7 +58,7 @@ var (
 )
 
 func init() {
-	escalateCmd.Flags().StringVarP(&escalateSeverity, "severity", "s", SeverityMedium,
+	escalateCmd.Flags().StringVarP(&escalateSeverity, "severity", "s", notify.SeverityMedium,
 		"Severity level: CRITICAL, HIGH, or MEDIUM")
 	escalateCmd.Flags().StringVarP(&escalateMessage, "message", "m", "",
 		"Additional details about the escalation")
@@ -80,16 +72,16 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 
 	// Validate severity
 	severity := strings.ToUpper(escalateSeverity)
-	if severity != SeverityCritical && severity != SeverityHigh && severity != SeverityMedium {
+	if severity != notify.SeverityCritical && severity != notify.SeverityHigh && severity != notify.SeverityMedium {
 		return fmt.Errorf("invalid severity '%s': must be CRITICAL, HIGH, or MEDIUM", escalateSeverity)
 	}
 
 	// Map severity to mail priority
 	var priority mail.Priority
 	switch severity {
-	case SeverityCritical:
+	case notify.SeverityCritical:
 		priority = mail.PriorityUrgent
-	case SeverityHigh:
+	case notify.SeverityHigh:
 		priority = mail.PriorityHigh
 	default:
 		priority = mail.PriorityNormal
@@ -128,6 +120,13 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Subject:  %s\n", subject)
 		fmt.Printf("  Body:\n%s\n", indentText(body, "    "))
 		fmt.Printf("Would send mail to: overseer\n")
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			for _, n := range settings.Notifiers {
+				if notify.Triggers(n, severity) {
+					fmt.Printf("Would notify: %s (%s)\n", n.DisplayName(), n.Type)
+				}
+			}
+		}
 		return nil
 	}
 
@@ -154,6 +153,15 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("sending escalation mail: %w", err)
 	}
 
+	// Fan out to outbound notifiers (webhook, email, desktop, ntfy)
+	notifyEscalation(townRoot, &notify.Escalation{
+		Severity: severity,
+		Topic:    topic,
+		Message:  escalateMessage,
+		From:     agentID,
+		BeadID:   beadID,
+	})
+
 	// Log to activity feed
 	payload := events.EscalationPayload("", agentID, "overseer", topic)
 	payload["severity"] = severity
@@ -165,9 +173,9 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	// Print confirmation with severity-appropriate styling
 	var emoji string
 	switch severity {
-	case SeverityCritical:
+	case notify.SeverityCritical:
 		emoji = "🚨"
-	case SeverityHigh:
+	case notify.SeverityHigh:
 		emoji = "⚠️"
 	default:
 		emoji = "📢"
@@ -182,6 +190,33 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// notifyEscalation delivers an escalation to the notifiers configured in
+// town settings. Failures are reported but never fail the escalation: the
+// bead and overseer mail are the source of truth.
+func notifyEscalation(townRoot string, esc *notify.Escalation) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		style.PrintWarning("could not load notifiers from town settings: %v", err)
+		return
+	}
+	if len(settings.Notifiers) == 0 {
+		return
+	}
+	if esc.Town == "" {
+		esc.Town = filepath.Base(townRoot)
+	}
+
+	results := notify.Dispatch(context.Background(), settings.Notifiers, esc)
+	notify.LogResults(esc.From, esc, results)
+	for _, r := range results {
+		if r.OK() {
+			fmt.Printf("%s Notified %s (%s)\n", style.Bold.Render("📣"), r.Name, r.Type)
+		} else {
+			style.PrintWarning("notifier %s (%s) failed: %v", r.Name, r.Type, r.Err)
+		}
+	}
+}
+
 // detectAgentIdentity returns the current agent's identity string.
 func detectAgentIdentity() (string, error) {
 	// Try GT_ROLE first
@@ -235,9 +270,9 @@ func createEscalationBead(topic, severity, from, details string) (string, error)
 // severityToBeadsPriority converts severity to beads priority string.
 func severityToBeadsPriority(severity string) string {
 	switch severity {
-	case SeverityCritical:
+	case notify.SeverityCritical:
 		return "0" // P0
-	case SeverityHigh:
+	case notify.SeverityHigh:
 		return "1" // P1
 	default:
 		return "2" // P2
@@ -2,17 +2,25 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"crypto/rand"
 	"encoding/base32"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -21,9 +29,13 @@ import (
 var (
 	formulaListJSON   bool
 	formulaShowJSON   bool
+	formulaShowResolv bool
+	formulaShowInputs bool
 	formulaRunPR      int
 	formulaRunRig     string
 	formulaRunDryRun  bool
+	formulaRunVars    []string
+	formulaRunTarget  string
 	formulaCreateType string
 )
 
@@ -43,6 +55,9 @@ Commands:
   list    List available formulas from all search paths
   show    Display formula details (steps, variables, composition)
   run     Execute a formula (pour and dispatch)
+  lint    Check formulas for likely mistakes
+  test    Render a formula against a golden file
+  graph   Show a formula's steps as a dependency graph
   create  Create a new formula template
 
 Search paths (in order):
@@ -54,6 +69,8 @@ Examples:
   gt formula list                    # List all formulas
   gt formula show shiny              # Show formula details
   gt formula run shiny --pr=123      # Run formula on PR #123
+  gt formula lint --all --strict     # Lint every formula (for CI)
+  gt formula graph shiny -f mermaid  # Step graph as a Mermaid flowchart
   gt formula create my-workflow      # Create new formula template`,
 }
 
@@ -84,9 +101,21 @@ Shows:
   - Steps with dependencies
   - Composition rules (extends, aspects)
 
+With --resolved, shows the formula as it runs: extends and imports merged
+in, overridden steps extended or replaced, and compose.expand and
+compose.aspects woven into the steps.
+
+With --inputs, lists the inputs and vars the formula takes: type, whether
+required, default and env var. With --json this is a machine-readable
+schema.
+
+Input types: string, int, bool, enum, path, bead-id, rig, glob.
+
 Examples:
   gt formula show shiny
-  gt formula show rule-of-five --json`,
+  gt formula show rule-of-five --json
+  gt formula show shiny-secure --resolved
+  gt formula show code-review --inputs --json`,
 	Args: cobra.ExactArgs(1),
 	RunE: runFormulaShow,
 }
@@ -94,25 +123,39 @@ Examples:
 var formulaRunCmd = &cobra.Command{
 	Use:   "run <name>",
 	Short: "Execute a formula",
-	Long: `Execute a formula by pouring it and dispatching work.
+	Long: `Execute a formula by creating beads for its work and dispatching them.
 
 This command:
-  1. Looks up the formula by name
-  2. Pours it to create a molecule (or uses existing proto)
-  3. Dispatches the molecule to available workers
-
-For PR-based workflows, use --pr to specify the GitHub PR number.
+  1. Looks up the formula by name, then parses and validates it
+  2. Resolves variables (--var, --pr, env and declared defaults), prompting
+     for missing required inputs when run interactively, and checks them
+     against their types (bead-id and rig inputs must exist in the town)
+  3. Creates a convoy bead tracking one bead per unit of work
+  4. Slings the work that is ready to polecats
+
+How each formula type runs:
+  convoy     Legs run in parallel; a synthesis bead waits on them
+  workflow   Steps become beads with their needs as dependencies; steps
+             with no needs are slung now, the rest as dependencies close.
+             Steps whose when condition is false are skipped, and for_each
+             steps fan out over their list var.
+  expansion  Templates are expanded against --target and run as a workflow
+  aspect     Aspects run in parallel (advice-only aspects must be composed
+             into a workflow instead)
 
 Options:
-  --pr=N      Run formula on GitHub PR #N
-  --rig=NAME  Target specific rig (default: current or gastown)
-  --dry-run   Show what would happen without executing
+  --pr=N          Run formula on GitHub PR #N (sets var "pr")
+  --var=KEY=VAL   Set a formula variable (repeatable)
+  --target=BEAD   Bead to expand an expansion formula against
+  --rig=NAME      Target specific rig (default: current or gastown)
+  --dry-run       Show the planned work without executing
 
 Examples:
-  gt formula run shiny                    # Run formula in current rig
-  gt formula run shiny --pr=123           # Run on PR #123
-  gt formula run security-audit --rig=beads  # Run in specific rig
-  gt formula run release --dry-run        # Preview execution`,
+  gt formula run code-review --pr=123        # Run convoy on PR #123
+  gt formula run release --var version=1.2.0 # Run a workflow
+  gt formula run rule-of-five --target gt-abc12  # Expand against a bead
+  gt formula run code-review --rig=beads --pr=7  # Run in specific rig
+  gt formula run release --dry-run           # Preview execution`,
 	Args: cobra.ExactArgs(1),
 	RunE: runFormulaRun,
 }
@@ -144,11 +187,15 @@ func init() {
 
 	// Show flags
 	formulaShowCmd.Flags().BoolVar(&formulaShowJSON, "json", false, "Output as JSON")
+	formulaShowCmd.Flags().BoolVar(&formulaShowResolv, "resolved", false, "Show the formula with imports and composition applied")
+	formulaShowCmd.Flags().BoolVar(&formulaShowInputs, "inputs", false, "Show only the formula's inputs and vars (schema with --json)")
 
 	// Run flags
 	formulaRunCmd.Flags().IntVar(&formulaRunPR, "pr", 0, "GitHub PR number to run formula on")
 	formulaRunCmd.Flags().StringVar(&formulaRunRig, "rig", "", "Target rig (default: current or gastown)")
 	formulaRunCmd.Flags().BoolVar(&formulaRunDryRun, "dry-run", false, "Preview execution without running")
+	formulaRunCmd.Flags().StringArrayVar(&formulaRunVars, "var", nil, "Formula variable as key=value (can be used multiple times)")
+	formulaRunCmd.Flags().StringVar(&formulaRunTarget, "target", "", "Target bead for expansion formulas")
 
 	// Create flags
 	formulaCreateCmd.Flags().StringVar(&formulaCreateType, "type", "task", "Formula type: task, workflow, or patrol")
@@ -178,6 +225,12 @@ func runFormulaList(cmd *cobra.Command, args []string) error {
 // runFormulaShow delegates to bd formula show
 func runFormulaShow(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
+	if formulaShowInputs {
+		return showFormulaInputs(formulaName)
+	}
+	if formulaShowResolv {
+		return showResolvedFormula(formulaName)
+	}
 	bdArgs := []string{"formula", "show", formulaName}
 	if formulaShowJSON {
 		bdArgs = append(bdArgs, "--json")
@@ -189,22 +242,23 @@ func runFormulaShow(cmd *cobra.Command, args []string) error {
 	return bdCmd.Run()
 }
 
-// runFormulaRun executes a formula by spawning a convoy of polecats.
-// For convoy-type formulas, it creates a convoy bead, creates leg beads,
-// and slings each leg to a separate polecat with leg-specific prompts.
+// runFormulaRun executes a formula by creating beads for its work and
+// slinging whatever is ready to polecats. The formula is parsed and
+// validated with the formula package; every type runs:
+//   - convoy: one bead per leg, all slung in parallel, plus a synthesis bead
+//     that waits on the legs
+//   - workflow: one bead per step with its needs as dependencies; steps with
+//     no needs are slung, the rest unblock as their dependencies close
+//   - expansion: the templates expanded against --target, run like a workflow
+//   - aspect: one bead per aspect, all slung in parallel
+//
+// All beads are tracked by a convoy bead recording the formula.
 func runFormulaRun(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
 
-	// Find the formula file
-	formulaPath, err := findFormulaFile(formulaName)
-	if err != nil {
-		return fmt.Errorf("finding formula: %w", err)
-	}
-
-	// Parse the formula
-	f, err := parseFormulaFile(formulaPath)
+	f, formulaPath, err := loadFormula(formulaName)
 	if err != nil {
-		return fmt.Errorf("parsing formula: %w", err)
+		return err
 	}
 
 	// Determine target rig
@@ -223,56 +277,338 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	vars, err := parseFormulaVars(formulaRunVars)
+	if err != nil {
+		return err
+	}
+	if formulaRunPR > 0 {
+		if _, ok := vars["pr"]; !ok {
+			vars["pr"] = strconv.Itoa(formulaRunPR)
+		}
+	}
+	vars = f.ApplyDefaults(vars)
+	if len(f.MissingVars(vars)) > 0 && term.IsTerminal(int(os.Stdin.Fd())) {
+		promptFormulaInputs(f, vars)
+	}
+	vars, err = f.ResolveVars(vars)
+	if err != nil {
+		return fmt.Errorf("formula %s: %w", formulaName, err)
+	}
+	var town formula.Town
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		town = &formulaTown{townRoot: townRoot}
+	}
+	if err := f.CheckInputs(vars, town); err != nil {
+		return fmt.Errorf("formula %s: %w", formulaName, err)
+	}
+
+	var target *formula.Target
+	if formulaRunTarget != "" {
+		target, err = loadFormulaTarget(formulaRunTarget)
+		if err != nil {
+			return err
+		}
+	} else if f.Type == formula.TypeExpansion {
+		return fmt.Errorf("expansion formula %s requires --target <bead>", formulaName)
+	}
+
+	steps, err := f.Plan(vars, target)
+	if err != nil {
+		return err
+	}
+
 	// Handle dry-run mode
 	if formulaRunDryRun {
-		return dryRunFormula(f, formulaName, targetRig)
-	}
-
-	// Currently only convoy formulas are supported for execution
-	if f.Type != "convoy" {
-		fmt.Printf("%s Formula type '%s' not yet supported for execution.\n",
-			style.Dim.Render("Note:"), f.Type)
-		fmt.Printf("Currently only 'convoy' formulas can be run.\n")
-		fmt.Printf("\nTo run '%s' manually:\n", formulaName)
-		fmt.Printf("  1. View formula:   gt formula show %s\n", formulaName)
-		fmt.Printf("  2. Cook to proto:  bd cook %s\n", formulaName)
-		fmt.Printf("  3. Pour molecule:  bd pour %s\n", formulaName)
-		fmt.Printf("  4. Sling to rig:   gt sling <mol-id> %s\n", targetRig)
+		return dryRunFormula(f, steps, targetRig)
+	}
+
+	return executeFormula(f, formulaPath, steps, vars, targetRig)
+}
+
+// loadFormula finds, parses and resolves a formula by name, returning it
+// with the path of its file.
+func loadFormula(name string) (*formula.Formula, string, error) {
+	formulaPath, err := findFormulaFile(name)
+	if err != nil {
+		return nil, "", fmt.Errorf("finding formula: %w", err)
+	}
+	f, err := formula.ParseFile(formulaPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing formula: %w", err)
+	}
+	f, err = f.Resolve(loadFormulaByName)
+	if err != nil {
+		return nil, "", fmt.Errorf("resolving formula: %w", err)
+	}
+	return f, formulaPath, nil
+}
+
+// loadFormulaByName is the formula.Loader for imports, searching the same
+// paths as findFormulaFile.
+func loadFormulaByName(name string) (*formula.Formula, error) {
+	path, err := findFormulaFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return formula.ParseFile(path)
+}
+
+// showResolvedFormula prints a formula with its composition applied, as
+// TOML or (with --json) JSON using the same keys.
+func showResolvedFormula(name string) error {
+	f, _, err := loadFormula(name)
+	if err != nil {
+		return err
+	}
+	out, err := encodeResolvedFormula(f, formulaShowJSON)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// encodeResolvedFormula renders a resolved formula as TOML, or as JSON
+// when asJSON is set.
+func encodeResolvedFormula(f *formula.Formula, asJSON bool) (string, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(f); err != nil {
+		return "", fmt.Errorf("encoding formula: %w", err)
+	}
+	if !asJSON {
+		return buf.String(), nil
+	}
+
+	var doc map[string]interface{}
+	if _, err := toml.Decode(buf.String(), &doc); err != nil {
+		return "", fmt.Errorf("encoding formula: %w", err)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// promptFormulaInputs asks for required inputs and vars that have no
+// value, re-checking after each answer since one input can satisfy
+// another's required_unless. Empty answers leave the value unset.
+func promptFormulaInputs(f *formula.Formula, vars map[string]string) {
+	reader := bufio.NewReader(os.Stdin)
+	asked := make(map[string]bool)
+	for {
+		name := ""
+		for _, m := range f.MissingVars(vars) {
+			if !asked[m] {
+				name = m
+				break
+			}
+		}
+		if name == "" {
+			return
+		}
+		asked[name] = true
+		if value := promptFormulaValue(reader, name, f.Inputs[name], f.Vars[name].Description); value != "" {
+			vars[name] = value
+		}
+	}
+}
+
+// promptFormulaValue reads one value, re-asking until it type-checks.
+func promptFormulaValue(reader *bufio.Reader, name string, in formula.Input, varDesc string) string {
+	desc := in.Description
+	if desc == "" {
+		desc = varDesc
+	}
+	hint := string(in.Type.Normalize())
+	switch {
+	case in.Type.Normalize() == formula.InputEnum:
+		hint = strings.Join(in.Enum, "|")
+	case len(in.RequiredUnless) > 0:
+		hint += ", or leave empty to give " + strings.Join(in.RequiredUnless, "/")
+	}
+
+	for {
+		if desc != "" {
+			fmt.Printf("%s %s\n", style.Bold.Render(name), style.Dim.Render(desc))
+		}
+		fmt.Printf("%s (%s): ", name, hint)
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if answer == "" || err != nil {
+			return answer
+		}
+		if err := in.Check(answer); err != nil {
+			fmt.Printf("%s %v\n", style.Warning.Render("⚠"), err)
+			continue
+		}
+		return answer
+	}
+}
+
+// formulaTown checks bead-id and rig inputs against the town.
+type formulaTown struct {
+	townRoot string
+	rigs     *config.RigsConfig
+}
+
+func (t *formulaTown) BeadExists(id string) bool {
+	_, err := beads.New(t.townRoot).Show(id)
+	return err == nil
+}
+
+func (t *formulaTown) RigExists(name string) bool {
+	if t.rigs == nil {
+		rigs, err := config.LoadRigsConfig(filepath.Join(t.townRoot, "mayor", "rigs.json"))
+		if err != nil {
+			return false
+		}
+		t.rigs = rigs
+	}
+	_, ok := t.rigs.Rigs[name]
+	return ok
+}
+
+// formulaInputSchema is the JSON output of gt formula show --inputs.
+type formulaInputSchema struct {
+	Formula string              `json:"formula"`
+	Type    formula.FormulaType `json:"type"`
+	Inputs  []formula.InputSpec `json:"inputs"`
+}
+
+// showFormulaInputs prints a formula's inputs and vars.
+func showFormulaInputs(name string) error {
+	f, _, err := loadFormula(name)
+	if err != nil {
+		return err
+	}
+	specs := f.InputSpecs()
+
+	if formulaShowJSON {
+		data, err := json.MarshalIndent(formulaInputSchema{Formula: f.Name, Type: f.Type, Inputs: specs}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
 		return nil
 	}
 
-	// Execute convoy formula
-	return executeConvoyFormula(f, formulaName, targetRig)
+	if len(specs) == 0 {
+		fmt.Printf("%s %s takes no inputs\n", style.Dim.Render("○"), f.Name)
+		return nil
+	}
+	fmt.Printf("%s inputs:\n\n", style.Bold.Render(f.Name))
+	for _, spec := range specs {
+		typ := string(spec.Type)
+		if len(spec.Enum) > 0 {
+			typ = strings.Join(spec.Enum, "|")
+		}
+		req := ""
+		switch {
+		case spec.Required:
+			req = style.Bold.Render("required")
+		case len(spec.RequiredUnless) > 0:
+			req = "required unless " + strings.Join(spec.RequiredUnless, "/")
+		}
+		fmt.Printf("  %-16s %-10s %s\n", spec.Name, typ, req)
+		if spec.Description != "" {
+			fmt.Printf("  %-16s %s\n", "", style.Dim.Render(spec.Description))
+		}
+		var extra []string
+		if spec.Default != "" {
+			extra = append(extra, "default: "+spec.Default)
+		}
+		if spec.Env != "" {
+			extra = append(extra, "env: $"+spec.Env)
+		}
+		if len(extra) > 0 {
+			fmt.Printf("  %-16s %s\n", "", style.Dim.Render(strings.Join(extra, ", ")))
+		}
+	}
+	return nil
+}
+
+// parseFormulaVars parses --var key=value flags.
+func parseFormulaVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (want key=value)", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// loadFormulaTarget looks up the bead an expansion formula is applied to.
+func loadFormulaTarget(beadID string) (*formula.Target, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	issue, err := beads.New(townRoot).Show(beadID)
+	if err != nil {
+		return nil, fmt.Errorf("loading target %s: %w", beadID, err)
+	}
+	return &formula.Target{ID: issue.ID, Title: issue.Title, Description: issue.Description}, nil
 }
 
 // dryRunFormula shows what would happen without executing
-func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
+func dryRunFormula(f *formula.Formula, steps []formula.PlannedStep, targetRig string) error {
 	fmt.Printf("%s Would execute formula:\n", style.Dim.Render("[dry-run]"))
-	fmt.Printf("  Formula: %s\n", style.Bold.Render(formulaName))
+	fmt.Printf("  Formula: %s\n", style.Bold.Render(f.Name))
 	fmt.Printf("  Type:    %s\n", f.Type)
 	fmt.Printf("  Rig:     %s\n", targetRig)
 	if formulaRunPR > 0 {
 		fmt.Printf("  PR:      #%d\n", formulaRunPR)
 	}
+	if formulaRunTarget != "" {
+		fmt.Printf("  Target:  %s\n", formulaRunTarget)
+	}
 
-	if f.Type == "convoy" && len(f.Legs) > 0 {
-		fmt.Printf("\n  Legs (%d parallel):\n", len(f.Legs))
-		for _, leg := range f.Legs {
-			fmt.Printf("    • %s: %s\n", leg.ID, leg.Title)
+	fmt.Printf("\n  %s (%d, in dependency order):\n", formulaStepNoun(f), len(steps))
+	for _, step := range steps {
+		marker := style.Dim.Render("○")
+		switch {
+		case step.Skipped:
+			marker = style.Dim.Render("⊘")
+		case step.Ready:
+			marker = "•"
+		case step.Synthesis:
+			marker = style.Dim.Render("★")
+		}
+		fmt.Printf("    %s %s: %s\n", marker, step.ID, step.Title)
+		if len(step.Needs) > 0 {
+			fmt.Printf("        %s\n", style.Dim.Render("needs: "+strings.Join(step.Needs, ", ")))
 		}
-		if f.Synthesis != nil {
-			fmt.Printf("\n  Synthesis:\n")
-			fmt.Printf("    • %s\n", f.Synthesis.Title)
+		for _, line := range step.Flow() {
+			fmt.Printf("        %s\n", style.Dim.Render(line))
 		}
 	}
+	fmt.Printf("\n  %s dispatched immediately; %s waits on its needs; %s skipped\n",
+		"•", style.Dim.Render("○"), style.Dim.Render("⊘"))
 
 	return nil
 }
 
-// executeConvoyFormula spawns a convoy of polecats to execute a convoy formula
-func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
-	fmt.Printf("%s Executing convoy formula: %s\n\n",
-		style.Bold.Render("🚚"), formulaName)
+// formulaStepNoun names the units of work for a formula type.
+func formulaStepNoun(f *formula.Formula) string {
+	switch f.Type {
+	case formula.TypeConvoy:
+		return "Legs"
+	case formula.TypeAspect:
+		return "Aspects"
+	default:
+		return "Steps"
+	}
+}
+
+// executeFormula creates the beads for a formula run and slings the ready ones.
+func executeFormula(f *formula.Formula, formulaPath string, steps []formula.PlannedStep, vars map[string]string, targetRig string) error {
+	fmt.Printf("%s Executing %s formula: %s\n\n",
+		style.Bold.Render("🚚"), f.Type, f.Name)
 
 	// Get town beads directory for convoy creation
 	townRoot, err := workspace.FindFromCwd()
@@ -283,139 +619,83 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 
 	// Step 1: Create convoy bead
 	convoyID := fmt.Sprintf("hq-cv-%s", generateFormulaShortID())
-	convoyTitle := fmt.Sprintf("%s: %s", formulaName, f.Description)
+	convoyTitle := fmt.Sprintf("%s: %s", f.Name, f.Description)
 	if len(convoyTitle) > 80 {
 		convoyTitle = convoyTitle[:77] + "..."
 	}
 
-	// Build description with formula context
-	description := fmt.Sprintf("Formula convoy: %s\n\nLegs: %d\nRig: %s",
-		formulaName, len(f.Legs), targetRig)
+	// Build description with formula context. The formula lines are read
+	// back by getConvoyMeta for synthesis.
+	description := fmt.Sprintf("formula: %s\nformula_path: %s\n\nType: %s\n%s: %d\nRig: %s",
+		f.Name, formulaPath, f.Type, formulaStepNoun(f), len(steps), targetRig)
 	if formulaRunPR > 0 {
 		description += fmt.Sprintf("\nPR: #%d", formulaRunPR)
 	}
-
-	createArgs := []string{
-		"create",
-		"--type=convoy",
-		"--id=" + convoyID,
-		"--title=" + convoyTitle,
-		"--description=" + description,
+	if formulaRunTarget != "" {
+		description += fmt.Sprintf("\nTarget: %s", formulaRunTarget)
+	}
+	if line := formatFormulaVars(vars); line != "" && f.Type == formula.TypeWorkflow {
+		// Read back by gt convoy check to evaluate when and until conditions
+		description += "\n" + line
 	}
 
-	createCmd := exec.Command("bd", createArgs...)
-	createCmd.Dir = townBeads
-	createCmd.Stderr = os.Stderr
-	if err := createCmd.Run(); err != nil {
+	if err := bdCreateBead(townBeads, "convoy", convoyID, convoyTitle, description); err != nil {
 		return fmt.Errorf("creating convoy bead: %w", err)
 	}
 
 	fmt.Printf("%s Created convoy: %s\n", style.Bold.Render("✓"), convoyID)
 
-	// Step 2: Create leg beads and track them
-	legBeads := make(map[string]string) // leg.ID -> bead ID
-	for _, leg := range f.Legs {
-		legBeadID := fmt.Sprintf("hq-leg-%s", generateFormulaShortID())
-
-		// Build leg description with prompt if available
-		legDesc := leg.Description
-		if f.Prompts != nil {
-			if basePrompt, ok := f.Prompts["base"]; ok {
-				legDesc = fmt.Sprintf("%s\n\n---\nBase Prompt:\n%s", leg.Description, basePrompt)
-			}
+	// Step 2: Create a bead per step and track it. Workflow steps that
+	// aren't ready yet are created by gt convoy check once their needs are
+	// done and their when condition can be evaluated (see formula_advance.go).
+	stepBeads := make(map[string]string) // step ID -> bead ID
+	waiting := 0
+	for _, step := range steps {
+		if step.Skipped {
+			// Dependents treat a skipped step as satisfied, so no bead
+			fmt.Printf("  %s Skipped %s (when: %s)\n", style.Dim.Render("⊘"), step.ID, step.When)
+			continue
 		}
-
-		legArgs := []string{
-			"create",
-			"--type=task",
-			"--id=" + legBeadID,
-			"--title=" + leg.Title,
-			"--description=" + legDesc,
+		if f.Type == formula.TypeWorkflow && !step.Ready {
+			fmt.Printf("  %s Waiting %s (created by gt convoy check when ready)\n", style.Dim.Render("○"), step.ID)
+			waiting++
+			continue
 		}
 
-		legCmd := exec.Command("bd", legArgs...)
-		legCmd.Dir = townBeads
-		legCmd.Stderr = os.Stderr
-		if err := legCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to create leg bead for %s: %v\n",
-				style.Dim.Render("Warning:"), leg.ID, err)
+		// Steps come in dependency order, so needs already have beads
+		beadID, err := createFormulaStepBead(townBeads, convoyID, f, step, stepBeads)
+		if err != nil {
+			fmt.Printf("%s Failed to create bead for %s: %v\n",
+				style.Dim.Render("Warning:"), step.ID, err)
 			continue
 		}
 
-		// Track the leg with the convoy
-		trackArgs := []string{"dep", "add", convoyID, legBeadID, "--type=tracks"}
-		trackCmd := exec.Command("bd", trackArgs...)
-		trackCmd.Dir = townBeads
-		if err := trackCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to track leg %s: %v\n",
-				style.Dim.Render("Warning:"), leg.ID, err)
-		}
-
-		legBeads[leg.ID] = legBeadID
-		fmt.Printf("  %s Created leg: %s (%s)\n", style.Dim.Render("○"), leg.ID, legBeadID)
-	}
-
-	// Step 3: Create synthesis bead if defined
-	var synthesisBeadID string
-	if f.Synthesis != nil {
-		synthesisBeadID = fmt.Sprintf("hq-syn-%s", generateFormulaShortID())
-
-		synDesc := f.Synthesis.Description
-		if synDesc == "" {
-			synDesc = "Synthesize findings from all legs into unified output"
-		}
-
-		synArgs := []string{
-			"create",
-			"--type=task",
-			"--id=" + synthesisBeadID,
-			"--title=" + f.Synthesis.Title,
-			"--description=" + synDesc,
-		}
-
-		synCmd := exec.Command("bd", synArgs...)
-		synCmd.Dir = townBeads
-		synCmd.Stderr = os.Stderr
-		if err := synCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to create synthesis bead: %v\n",
-				style.Dim.Render("Warning:"), err)
-		} else {
-			// Track synthesis with convoy
-			trackArgs := []string{"dep", "add", convoyID, synthesisBeadID, "--type=tracks"}
-			trackCmd := exec.Command("bd", trackArgs...)
-			trackCmd.Dir = townBeads
-			_ = trackCmd.Run()
-
-			// Add dependencies: synthesis depends on all legs
-			for _, legBeadID := range legBeads {
-				depArgs := []string{"dep", "add", synthesisBeadID, legBeadID}
-				depCmd := exec.Command("bd", depArgs...)
-				depCmd.Dir = townBeads
-				_ = depCmd.Run()
-			}
-
-			fmt.Printf("  %s Created synthesis: %s\n", style.Dim.Render("★"), synthesisBeadID)
+		stepBeads[step.ID] = beadID
+		marker := style.Dim.Render("○")
+		if step.Synthesis {
+			marker = style.Dim.Render("★")
 		}
+		fmt.Printf("  %s Created %s (%s)\n", marker, step.ID, beadID)
 	}
 
-	// Step 4: Sling each leg to a polecat
-	fmt.Printf("\n%s Dispatching legs to polecats...\n\n", style.Bold.Render("→"))
+	// Step 3: Sling each ready step to a polecat
+	fmt.Printf("\n%s Dispatching ready work to polecats...\n\n", style.Bold.Render("→"))
 
-	slingCount := 0
-	for _, leg := range f.Legs {
-		legBeadID, ok := legBeads[leg.ID]
+	slingCount, blocked := 0, 0
+	for _, step := range steps {
+		beadID, ok := stepBeads[step.ID]
 		if !ok {
 			continue
 		}
+		if !step.Ready {
+			blocked++
+			continue
+		}
 
-		// Build context message for the polecat
-		contextMsg := fmt.Sprintf("Convoy leg: %s\nFocus: %s", leg.Title, leg.Focus)
-
-		// Use gt sling with args for leg-specific context
 		slingArgs := []string{
-			"sling", legBeadID, targetRig,
-			"-a", leg.Description,
-			"-s", leg.Title,
+			"sling", beadID, targetRig,
+			"-a", step.Description,
+			"-s", step.Title,
 		}
 
 		slingCmd := exec.Command("gt", slingArgs...)
@@ -423,78 +703,52 @@ func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
 		slingCmd.Stderr = os.Stderr
 
 		if err := slingCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to sling leg %s: %v\n",
-				style.Dim.Render("Warning:"), leg.ID, err)
+			fmt.Printf("%s Failed to sling %s: %v\n",
+				style.Dim.Render("Warning:"), step.ID, err)
 			// Add comment to bead about failure
-			commentArgs := []string{"comment", legBeadID, fmt.Sprintf("Failed to sling: %v", err)}
-			commentCmd := exec.Command("bd", commentArgs...)
+			commentCmd := exec.Command("bd", "comment", beadID, fmt.Sprintf("Failed to sling: %v", err))
 			commentCmd.Dir = townBeads
 			_ = commentCmd.Run()
 			continue
 		}
 
 		slingCount++
-		_ = contextMsg // Used in future for richer context
 	}
 
 	// Summary
 	fmt.Printf("\n%s Convoy dispatched!\n", style.Bold.Render("✓"))
 	fmt.Printf("  Convoy:  %s\n", convoyID)
-	fmt.Printf("  Legs:    %d dispatched\n", slingCount)
-	if synthesisBeadID != "" {
-		fmt.Printf("  Synthesis: %s (blocked until legs complete)\n", synthesisBeadID)
+	fmt.Printf("  %s: %d dispatched", formulaStepNoun(f), slingCount)
+	if blocked > 0 {
+		fmt.Printf(", %d blocked until their dependencies close", blocked)
+	}
+	if waiting > 0 {
+		fmt.Printf(", %d created as they become ready", waiting)
 	}
+	fmt.Println()
 	fmt.Printf("\n  Track progress: gt convoy status %s\n", convoyID)
 
 	return nil
 }
 
-// formulaData holds parsed formula information
-type formulaData struct {
-	Name        string
-	Description string
-	Type        string
-	Legs        []formulaLeg
-	Synthesis   *formulaSynthesis
-	Prompts     map[string]string
-}
-
-type formulaLeg struct {
-	ID          string
-	Title       string
-	Focus       string
-	Description string
-}
-
-type formulaSynthesis struct {
-	Title       string
-	Description string
-	DependsOn   []string
+// bdCreateBead creates a bead of the given type with an explicit ID in the given beads dir.
+func bdCreateBead(beadsDir, issueType, id, title, description string) error {
+	createCmd := exec.Command("bd", "create",
+		"--type="+issueType,
+		"--id="+id,
+		"--title="+title,
+		"--description="+description,
+	)
+	createCmd.Dir = beadsDir
+	createCmd.Stderr = os.Stderr
+	return createCmd.Run()
 }
 
 // findFormulaFile searches for a formula file by name
 func findFormulaFile(name string) (string, error) {
-	// Search paths in order
-	searchPaths := []string{}
-
-	// 1. Project .beads/formulas/
-	if cwd, err := os.Getwd(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(cwd, ".beads", "formulas"))
-	}
-
-	// 2. Town .beads/formulas/
-	if townRoot, err := workspace.FindFromCwd(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(townRoot, ".beads", "formulas"))
-	}
-
-	// 3. User ~/.beads/formulas/
-	if home, err := os.UserHomeDir(); err == nil {
-		searchPaths = append(searchPaths, filepath.Join(home, ".beads", "formulas"))
-	}
-
 	// Try each path with common extensions
 	extensions := []string{".formula.toml", ".formula.json"}
-	for _, basePath := range searchPaths {
+	for _, basePath := range formulaSearchPaths() {
 		for _, ext := range extensions {
 			path := filepath.Join(basePath, name+ext)
 			if _, err := os.Stat(path); err == nil {
@@ -506,178 +760,26 @@ func findFormulaFile(name string) (string, error) {
 	return "", fmt.Errorf("formula '%s' not found in search paths", name)
 }
 
-// parseFormulaFile parses a formula file into formulaData
-func parseFormulaFile(path string) (*formulaData, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	// Use simple TOML parsing for the fields we need
-	// (avoids importing the full formula package which might cause cycles)
-	f := &formulaData{
-		Prompts: make(map[string]string),
-	}
-
-	content := string(data)
-
-	// Parse formula name
-	if match := extractTOMLValue(content, "formula"); match != "" {
-		f.Name = match
-	}
-
-	// Parse description
-	if match := extractTOMLMultiline(content, "description"); match != "" {
-		f.Description = match
-	}
-
-	// Parse type
-	if match := extractTOMLValue(content, "type"); match != "" {
-		f.Type = match
-	}
-
-	// Parse legs (convoy formulas)
-	f.Legs = extractLegs(content)
-
-	// Parse synthesis
-	f.Synthesis = extractSynthesis(content)
-
-	// Parse prompts
-	f.Prompts = extractPrompts(content)
-
-	return f, nil
-}
-
-// extractTOMLValue extracts a simple quoted value from TOML
-func extractTOMLValue(content, key string) string {
-	// Match: key = "value" or key = 'value'
-	for _, line := range strings.Split(content, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, key+" =") || strings.HasPrefix(line, key+"=") {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				val := strings.TrimSpace(parts[1])
-				// Remove quotes
-				if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') {
-					return val[1 : len(val)-1]
-				}
-				return val
-			}
-		}
-	}
-	return ""
-}
-
-// extractTOMLMultiline extracts a multiline string (""" ... """)
-func extractTOMLMultiline(content, key string) string {
-	// Look for key = """
-	keyPattern := key + ` = """`
-	idx := strings.Index(content, keyPattern)
-	if idx == -1 {
-		// Try single-line
-		return extractTOMLValue(content, key)
-	}
-
-	start := idx + len(keyPattern)
-	end := strings.Index(content[start:], `"""`)
-	if end == -1 {
-		return ""
-	}
-
-	return strings.TrimSpace(content[start : start+end])
-}
-
-// extractLegs parses [[legs]] sections from TOML
-func extractLegs(content string) []formulaLeg {
-	var legs []formulaLeg
-
-	// Split by [[legs]]
-	sections := strings.Split(content, "[[legs]]")
-	for i, section := range sections {
-		if i == 0 {
-			continue // Skip content before first [[legs]]
-		}
-
-		// Find where this section ends (next [[ or EOF)
-		endIdx := strings.Index(section, "[[")
-		if endIdx == -1 {
-			endIdx = len(section)
-		}
-		section = section[:endIdx]
-
-		leg := formulaLeg{
-			ID:          extractTOMLValue(section, "id"),
-			Title:       extractTOMLValue(section, "title"),
-			Focus:       extractTOMLValue(section, "focus"),
-			Description: extractTOMLMultiline(section, "description"),
-		}
-
-		if leg.ID != "" {
-			legs = append(legs, leg)
-		}
-	}
-
-	return legs
-}
-
-// extractSynthesis parses [synthesis] section from TOML
-func extractSynthesis(content string) *formulaSynthesis {
-	idx := strings.Index(content, "[synthesis]")
-	if idx == -1 {
-		return nil
-	}
-
-	section := content[idx:]
-	// Find where section ends
-	if endIdx := strings.Index(section[1:], "\n["); endIdx != -1 {
-		section = section[:endIdx+1]
-	}
-
-	syn := &formulaSynthesis{
-		Title:       extractTOMLValue(section, "title"),
-		Description: extractTOMLMultiline(section, "description"),
-	}
-
-	// Parse depends_on array
-	if depsLine := extractTOMLValue(section, "depends_on"); depsLine != "" {
-		// Simple array parsing: ["a", "b", "c"]
-		depsLine = strings.Trim(depsLine, "[]")
-		for _, dep := range strings.Split(depsLine, ",") {
-			dep = strings.Trim(strings.TrimSpace(dep), `"'`)
-			if dep != "" {
-				syn.DependsOn = append(syn.DependsOn, dep)
-			}
-		}
-	}
-
-	if syn.Title == "" && syn.Description == "" {
-		return nil
-	}
-
-	return syn
-}
-
-// extractPrompts parses [prompts] section from TOML
-func extractPrompts(content string) map[string]string {
-	prompts := make(map[string]string)
+// formulaSearchPaths returns the directories searched for formulas, in order.
+func formulaSearchPaths() []string {
+	searchPaths := []string{}
 
-	idx := strings.Index(content, "[prompts]")
-	if idx == -1 {
-		return prompts
+	// 1. Project .beads/formulas/
+	if cwd, err := os.Getwd(); err == nil {
+		searchPaths = append(searchPaths, filepath.Join(cwd, ".beads", "formulas"))
 	}
 
-	section := content[idx:]
-	// Find where section ends
-	if endIdx := strings.Index(section[1:], "\n["); endIdx != -1 {
-		section = section[:endIdx+1]
+	// 2. Town .beads/formulas/
+	if townRoot, err := workspace.FindFromCwd(); err == nil {
+		searchPaths = append(searchPaths, filepath.Join(townRoot, ".beads", "formulas"))
 	}
 
-	// Extract base prompt
-	if base := extractTOMLMultiline(section, "base"); base != "" {
-		prompts["base"] = base
+	// 3. User ~/.beads/formulas/
+	if home, err := os.UserHomeDir(); err == nil {
+		searchPaths = append(searchPaths, filepath.Join(home, ".beads", "formulas"))
 	}
 
-	return prompts
+	return searchPaths
 }
 
 // generateFormulaShortID generates a short random ID (5 lowercase chars)
@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Workflow formula runs.
+//
+// gt formula run creates beads only for the workflow steps that are ready
+// up front. gt convoy check (run by the Deacon patrol) advances each run:
+// it reads the step beads back into a formula.RunState - closed beads are
+// completed, open beads with a recorded failure have failed, and
+// step_output lines are outputs - and acts on formula.Advance. Steps are
+// created and slung once their needs are done and their when condition
+// holds, loop steps are reopened until their until condition holds, and
+// a loop that runs out of iterations is escalated. Retry blocks become
+// the step bead's on_failure retry policy, enforced by gt mol step fail.
+
+// formulaRunAdvance records what gt convoy check did for a formula run.
+type formulaRunAdvance struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Dispatched []string `json:"dispatched,omitempty"`
+	Rerun      []string `json:"rerun,omitempty"`
+	Failed     []string `json:"failed,omitempty"`
+}
+
+// formulaRunMeta is what a formula run's convoy records about the run.
+type formulaRunMeta struct {
+	Path string
+	Rig  string
+	Vars map[string]string
+}
+
+// parseFormulaRunMeta reads the formula lines written by executeFormula
+// from a convoy description. Returns nil for convoys not created by a
+// formula run.
+func parseFormulaRunMeta(description string) *formulaRunMeta {
+	meta := &formulaRunMeta{}
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "formula_path":
+			meta.Path = value
+		case "rig":
+			meta.Rig = value
+		case "formula_vars":
+			_ = json.Unmarshal([]byte(value), &meta.Vars)
+		}
+	}
+	if meta.Path == "" {
+		return nil
+	}
+	return meta
+}
+
+// formatFormulaVars renders run vars as a convoy description line.
+func formatFormulaVars(vars map[string]string) string {
+	if len(vars) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(vars)
+	if err != nil {
+		return ""
+	}
+	return "formula_vars: " + string(data)
+}
+
+// formulaStepPolicy returns the failure policy tracked on a step's bead,
+// or nil if it has none. A retry block is an on_failure retry policy with
+// its max as the attempt limit.
+func formulaStepPolicy(step formula.PlannedStep) *beads.StepPolicyFields {
+	policy := &beads.StepPolicyFields{
+		Timeout:     step.Timeout,
+		MaxAttempts: step.MaxAttempts,
+		OnFailure:   step.OnFailure,
+	}
+	if step.Retry != nil {
+		policy.OnFailure = beads.OnFailureRetry
+		policy.MaxAttempts = step.Retry.Max
+	}
+	if policy.Timeout == "" && policy.OnFailure == "" {
+		return nil
+	}
+	return policy
+}
+
+// formulaStepDescription builds the description of a step's bead: the
+// step's instructions, its control flow, the formula step it runs and its
+// failure policy.
+func formulaStepDescription(f *formula.Formula, step formula.PlannedStep) string {
+	desc := step.Description
+	if flow := step.Flow(); len(flow) > 0 {
+		desc += "\n\n---\nControl flow:\n" + strings.Join(flow, "\n")
+	}
+	if f.Type != formula.TypeWorkflow {
+		return desc
+	}
+	if step.Loop != nil || f.OutputRead(step.ID) {
+		desc += "\n\nLater steps read this step's output: finish with\n  gt mol step done <this bead> --output \"<result>\""
+	}
+	issue := &beads.Issue{Description: desc}
+	issue.Description = beads.SetFormulaStepFields(issue, &beads.FormulaStepFields{Step: step.ID, Iteration: 1})
+	if policy := formulaStepPolicy(step); policy != nil {
+		// Tracked on the bead; enforced by gt mol step check and fail
+		issue.Description = beads.SetStepPolicyFields(issue, policy)
+	}
+	return issue.Description
+}
+
+// formulaRunState reads a workflow run's progress from its step beads,
+// keyed by formula step ID.
+func formulaRunState(vars map[string]string, stepBeads map[string]*beads.Issue) *formula.RunState {
+	state := &formula.RunState{
+		Vars:      vars,
+		Completed: make(map[string]bool),
+		Failed:    make(map[string]bool),
+		Outputs:   make(map[string]string),
+		Attempts:  make(map[string]int),
+	}
+	for id, issue := range stepBeads {
+		fields := beads.ParseFormulaStepFields(issue)
+		policy := beads.ParseStepPolicyFields(issue)
+		if policy == nil {
+			policy = &beads.StepPolicyFields{}
+		}
+
+		switch {
+		case issueLanded(issue.Status):
+			state.Completed[id] = true
+		case policy.LastFailure != "":
+			// Reopened for a retry, or escalated once attempts ran out
+			state.Failed[id] = true
+		}
+		if fields != nil {
+			state.Outputs[id] = fields.Output
+			state.Attempts[id] = fields.Iteration
+		}
+		if policy.Attempts > state.Attempts[id] {
+			state.Attempts[id] = policy.Attempts
+		}
+	}
+	return state
+}
+
+// advanceFormulaRuns advances every open workflow formula run: it creates
+// and slings steps that became ready, reruns loop steps whose until
+// condition doesn't hold yet, and escalates loops that ran out of
+// iterations.
+func advanceFormulaRuns(townBeads string) ([]formulaRunAdvance, error) {
+	b := beads.New(townBeads)
+	convoys, err := b.List(beads.ListOptions{Type: "convoy", Status: "open", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
+	}
+
+	var advances []formulaRunAdvance
+	for _, convoy := range convoys {
+		meta := parseFormulaRunMeta(convoy.Description)
+		if meta == nil {
+			continue
+		}
+		adv, err := advanceFormulaRun(b, townBeads, convoy, meta)
+		if err != nil {
+			style.PrintWarning("couldn't advance formula run %s: %v", convoy.ID, err)
+			continue
+		}
+		if len(adv.Dispatched)+len(adv.Rerun)+len(adv.Failed) > 0 {
+			advances = append(advances, *adv)
+		}
+	}
+	return advances, nil
+}
+
+// advanceFormulaRun advances one formula run's convoy.
+func advanceFormulaRun(b *beads.Beads, townBeads string, convoy *beads.Issue, meta *formulaRunMeta) (*formulaRunAdvance, error) {
+	adv := &formulaRunAdvance{ID: convoy.ID, Title: convoy.Title}
+
+	f, err := formula.ParseFile(meta.Path)
+	if err != nil {
+		return nil, err
+	}
+	if f, err = f.Resolve(loadFormulaByName); err != nil {
+		return nil, err
+	}
+	if f.Type != formula.TypeWorkflow {
+		return adv, nil // legs and templates are all created up front
+	}
+	expanded, err := f.ExpandForEach(meta.Vars)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := f.Plan(meta.Vars, nil)
+	if err != nil {
+		return nil, err
+	}
+	planned := make(map[string]formula.PlannedStep, len(plan))
+	for _, step := range plan {
+		planned[step.ID] = step
+	}
+
+	stepBeads := make(map[string]*beads.Issue)
+	beadIDs := make(map[string]string)
+	dispatched := make(map[string]bool)
+	for _, t := range getTrackedIssues(townBeads, convoy.ID) {
+		issue, err := b.Show(t.ID)
+		if err != nil {
+			continue
+		}
+		if fields := beads.ParseFormulaStepFields(issue); fields != nil {
+			stepBeads[fields.Step] = issue
+			beadIDs[fields.Step] = issue.ID
+			dispatched[fields.Step] = true
+		}
+	}
+
+	state := formulaRunState(meta.Vars, stepBeads)
+	actions := expanded.Advance(state, dispatched)
+	townRoot := filepath.Dir(townBeads)
+	for _, step := range expanded.Steps {
+		switch actions[step.ID] {
+		case formula.ActionDispatch:
+			beadID, err := createFormulaStepBead(townBeads, convoy.ID, f, planned[step.ID], beadIDs)
+			if err != nil {
+				style.PrintWarning("couldn't create step %s of %s: %v", step.ID, convoy.ID, err)
+				continue
+			}
+			beadIDs[step.ID] = beadID
+			if err := slingConvoyIssue(townRoot, beadID, meta.Rig); err != nil {
+				style.PrintWarning("couldn't sling %s for %s: %v", beadID, convoy.ID, err)
+			}
+			adv.Dispatched = append(adv.Dispatched, step.ID)
+
+		case formula.ActionRerun:
+			if err := rerunFormulaStep(b, stepBeads[step.ID]); err != nil {
+				style.PrintWarning("couldn't rerun %s of %s: %v", step.ID, convoy.ID, err)
+				continue
+			}
+			if err := slingConvoyIssue(townRoot, beadIDs[step.ID], meta.Rig); err != nil {
+				style.PrintWarning("couldn't sling %s for %s: %v", beadIDs[step.ID], convoy.ID, err)
+			}
+			adv.Rerun = append(adv.Rerun, step.ID)
+
+		case formula.ActionFail:
+			reason := fmt.Sprintf("loop ran out of iterations (%d) before %s held", step.Loop.Max, step.Loop.Until)
+			reported, err := failFormulaStep(b, stepBeads[step.ID], reason)
+			if err != nil {
+				style.PrintWarning("couldn't fail %s of %s: %v", step.ID, convoy.ID, err)
+				continue
+			}
+			if reported {
+				adv.Failed = append(adv.Failed, step.ID)
+			}
+		}
+	}
+	return adv, nil
+}
+
+// createFormulaStepBead creates a step's bead, tracks it with the run's
+// convoy and makes it depend on the beads of its needs. beadIDs maps step
+// IDs to the beads created so far.
+func createFormulaStepBead(townBeads, convoyID string, f *formula.Formula, step formula.PlannedStep, beadIDs map[string]string) (string, error) {
+	prefix := "hq-step-"
+	switch {
+	case step.Synthesis:
+		prefix = "hq-syn-"
+	case f.Type == formula.TypeConvoy:
+		prefix = "hq-leg-"
+	}
+	beadID := prefix + generateFormulaShortID()
+
+	if err := bdCreateBead(townBeads, "task", beadID, step.Title, formulaStepDescription(f, step)); err != nil {
+		return "", err
+	}
+
+	// Track the step with the convoy
+	trackCmd := exec.Command("bd", "dep", "add", convoyID, beadID, "--type=tracks")
+	trackCmd.Dir = townBeads
+	if err := trackCmd.Run(); err != nil {
+		style.PrintWarning("failed to track %s: %v", step.ID, err)
+	}
+
+	for _, need := range step.Needs {
+		needBead, ok := beadIDs[need]
+		if !ok {
+			continue // skipped, so satisfied
+		}
+		depCmd := exec.Command("bd", "dep", "add", beadID, needBead)
+		depCmd.Dir = townBeads
+		if err := depCmd.Run(); err != nil {
+			style.PrintWarning("failed to add dependency %s -> %s: %v", step.ID, need, err)
+		}
+	}
+	return beadID, nil
+}
+
+// rerunFormulaStep reopens a finished loop step for its next iteration,
+// clearing the last iteration's output.
+func rerunFormulaStep(b *beads.Beads, issue *beads.Issue) error {
+	fields := beads.ParseFormulaStepFields(issue)
+	if fields.Iteration < 1 {
+		fields.Iteration = 1
+	}
+	fields.Iteration++
+	fields.Output = ""
+	desc := beads.SetFormulaStepFields(issue, fields)
+	status := "open"
+	return b.Update(issue.ID, beads.UpdateOptions{Status: &status, Description: &desc})
+}
+
+// failFormulaStep marks a finished loop step as failed and escalates it.
+// The bead is reopened so the run stays open until the overseer resolves
+// it; a step the overseer closes again after escalation is left alone.
+// reported is false in that case.
+func failFormulaStep(b *beads.Beads, issue *beads.Issue, reason string) (reported bool, err error) {
+	if policy := beads.ParseStepPolicyFields(issue); policy != nil && policy.EscalatedAt != "" {
+		return false, nil
+	}
+	status := "open"
+	if err := b.Update(issue.ID, beads.UpdateOptions{Status: &status}); err != nil {
+		return false, fmt.Errorf("reopening step: %w", err)
+	}
+	issue.Status = status
+	if _, err := applyStepFailure(b, issue, reason, false); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// closeFormulaRunStep closes a step of a workflow formula run for gt mol
+// step done. gt convoy check dispatches whatever it unblocks.
+func closeFormulaRunStep(b *beads.Beads, step *beads.Issue, dryRun bool) error {
+	if dryRun {
+		fmt.Printf("[dry-run] Would close step: %s\n", step.ID)
+		return nil
+	}
+	if err := b.Close(step.ID); err != nil {
+		return fmt.Errorf("closing step: %w", err)
+	}
+	fmt.Printf("%s Closed step %s: %s\n", style.Bold.Render("✓"), step.ID, step.Title)
+	fmt.Printf("%s\n", style.Dim.Render("The formula run advances on the next gt convoy check."))
+	return nil
+}
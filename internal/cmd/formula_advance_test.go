@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+const advanceFormula = `
+formula = "ship"
+type = "workflow"
+
+[[steps]]
+id = "build"
+
+[[steps]]
+id = "deploy"
+needs = ["build"]
+[steps.retry]
+max = 2
+
+[[steps]]
+id = "announce"
+needs = ["deploy"]
+`
+
+// stepBead builds the bead gt convoy check would have created for a step.
+func stepBead(t *testing.T, f *formula.Formula, id, status string) *beads.Issue {
+	t.Helper()
+	plan, err := f.Plan(nil, nil)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	for _, step := range plan {
+		if step.ID == id {
+			return &beads.Issue{ID: "hq-step-" + id, Status: status, Description: formulaStepDescription(f, step)}
+		}
+	}
+	t.Fatalf("no planned step %q", id)
+	return nil
+}
+
+// failStep records a failed attempt on a step bead the way gt mol step
+// fail does: the attempt is counted and the bead stays open.
+func failStep(issue *beads.Issue, attempts int) {
+	policy := beads.ParseStepPolicyFields(issue)
+	policy.Attempts = attempts
+	policy.LastFailure = "deploy target unreachable"
+	issue.Status = "open"
+	issue.Description = beads.SetStepPolicyFields(issue, policy)
+}
+
+func TestFormulaRunState_FailedStepRetries(t *testing.T) {
+	f, err := formula.Parse([]byte(advanceFormula))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	deploy := stepBead(t, f, "deploy", "in_progress")
+	stepBeads := map[string]*beads.Issue{
+		"build":  stepBead(t, f, "build", "closed"),
+		"deploy": deploy,
+	}
+	dispatched := map[string]bool{"build": true, "deploy": true}
+
+	// First attempt failed: the retry policy reopened the bead, so the
+	// step is ready again and nothing new is dispatched.
+	failStep(deploy, 1)
+	state := formulaRunState(nil, stepBeads)
+	if !state.Failed["deploy"] || state.Attempts["deploy"] != 1 {
+		t.Fatalf("state = %+v, want deploy failed once", state)
+	}
+	if s := f.StepStates(state)["deploy"]; s != formula.StepReady {
+		t.Errorf("deploy = %q after one failure, want ready", s)
+	}
+	if actions := f.Advance(state, dispatched); len(actions) != 0 {
+		t.Errorf("Advance = %v, want no actions while deploy retries", actions)
+	}
+
+	// Second attempt failed: out of retries, announce never starts
+	failStep(deploy, 2)
+	state = formulaRunState(nil, stepBeads)
+	states := f.StepStates(state)
+	if states["deploy"] != formula.StepFailed || states["announce"] != formula.StepBlocked {
+		t.Errorf("states = %v, want deploy failed, announce blocked", states)
+	}
+
+	// The retry succeeds: announce is dispatched
+	deploy.Status = "closed"
+	state = formulaRunState(nil, stepBeads)
+	actions := f.Advance(state, dispatched)
+	if len(actions) != 1 || actions["announce"] != formula.ActionDispatch {
+		t.Errorf("Advance = %v, want announce dispatched", actions)
+	}
+}
+
+func TestFormulaStepDescription_RetryPolicy(t *testing.T) {
+	f, err := formula.Parse([]byte(advanceFormula))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	deploy := stepBead(t, f, "deploy", "open")
+
+	fields := beads.ParseFormulaStepFields(deploy)
+	if fields == nil || fields.Step != "deploy" || fields.Iteration != 1 {
+		t.Errorf("formula step fields = %+v", fields)
+	}
+	policy := beads.ParseStepPolicyFields(deploy)
+	if policy == nil || policy.OnFailure != beads.OnFailureRetry || policy.MaxAttempts != 2 {
+		t.Errorf("policy = %+v, want on_failure retry with 2 attempts", policy)
+	}
+
+	if policy := beads.ParseStepPolicyFields(stepBead(t, f, "build", "open")); policy != nil {
+		t.Errorf("build has no policy, got %+v", policy)
+	}
+}
+
+func TestParseFormulaRunMeta(t *testing.T) {
+	desc := strings.Join([]string{
+		"Formula run: ship",
+		"formula: ship",
+		"formula_path: /town/.beads/formulas/ship.formula.toml",
+		"rig: gastown",
+		formatFormulaVars(map[string]string{"env": "staging"}),
+	}, "\n")
+
+	meta := parseFormulaRunMeta(desc)
+	if meta == nil {
+		t.Fatal("parseFormulaRunMeta returned nil")
+	}
+	if meta.Path != "/town/.beads/formulas/ship.formula.toml" || meta.Rig != "gastown" || meta.Vars["env"] != "staging" {
+		t.Errorf("meta = %+v", meta)
+	}
+
+	if parseFormulaRunMeta("Convoy tracking 3 issues") != nil {
+		t.Error("expected nil for a convoy without a formula run")
+	}
+}
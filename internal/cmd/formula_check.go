@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	formulaLintAll    bool
+	formulaLintJSON   bool
+	formulaLintStrict bool
+
+	formulaTestVars   []string
+	formulaTestTarget string
+	formulaTestGolden string
+	formulaTestUpdate bool
+)
+
+var formulaLintCmd = &cobra.Command{
+	Use:   "lint [name|file...]",
+	Short: "Check formulas for likely mistakes",
+	Long: `Check formulas for mistakes that parse but won't run as intended.
+
+Formulas are parsed and resolved (imports and composition applied), then
+checked for:
+
+  undefined-var     {{name}} used but not declared in [vars] or [inputs]
+  unused-var        var or input declared but never referenced
+  unreachable-step  own when condition always false (dependents still run)
+  synthesis-deps    convoy leg missing from synthesis depends_on
+  long-prompt       step prompt over 6000 characters
+
+A formula that fails to parse or resolve is reported as an error. The
+command exits non-zero when there are errors, or with --strict when there
+are warnings, so it can gate CI.
+
+Arguments are formula names (looked up in the search paths) or files.
+
+Examples:
+  gt formula lint shiny
+  gt formula lint --all --strict
+  gt formula lint .beads/formulas/release.formula.toml --json`,
+	SilenceUsage: true,
+	RunE:         runFormulaLint,
+}
+
+var formulaTestCmd = &cobra.Command{
+	Use:   "test <name|file>",
+	Short: "Render a formula against a golden file",
+	Long: `Plan a formula for the given inputs without creating any beads, and
+compare the rendering with a golden file.
+
+The rendering lists the vars used, the simulated execution order (steps
+grouped into waves that can run in parallel, skipped steps last) and every
+step's title, needs, control flow and description with vars expanded.
+Vars not given with --var take their declared defaults; input env vars
+are ignored so the rendering doesn't depend on the environment.
+
+The golden file defaults to testdata/<name>.golden next to the formula.
+Use --update to write it after reviewing a change. Expansion formulas are
+rendered against a stand-in target (--target sets its ID) so the test
+needs no beads database.
+
+Examples:
+  gt formula test release --var version=1.2.0 --update
+  gt formula test release --var version=1.2.0
+  gt formula test rule-of-five --target gt-abc12 --golden testdata/r5.golden`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runFormulaTest,
+}
+
+func init() {
+	formulaCmd.AddCommand(formulaLintCmd)
+	formulaCmd.AddCommand(formulaTestCmd)
+
+	formulaLintCmd.Flags().BoolVar(&formulaLintAll, "all", false, "Lint every formula in the search paths")
+	formulaLintCmd.Flags().BoolVar(&formulaLintJSON, "json", false, "Output as JSON")
+	formulaLintCmd.Flags().BoolVar(&formulaLintStrict, "strict", false, "Fail on warnings as well as errors")
+
+	formulaTestCmd.Flags().StringArrayVar(&formulaTestVars, "var", nil, "Formula variable as key=value (can be used multiple times)")
+	formulaTestCmd.Flags().StringVar(&formulaTestTarget, "target", "gt-target", "Stand-in target bead ID for expansion formulas")
+	formulaTestCmd.Flags().StringVar(&formulaTestGolden, "golden", "", "Golden file (default: testdata/<name>.golden next to the formula)")
+	formulaTestCmd.Flags().BoolVar(&formulaTestUpdate, "update", false, "Write the golden file instead of comparing")
+}
+
+// formulaLintResult is the lint output for one formula file.
+type formulaLintResult struct {
+	Formula string              `json:"formula"`
+	Path    string              `json:"path"`
+	Issues  []formula.LintIssue `json:"issues"`
+}
+
+func runFormulaLint(cmd *cobra.Command, args []string) error {
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		path, err := findFormulaArg(arg)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+	}
+	if formulaLintAll {
+		paths = append(paths, allFormulaFiles()...)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("specify formulas to lint, or --all")
+	}
+
+	var results []formulaLintResult
+	var all []formula.LintIssue
+	for _, path := range paths {
+		result := lintFormulaFile(path)
+		results = append(results, result)
+		all = append(all, result.Issues...)
+	}
+
+	if formulaLintJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, r := range results {
+			if len(r.Issues) == 0 {
+				fmt.Printf("%s %s\n", style.SuccessPrefix, r.Formula)
+				continue
+			}
+			fmt.Printf("%s %s %s\n", style.Warning.Render("⚠"), style.Bold.Render(r.Formula), style.Dim.Render(r.Path))
+			for _, issue := range r.Issues {
+				fmt.Printf("    %s\n", issue)
+			}
+		}
+		noun := "formulas"
+		if len(results) == 1 {
+			noun = "formula"
+		}
+		fmt.Printf("\n%d %s: %s\n", len(results), noun, formula.LintSummary(all))
+	}
+
+	if formula.HasErrors(all) || (formulaLintStrict && len(all) > 0) {
+		return fmt.Errorf("lint failed: %s", formula.LintSummary(all))
+	}
+	return nil
+}
+
+// lintFormulaFile parses, resolves and lints one formula file. Parse and
+// resolve failures are reported as an "invalid" lint error.
+func lintFormulaFile(path string) formulaLintResult {
+	name := formulaNameFromPath(path)
+	invalid := func(err error) formulaLintResult {
+		return formulaLintResult{Formula: name, Path: path, Issues: []formula.LintIssue{
+			{Severity: formula.LintError, Rule: "invalid", Message: err.Error()},
+		}}
+	}
+	f, err := formula.ParseFile(path)
+	if err != nil {
+		return invalid(err)
+	}
+	if f.Name != "" {
+		name = f.Name
+	}
+	f, err = f.Resolve(formulaFileLoader(path))
+	if err != nil {
+		return invalid(err)
+	}
+	return formulaLintResult{Formula: name, Path: path, Issues: f.Lint()}
+}
+
+func runFormulaTest(cmd *cobra.Command, args []string) error {
+	path, err := findFormulaArg(args[0])
+	if err != nil {
+		return err
+	}
+	f, err := formula.ParseFile(path)
+	if err != nil {
+		return fmt.Errorf("parsing formula: %w", err)
+	}
+	f, err = f.Resolve(formulaFileLoader(path))
+	if err != nil {
+		return fmt.Errorf("resolving formula: %w", err)
+	}
+
+	vars, err := parseFormulaVars(formulaTestVars)
+	if err != nil {
+		return err
+	}
+	// Declared defaults only: golden files mustn't depend on the environment
+	vars, err = f.ResolveDeclaredVars(vars)
+	if err != nil {
+		return fmt.Errorf("formula %s: %w", f.Name, err)
+	}
+	if err := f.CheckInputs(vars, nil); err != nil {
+		return fmt.Errorf("formula %s: %w", f.Name, err)
+	}
+
+	var target *formula.Target
+	if f.Type == formula.TypeExpansion {
+		target = &formula.Target{
+			ID:          formulaTestTarget,
+			Title:       "Title of " + formulaTestTarget,
+			Description: "Description of " + formulaTestTarget,
+		}
+	}
+	steps, err := f.Plan(vars, target)
+	if err != nil {
+		return err
+	}
+	got := f.RenderPlan(vars, steps)
+
+	golden := formulaTestGolden
+	if golden == "" {
+		golden = filepath.Join(filepath.Dir(path), "testdata", f.Name+".golden")
+	}
+
+	if formulaTestUpdate {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			return fmt.Errorf("creating golden dir: %w", err)
+		}
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			return fmt.Errorf("writing golden file: %w", err)
+		}
+		fmt.Printf("%s Wrote %s (%d steps)\n", style.SuccessPrefix, golden, len(steps))
+		return nil
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("golden file %s not found (run with --update to create it)", golden)
+		}
+		return fmt.Errorf("reading golden file: %w", err)
+	}
+	if string(want) == got {
+		fmt.Printf("%s %s matches %s\n", style.SuccessPrefix, f.Name, golden)
+		return nil
+	}
+
+	fmt.Printf("%s %s differs from %s\n", style.ErrorPrefix, f.Name, golden)
+	line, wantLine, gotLine := firstDiffLine(string(want), got)
+	fmt.Printf("  line %d:\n", line)
+	fmt.Printf("    want: %s\n", wantLine)
+	fmt.Printf("    got:  %s\n", gotLine)
+	fmt.Printf("\n  %s\n", style.Dim.Render("Review the change, then run with --update to accept it."))
+	return fmt.Errorf("%s does not match its golden file", f.Name)
+}
+
+// firstDiffLine returns the 1-based number of the first line where want
+// and got differ, with both versions of it.
+func firstDiffLine(want, got string) (int, string, string) {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		w, g := "<end of file>", "<end of file>"
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return i + 1, w, g
+		}
+	}
+	return 0, "", ""
+}
+
+// findFormulaArg resolves a formula argument: an existing file, or a name
+// looked up in the search paths.
+func findFormulaArg(arg string) (string, error) {
+	if strings.HasSuffix(arg, ".toml") || strings.HasSuffix(arg, ".json") {
+		if _, err := os.Stat(arg); err != nil {
+			return "", fmt.Errorf("formula file %s: %w", arg, err)
+		}
+		return arg, nil
+	}
+	path, err := findFormulaFile(arg)
+	if err != nil {
+		return "", fmt.Errorf("finding formula: %w", err)
+	}
+	return path, nil
+}
+
+// formulaFileLoader loads the imports of the formula at path from its own
+// directory first, then the search paths, so a checkout can be linted and
+// tested from anywhere.
+func formulaFileLoader(path string) formula.Loader {
+	return formula.DirLoader(append([]string{filepath.Dir(path)}, formulaSearchPaths()...)...)
+}
+
+// allFormulaFiles returns every TOML formula file in the search paths. A
+// name found in more than one path is taken from the first, as
+// findFormulaFile would. JSON formulas are bd's to parse and are skipped.
+func allFormulaFiles() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, dir := range formulaSearchPaths() {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.formula.toml"))
+		sort.Strings(matches)
+		for _, path := range matches {
+			name := formulaNameFromPath(path)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// formulaNameFromPath strips the directory and formula extension.
+func formulaNameFromPath(path string) string {
+	base := filepath.Base(path)
+	for _, ext := range []string{".formula.toml", ".formula.json", ".toml", ".json"} {
+		if strings.HasSuffix(base, ext) {
+			return strings.TrimSuffix(base, ext)
+		}
+	}
+	return base
+}
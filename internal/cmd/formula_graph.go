@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+var formulaGraphFormat string
+
+var formulaGraphCmd = &cobra.Command{
+	Use:   "graph <name|file>",
+	Short: "Show a formula's steps as a dependency graph",
+	Long: `Render a formula's steps as a DAG, after imports and composition are
+resolved. Nothing is poured, so steps have no state; use 'gt mol graph' to
+see the progress of a poured molecule.
+
+Workflow steps and expansion templates are linked by their needs. Convoy
+legs run in parallel and feed the synthesis step.
+
+Formats:
+  ascii    Steps grouped into waves for the terminal (default)
+  dot      Graphviz DOT: gt formula graph shiny --format dot | dot -Tsvg > shiny.svg
+  mermaid  Mermaid flowchart, for markdown and docs
+
+Examples:
+  gt formula graph shiny
+  gt formula graph code-review --format mermaid`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runFormulaGraph,
+}
+
+func init() {
+	formulaGraphCmd.Flags().StringVarP(&formulaGraphFormat, "format", "f", graph.FormatASCII, "Output format: ascii, dot or mermaid")
+	formulaCmd.AddCommand(formulaGraphCmd)
+}
+
+func runFormulaGraph(cmd *cobra.Command, args []string) error {
+	path, err := findFormulaArg(args[0])
+	if err != nil {
+		return err
+	}
+	f, err := formula.ParseFile(path)
+	if err != nil {
+		return fmt.Errorf("parsing formula: %w", err)
+	}
+	f, err = f.Resolve(formulaFileLoader(path))
+	if err != nil {
+		return fmt.Errorf("resolving formula: %w", err)
+	}
+
+	out, err := graph.Render(formulaGraph(f), formulaGraphFormat)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// formulaGraph builds the step graph of a resolved formula.
+func formulaGraph(f *formula.Formula) *graph.Graph {
+	g := &graph.Graph{Name: fmt.Sprintf("%s (%s)", f.Name, f.Type)}
+	add := func(id, title string, needs []string) {
+		if title == "" {
+			title = id
+		}
+		g.Nodes = append(g.Nodes, graph.Node{ID: id, Label: title, Needs: needs})
+	}
+
+	switch f.Type {
+	case formula.TypeWorkflow:
+		for _, step := range f.Steps {
+			add(step.ID, step.Title, step.Needs)
+		}
+	case formula.TypeExpansion:
+		for _, tmpl := range f.Template {
+			add(tmpl.ID, tmpl.Title, tmpl.Needs)
+		}
+	case formula.TypeConvoy:
+		for _, leg := range f.Legs {
+			add(leg.ID, leg.Title, nil)
+		}
+		if f.Synthesis != nil {
+			needs := f.Synthesis.DependsOn
+			if len(needs) == 0 {
+				for _, leg := range f.Legs {
+					needs = append(needs, leg.ID)
+				}
+			}
+			add(formula.SynthesisID, f.Synthesis.Title, needs)
+		}
+	case formula.TypeAspect:
+		for _, a := range f.Aspects {
+			add(a.ID, a.Title, nil)
+		}
+	}
+	return g
+}
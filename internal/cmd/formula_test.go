@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestEncodeResolvedFormula(t *testing.T) {
+	f, err := formula.Parse([]byte(`
+formula = "ship"
+type = "workflow"
+
+[[steps]]
+id = "build"
+
+[[steps]]
+id = "deploy"
+needs = ["build"]
+max_attempts = 3
+on_failure = "retry"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	out, err := encodeResolvedFormula(f, false)
+	if err != nil {
+		t.Fatalf("encodeResolvedFormula: %v", err)
+	}
+	if n := strings.Count(out, "max_attempts"); n != 1 {
+		t.Errorf("max_attempts appears %d times, want once (only deploy sets it):\n%s", n, out)
+	}
+
+	// The output parses back to the same policy
+	again, err := formula.Parse([]byte(out))
+	if err != nil {
+		t.Fatalf("resolved output doesn't parse: %v\n%s", err, out)
+	}
+	if s := again.GetStep("deploy"); s == nil || s.MaxAttempts != 3 || s.OnFailure != "retry" {
+		t.Errorf("deploy after round trip = %+v", s)
+	}
+
+	js, err := encodeResolvedFormula(f, true)
+	if err != nil {
+		t.Fatalf("encodeResolvedFormula json: %v", err)
+	}
+	if strings.Count(js, "max_attempts") != 1 {
+		t.Errorf("JSON output:\n%s", js)
+	}
+}
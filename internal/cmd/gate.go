@@ -31,6 +31,8 @@ Most gate commands are in beads:
   bd gate eval     - Evaluate and close elapsed gates
 
 The gt gate command provides Gas Town integration:
+  gt gate create   - Create a gate the daemon opens (timer, bead, command, mrqueue)
+  gt gate eval     - Open gates whose condition holds and wake their waiters
   gt gate wake     - Send wake mail to gate waiters after close`,
 }
 
@@ -81,24 +83,37 @@ type GateWakeResult struct {
 	Failed      []string `json:"failed,omitempty"`
 }
 
-func runGateWake(cmd *cobra.Command, args []string) error {
-	gateID := args[0]
+// gateShowInfo is the part of bd gate show output used by gt.
+type gateShowInfo struct {
+	ID          string   `json:"id"`
+	Status      string   `json:"status"`
+	CloseReason string   `json:"close_reason"`
+	Waiters     []string `json:"waiters"`
+}
 
-	// Get gate info
+// showGate runs bd gate show in dir (the current directory if empty).
+func showGate(dir, gateID string) (*gateShowInfo, error) {
 	gateCheck := exec.Command("bd", "gate", "show", gateID, "--json")
+	gateCheck.Dir = dir
 	gateOutput, err := gateCheck.Output()
 	if err != nil {
-		return fmt.Errorf("gate '%s' not found or not accessible", gateID)
+		return nil, fmt.Errorf("gate '%s' not found or not accessible", gateID)
 	}
 
-	var gateInfo struct {
-		ID          string   `json:"id"`
-		Status      string   `json:"status"`
-		CloseReason string   `json:"close_reason"`
-		Waiters     []string `json:"waiters"`
-	}
+	var gateInfo gateShowInfo
 	if err := json.Unmarshal(gateOutput, &gateInfo); err != nil {
-		return fmt.Errorf("parsing gate info: %w", err)
+		return nil, fmt.Errorf("parsing gate info: %w", err)
+	}
+	return &gateInfo, nil
+}
+
+func runGateWake(cmd *cobra.Command, args []string) error {
+	gateID := args[0]
+
+	// Get gate info
+	gateInfo, err := showGate("", gateID)
+	if err != nil {
+		return err
 	}
 
 	if gateInfo.Status != "closed" {
@@ -133,21 +148,40 @@ func runGateWake(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding town root: %w", err)
 	}
 
+	result := sendGateWake(townRoot, gateID, gateInfo.CloseReason, gateInfo.Waiters)
+
+	if gateWakeJSON {
+		return outputGateWakeResult(result)
+	}
+
+	fmt.Printf("%s Sent wake mail for gate %s\n", style.Bold.Render("🚦"), gateID)
+	if len(result.Notified) > 0 {
+		fmt.Printf("  Notified: %v\n", result.Notified)
+	}
+	if len(result.Failed) > 0 {
+		fmt.Printf("  Failed: %v\n", result.Failed)
+	}
+
+	return nil
+}
+
+// sendGateWake mails each waiter on a closed gate to resume its work.
+func sendGateWake(townRoot, gateID, closeReason string, waiters []string) GateWakeResult {
 	router := mail.NewRouter(townRoot)
 
 	result := GateWakeResult{
 		GateID:      gateID,
-		CloseReason: gateInfo.CloseReason,
-		Waiters:     gateInfo.Waiters,
+		CloseReason: closeReason,
+		Waiters:     waiters,
 		Notified:    []string{},
 		Failed:      []string{},
 	}
 
 	subject := fmt.Sprintf("🚦 GATE CLEARED: %s", gateID)
 	body := fmt.Sprintf("Gate %s has closed.\n\nReason: %s\n\nRun 'gt resume' to continue your parked work.",
-		gateID, gateInfo.CloseReason)
+		gateID, closeReason)
 
-	for _, waiter := range gateInfo.Waiters {
+	for _, waiter := range waiters {
 		msg := &mail.Message{
 			From:     "deacon/",
 			To:       waiter,
@@ -163,20 +197,7 @@ func runGateWake(cmd *cobra.Command, args []string) error {
 			result.Notified = append(result.Notified, waiter)
 		}
 	}
-
-	if gateWakeJSON {
-		return outputGateWakeResult(result)
-	}
-
-	fmt.Printf("%s Sent wake mail for gate %s\n", style.Bold.Render("🚦"), gateID)
-	if len(result.Notified) > 0 {
-		fmt.Printf("  Notified: %v\n", result.Notified)
-	}
-	if len(result.Failed) > 0 {
-		fmt.Printf("  Failed: %v\n", result.Failed)
-	}
-
-	return nil
+	return result
 }
 
 func outputGateWakeResult(result GateWakeResult) error {
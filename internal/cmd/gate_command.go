@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Command gates run a shell command from the daemon, so gt gate eval only
+// runs the ones created in this town by gt gate create or gt park. Those
+// record the gate ID and a hash of its command and directory in the town's
+// command gate registry; a gate that isn't registered, whose command or
+// directory changed since, or whose directory is outside the town is
+// refused and reported as an error.
+
+// commandGatesFile is the command gate registry, in the town's runtime
+// directory.
+const commandGatesFile = "command-gates.json"
+
+// commandGatesPath returns the command gate registry path for a town.
+func commandGatesPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, commandGatesFile)
+}
+
+// commandGateHash identifies a command gate's command and directory.
+func commandGateHash(f *beads.GateFields) string {
+	sum := sha256.Sum256([]byte(f.Command + "\x00" + f.Dir))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadCommandGates reads the registry: gate ID to command hash.
+func loadCommandGates(townRoot string) (map[string]string, error) {
+	gates := make(map[string]string)
+	data, err := os.ReadFile(commandGatesPath(townRoot)) //nolint:gosec // G304: path is constructed from trusted town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return gates, nil
+		}
+		return nil, fmt.Errorf("reading command gate registry: %w", err)
+	}
+	if err := json.Unmarshal(data, &gates); err != nil {
+		return nil, fmt.Errorf("parsing command gate registry: %w", err)
+	}
+	return gates, nil
+}
+
+// saveCommandGates writes the registry atomically (temp file, then rename).
+func saveCommandGates(townRoot string, gates map[string]string) error {
+	path := commandGatesPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	data, err := json.MarshalIndent(gates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling command gate registry: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("writing command gate registry: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("writing command gate registry: %w", err)
+	}
+	return nil
+}
+
+// registerCommandGate records a command gate created in this town.
+func registerCommandGate(townRoot, gateID string, f *beads.GateFields) error {
+	gates, err := loadCommandGates(townRoot)
+	if err != nil {
+		return err
+	}
+	gates[gateID] = commandGateHash(f)
+	return saveCommandGates(townRoot, gates)
+}
+
+// unregisterCommandGate drops a gate from the registry once it opened.
+func unregisterCommandGate(townRoot, gateID string) error {
+	gates, err := loadCommandGates(townRoot)
+	if err != nil {
+		return err
+	}
+	if _, ok := gates[gateID]; !ok {
+		return nil
+	}
+	delete(gates, gateID)
+	return saveCommandGates(townRoot, gates)
+}
+
+// checkCommandGateDir returns an error unless dir is inside the town.
+func checkCommandGateDir(townRoot, dir string) error {
+	root, err := filepath.EvalSymlinks(townRoot)
+	if err != nil {
+		root = townRoot
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		resolved = dir
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return fmt.Errorf("command gate directory %s is outside the town", dir)
+	}
+	return nil
+}
+
+// checkCommandGate returns an error unless the gate may run its command:
+// it is registered with the same command and directory, and the
+// directory is inside the town.
+func checkCommandGate(townRoot string, gates map[string]string, gateID string, f *beads.GateFields) error {
+	hash, ok := gates[gateID]
+	if !ok {
+		return fmt.Errorf("refusing to run command gate %s: not created in this town", gateID)
+	}
+	if hash != commandGateHash(f) {
+		return fmt.Errorf("refusing to run command gate %s: its command or directory changed since it was created", gateID)
+	}
+	if err := checkCommandGateDir(townRoot, f.Dir); err != nil {
+		return fmt.Errorf("refusing to run command gate %s: %w", gateID, err)
+	}
+	return nil
+}
@@ -0,0 +1,463 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mrqueue"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Gate kinds opened by gt gate eval.
+//
+// A gate created with gt gate create (or gt park --timer/--bead/...) stores
+// its condition in its description. The daemon runs gt gate eval every
+// heartbeat: gates whose condition holds are closed with the reason and
+// their waiters get wake mail, as gt gate wake sends.
+
+// gateCommandTimeout bounds how long a command gate's command may run.
+const gateCommandTimeout = 30 * time.Second
+
+// gateConditionFlags are the condition flags shared by gt gate create and
+// gt park.
+type gateConditionFlags struct {
+	timer   string
+	bead    string
+	label   string
+	command string
+	mr      string
+}
+
+func (f *gateConditionFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.timer, "timer", "", "Open at a time: duration (2h) or time (2026-11-01 09:00, RFC3339)")
+	cmd.Flags().StringVar(&f.bead, "bead", "", "Open when this bead closes (or gets --label)")
+	cmd.Flags().StringVar(&f.label, "label", "", "With --bead: open when the bead gets this label")
+	cmd.Flags().StringVar(&f.command, "command", "", "Open when this shell command exits 0 (run from the current directory)")
+	cmd.Flags().StringVar(&f.mr, "mr", "", "Open when this merge request merges (MR ID or source issue)")
+}
+
+// set reports whether any condition flag was given.
+func (f *gateConditionFlags) set() bool {
+	return f.timer != "" || f.bead != "" || f.label != "" || f.command != "" || f.mr != ""
+}
+
+// fields builds the gate condition from the flags. dir is where command
+// gates run.
+func (f *gateConditionFlags) fields(dir string, now time.Time) (*beads.GateFields, error) {
+	given := 0
+	for _, v := range []string{f.timer, f.bead, f.command, f.mr} {
+		if v != "" {
+			given++
+		}
+	}
+	if given != 1 {
+		return nil, fmt.Errorf("give exactly one of --timer, --bead, --command or --mr")
+	}
+	if f.label != "" && f.bead == "" {
+		return nil, fmt.Errorf("--label applies to --bead gates")
+	}
+
+	switch {
+	case f.timer != "":
+		at, err := parseGateTime(f.timer, now)
+		if err != nil {
+			return nil, err
+		}
+		return &beads.GateFields{Kind: beads.GateKindTimer, At: at.Format(time.RFC3339)}, nil
+	case f.bead != "":
+		return &beads.GateFields{Kind: beads.GateKindBead, Bead: f.bead, Label: f.label}, nil
+	case f.command != "":
+		return &beads.GateFields{Kind: beads.GateKindCommand, Command: f.command, Dir: dir}, nil
+	default:
+		return &beads.GateFields{Kind: beads.GateKindMRQueue, MR: f.mr}, nil
+	}
+}
+
+// parseGateTime parses a timer gate's --timer: a duration from now, or a
+// time (RFC3339, "2006-01-02 15:04" or "2006-01-02", local time).
+func parseGateTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("--timer duration must be positive")
+		}
+		return now.Add(d).Truncate(time.Second), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --timer %q (want a duration like 2h, or a time like \"2026-11-01 09:00\")", s)
+}
+
+// createConditionGate creates a gate in the beads at dir that gt gate
+// eval opens when the condition holds. Command gates must run inside the
+// town, and are registered so gt gate eval will run them.
+func createConditionGate(dir string, fields *beads.GateFields, title string) (*beads.Issue, error) {
+	if title == "" {
+		title = "Wait until " + fields.Describe()
+	}
+	var townRoot string
+	if fields.Kind == beads.GateKindCommand {
+		var err error
+		if townRoot, err = workspace.FindFromCwdOrError(); err != nil {
+			return nil, fmt.Errorf("command gates need a Gas Town workspace: %w", err)
+		}
+		if err := checkCommandGateDir(townRoot, fields.Dir); err != nil {
+			return nil, err
+		}
+	}
+	b := beads.New(dir)
+	// bd never opens a human gate on its own; gt gate eval does
+	gate, err := b.CreateGate("human:gt-"+fields.Kind, title)
+	if err != nil {
+		return nil, err
+	}
+	description := beads.SetGateFields(gate, fields)
+	if err := b.Update(gate.ID, beads.UpdateOptions{Description: &description}); err != nil {
+		return nil, fmt.Errorf("recording gate condition on %s: %w", gate.ID, err)
+	}
+	if townRoot != "" {
+		if err := registerCommandGate(townRoot, gate.ID, fields); err != nil {
+			return nil, fmt.Errorf("registering command gate %s: %w", gate.ID, err)
+		}
+	}
+	return gate, nil
+}
+
+// gateEnv is what gate conditions are evaluated against.
+type gateEnv struct {
+	now        time.Time
+	showBead   func(id string) (*beads.Issue, error)
+	runCommand func(command, dir string) error
+	mrMerged   func(ref string) bool
+
+	// checkCommand returns an error unless a command gate may run (see
+	// checkCommandGate).
+	checkCommand func(gateID string, f *beads.GateFields) error
+}
+
+// evalGate reports whether a gate's condition holds, with the close
+// reason. A command that fails means not yet; err is for conditions that
+// can't be checked, including command gates that may not run.
+func evalGate(gateID string, f *beads.GateFields, env gateEnv) (bool, string, error) {
+	switch f.Kind {
+	case beads.GateKindTimer:
+		at, err := time.Parse(time.RFC3339, f.At)
+		if err != nil {
+			return false, "", fmt.Errorf("invalid gate_at %q", f.At)
+		}
+		if env.now.Before(at) {
+			return false, "", nil
+		}
+		return true, "Timer reached " + f.At, nil
+
+	case beads.GateKindBead:
+		issue, err := env.showBead(f.Bead)
+		if err != nil {
+			return false, "", fmt.Errorf("bead %s: %w", f.Bead, err)
+		}
+		if f.Label != "" {
+			if slices.Contains(issue.Labels, f.Label) {
+				return true, fmt.Sprintf("%s labelled %s", f.Bead, f.Label), nil
+			}
+			return false, "", nil
+		}
+		if issueLanded(issue.Status) {
+			return true, f.Bead + " closed", nil
+		}
+		return false, "", nil
+
+	case beads.GateKindCommand:
+		if err := env.checkCommand(gateID, f); err != nil {
+			return false, "", err
+		}
+		if err := env.runCommand(f.Command, f.Dir); err != nil {
+			return false, "", nil
+		}
+		return true, fmt.Sprintf("`%s` exited 0", f.Command), nil
+
+	case beads.GateKindMRQueue:
+		if env.mrMerged(f.MR) {
+			return true, f.MR + " merged", nil
+		}
+		return false, "", nil
+	}
+	return false, "", fmt.Errorf("unknown gate kind %q", f.Kind)
+}
+
+// newGateEnv evaluates gates against the live town. Merge queue events
+// are read once, on first use.
+func newGateEnv(townRoot string, now time.Time) gateEnv {
+	var merged map[string]bool
+	var commandGates map[string]string
+	return gateEnv{
+		now: now,
+		showBead: func(id string) (*beads.Issue, error) {
+			return routedBeads(townRoot, id).Show(id)
+		},
+		runCommand: func(command, dir string) error {
+			if _, err := os.Stat(dir); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), gateCommandTimeout)
+			defer cancel()
+			c := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: the gate's own command, set by its creator
+			c.Dir = dir
+			return c.Run()
+		},
+		mrMerged: func(ref string) bool {
+			if merged == nil {
+				merged = mergedMRRefs(townRoot)
+			}
+			return merged[ref]
+		},
+		checkCommand: func(gateID string, f *beads.GateFields) error {
+			if commandGates == nil {
+				gates, err := loadCommandGates(townRoot)
+				if err != nil {
+					return err
+				}
+				commandGates = gates
+			}
+			return checkCommandGate(townRoot, commandGates, gateID, f)
+		},
+	}
+}
+
+// mergedMRRefs returns the MR IDs and source issues of every
+// merged merge request in the town's merge queue event logs.
+func mergedMRRefs(townRoot string) map[string]bool {
+	merged := make(map[string]bool)
+	routes, _ := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	seen := make(map[string]bool)
+	for _, r := range routes {
+		rigName := strings.Split(filepath.ToSlash(r.Path), "/")[0]
+		if rigName == "" || rigName == "." || seen[rigName] {
+			continue
+		}
+		seen[rigName] = true
+
+		events, _ := mrqueue.NewEventLoggerFromRig(filepath.Join(townRoot, rigName)).ReadEvents()
+		for _, e := range events {
+			if e.Type != mrqueue.EventMerged {
+				continue
+			}
+			for _, ref := range []string{e.MRID, e.SourceIssue} {
+				if ref != "" {
+					merged[ref] = true
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// gateBeadsDirs returns the town's beads locations: the town root and
+// every routed rig, once each.
+func gateBeadsDirs(townRoot string) []string {
+	dirs := []string{townRoot}
+	routes, _ := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	for _, r := range routes {
+		dir := filepath.Join(townRoot, r.Path)
+		if slices.Contains(dirs, dir) {
+			continue
+		}
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+var gateCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a gate that opens on a condition",
+	Long: `Create a gate that the daemon opens when its condition holds.
+
+Kinds:
+  --timer 2h | "2026-11-01 09:00"     Opens at a time
+  --bead gt-abc                       Opens when the bead closes
+  --bead gt-abc --label approved      Opens when the bead gets the label
+  --command "gh run view 123 --exit-status"
+                                      Opens when the command exits 0
+                                      (run from here, inside the town,
+                                      every heartbeat)
+  --mr gt-abc                         Opens when the MR merges (MR ID
+                                      or source issue)
+
+The daemon runs 'gt gate eval' every heartbeat: open gates are closed and
+their waiters get wake mail. Park work on the gate with 'gt park <gate-id>',
+or create and park in one step with 'gt park --timer 2h'.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runGateCreate,
+}
+
+var gateEvalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Open gates whose condition holds (called by the daemon)",
+	Long: `Evaluate every open gate created with a condition (gt gate create,
+gt park --timer/--bead/--command/--mr) across the town.
+
+Gates whose condition holds are closed with the reason, and their waiters
+get wake mail. Gates without a condition are left for bd and for manual
+close. Command gates run their command (up to 30s) in the directory they
+were created from. Only command gates created in this town by gt gate
+create or gt park run: a gate that isn't in the town's command gate
+registry (.runtime/command-gates.json), whose command or directory was
+changed, or whose directory is outside the town is reported as an error.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runGateEval,
+}
+
+var (
+	gateCreateFlags gateConditionFlags
+	gateCreateTitle string
+	gateCreateJSON  bool
+	gateEvalDryRun  bool
+	gateEvalJSON    bool
+)
+
+func init() {
+	gateCreateFlags.register(gateCreateCmd)
+	gateCreateCmd.Flags().StringVar(&gateCreateTitle, "title", "", "Gate title (default: describes the condition)")
+	gateCreateCmd.Flags().BoolVar(&gateCreateJSON, "json", false, "Output as JSON")
+
+	gateEvalCmd.Flags().BoolVarP(&gateEvalDryRun, "dry-run", "n", false, "Show which gates would open")
+	gateEvalCmd.Flags().BoolVar(&gateEvalJSON, "json", false, "Output as JSON")
+
+	gateCmd.AddCommand(gateCreateCmd)
+	gateCmd.AddCommand(gateEvalCmd)
+}
+
+func runGateCreate(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	fields, err := gateCreateFlags.fields(cwd, time.Now())
+	if err != nil {
+		return err
+	}
+	dir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("no beads database here: %w", err)
+	}
+
+	gate, err := createConditionGate(dir, fields, gateCreateTitle)
+	if err != nil {
+		return err
+	}
+
+	if gateCreateJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			ID        string `json:"id"`
+			Kind      string `json:"kind"`
+			Condition string `json:"condition"`
+		}{gate.ID, fields.Kind, fields.Describe()})
+	}
+	fmt.Printf("%s Created %s gate %s: opens when %s\n", style.Bold.Render("🚦"), fields.Kind, gate.ID, fields.Describe())
+	fmt.Printf("  %s\n", style.Dim.Render("Park work on it with: gt park "+gate.ID))
+	return nil
+}
+
+// gateEvalResult is one evaluated gate.
+type gateEvalResult struct {
+	GateID    string          `json:"gate_id"`
+	Kind      string          `json:"kind"`
+	Condition string          `json:"condition"`
+	Opened    bool            `json:"opened"`
+	Reason    string          `json:"reason,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Wake      *GateWakeResult `json:"wake,omitempty"`
+}
+
+func runGateEval(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	env := newGateEnv(townRoot, time.Now())
+
+	results := []gateEvalResult{}
+	for _, dir := range gateBeadsDirs(townRoot) {
+		b := beads.New(dir)
+		gates, err := b.List(beads.ListOptions{Type: "gate", Status: "open", Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, gate := range gates {
+			fields := beads.ParseGateFields(gate)
+			if fields == nil {
+				continue
+			}
+			result := gateEvalResult{GateID: gate.ID, Kind: fields.Kind, Condition: fields.Describe()}
+			open, reason, err := evalGate(gate.ID, fields, env)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			if open && !gateEvalDryRun {
+				if err := b.CloseGate(gate.ID, reason); err != nil {
+					result.Error = err.Error()
+					open = false
+				} else {
+					if fields.Kind == beads.GateKindCommand {
+						_ = unregisterCommandGate(townRoot, gate.ID)
+					}
+					if info, err := showGate(dir, gate.ID); err == nil {
+						wake := sendGateWake(townRoot, gate.ID, reason, info.Waiters)
+						result.Wake = &wake
+					}
+				}
+			}
+			result.Opened, result.Reason = open, reason
+			results = append(results, result)
+		}
+	}
+
+	if gateEvalJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	waiting := 0
+	for _, r := range results {
+		switch {
+		case r.Opened && gateEvalDryRun:
+			fmt.Printf("Would open %s: %s\n", r.GateID, r.Reason)
+		case r.Opened:
+			woken := 0
+			if r.Wake != nil {
+				woken = len(r.Wake.Notified)
+			}
+			fmt.Printf("%s Opened gate %s: %s (woke %d)\n", style.Bold.Render("🚦"), r.GateID, r.Reason, woken)
+		case r.Error != "":
+			fmt.Printf("%s Gate %s: %s\n", style.ErrorPrefix, r.GateID, r.Error)
+		default:
+			waiting++
+		}
+	}
+	if waiting > 0 {
+		fmt.Printf("%s %d gate(s) still waiting\n", style.Dim.Render("○"), waiting)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/handoff"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -44,6 +45,20 @@ The --collect (-c) flag gathers current state (hooked work, inbox, ready beads,
 in-progress items) and includes it in the handoff mail. This provides context
 for the next session without manual summarization.
 
+Structured handoff:
+  gt handoff --goal "Fix token refresh" \
+    --done "Found the race in refresh()" \
+    --next "Add a lock around refresh()" --next "Run the auth tests" \
+    --question "Should refresh retry on 401?" \
+    --rerun "go test ./internal/auth/..."
+
+A structured handoff needs a goal and at least one --next action; an
+incomplete one is refused before the session restarts (override with
+--force). Files touched default to git status, and the hooked bead and
+molecule step are filled in automatically. The handoff mail is rendered
+from the handoff template, and 'gt prime' shows the next session a
+checklist of next actions, open questions and commands to re-run.
+
 Any molecule on the hook will be auto-continued by the new session.
 The SessionStart hook runs 'gt prime' to restore context.`,
 	RunE: runHandoff,
@@ -63,6 +78,7 @@ func init() {
 	handoffCmd.Flags().StringVarP(&handoffSubject, "subject", "s", "", "Subject for handoff mail (optional)")
 	handoffCmd.Flags().StringVarP(&handoffMessage, "message", "m", "", "Message body for handoff mail (optional)")
 	handoffCmd.Flags().BoolVarP(&handoffCollect, "collect", "c", false, "Auto-collect state (status, inbox, beads) into handoff message")
+	handoffDoc.register(handoffCmd)
 	rootCmd.AddCommand(handoffCmd)
 }
 
@@ -93,6 +109,24 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Build and validate a structured handoff before any side effects
+	var doc *handoff.Document
+	var docHome string
+	if handoffDoc.set() {
+		agentID, _, home, err := resolveSelfTarget()
+		if err != nil {
+			return fmt.Errorf("detecting agent identity: %w", err)
+		}
+		doc = handoffDoc.document(agentID, handoffMessage)
+		if err := doc.Validate(); err != nil {
+			if !handoffDoc.force {
+				return fmt.Errorf("%w (use --force to hand off anyway)", err)
+			}
+			style.PrintWarning("%v", err)
+		}
+		docHome = home
+	}
+
 	t := tmux.NewTmux()
 
 	// Verify we're in tmux
@@ -143,9 +177,25 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 
 	// If handing off a different session, we need to find its pane and respawn there
 	if targetSession != currentSession {
+		if doc != nil {
+			return fmt.Errorf("structured handoff flags only apply to your own session")
+		}
 		return handoffRemoteSession(t, targetSession, restartCmd)
 	}
 
+	// Render the structured handoff as the handoff mail
+	if doc != nil {
+		fillHandoffState(doc, docHome)
+		body, err := renderHandoffMail(doc)
+		if err != nil {
+			return fmt.Errorf("rendering handoff: %w", err)
+		}
+		handoffMessage = body
+		if handoffSubject == "" {
+			handoffSubject = doc.Goal
+		}
+	}
+
 	// Handing off ourselves - print feedback then respawn
 	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), currentSession)
 
@@ -165,12 +215,28 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		if handoffSubject != "" || handoffMessage != "" {
 			fmt.Printf("Would send handoff mail: subject=%q (auto-hooked)\n", handoffSubject)
 		}
+		if doc != nil {
+			fmt.Printf("Would write handoff checklist: %s\n", handoff.Path(docHome))
+			fmt.Println(doc.Checklist())
+		}
 		fmt.Printf("Would execute: tmux clear-history -t %s\n", pane)
 		fmt.Printf("Would execute: tmux respawn-pane -k -t %s %s\n", pane, restartCmd)
 		return nil
 	}
 
-	// If subject/message provided, send handoff mail to self first
+	// Write the structured handoff for gt prime before anything is sent, so
+	// a failed write leaves no handoff mail behind. Clear a stale one
+	// otherwise.
+	if doc != nil {
+		if err := handoff.Write(docHome, doc); err != nil {
+			return err
+		}
+		fmt.Printf("%s Wrote handoff checklist (%d next actions)\n", style.Bold.Render("✅"), len(doc.Next))
+	} else if _, _, home, err := resolveSelfTarget(); err == nil {
+		_ = handoff.Remove(home)
+	}
+
+	// If subject/message provided, send handoff mail to self
 	// The mail is auto-hooked so the next session picks it up
 	if handoffSubject != "" || handoffMessage != "" {
 		beadID, err := sendHandoffMail(handoffSubject, handoffMessage)
@@ -179,6 +245,12 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 			// Continue anyway - the respawn is more important
 		} else {
 			fmt.Printf("%s Sent handoff mail %s (auto-hooked)\n", style.Bold.Render("📬"), beadID)
+			if doc != nil {
+				doc.MailID = beadID
+				if err := handoff.Write(docHome, doc); err != nil {
+					style.PrintWarning("could not record handoff mail in checklist: %v", err)
+				}
+			}
 		}
 	}
 
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/handoff"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+// Structured handoffs.
+//
+// gt handoff --goal ... --next ... writes a handoff document to the agent's
+// home before respawning, and sends it as the handoff mail rendered from
+// the handoff message template. The document must have a goal and at least
+// one next action; an incomplete one stops the handoff before the session
+// restarts. gt prime shows the document to the successor as a checklist.
+
+// handoffDocFlags holds the structured handoff flags of gt handoff.
+type handoffDocFlags struct {
+	goal      string
+	done      []string
+	next      []string
+	questions []string
+	files     []string
+	commands  []string
+	force     bool
+}
+
+var handoffDoc handoffDocFlags
+
+// register adds the structured handoff flags to a command.
+func (f *handoffDocFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.goal, "goal", "", "Structured handoff: what this session was working toward")
+	cmd.Flags().StringArrayVar(&f.done, "done", nil, "Structured handoff: something finished this session (repeatable)")
+	cmd.Flags().StringArrayVar(&f.next, "next", nil, "Structured handoff: next action for the successor (repeatable)")
+	cmd.Flags().StringArrayVar(&f.questions, "question", nil, "Structured handoff: open question (repeatable)")
+	cmd.Flags().StringArrayVar(&f.files, "file", nil, "Structured handoff: file touched (repeatable; default: from git status)")
+	cmd.Flags().StringArrayVar(&f.commands, "rerun", nil, "Structured handoff: command to re-run (repeatable)")
+	cmd.Flags().BoolVar(&f.force, "force", false, "Hand off even if the structured handoff is incomplete")
+}
+
+// set reports whether any structured handoff flag was given.
+func (f *handoffDocFlags) set() bool {
+	return f.goal != "" || len(f.done) > 0 || len(f.next) > 0 ||
+		len(f.questions) > 0 || len(f.files) > 0 || len(f.commands) > 0
+}
+
+// document builds the handoff document from the flags. notes is the free
+// text handoff message, if any.
+func (f *handoffDocFlags) document(agentID, notes string) *handoff.Document {
+	return &handoff.Document{
+		Agent:     agentID,
+		Goal:      strings.TrimSpace(f.goal),
+		Done:      f.done,
+		Next:      f.next,
+		Questions: f.questions,
+		Files:     f.files,
+		Commands:  f.commands,
+		Notes:     notes,
+	}
+}
+
+// fillHandoffState fills in what the document can discover for itself: the
+// hooked bead and molecule step, and the files touched if none were given.
+func fillHandoffState(doc *handoff.Document, home string) {
+	if len(doc.Files) == 0 {
+		doc.Files = gitTouchedFiles(home)
+	}
+
+	molCmd := exec.Command("gt", "mol", "current", "--json")
+	molCmd.Dir = home
+	out, err := molCmd.Output()
+	if err != nil {
+		return
+	}
+	var info MoleculeCurrentInfo
+	if err := json.Unmarshal(out, &info); err != nil {
+		return
+	}
+	doc.HookedBead = info.HandoffID
+	doc.Molecule = info.MoleculeID
+	if info.CurrentStepID != "" {
+		doc.MoleculeStep = info.CurrentStepID
+		if info.CurrentStep != "" {
+			doc.MoleculeStep += " - " + info.CurrentStep
+		}
+	}
+}
+
+// gitTouchedFiles returns the files git status reports as changed in dir.
+func gitTouchedFiles(dir string) []string {
+	statusCmd := exec.Command("git", "status", "--porcelain")
+	statusCmd.Dir = dir
+	out, err := statusCmd.Output()
+	if err != nil {
+		return nil
+	}
+	return parsePorcelainFiles(string(out))
+}
+
+// parsePorcelainFiles extracts file names from git status --porcelain
+// output. Renames report the new name.
+func parsePorcelainFiles(out string) []string {
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) <= 3 {
+			continue
+		}
+		file := strings.TrimSpace(line[3:])
+		if _, newName, ok := strings.Cut(file, " -> "); ok {
+			file = newName
+		}
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// renderHandoffMail renders the handoff mail body from the handoff message
+// template.
+func renderHandoffMail(doc *handoff.Document) (string, error) {
+	tmpl, err := templates.New()
+	if err != nil {
+		return "", err
+	}
+	return tmpl.RenderMessage("handoff", templates.HandoffData{
+		Role:         doc.Agent,
+		Goal:         doc.Goal,
+		Done:         doc.Done,
+		Next:         doc.Next,
+		Questions:    doc.Questions,
+		Files:        doc.Files,
+		Commands:     doc.Commands,
+		HookedBead:   doc.HookedBead,
+		Molecule:     doc.Molecule,
+		MoleculeStep: doc.MoleculeStep,
+		Notes:        doc.Notes,
+	})
+}
+
+// outputHandoffChecklist shows the structured handoff left by the previous
+// session as a checklist. Handoffs older than a day are removed instead.
+func outputHandoffChecklist(ctx RoleContext) {
+	home := getRoleHome(ctx.Role, ctx.Rig, ctx.Polecat, ctx.TownRoot)
+	if home == "" {
+		return
+	}
+	doc, err := handoff.Read(home)
+	if err != nil || doc == nil {
+		return
+	}
+	if doc.IsStale(24 * time.Hour) {
+		_ = handoff.Remove(home)
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render("## ✅ Handoff Checklist"))
+	fmt.Printf("%s handed off %s ago.\n\n", doc.Agent, doc.Age().Round(time.Minute))
+	fmt.Print(doc.Checklist())
+	if missing := doc.Missing(); len(missing) > 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render("(handed off incomplete: missing "+strings.Join(missing, ", ")+")"))
+	}
+	fmt.Println()
+	fmt.Println(style.Dim.Render("(Replaced by the next gt handoff)"))
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePorcelainFiles(t *testing.T) {
+	out := " M internal/auth/refresh.go\n?? notes.txt\nR  old.go -> new.go\n\n"
+	want := []string{"internal/auth/refresh.go", "notes.txt", "new.go"}
+	if got := parsePorcelainFiles(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePorcelainFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestHandoffDocFlags(t *testing.T) {
+	var f handoffDocFlags
+	if f.set() {
+		t.Error("no flags should not be a structured handoff")
+	}
+	f.next = []string{"Run tests"}
+	if !f.set() {
+		t.Error("--next should make a structured handoff")
+	}
+	doc := f.document("mayor/", "notes")
+	if err := doc.Validate(); err == nil {
+		t.Error("a handoff without a goal should not validate")
+	}
+	f.goal = " Ship it "
+	if doc := f.document("mayor/", ""); doc.Goal != "Ship it" || doc.Validate() != nil {
+		t.Errorf("document() = %+v", doc)
+	}
+}
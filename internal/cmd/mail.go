@@ -35,6 +35,8 @@ var (
 	mailNotify        bool
 	mailSendSelf      bool
 	mailCC            []string // CC recipients
+	mailRequireAck    bool
+	mailAckTimeout    time.Duration
 	mailInboxJSON     bool
 	mailReadJSON      bool
 	mailInboxUnread   bool
@@ -52,6 +54,9 @@ var (
 	mailSearchBody    bool
 	mailSearchArchive bool
 	mailSearchJSON    bool
+	mailSearchAll     bool
+	mailSearchReindex bool
+	mailSearchLimit   int
 
 	// Announces flags
 	mailAnnouncesJSON bool
@@ -142,7 +147,13 @@ Examples:
   gt mail send mayor/ -s "Re: Status" -m "Done" --reply-to msg-abc123
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
-  gt mail send list:oncall -s "Alert" -m "System down"`,
+  gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send mayor/ -s "HELP" -m "Blocked on auth" --require-ack
+
+Use --require-ack for messages that must not be missed. Recipients acknowledge
+by archiving or deleting the message. If they don't within --ack-timeout, the
+daemon re-notifies them and finally escalates to the overseer. Check delivery
+and read state with 'gt mail status <id>'.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMailSend,
 }
@@ -351,16 +362,37 @@ FLAGS:
   --body            Only search message body
   --archive         Include archived (closed) messages
   --json            Output as JSON
+  --all             Search every mailbox in town (indexed)
+  --reindex         Rebuild the town-wide index first (implies --all)
 
 By default, searches both subject and body text.
 
+TOWN-WIDE SEARCH (--all):
+Uses the town search index (.beads/mail-index.json), which is updated as
+mail is sent and archived. Covers every agent's inbox, archived mail and
+threads. The query is words (all must match, prefix matching) and "quoted
+phrases", plus field filters:
+
+  from:<sender>      Sender contains value
+  to:<recipient>     Recipient (or CC) contains value
+  thread:<id>        Messages in a thread
+  before:<when>      Sent before date
+  after:<when>       Sent on or after date
+  is:inbox|archived  Message state
+
+Dates: 2006-01-02, RFC3339, today, yesterday, or relative (12h, 7d, 2w).
+Run with --reindex once to index mail sent before the index existed.
+
 Examples:
   gt mail search "urgent"                    # Find messages with "urgent"
   gt mail search "status.*check" --subject   # Regex in subjects only
   gt mail search "error" --from witness      # From witness, containing "error"
   gt mail search "handoff" --archive         # Include archived messages
-  gt mail search "" --from mayor/            # All messages from mayor`,
-	Args: cobra.ExactArgs(1),
+  gt mail search "" --from mayor/            # All messages from mayor
+  gt mail search --all "auth migration after:7d"
+  gt mail search --all "thread:thread-abc123"
+  gt mail search --all "from:witness before:2026-01-15 is:archived"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runMailSearch,
 }
 
@@ -410,6 +442,8 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().BoolVar(&mailRequireAck, "require-ack", false, "Require recipients to acknowledge (daemon re-notifies, then escalates)")
+	mailSendCmd.Flags().DurationVar(&mailAckTimeout, "ack-timeout", mail.DefaultAckTimeout, "Time allowed to acknowledge before follow-up (with --require-ack)")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -441,6 +475,9 @@ func init() {
 	mailSearchCmd.Flags().BoolVar(&mailSearchBody, "body", false, "Only search message body")
 	mailSearchCmd.Flags().BoolVar(&mailSearchArchive, "archive", false, "Include archived messages")
 	mailSearchCmd.Flags().BoolVar(&mailSearchJSON, "json", false, "Output as JSON")
+	mailSearchCmd.Flags().BoolVar(&mailSearchAll, "all", false, "Search every mailbox in town via the search index")
+	mailSearchCmd.Flags().BoolVar(&mailSearchReindex, "reindex", false, "Rebuild the town-wide search index before searching")
+	mailSearchCmd.Flags().IntVarP(&mailSearchLimit, "limit", "n", 0, "Maximum results with --all (0 = unlimited)")
 
 	// Announces flags
 	mailAnnouncesCmd.Flags().BoolVar(&mailAnnouncesJSON, "json", false, "Output as JSON")
@@ -540,6 +577,12 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	// Request read acknowledgement (tracked on the delivery receipt)
+	msg.RequireAck = mailRequireAck
+	if mailRequireAck {
+		msg.AckTimeout = mailAckTimeout
+	}
+
 	// Handle reply-to: auto-set type to reply and look up thread
 	if mailReplyTo != "" {
 		msg.ReplyTo = mailReplyTo
@@ -596,6 +639,9 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	if msg.Type != mail.TypeNotification {
 		fmt.Printf("  Type: %s\n", msg.Type)
 	}
+	if msg.RequireAck {
+		fmt.Printf("  Ack required within %s (track: gt mail status %s)\n", mailAckTimeout, msg.ReceiptID)
+	}
 
 	return nil
 }
@@ -708,6 +754,8 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 	// Note: We intentionally do NOT mark as read/ack on read.
 	// User must explicitly delete/ack the message.
 	// This preserves handoff messages for reference.
+	// We do record a read receipt so the sender can see it was opened.
+	_ = mailbox.RecordRead(msgID) // non-fatal: receipts are advisory
 
 	// JSON output
 	if mailReadJSON {
@@ -741,6 +789,9 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 	if msg.ReplyTo != "" {
 		fmt.Printf("Reply-To: %s\n", style.Dim.Render(msg.ReplyTo))
 	}
+	if msg.RequireAck {
+		fmt.Printf("%s\n", style.Bold.Render("Acknowledgement required: archive this message when handled"))
+	}
 
 	if msg.Body != "" {
 		fmt.Printf("\n%s\n", msg.Body)
@@ -1711,6 +1762,12 @@ func releaseMessage(townRoot, messageID, queueAssignee, actor string) error {
 
 // runMailSearch searches for messages matching a pattern.
 func runMailSearch(cmd *cobra.Command, args []string) error {
+	if mailSearchAll || mailSearchReindex {
+		return runMailSearchIndex(args)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("query required (use --all for town-wide search)")
+	}
 	query := args[0]
 
 	// Determine which inbox to search
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Mail export/import command flags
+var (
+	mailExportFormat string
+	mailExportOutput string
+	mailExportAll    bool
+	mailImportFormat string
+	mailImportInbox  bool
+	mailImportTo     string
+)
+
+var mailExportCmd = &cobra.Command{
+	Use:   "export [address]",
+	Short: "Export mail to mbox or Maildir",
+	Long: `Export mail so it can be browsed in a normal mail client or restored
+into another town with 'gt mail import'.
+
+Exports every message in the mailbox: unread, read and archived. With
+--all, exports every message in the town.
+
+Threading is preserved: ThreadID becomes the References header (the
+thread root's Message-ID) and ReplyTo becomes In-Reply-To, so mail
+clients group conversations the same way 'gt mail thread' does. CC
+recipients, priority and message type are preserved too.
+
+FORMATS:
+  mbox      Single mboxrd file (default). Written to stdout if no --output.
+  maildir   Maildir directory (cur/, new/, tmp/). Requires --output.
+
+Examples:
+  gt mail export -o mayor.mbox                     # Your mailbox
+  gt mail export gastown/witness -o witness.mbox   # Another mailbox
+  gt mail export --all --format maildir -o ~/Mail/gastown
+  gt mail export | grep -c '^From '                # Count your messages`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMailExport,
+}
+
+var mailImportCmd = &cobra.Command{
+	Use:   "import <path>",
+	Short: "Import mail from mbox or Maildir",
+	Long: `Import mail previously exported with 'gt mail export' (or any mbox or
+Maildir mail) into this town.
+
+By default messages are restored into the town mail archive with their
+original IDs, timestamps and threading intact; messages already in the
+archive are skipped, so importing twice is safe. Imported mail is
+searchable with 'gt mail search --all'.
+
+With --inbox, messages are recreated as live beads messages in their
+recipients' inboxes instead (messages marked read are created closed).
+Live messages get new bead IDs. No notifications are sent either way.
+
+The format is detected from the path (directories with cur/ or new/ are
+Maildirs) unless --format is given.
+
+Examples:
+  gt mail import old-town.mbox
+  gt mail import ~/Mail/gastown --format maildir
+  gt mail import handoff.mbox --inbox --to gastown/crew/max`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailImport,
+}
+
+func init() {
+	mailExportCmd.Flags().StringVar(&mailExportFormat, "format", string(mail.FormatMbox), "Output format: mbox or maildir")
+	mailExportCmd.Flags().StringVarP(&mailExportOutput, "output", "o", "", "Output file (mbox) or directory (maildir)")
+	mailExportCmd.Flags().BoolVar(&mailExportAll, "all", false, "Export every message in the town")
+
+	mailImportCmd.Flags().StringVar(&mailImportFormat, "format", "", "Input format: mbox or maildir (default: detect)")
+	mailImportCmd.Flags().BoolVar(&mailImportInbox, "inbox", false, "Restore as live inbox messages instead of archive")
+	mailImportCmd.Flags().StringVar(&mailImportTo, "to", "", "Deliver every imported message to this address")
+
+	mailCmd.AddCommand(mailExportCmd)
+	mailCmd.AddCommand(mailImportCmd)
+}
+
+func runMailExport(cmd *cobra.Command, args []string) error {
+	format, err := mail.ParseExportFormat(mailExportFormat)
+	if err != nil {
+		return err
+	}
+	if format == mail.FormatMaildir && mailExportOutput == "" {
+		return fmt.Errorf("--output is required for maildir export")
+	}
+
+	// All mail uses town beads (two-level architecture)
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(workDir)
+
+	var messages []*mail.Message
+	source := "town"
+	if mailExportAll {
+		messages, err = router.ListAllMessages()
+	} else {
+		source = detectSender()
+		if len(args) > 0 {
+			source = args[0]
+		}
+		var mailbox *mail.Mailbox
+		mailbox, err = router.GetMailbox(source)
+		if err != nil {
+			return fmt.Errorf("getting mailbox: %w", err)
+		}
+		messages, err = mailbox.ListAll()
+	}
+	if err != nil {
+		return fmt.Errorf("listing messages: %w", err)
+	}
+
+	switch format {
+	case mail.FormatMaildir:
+		err = mail.WriteMaildir(mailExportOutput, messages)
+	default:
+		out := os.Stdout
+		if mailExportOutput != "" && mailExportOutput != "-" {
+			f, createErr := os.Create(mailExportOutput)
+			if createErr != nil {
+				return fmt.Errorf("creating %s: %w", mailExportOutput, createErr)
+			}
+			defer func() { _ = f.Close() }()
+			out = f
+		}
+		err = mail.WriteMbox(out, messages)
+	}
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", format, err)
+	}
+
+	// Keep stdout clean when it carries the mbox itself
+	if mailExportOutput != "" && mailExportOutput != "-" {
+		fmt.Printf("%s Exported %d message(s) from %s to %s (%s)\n",
+			style.Bold.Render("✓"), len(messages), source, mailExportOutput, format)
+	}
+	return nil
+}
+
+func runMailImport(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	format := mail.DetectExportFormat(path)
+	if mailImportFormat != "" {
+		var err error
+		if format, err = mail.ParseExportFormat(mailImportFormat); err != nil {
+			return err
+		}
+	}
+
+	var messages []*mail.Message
+	switch format {
+	case mail.FormatMaildir:
+		var err error
+		if messages, err = mail.ReadMaildir(path); err != nil {
+			return fmt.Errorf("reading maildir: %w", err)
+		}
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer func() { _ = f.Close() }()
+		if messages, err = mail.ReadMbox(f); err != nil {
+			return fmt.Errorf("reading mbox: %w", err)
+		}
+	}
+
+	// All mail uses town beads (two-level architecture)
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(workDir)
+
+	result, err := router.Import(messages, mail.ImportOptions{
+		Inbox: mailImportInbox,
+		To:    mailImportTo,
+	})
+	if err != nil {
+		if result != nil && result.Imported > 0 {
+			fmt.Printf("%s Imported %d message(s) before failing\n", style.Bold.Render("⚠"), result.Imported)
+		}
+		return err
+	}
+
+	dest := "archive"
+	if mailImportInbox {
+		dest = "inbox"
+	}
+	fmt.Printf("%s Imported %d message(s) into %s", style.Bold.Render("✓"), result.Imported, dest)
+	if result.Skipped > 0 {
+		fmt.Printf(" (%d already present)", result.Skipped)
+	}
+	fmt.Println()
+	return nil
+}
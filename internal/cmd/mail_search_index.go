@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// runMailSearchIndex searches every mailbox in town via the search index.
+func runMailSearchIndex(args []string) error {
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+
+	// All mail uses town beads (two-level architecture)
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(workDir)
+
+	if mailSearchReindex {
+		n, err := router.RebuildSearchIndex()
+		if err != nil {
+			return fmt.Errorf("rebuilding search index: %w", err)
+		}
+		if !mailSearchJSON {
+			fmt.Printf("%s Indexed %d message(s)\n", style.Bold.Render("✓"), n)
+		}
+		if len(args) == 0 {
+			return nil
+		}
+	}
+
+	q, err := mail.ParseIndexQuery(query, time.Now())
+	if err != nil {
+		return err
+	}
+	if q.From == "" && mailSearchFrom != "" {
+		q.From = strings.ToLower(mailSearchFrom)
+	}
+	q.Limit = mailSearchLimit
+
+	results, err := router.SearchIndex().Search(q)
+	if err != nil {
+		if errors.Is(err, mail.ErrIndexEmpty) {
+			return fmt.Errorf("no search index yet: run 'gt mail search --reindex' to build it")
+		}
+		return fmt.Errorf("searching index: %w", err)
+	}
+
+	if mailSearchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if results == nil {
+			results = []*mail.SearchResult{}
+		}
+		return enc.Encode(results)
+	}
+
+	fmt.Printf("%s Town-wide search results: %d message(s)\n\n",
+		style.Bold.Render("🔍"), len(results))
+
+	if len(results) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no matches)"))
+		return nil
+	}
+
+	for _, r := range results {
+		archivedMarker := ""
+		if r.Archived {
+			archivedMarker = " " + style.Dim.Render("(archived)")
+		}
+		fmt.Printf("  %s%s\n", r.Subject, archivedMarker)
+		fmt.Printf("    %s from %s to %s\n",
+			style.Dim.Render(r.ID), r.From, formatSearchMailboxes(r.Mailboxes))
+		meta := r.Timestamp.Format("2006-01-02 15:04")
+		if r.ThreadID != "" {
+			meta += "  thread:" + r.ThreadID
+		}
+		fmt.Printf("    %s\n", style.Dim.Render(meta))
+	}
+
+	return nil
+}
+
+// formatSearchMailboxes summarizes the recipient mailboxes of a search hit.
+func formatSearchMailboxes(mailboxes []string) string {
+	const maxShown = 3
+	if len(mailboxes) <= maxShown {
+		return strings.Join(mailboxes, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(mailboxes[:maxShown], ", "), len(mailboxes)-maxShown)
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Mail status command flags
+var (
+	mailStatusJSON bool
+)
+
+var mailStatusCmd = &cobra.Command{
+	Use:   "status <message-id>",
+	Short: "Show delivery and read receipts for a message",
+	Long: `Show who a message was delivered to and who has read it.
+
+Accepts either the receipt ID printed by 'gt mail send' or the bead ID of
+any recipient's copy. For mailing lists and @group addresses, every fan-out
+recipient is listed; CC recipients are marked.
+
+Recipient states:
+  delivered  Message bead created in the recipient's inbox
+  read       Recipient opened the message (gt mail read)
+  acked      Recipient archived/deleted the message
+  failed     Delivery failed (see error)
+
+For --require-ack messages, also shows how many times the daemon has
+re-notified each recipient and whether the message was escalated.
+
+Examples:
+  gt mail status msg-1a2b3c4d5e6f7a8b
+  gt mail status hq-abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailStatus,
+}
+
+func init() {
+	mailStatusCmd.Flags().BoolVar(&mailStatusJSON, "json", false, "Output as JSON")
+
+	mailCmd.AddCommand(mailStatusCmd)
+}
+
+func runMailStatus(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	// All mail uses town beads (two-level architecture)
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	router := mail.NewRouter(workDir)
+	receipt, err := router.Receipts().Find(id)
+	if err != nil {
+		if errors.Is(err, mail.ErrReceiptNotFound) {
+			return fmt.Errorf("no receipt for %s (sent before receipts were tracked, or pruned)", id)
+		}
+		return fmt.Errorf("loading receipt: %w", err)
+	}
+
+	if mailStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(receipt)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Subject:"), receipt.Subject)
+	fmt.Printf("From: %s\n", receipt.From)
+	fmt.Printf("To: %s\n", receipt.To)
+	fmt.Printf("Sent: %s\n", receipt.SentAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Receipt: %s\n", style.Dim.Render(receipt.ID))
+	if receipt.RequireAck {
+		timeout := receipt.AckTimeout
+		if timeout == 0 {
+			timeout = mail.DefaultAckTimeout
+		}
+		fmt.Printf("Ack required: within %s\n", timeout)
+		if receipt.EscalatedAt != nil {
+			fmt.Printf("Escalated: %s\n", style.Warning.Render(receipt.EscalatedAt.Format("2006-01-02 15:04:05")))
+		}
+	}
+
+	read, acked := 0, 0
+	for _, rr := range receipt.Recipients {
+		if rr.ReadAt != nil {
+			read++
+		}
+		if rr.AckedAt != nil {
+			acked++
+		}
+	}
+	fmt.Printf("\n%s (%d recipients, %d read, %d acked)\n",
+		style.Bold.Render("Recipients"), len(receipt.Recipients), read, acked)
+
+	for _, rr := range receipt.Recipients {
+		fmt.Printf("  %s %s%s\n", recipientStateIcon(rr), rr.Address, recipientSuffix(rr))
+		if rr.MessageID != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(rr.MessageID))
+		}
+		if rr.DeliveryError != "" {
+			fmt.Printf("    error: %s\n", rr.DeliveryError)
+		}
+	}
+
+	return nil
+}
+
+// recipientStateIcon returns a status icon for a recipient receipt.
+func recipientStateIcon(rr *mail.RecipientReceipt) string {
+	switch rr.State() {
+	case "failed":
+		return style.ErrorPrefix
+	case "acked":
+		return style.SuccessPrefix
+	case "read":
+		return style.Info.Render("◉")
+	case "delivered":
+		return "●"
+	default:
+		return "○"
+	}
+}
+
+// recipientSuffix describes the recipient's state and timing.
+func recipientSuffix(rr *mail.RecipientReceipt) string {
+	suffix := ""
+	if rr.CC {
+		suffix += " (cc)"
+	}
+	state := rr.State()
+	suffix += " " + style.Dim.Render("["+state+"]")
+	switch {
+	case rr.AckedAt != nil:
+		suffix += " " + style.Dim.Render(formatReceiptTime(*rr.AckedAt))
+	case rr.ReadAt != nil:
+		suffix += " " + style.Dim.Render(formatReceiptTime(*rr.ReadAt))
+	case rr.DeliveredAt != nil:
+		suffix += " " + style.Dim.Render(formatReceiptTime(*rr.DeliveredAt))
+	}
+	if rr.NotifyCount > 0 {
+		suffix += " " + style.Dim.Render(fmt.Sprintf("(re-notified %dx)", rr.NotifyCount))
+	}
+	return suffix
+}
+
+// formatReceiptTime formats a receipt timestamp with a relative age.
+func formatReceiptTime(t time.Time) string {
+	return fmt.Sprintf("%s (%s ago)", t.Format("2006-01-02 15:04"), time.Since(t).Round(time.Second))
+}
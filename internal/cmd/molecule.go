@@ -24,6 +24,7 @@ VIEWING YOUR WORK:
   gt hook              Show what's on your hook
   gt mol current       Show what you should be working on
   gt mol progress      Show execution progress
+  gt mol graph         Show steps as a dependency graph
 
 WORKING ON STEPS:
   gt mol step done     Complete current step (auto-continues)
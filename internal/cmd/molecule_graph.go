@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+var moleculeGraphFormat string
+
+var moleculeGraphCmd = &cobra.Command{
+	Use:   "graph <root-issue-id>",
+	Short: "Show a molecule's steps as a dependency graph",
+	Long: `Render an instantiated molecule's steps as a DAG.
+
+Each step is coloured by state and shows its assignee:
+
+  done         step closed
+  in progress  step claimed (in progress, hooked or pinned)
+  ready        open, and everything it needs is done
+  blocked      open, waiting on steps it needs
+  failed       escalated by its failure policy and still open
+
+Formats:
+  ascii    Steps grouped into waves for the terminal (default)
+  dot      Graphviz DOT: gt mol graph gt-abc --format dot | dot -Tsvg > mol.svg
+  mermaid  Mermaid flowchart, for markdown and PR descriptions
+
+Examples:
+  gt mol graph gt-abc
+  gt mol graph gt-abc --format mermaid`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runMoleculeGraph,
+}
+
+func init() {
+	moleculeGraphCmd.Flags().StringVarP(&moleculeGraphFormat, "format", "f", graph.FormatASCII, "Output format: ascii, dot or mermaid")
+	moleculeCmd.AddCommand(moleculeGraphCmd)
+}
+
+func runMoleculeGraph(cmd *cobra.Command, args []string) error {
+	rootID := args[0]
+
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("not in a beads workspace: %w", err)
+	}
+	b := beads.New(workDir)
+
+	root, err := b.Show(rootID)
+	if err != nil {
+		return fmt.Errorf("getting root issue: %w", err)
+	}
+	children, err := b.List(beads.ListOptions{
+		Parent:   rootID,
+		Status:   "all",
+		Priority: -1,
+	})
+	if err != nil {
+		return fmt.Errorf("listing children: %w", err)
+	}
+	if len(children) == 0 {
+		return fmt.Errorf("no steps found for %s (not a molecule root?)", rootID)
+	}
+
+	g := moleculeGraph(root, children)
+	out, err := graph.Render(g, moleculeGraphFormat)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// moleculeGraph builds the step graph of a molecule root and its children.
+func moleculeGraph(root *beads.Issue, children []*beads.Issue) *graph.Graph {
+	closedIDs := make(map[string]bool)
+	for _, child := range children {
+		if child.Status == "closed" {
+			closedIDs[child.ID] = true
+		}
+	}
+
+	g := &graph.Graph{Name: fmt.Sprintf("%s (%s)", root.Title, root.ID)}
+	for _, child := range children {
+		g.Nodes = append(g.Nodes, graph.Node{
+			ID:       child.ID,
+			Label:    child.Title,
+			State:    moleculeStepState(child, closedIDs),
+			Assignee: child.Assignee,
+			Needs:    child.DependsOn,
+		})
+	}
+	return g
+}
+
+// moleculeStepState classifies a step bead the way gt mol progress does,
+// plus failed for steps escalated by their failure policy.
+func moleculeStepState(step *beads.Issue, closedIDs map[string]bool) graph.State {
+	if step.Status == "closed" {
+		return graph.StateDone
+	}
+	if policy := beads.ParseStepPolicyFields(step); policy != nil && policy.EscalatedAt != "" {
+		return graph.StateFailed
+	}
+	switch step.Status {
+	case "in_progress", beads.StatusHooked, beads.StatusPinned:
+		return graph.StateInProgress
+	}
+	for _, dep := range step.DependsOn {
+		if !closedIDs[dep] {
+			return graph.StateBlocked
+		}
+	}
+	return graph.StateReady
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/graph"
+)
+
+func TestMoleculeGraph_States(t *testing.T) {
+	root := &beads.Issue{ID: "gt-m1", Title: "Release"}
+	children := []*beads.Issue{
+		{ID: "gt-m1.1", Title: "Bump", Status: "closed"},
+		{ID: "gt-m1.2", Title: "Test", Status: beads.StatusHooked, Assignee: "gastown/polecats/toast", DependsOn: []string{"gt-m1.1"}},
+		{ID: "gt-m1.3", Title: "Docs", Status: "open", DependsOn: []string{"gt-m1.1"}},
+		{ID: "gt-m1.4", Title: "Tag", Status: "open", DependsOn: []string{"gt-m1.2", "gt-m1.3"}},
+		{ID: "gt-m1.5", Title: "Deploy", Status: "in_progress", Description: "Deploy it.\n\nstep_escalated_at: 2026-01-02T15:04:05Z"},
+	}
+
+	g := moleculeGraph(root, children)
+	if g.Name != "Release (gt-m1)" {
+		t.Errorf("Name = %q", g.Name)
+	}
+	want := []graph.State{graph.StateDone, graph.StateInProgress, graph.StateReady, graph.StateBlocked, graph.StateFailed}
+	for i, n := range g.Nodes {
+		if n.State != want[i] {
+			t.Errorf("%s state = %q, want %q", n.ID, n.State, want[i])
+		}
+	}
+	if g.Nodes[1].Assignee != "gastown/polecats/toast" {
+		t.Errorf("assignee = %q", g.Nodes[1].Assignee)
+	}
+}
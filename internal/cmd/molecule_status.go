@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -102,6 +103,9 @@ type MoleculeProgressInfo struct {
 	BlockedSteps []string `json:"blocked_steps"`
 	Percent      int      `json:"percent_complete"`
 	Complete     bool     `json:"complete"`
+
+	// Time limits and attempts of open steps with a failure policy
+	StepPolicies []StepPolicyStatus `json:"step_policies,omitempty"`
 }
 
 // MoleculeStatusInfo contains status information for an agent's work.
@@ -212,6 +216,16 @@ func runMoleculeProgress(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	now := time.Now()
+	for _, child := range children {
+		if child.Status == "closed" {
+			continue
+		}
+		if ps := stepPolicyStatus(child, now); ps != nil {
+			progress.StepPolicies = append(progress.StepPolicies, *ps)
+		}
+	}
+
 	// Calculate completion percentage
 	if progress.TotalSteps > 0 {
 		progress.Percent = (progress.DoneSteps * 100) / progress.TotalSteps
@@ -520,6 +534,16 @@ func getMoleculeProgressInfo(b *beads.Beads, moleculeRootID string) (*MoleculePr
 		}
 	}
 
+	now := time.Now()
+	for _, child := range children {
+		if child.Status == "closed" {
+			continue
+		}
+		if ps := stepPolicyStatus(child, now); ps != nil {
+			progress.StepPolicies = append(progress.StepPolicies, *ps)
+		}
+	}
+
 	// Calculate completion percentage
 	if progress.TotalSteps > 0 {
 		progress.Percent = (progress.DoneSteps * 100) / progress.TotalSteps
@@ -631,6 +655,17 @@ func outputMoleculeStatus(status MoleculeStatusInfo) error {
 		fmt.Println()
 		fmt.Printf("  Blocked:     %d\n", len(status.Progress.BlockedSteps))
 
+		if len(status.Progress.StepPolicies) > 0 {
+			fmt.Printf("\nStep limits:\n")
+			for _, ps := range status.Progress.StepPolicies {
+				fmt.Printf("  %s %s\n", ps.StepID, style.Dim.Render("("+ps.Status+")"))
+				fmt.Printf("    %s\n", formatStepPolicyStatus(ps))
+				if ps.LastFailure != "" {
+					fmt.Printf("    %s\n", style.Dim.Render("last failure: "+ps.LastFailure))
+				}
+			}
+		}
+
 		if status.Progress.Complete {
 			fmt.Printf("\n%s\n", style.Bold.Render("✓ Molecule complete!"))
 		}
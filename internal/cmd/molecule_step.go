@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -36,19 +37,26 @@ This command handles the step-to-step transition for polecats:
 IMPORTANT: This is the canonical way to complete molecule steps. Do NOT manually
 close steps with 'bd close' - it skips the auto-continuation logic.
 
+--output records the step's result for formula conditions that read it
+(steps.ID.output). Steps of a workflow formula run are closed, and the run
+advances on the next 'gt convoy check'.
+
 Example:
-  gt mol step done gt-abc.1    # Complete step 1 of molecule gt-abc`,
+  gt mol step done gt-abc.1    # Complete step 1 of molecule gt-abc
+  gt mol step done hq-step-x1 --output "PASS"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMoleculeStepDone,
 }
 
 var (
 	moleculeStepDryRun bool
+	moleculeStepOutput string
 )
 
 func init() {
 	moleculeStepDoneCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
 	moleculeStepDoneCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeStepDoneCmd.Flags().StringVar(&moleculeStepOutput, "output", "", "Record the step's result for formula conditions")
 }
 
 // StepDoneResult is the result of a step done operation.
@@ -93,8 +101,21 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("step not found: %w", err)
 	}
 
+	// Formula run steps record their output for when/until conditions
+	formulaStep := beads.ParseFormulaStepFields(step)
+	if formulaStep != nil && moleculeStepOutput != "" && !moleculeStepDryRun {
+		formulaStep.Output = moleculeStepOutput
+		desc := beads.SetFormulaStepFields(step, formulaStep)
+		if err := b.Update(stepID, beads.UpdateOptions{Description: &desc}); err != nil {
+			return fmt.Errorf("recording output: %w", err)
+		}
+	}
+
 	// Step 2: Extract molecule ID from step ID (gt-xxx.1 -> gt-xxx)
 	moleculeID := extractMoleculeIDFromStep(stepID)
+	if moleculeID == "" && formulaStep != nil {
+		return closeFormulaRunStep(b, step, moleculeStepDryRun)
+	}
 	if moleculeID == "" {
 		return fmt.Errorf("cannot extract molecule ID from step %s (expected format: gt-xxx.N)", stepID)
 	}
@@ -250,7 +271,7 @@ func findNextReadyStep(b *beads.Beads, moleculeID string) (*beads.Issue, bool, e
 }
 
 // handleStepContinue handles continuing to the next step.
-func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun bool) error { // workDir unused but kept for signature consistency
+func handleStepContinue(cwd, townRoot, workDir string, nextStep *beads.Issue, dryRun bool) error {
 	fmt.Printf("\n%s Next step: %s\n", style.Bold.Render("→"), nextStep.ID)
 	fmt.Printf("  %s\n", nextStep.Title)
 
@@ -294,6 +315,13 @@ func handleStepContinue(cwd, townRoot, _ string, nextStep *beads.Issue, dryRun b
 
 	fmt.Printf("%s Next step pinned: %s\n", style.Bold.Render("📌"), nextStep.ID)
 
+	// Start the clock on steps with a time limit or attempt budget
+	if policy := beads.ParseStepPolicyFields(nextStep); policy != nil {
+		if err := startStepAttempt(beads.New(workDir), nextStep, policy, time.Now()); err != nil {
+			style.PrintWarning("could not record step attempt: %v", err)
+		}
+	}
+
 	// Respawn the pane
 	if !tmux.IsInsideTmux() {
 		// Not in tmux - just print next action
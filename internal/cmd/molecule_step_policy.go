@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/notify"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var moleculeStepFailCmd = &cobra.Command{
+	Use:   "fail <step-id>",
+	Short: "Report that a step failed and apply its failure policy",
+	Long: `Report that the current attempt at a molecule step failed.
+
+The step's on_failure policy (from its formula, stored on the step bead)
+decides what happens next:
+
+  retry     Reopen the step for another attempt, until max_attempts is
+            used up; then escalate
+  skip      Close the step so the molecule continues without it
+  escalate  Leave the step open and escalate to the overseer (default)
+  burn      Abandon the molecule: close all its steps and the root
+
+Example:
+  gt mol step fail gt-abc.2 --reason "tests still failing after fix"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeStepFail,
+}
+
+var moleculeStepCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Enforce step time limits (patrol)",
+	Long: `Check running molecule steps against their failure policies.
+
+Run by the Witness (per rig) and Deacon (town beads) patrols. For each
+in-progress, hooked or pinned step bead with a policy:
+
+  - If no attempt is recorded yet, the clock starts now
+  - If the attempt has run past the step's timeout, the step fails and
+    its on_failure policy is applied (see 'gt mol step fail')
+
+Steps that were already escalated are not escalated again.
+
+Examples:
+  gt mol step check                  # Steps in the local beads
+  gt mol step check --rig gastown    # Steps in a rig's beads
+  gt mol step check --dry-run --json`,
+	Args: cobra.NoArgs,
+	RunE: runMoleculeStepCheck,
+}
+
+var (
+	stepFailReason  string
+	stepCheckRig    string
+	stepCheckDryRun bool
+)
+
+func init() {
+	moleculeStepFailCmd.Flags().StringVarP(&stepFailReason, "reason", "r", "", "Why the attempt failed")
+	moleculeStepFailCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
+	moleculeStepFailCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+
+	moleculeStepCheckCmd.Flags().StringVar(&stepCheckRig, "rig", "", "Check a rig's beads instead of the local beads")
+	moleculeStepCheckCmd.Flags().BoolVarP(&stepCheckDryRun, "dry-run", "n", false, "Show what would be done without executing")
+	moleculeStepCheckCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+
+	moleculeStepCmd.AddCommand(moleculeStepFailCmd)
+	moleculeStepCmd.AddCommand(moleculeStepCheckCmd)
+}
+
+// StepPolicyResult is the outcome of enforcing a step's policy.
+type StepPolicyResult struct {
+	StepID      string `json:"step_id"`
+	Title       string `json:"title"`
+	Action      string `json:"action"` // started, retry, skip, escalate, escalated, burn
+	Attempt     int    `json:"attempt"`
+	MaxAttempts int    `json:"max_attempts"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// StepPolicyStatus describes a step's time limit and attempts for status
+// output.
+type StepPolicyStatus struct {
+	StepID      string `json:"step_id"`
+	Status      string `json:"status"`
+	Timeout     string `json:"timeout,omitempty"`
+	Remaining   string `json:"remaining,omitempty"` // time left in the current attempt
+	Overdue     bool   `json:"overdue,omitempty"`
+	Attempts    int    `json:"attempts"`
+	MaxAttempts int    `json:"max_attempts"`
+	OnFailure   string `json:"on_failure"`
+	LastFailure string `json:"last_failure,omitempty"`
+}
+
+func runMoleculeStepFail(cmd *cobra.Command, args []string) error {
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("not in a beads workspace: %w", err)
+	}
+	b := beads.New(workDir)
+
+	step, err := b.Show(args[0])
+	if err != nil {
+		return fmt.Errorf("step not found: %w", err)
+	}
+	if step.Status == "closed" {
+		return fmt.Errorf("step %s is already closed", step.ID)
+	}
+
+	reason := stepFailReason
+	if reason == "" {
+		reason = "reported failed"
+	}
+	result, err := applyStepFailure(b, step, reason, moleculeStepDryRun)
+	if err != nil {
+		return err
+	}
+
+	if moleculeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	printStepPolicyResult(result, moleculeStepDryRun)
+	if result.Action == beads.OnFailureRetry {
+		fmt.Printf("\nThe step is open again - start over on it.\n")
+	}
+	return nil
+}
+
+func runMoleculeStepCheck(cmd *cobra.Command, args []string) error {
+	var workDir string
+	if stepCheckRig != "" {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		workDir = filepath.Join(townRoot, stepCheckRig, "mayor", "rig")
+		if _, err := os.Stat(workDir); err != nil {
+			return fmt.Errorf("rig %s not found: %w", stepCheckRig, err)
+		}
+	} else {
+		var err error
+		workDir, err = findLocalBeadsDir()
+		if err != nil {
+			return fmt.Errorf("not in a beads workspace: %w", err)
+		}
+	}
+	b := beads.New(workDir)
+
+	var running []*beads.Issue
+	for _, status := range []string{"in_progress", beads.StatusHooked, beads.StatusPinned} {
+		issues, err := b.List(beads.ListOptions{Status: status, Priority: -1})
+		if err != nil {
+			return fmt.Errorf("listing %s steps: %w", status, err)
+		}
+		running = append(running, issues...)
+	}
+
+	now := time.Now()
+	results := []StepPolicyResult{}
+	for _, step := range running {
+		policy := beads.ParseStepPolicyFields(step)
+		if policy == nil || policy.EscalatedAt != "" {
+			continue // no policy, or waiting on the overseer
+		}
+
+		if policy.StartedAt == "" {
+			// First sighting of this attempt: start the clock
+			if !stepCheckDryRun {
+				if err := startStepAttempt(b, step, policy, now); err != nil {
+					style.PrintWarning("could not start attempt for %s: %v", step.ID, err)
+					continue
+				}
+			} else {
+				policy.Attempts++
+			}
+			results = append(results, StepPolicyResult{
+				StepID: step.ID, Title: step.Title, Action: "started",
+				Attempt: policy.Attempts, MaxAttempts: policy.AttemptLimit(),
+			})
+			continue
+		}
+
+		if !policy.TimedOut(now) {
+			continue
+		}
+		reason := fmt.Sprintf("timed out after %s", policy.Timeout)
+		result, err := applyStepFailure(b, step, reason, stepCheckDryRun)
+		if err != nil {
+			style.PrintWarning("could not apply failure policy for %s: %v", step.ID, err)
+			continue
+		}
+		results = append(results, result)
+	}
+
+	if moleculeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("%s No steps over their time limit\n", style.SuccessPrefix)
+		return nil
+	}
+	for _, r := range results {
+		printStepPolicyResult(r, stepCheckDryRun)
+	}
+	return nil
+}
+
+// startStepAttempt records the start of a new attempt on a step bead.
+func startStepAttempt(b *beads.Beads, step *beads.Issue, policy *beads.StepPolicyFields, now time.Time) error {
+	policy.StartAttempt(now)
+	desc := beads.SetStepPolicyFields(step, policy)
+	return b.Update(step.ID, beads.UpdateOptions{Description: &desc})
+}
+
+// applyStepFailure ends the current attempt at a step and applies its
+// failure policy. Steps without a policy escalate.
+func applyStepFailure(b *beads.Beads, step *beads.Issue, reason string, dryRun bool) (StepPolicyResult, error) {
+	policy := beads.ParseStepPolicyFields(step)
+	if policy == nil {
+		policy = &beads.StepPolicyFields{}
+	}
+	if policy.Attempts == 0 {
+		policy.Attempts = 1
+	}
+
+	result := StepPolicyResult{
+		StepID:      step.ID,
+		Title:       step.Title,
+		Action:      policy.FailureAction(),
+		Attempt:     policy.Attempts,
+		MaxAttempts: policy.AttemptLimit(),
+		Reason:      reason,
+	}
+	if result.Action == beads.OnFailureEscalate && policy.EscalatedAt != "" {
+		result.Action = "escalated" // already escalated; don't repeat every patrol
+	}
+	if dryRun || result.Action == "escalated" {
+		return result, nil
+	}
+
+	policy.LastFailure = reason
+	policy.StartedAt = "" // the attempt is over
+
+	switch result.Action {
+	case beads.OnFailureRetry:
+		desc := beads.SetStepPolicyFields(step, policy)
+		status := "open"
+		if err := b.Update(step.ID, beads.UpdateOptions{Status: &status, Description: &desc}); err != nil {
+			return result, fmt.Errorf("reopening step: %w", err)
+		}
+		if step.Assignee != "" {
+			msg := fmt.Sprintf("Step %s failed (%s). Attempt %d of %d used - start over on it.",
+				step.ID, reason, policy.Attempts, policy.AttemptLimit())
+			nudgeCmd := exec.Command("gt", "nudge", strings.Replace(step.Assignee, "/polecats/", "/", 1), msg)
+			_ = nudgeCmd.Run() // best effort: the reopened step is the real signal
+		}
+
+	case beads.OnFailureSkip:
+		desc := beads.SetStepPolicyFields(step, policy)
+		if err := b.Update(step.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+			return result, fmt.Errorf("updating step: %w", err)
+		}
+		if err := b.CloseWithReason("skipped after failure: "+reason, step.ID); err != nil {
+			return result, fmt.Errorf("closing step: %w", err)
+		}
+
+	case beads.OnFailureEscalate:
+		policy.EscalatedAt = time.Now().UTC().Format(time.RFC3339)
+		desc := beads.SetStepPolicyFields(step, policy)
+		if err := b.Update(step.ID, beads.UpdateOptions{Description: &desc}); err != nil {
+			return result, fmt.Errorf("updating step: %w", err)
+		}
+		topic := fmt.Sprintf("Step %s failed: %s", step.ID, step.Title)
+		body := fmt.Sprintf("Step: %s\nAssignee: %s\nAttempt: %d of %d\nReason: %s",
+			step.ID, step.Assignee, policy.Attempts, policy.AttemptLimit(), reason)
+		escCmd := exec.Command("gt", "escalate", topic, "--severity", notify.SeverityHigh, "--message", body)
+		escCmd.Stderr = os.Stderr
+		if err := escCmd.Run(); err != nil {
+			return result, fmt.Errorf("escalating: %w", err)
+		}
+
+	case beads.OnFailureBurn:
+		moleculeID := step.Parent
+		if moleculeID == "" {
+			moleculeID = extractMoleculeIDFromStep(step.ID)
+		}
+		if moleculeID == "" {
+			return result, fmt.Errorf("cannot find molecule of step %s to burn", step.ID)
+		}
+		closed := closeDescendants(b, moleculeID)
+		if err := b.CloseWithReason(fmt.Sprintf("burned: step %s failed: %s", step.ID, reason), moleculeID); err != nil {
+			return result, fmt.Errorf("closing molecule %s: %w", moleculeID, err)
+		}
+		result.Reason = fmt.Sprintf("%s; closed %s and %d steps", reason, moleculeID, closed)
+	}
+	return result, nil
+}
+
+// printStepPolicyResult prints one enforcement outcome.
+func printStepPolicyResult(r StepPolicyResult, dryRun bool) {
+	prefix := ""
+	if dryRun {
+		prefix = "[dry-run] would "
+	}
+	attempt := fmt.Sprintf("attempt %d/%d", r.Attempt, r.MaxAttempts)
+	switch r.Action {
+	case "started":
+		fmt.Printf("%s %s%s %s: %s\n", style.Dim.Render("⏱"), prefix, "start clock on", r.StepID, attempt)
+	case "escalated":
+		fmt.Printf("%s %s: %s, already escalated\n", style.Dim.Render("⚠"), r.StepID, r.Reason)
+	case beads.OnFailureRetry:
+		fmt.Printf("%s %sretry %s (%s): %s\n", style.Bold.Render("↻"), prefix, r.StepID, attempt, r.Reason)
+	case beads.OnFailureSkip:
+		fmt.Printf("%s %sskip %s: %s\n", style.Dim.Render("⊘"), prefix, r.StepID, r.Reason)
+	case beads.OnFailureEscalate:
+		fmt.Printf("%s %sescalate %s (%s): %s\n", style.Warning.Render("⚠"), prefix, r.StepID, attempt, r.Reason)
+	case beads.OnFailureBurn:
+		fmt.Printf("%s %sburn molecule of %s: %s\n", style.Bold.Render("🔥"), prefix, r.StepID, r.Reason)
+	}
+}
+
+// stepPolicyStatus summarizes an open step's policy, or returns nil if it
+// has none.
+func stepPolicyStatus(step *beads.Issue, now time.Time) *StepPolicyStatus {
+	policy := beads.ParseStepPolicyFields(step)
+	if policy == nil {
+		return nil
+	}
+	onFailure := policy.OnFailure
+	if onFailure == "" {
+		onFailure = beads.OnFailureEscalate
+	}
+	status := &StepPolicyStatus{
+		StepID:      step.ID,
+		Status:      step.Status,
+		Timeout:     policy.Timeout,
+		Attempts:    policy.Attempts,
+		MaxAttempts: policy.AttemptLimit(),
+		OnFailure:   onFailure,
+		LastFailure: policy.LastFailure,
+	}
+	if remaining, ok := policy.Remaining(now); ok {
+		if remaining <= 0 {
+			status.Overdue = true
+			status.Remaining = "0s"
+		} else {
+			status.Remaining = formatDuration(remaining.Round(time.Second))
+		}
+	}
+	return status
+}
+
+// formatStepPolicyStatus renders time left and attempts on one line, e.g.
+// "12m 3s left · attempt 1/3 · on failure: retry".
+func formatStepPolicyStatus(ps StepPolicyStatus) string {
+	var parts []string
+	switch {
+	case ps.Overdue:
+		parts = append(parts, style.Warning.Render("over "+ps.Timeout+" limit"))
+	case ps.Remaining != "":
+		parts = append(parts, ps.Remaining+" left")
+	case ps.Timeout != "":
+		parts = append(parts, ps.Timeout+" limit, not started")
+	}
+	parts = append(parts, fmt.Sprintf("attempt %d/%d", ps.Attempts, ps.MaxAttempts))
+	parts = append(parts, "on failure: "+ps.OnFailure)
+	return strings.Join(parts, " · ")
+}
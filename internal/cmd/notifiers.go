@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/notify"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	notifiersListJSON     bool
+	notifiersTestSeverity string
+)
+
+var notifiersCmd = &cobra.Command{
+	Use:     "notifiers",
+	GroupID: GroupComm,
+	Short:   "Manage outbound escalation notifiers",
+	RunE:    requireSubcommand,
+	Long: `Manage outbound notifiers that deliver escalations outside the terminal.
+
+Notifiers live in town settings (settings/config.json) under "notifiers".
+Each fires when 'gt escalate' is run at or above its min_severity
+(default HIGH). Delivery results are logged as events:
+escalation_notified (audit) and escalation_notify_failed (feed).
+
+Types:
+  webhook   POST a templated JSON body (url, template, headers)
+  smtp      Email via SMTP relay (host, port, username, password_env, from, to)
+  desktop   notify-send on this machine (command)
+  ntfy      ntfy-style push: POST text to a topic URL (url, token_env)
+
+Example settings/config.json:
+  "notifiers": [
+    {"type": "ntfy", "url": "https://ntfy.sh/my-town", "min_severity": "HIGH"},
+    {"name": "slack", "type": "webhook", "url": "https://hooks.slack.com/...",
+     "template": "{\"text\": {{json .Title}}}", "min_severity": "CRITICAL"},
+    {"type": "desktop", "min_severity": "MEDIUM"}
+  ]`,
+}
+
+var notifiersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured notifiers",
+	Args:  cobra.NoArgs,
+	RunE:  runNotifiersList,
+}
+
+var notifiersTestCmd = &cobra.Command{
+	Use:   "test [name]",
+	Short: "Send a test escalation through notifiers",
+	Long: `Send a test escalation through configured notifiers.
+
+Without a name, every notifier that triggers at the given severity is
+tested. With a name, only that notifier is tested (regardless of its
+min_severity). No bead or overseer mail is created.
+
+Examples:
+  gt notifiers test                  # All notifiers that fire for HIGH
+  gt notifiers test -s CRITICAL      # All notifiers that fire for CRITICAL
+  gt notifiers test slack            # Just the "slack" notifier`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runNotifiersTest,
+}
+
+func init() {
+	notifiersListCmd.Flags().BoolVar(&notifiersListJSON, "json", false, "Output as JSON")
+	notifiersTestCmd.Flags().StringVarP(&notifiersTestSeverity, "severity", "s", notify.SeverityHigh,
+		"Severity of the test escalation: CRITICAL, HIGH, or MEDIUM")
+
+	notifiersCmd.AddCommand(notifiersListCmd)
+	notifiersCmd.AddCommand(notifiersTestCmd)
+	rootCmd.AddCommand(notifiersCmd)
+}
+
+// loadNotifiers loads the notifier configs from town settings.
+func loadNotifiers() (string, []*config.NotifierConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return "", nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if err := config.ValidateTownSettings(settings); err != nil {
+		return "", nil, fmt.Errorf("invalid town settings: %w", err)
+	}
+	return townRoot, settings.Notifiers, nil
+}
+
+func runNotifiersList(cmd *cobra.Command, args []string) error {
+	_, notifiers, err := loadNotifiers()
+	if err != nil {
+		return err
+	}
+
+	if notifiersListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if notifiers == nil {
+			notifiers = []*config.NotifierConfig{}
+		}
+		return enc.Encode(notifiers)
+	}
+
+	if len(notifiers) == 0 {
+		fmt.Printf("%s No notifiers configured (see 'gt notifiers --help')\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Escalation notifiers"))
+	for _, n := range notifiers {
+		min := n.MinSeverity
+		if min == "" {
+			min = notify.DefaultMinSeverity
+		}
+		status := ""
+		if n.Disabled {
+			status = " " + style.Dim.Render("(disabled)")
+		}
+		fmt.Printf("  %s %s%s\n", style.Bold.Render(n.DisplayName()), style.Dim.Render("["+n.Type+"]"), status)
+		fmt.Printf("    min severity: %s\n", strings.ToUpper(min))
+		if target := notifierTarget(n); target != "" {
+			fmt.Printf("    target: %s\n", target)
+		}
+	}
+	return nil
+}
+
+// notifierTarget describes where a notifier delivers to.
+func notifierTarget(n *config.NotifierConfig) string {
+	switch n.Type {
+	case config.NotifierWebhook, config.NotifierNtfy:
+		return n.URL
+	case config.NotifierSMTP:
+		return strings.Join(n.To, ", ") + " via " + n.Host
+	case config.NotifierDesktop:
+		if n.Command != "" {
+			return n.Command
+		}
+		return "notify-send"
+	}
+	return ""
+}
+
+func runNotifiersTest(cmd *cobra.Command, args []string) error {
+	severity := strings.ToUpper(notifiersTestSeverity)
+	if severity != notify.SeverityCritical && severity != notify.SeverityHigh && severity != notify.SeverityMedium {
+		return fmt.Errorf("invalid severity '%s': must be CRITICAL, HIGH, or MEDIUM", notifiersTestSeverity)
+	}
+
+	townRoot, notifiers, err := loadNotifiers()
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		var selected []*config.NotifierConfig
+		for _, n := range notifiers {
+			if n.DisplayName() == args[0] {
+				// Force the named notifier to fire at the test severity
+				forced := *n
+				forced.MinSeverity = notify.SeverityMedium
+				forced.Disabled = false
+				selected = append(selected, &forced)
+			}
+		}
+		if len(selected) == 0 {
+			return fmt.Errorf("no notifier named %q (see 'gt notifiers list')", args[0])
+		}
+		notifiers = selected
+	}
+
+	from, err := detectAgentIdentity()
+	if err != nil {
+		from = "overseer"
+	}
+	esc := &notify.Escalation{
+		Severity: severity,
+		Topic:    "Test notification from gt notifiers test",
+		Message:  "This is a test escalation. No action is needed.",
+		From:     from,
+		Town:     filepath.Base(townRoot),
+	}
+
+	results := notify.Dispatch(context.Background(), notifiers, esc)
+	notify.LogResults(from, esc, results)
+
+	if len(results) == 0 {
+		fmt.Printf("%s No notifiers fire at %s\n", style.Dim.Render("○"), severity)
+		return nil
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.OK() {
+			fmt.Printf("%s %s (%s) %s\n", style.SuccessPrefix, r.Name, r.Type, style.Dim.Render(r.Duration.Round(1e6).String()))
+		} else {
+			failed++
+			fmt.Printf("%s %s (%s): %v\n", style.ErrorPrefix, r.Name, r.Type, r.Err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d notifier(s) failed", failed, len(results))
+	}
+	return nil
+}
@@ -18,7 +18,7 @@ import (
 // When the gate closes, waiters are notified and can resume.
 
 var parkCmd = &cobra.Command{
-	Use:     "park <gate-id>",
+	Use:     "park [gate-id]",
 	GroupID: GroupWork,
 	Short:   "Park work on a gate for async resumption",
 	Long: `Park current work on a gate, allowing the agent to exit safely.
@@ -34,7 +34,21 @@ The park command:
 After parking, you can exit the session safely. Use 'gt resume' to check
 for cleared gates and continue work.
 
+Instead of a gate ID, give a condition to create a gate and park on it.
+The daemon opens it when the condition holds and sends you wake mail:
+  --timer 2h | "2026-11-01 09:00"     At a time
+  --bead gt-abc [--label approved]    When a bead closes (or gets a label)
+  --command "<cmd>"                   When a command exits 0 (run from here;
+                                      must be inside the town)
+  --mr gt-abc                         When a merge request merges
+
 Examples:
+  # Park until CI passes
+  gt park --command "gh run view 123456789 --exit-status" -m "Waiting for CI"
+
+  # Park until a dependency lands
+  gt park --bead gt-schema -m "Resume API work once the schema is in"
+
   # Create a timer gate and park work on it
   bd gate create --await timer:30m --title "Coffee break"
   gt park <gate-id> -m "Taking a break, will resume auth work"
@@ -46,16 +60,18 @@ Examples:
   # Park on a GitHub Actions gate
   bd gate create --await gh:run:123456789
   gt park <gate-id> -m "Waiting for CI to complete"`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	RunE: runPark,
 }
 
 var (
-	parkMessage string
-	parkDryRun  bool
+	parkMessage   string
+	parkDryRun    bool
+	parkCondition gateConditionFlags
 )
 
 func init() {
+	parkCondition.register(parkCmd)
 	parkCmd.Flags().StringVarP(&parkMessage, "message", "m", "", "Context notes for resumption")
 	parkCmd.Flags().BoolVarP(&parkDryRun, "dry-run", "n", false, "Show what would be done without executing")
 	rootCmd.AddCommand(parkCmd)
@@ -83,25 +99,50 @@ type ParkedWork struct {
 }
 
 func runPark(cmd *cobra.Command, args []string) error {
-	gateID := args[0]
-
-	// Verify gate exists and is open
-	gateCheck := exec.Command("bd", "gate", "show", gateID, "--json")
-	gateOutput, err := gateCheck.Output()
-	if err != nil {
-		return fmt.Errorf("gate '%s' not found or not accessible", gateID)
+	// A condition creates the gate to park on
+	var condition *beads.GateFields
+	switch {
+	case len(args) == 1 && parkCondition.set():
+		return fmt.Errorf("give a gate ID or a condition, not both")
+	case len(args) == 0 && !parkCondition.set():
+		return fmt.Errorf("give a gate ID, or a condition: --timer, --bead, --command or --mr")
+	case len(args) == 0:
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if condition, err = parkCondition.fields(cwd, time.Now()); err != nil {
+			return err
+		}
 	}
 
-	// Parse gate info to verify it's open
-	var gateInfo struct {
-		ID     string `json:"id"`
-		Status string `json:"status"`
-	}
-	if err := json.Unmarshal(gateOutput, &gateInfo); err != nil {
-		return fmt.Errorf("parsing gate info: %w", err)
-	}
-	if gateInfo.Status == "closed" {
-		return fmt.Errorf("gate '%s' is already closed - nothing to park on", gateID)
+	var gateID string
+	switch {
+	case condition == nil:
+		gateID = args[0]
+
+		// Verify gate exists and is open
+		gateInfo, err := showGate("", gateID)
+		if err != nil {
+			return err
+		}
+		if gateInfo.Status == "closed" {
+			return fmt.Errorf("gate '%s' is already closed - nothing to park on", gateID)
+		}
+	case parkDryRun:
+		gateID = "<new gate>"
+		fmt.Printf("Would create a %s gate that opens when %s\n", condition.Kind, condition.Describe())
+	default:
+		dir, err := findLocalBeadsDir()
+		if err != nil {
+			return fmt.Errorf("no beads database here: %w", err)
+		}
+		gate, err := createConditionGate(dir, condition, "")
+		if err != nil {
+			return err
+		}
+		gateID = gate.ID
+		fmt.Printf("%s Created %s gate %s: opens when %s\n", style.Bold.Render("🚦"), condition.Kind, gateID, condition.Describe())
 	}
 
 	// Detect agent identity
@@ -196,6 +237,9 @@ func runPark(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("  Context: %s\n", displayContext)
 	}
+	if condition != nil {
+		fmt.Printf("  Opens when: %s %s\n", condition.Describe(), style.Dim.Render("(checked by the daemon)"))
+	}
 	fmt.Printf("\n%s You can now safely exit. Run 'gt resume' to check for cleared gates.\n",
 		style.Dim.Render("→"))
 
@@ -34,7 +34,7 @@ func (s *SpawnedPolecatInfo) AgentID() string {
 
 // SlingSpawnOptions contains options for spawning a polecat via sling.
 type SlingSpawnOptions struct {
-	Force    bool   // Force spawn even if polecat has uncommitted work
+	Force    bool   // Force spawn even if polecat has uncommitted work or the rig is full
 	Naked    bool   // No-tmux mode: skip session creation
 	Account  string // Claude Code account handle to use
 	Create   bool   // Create polecat if it doesn't exist (currently always true for sling)
@@ -65,6 +65,13 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("rig '%s' not found", rigName)
 	}
 
+	// Respect the rig's max_polecats unless forced
+	if !opts.Force {
+		if err := checkRigCapacity(townRoot, r); err != nil {
+			return nil, err
+		}
+	}
+
 	// Get polecat manager
 	polecatGit := git.NewGit(r.Path)
 	polecatMgr := polecat.NewManager(r, polecatGit)
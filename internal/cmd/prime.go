@@ -164,6 +164,7 @@ func runPrime(cmd *cobra.Command, args []string) error {
 
 	// Output handoff content if present
 	outputHandoffContent(ctx)
+	outputHandoffChecklist(ctx)
 
 	// Output attachment status (for autonomous work detection)
 	outputAttachmentStatus(ctx)
@@ -46,6 +46,10 @@ DISCOVERY:
   gt seance --rig gastown       # Filter by rig
   gt seance --recent 10         # Last N sessions
 
+SEARCH (offline, no tokens):
+  gt seance search "refresh token"           # Search predecessor transcripts
+  gt seance search migration --rig gastown   # Filter by rig
+
 THE SEANCE (talk to predecessor):
   gt seance --talk <session-id>              # Interactive conversation
   gt seance --talk <id> -p "Where is X?"     # One-shot question
@@ -0,0 +1,433 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	seanceSearchRole     string
+	seanceSearchRig      string
+	seanceSearchLimit    int
+	seanceSearchSessions int
+	seanceSearchJSON     bool
+)
+
+var seanceSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search predecessor transcripts offline",
+	Long: `Search the local transcripts of predecessor sessions.
+
+Sessions are discovered from session_start events, as in 'gt seance'. Each
+session's transcript is read from Claude's local project files (under
+~/.claude, CLAUDE_CONFIG_DIR and any configured account directories), so
+searching costs no tokens and works offline.
+
+Every query word must appear in a turn for it to match. Matches are ranked
+by how often the words appear, with a bonus when the whole query appears
+as a phrase, then by recency.
+
+Examples:
+  gt seance search "refresh token"
+  gt seance search migration --rig gastown
+  gt seance search "where I left" --role crew -n 5
+  gt seance search flaky --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSeanceSearch,
+}
+
+func init() {
+	seanceSearchCmd.Flags().StringVar(&seanceSearchRole, "role", "", "Filter by role (crew, polecat, witness, etc.)")
+	seanceSearchCmd.Flags().StringVar(&seanceSearchRig, "rig", "", "Filter by rig name")
+	seanceSearchCmd.Flags().IntVarP(&seanceSearchLimit, "limit", "n", 10, "Maximum number of matches to show")
+	seanceSearchCmd.Flags().IntVar(&seanceSearchSessions, "sessions", 50, "Number of recent sessions to search (0 for all)")
+	seanceSearchCmd.Flags().BoolVar(&seanceSearchJSON, "json", false, "Output as JSON")
+
+	seanceCmd.AddCommand(seanceSearchCmd)
+}
+
+// transcriptTurn is one searchable turn of a session transcript.
+type transcriptTurn struct {
+	Role      string // user, assistant, or tool
+	Timestamp string
+	Text      string
+}
+
+// seanceHit is a transcript turn matching a seance search.
+type seanceHit struct {
+	SessionID string `json:"session_id"`
+	Actor     string `json:"actor"`
+	Rig       string `json:"rig,omitempty"`
+	Role      string `json:"role"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Score     int    `json:"score"`
+	Excerpt   string `json:"excerpt"`
+}
+
+func runSeanceSearch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("not in a Gas Town workspace")
+	}
+
+	query := strings.Join(args, " ")
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return fmt.Errorf("empty search query")
+	}
+
+	sessions, err := discoverSessions(townRoot)
+	if err != nil {
+		return fmt.Errorf("discovering sessions: %w", err)
+	}
+	sessions = filterSeanceSessions(sessions, seanceSearchRole, seanceSearchRig)
+	if seanceSearchSessions > 0 && len(sessions) > seanceSearchSessions {
+		sessions = sessions[:seanceSearchSessions]
+	}
+
+	configDirs := claudeConfigDirs(townRoot)
+	var hits []seanceHit
+	searched := 0
+	for _, s := range sessions {
+		sessionID := getPayloadString(s.Payload, "session_id")
+		path := findTranscript(configDirs, sessionID, getPayloadString(s.Payload, "cwd"))
+		if path == "" {
+			continue
+		}
+		turns, err := readTranscript(path)
+		if err != nil {
+			continue
+		}
+		searched++
+
+		for _, turn := range turns {
+			score := scoreTurn(turn.Text, terms, query)
+			if score == 0 {
+				continue
+			}
+			hits = append(hits, seanceHit{
+				SessionID: sessionID,
+				Actor:     s.Actor,
+				Rig:       actorRig(s.Actor),
+				Role:      turn.Role,
+				Timestamp: turn.Timestamp,
+				Score:     score,
+				Excerpt:   searchExcerpt(turn.Text, terms, 160),
+			})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Timestamp > hits[j].Timestamp
+	})
+	if seanceSearchLimit > 0 && len(hits) > seanceSearchLimit {
+		hits = hits[:seanceSearchLimit]
+	}
+
+	if seanceSearchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(hits)
+	}
+
+	if len(hits) == 0 {
+		fmt.Printf("No matches for %q in %d session transcript(s).\n", query, searched)
+		if searched < len(sessions) {
+			fmt.Println(style.Dim.Render(fmt.Sprintf("%d session(s) had no local transcript", len(sessions)-searched)))
+		}
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Matches for %q", query)))
+	for _, h := range hits {
+		who := h.Actor
+		if h.Rig != "" {
+			who = fmt.Sprintf("%s (rig %s)", h.Actor, h.Rig)
+		}
+		fmt.Printf("%s %s · %s · %s\n", style.Bold.Render(h.SessionID), who, h.Role,
+			style.Dim.Render(formatEventTime(h.Timestamp)))
+		fmt.Printf("  %s\n\n", h.Excerpt)
+	}
+	fmt.Println(style.Dim.Render(fmt.Sprintf("Searched %d session transcript(s). Ask a predecessor: gt seance --talk <session-id>", searched)))
+	return nil
+}
+
+// filterSeanceSessions keeps the sessions whose actor matches the role and
+// rig filters, and only the latest event of each session.
+func filterSeanceSessions(sessions []sessionEvent, role, rig string) []sessionEvent {
+	seen := make(map[string]bool)
+	var filtered []sessionEvent
+	for _, s := range sessions {
+		actor := strings.ToLower(s.Actor)
+		if role != "" && !strings.Contains(actor, strings.ToLower(role)) {
+			continue
+		}
+		if rig != "" && !strings.Contains(actor, strings.ToLower(rig)) {
+			continue
+		}
+		sessionID := getPayloadString(s.Payload, "session_id")
+		if sessionID == "" || seen[sessionID] {
+			continue
+		}
+		seen[sessionID] = true
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
+
+// actorRig returns the rig of an agent identity like "gastown/crew/max",
+// or "" for town-level agents.
+func actorRig(actor string) string {
+	rig, _, ok := strings.Cut(actor, "/")
+	if !ok || rig == "mayor" || rig == "deacon" {
+		return ""
+	}
+	return rig
+}
+
+// claudeConfigDirs returns the Claude config directories that may hold
+// session transcripts: CLAUDE_CONFIG_DIR, ~/.claude, and every configured
+// account's directory.
+func claudeConfigDirs(townRoot string) []string {
+	var dirs []string
+	add := func(dir string) {
+		if dir == "" {
+			return
+		}
+		for _, d := range dirs {
+			if d == dir {
+				return
+			}
+		}
+		dirs = append(dirs, dir)
+	}
+
+	add(os.Getenv("CLAUDE_CONFIG_DIR"))
+	if home, err := os.UserHomeDir(); err == nil {
+		add(filepath.Join(home, ".claude"))
+	}
+	if cfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot)); err == nil {
+		for _, dir := range cfg.ConfigDirs() {
+			add(dir)
+		}
+	}
+	return dirs
+}
+
+// claudeProjectDir returns the name Claude gives the project directory for
+// a working directory: every character other than a letter or digit
+// becomes a dash.
+func claudeProjectDir(cwd string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '-'
+	}, cwd)
+}
+
+// findTranscript returns the transcript file of a session, looking in the
+// session's project directory first and then in every project. Returns ""
+// if there is none.
+func findTranscript(configDirs []string, sessionID, cwd string) string {
+	if sessionID == "" || strings.ContainsAny(sessionID, `/\*?[`) {
+		return ""
+	}
+	name := sessionID + ".jsonl"
+	for _, dir := range configDirs {
+		if cwd != "" {
+			path := filepath.Join(dir, "projects", claudeProjectDir(cwd), name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+		if matches, _ := filepath.Glob(filepath.Join(dir, "projects", "*", name)); len(matches) > 0 {
+			return matches[0]
+		}
+	}
+	return ""
+}
+
+// transcriptLine is the part of a transcript line seance search reads.
+type transcriptLine struct {
+	Type      string `json:"type"`
+	Timestamp string `json:"timestamp"`
+	Message   struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// transcriptBlock is a content block of a transcript message.
+type transcriptBlock struct {
+	Type    string          `json:"type"`
+	Text    string          `json:"text"`
+	Input   json.RawMessage `json:"input"`
+	Content json.RawMessage `json:"content"`
+}
+
+// readTranscript reads the user, assistant and tool turns of a session
+// transcript. Text blocks keep the speaker's role; tool calls and results
+// become tool turns.
+func readTranscript(path string) ([]transcriptTurn, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is found under Claude's config dirs
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var turns []transcriptTurn
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var line transcriptLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if line.Type != "user" && line.Type != "assistant" {
+			continue
+		}
+		role := line.Message.Role
+		if role == "" {
+			role = line.Type
+		}
+		for _, turn := range transcriptContent(role, line.Message.Content) {
+			turn.Timestamp = line.Timestamp
+			turns = append(turns, turn)
+		}
+	}
+	return turns, scanner.Err()
+}
+
+// transcriptContent splits a message's content into searchable turns.
+func transcriptContent(role string, content json.RawMessage) []transcriptTurn {
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return []transcriptTurn{{Role: role, Text: text}}
+	}
+
+	var blocks []transcriptBlock
+	if err := json.Unmarshal(content, &blocks); err != nil {
+		return nil
+	}
+	var turns []transcriptTurn
+	for _, b := range blocks {
+		var turn transcriptTurn
+		switch b.Type {
+		case "text":
+			turn = transcriptTurn{Role: role, Text: b.Text}
+		case "tool_use":
+			turn = transcriptTurn{Role: "tool", Text: string(b.Input)}
+		case "tool_result":
+			turn = transcriptTurn{Role: "tool", Text: toolResultText(b.Content)}
+		}
+		if strings.TrimSpace(turn.Text) != "" {
+			turns = append(turns, turn)
+		}
+	}
+	return turns
+}
+
+// toolResultText returns the text of a tool result, which is either a
+// string or a list of text blocks.
+func toolResultText(content json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		return text
+	}
+	var blocks []transcriptBlock
+	if err := json.Unmarshal(content, &blocks); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// searchTerms splits a query into lowercase words.
+func searchTerms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// scoreTurn scores a turn against a query: 0 unless every term appears,
+// otherwise the number of term occurrences, plus 5 if the whole query
+// appears as a phrase.
+func scoreTurn(text string, terms []string, query string) int {
+	lower := strings.ToLower(text)
+	score := 0
+	for _, term := range terms {
+		n := strings.Count(lower, term)
+		if n == 0 {
+			return 0
+		}
+		score += n
+	}
+	if len(terms) > 1 && strings.Contains(lower, strings.ToLower(strings.Join(strings.Fields(query), " "))) {
+		score += 5
+	}
+	return score
+}
+
+// searchExcerpt returns about width characters of text around the first
+// match of any term, on one line.
+func searchExcerpt(text string, terms []string, width int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+
+	lower := strings.ToLower(text)
+	first := -1
+	for _, term := range terms {
+		if i := strings.Index(lower, term); i >= 0 && (first == -1 || i < first) {
+			first = i
+		}
+	}
+	// Convert the byte offset to a rune offset
+	pos := 0
+	if first > 0 {
+		pos = min(len([]rune(lower[:first])), len(runes))
+	}
+
+	start := pos - width/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + width
+	if end > len(runes) {
+		end = len(runes)
+		start = max(0, end-width)
+	}
+
+	excerpt := string(runes[start:end])
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(runes) {
+		excerpt += "…"
+	}
+	return excerpt
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testTranscript = `{"type":"mode","mode":"normal","sessionId":"abc"}
+{"type":"user","timestamp":"2026-10-19T10:00:00Z","message":{"role":"user","content":"Where did the refresh token logic go?"}}
+{"type":"assistant","timestamp":"2026-10-19T10:00:05Z","message":{"role":"assistant","content":[{"type":"text","text":"I moved the refresh token code into auth/refresh.go."},{"type":"tool_use","input":{"command":"git mv token.go auth/refresh.go"}}]}}
+{"type":"user","timestamp":"2026-10-19T10:00:06Z","message":{"role":"user","content":[{"type":"tool_result","content":[{"type":"text","text":"renamed token.go"}]}]}}
+not json
+`
+
+func TestReadTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc.jsonl")
+	if err := os.WriteFile(path, []byte(testTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	turns, err := readTranscript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roles []string
+	for _, turn := range turns {
+		roles = append(roles, turn.Role)
+	}
+	if want := []string{"user", "assistant", "tool", "tool"}; !reflect.DeepEqual(roles, want) {
+		t.Fatalf("roles = %v, want %v", roles, want)
+	}
+	if !strings.Contains(turns[2].Text, "git mv token.go") {
+		t.Errorf("tool_use turn = %q", turns[2].Text)
+	}
+	if turns[3].Text != "renamed token.go" || turns[3].Timestamp != "2026-10-19T10:00:06Z" {
+		t.Errorf("tool_result turn = %+v", turns[3])
+	}
+}
+
+func TestFindTranscript(t *testing.T) {
+	configDir := t.TempDir()
+	projectDir := filepath.Join(configDir, "projects", claudeProjectDir("/home/gt/gastown/crew/max"))
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(projectDir, "abc.jsonl")
+	if err := os.WriteFile(want, []byte(testTranscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dirs := []string{filepath.Join(t.TempDir(), "missing"), configDir}
+	if got := findTranscript(dirs, "abc", "/home/gt/gastown/crew/max"); got != want {
+		t.Errorf("findTranscript() by cwd = %q, want %q", got, want)
+	}
+	if got := findTranscript(dirs, "abc", "/elsewhere"); got != want {
+		t.Errorf("findTranscript() by glob = %q, want %q", got, want)
+	}
+	if got := findTranscript(dirs, "*", ""); got != "" {
+		t.Errorf("findTranscript() with a pattern ID = %q, want none", got)
+	}
+}
+
+func TestClaudeProjectDir(t *testing.T) {
+	if got := claudeProjectDir("/home/gt/my.rig/crew_max"); got != "-home-gt-my-rig-crew-max" {
+		t.Errorf("claudeProjectDir() = %q", got)
+	}
+}
+
+func TestScoreTurn(t *testing.T) {
+	terms := searchTerms("Refresh Token")
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"the refresh token moved", 2 + 5},
+		{"token refresh, then refresh", 3},
+		{"refresh only", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := scoreTurn(tt.text, terms, "Refresh Token"); got != tt.want {
+			t.Errorf("scoreTurn(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSearchExcerpt(t *testing.T) {
+	if got := searchExcerpt("short\n  text", []string{"text"}, 40); got != "short text" {
+		t.Errorf("short excerpt = %q", got)
+	}
+
+	long := strings.Repeat("a ", 100) + "needle" + strings.Repeat(" b", 100)
+	got := searchExcerpt(long, []string{"needle"}, 40)
+	if !strings.Contains(got, "needle") || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("long excerpt = %q", got)
+	}
+}
+
+func TestFilterSeanceSessions(t *testing.T) {
+	event := func(actor, id string) sessionEvent {
+		return sessionEvent{Actor: actor, Payload: map[string]interface{}{"session_id": id}}
+	}
+	sessions := []sessionEvent{
+		event("gastown/crew/max", "s3"),
+		event("gastown/crew/max", "s3"), // Same session primed again
+		event("beads/witness", "s2"),
+		event("mayor/", "s1"),
+		event("gastown/crew/joe", ""),
+	}
+
+	var ids []string
+	for _, s := range filterSeanceSessions(sessions, "", "gastown") {
+		ids = append(ids, getPayloadString(s.Payload, "session_id"))
+	}
+	if want := []string{"s3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("filtered = %v, want %v", ids, want)
+	}
+
+	if got := actorRig("gastown/crew/max"); got != "gastown" {
+		t.Errorf("actorRig() = %q", got)
+	}
+	if got := actorRig("mayor/"); got != "" {
+		t.Errorf("actorRig(mayor/) = %q, want none", got)
+	}
+}
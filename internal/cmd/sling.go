@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/base32"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -86,6 +87,28 @@ Compare:
 
 The propulsion principle: if it's on your hook, YOU RUN IT.
 
+Auto-Routing (--auto):
+  gt sling gt-abc --auto                # Pick a rig, spawn a polecat there
+
+  Bead labels of the form lang:X, area:X, cli:X and cap:X are requirements.
+  A rig qualifies if its capabilities (rig settings, plus "cli:<agent>" and
+  the agent's own tags) include all of them and it is below max_polecats.
+  The least loaded qualifying rig wins, then the bead's home rig. The
+  candidates and the reason for the choice are printed.
+
+  Rig settings (<rig>/settings/config.json):
+    "capabilities": ["lang:go", "area:cli"],
+    "max_polecats": 4
+
+Dispatch Queue (backpressure):
+  gt sling gt-abc gastown --queue       # Queue now, start when a slot frees
+  gt sling gt-abc --auto --queue        # Queue, choose the rig when started
+
+  Slinging to a rig at its max_polecats (or the town at its max_polecats)
+  queues the work instead of spawning; --force spawns anyway. The daemon
+  starts queued work in priority order as polecats finish. See
+  'gt dispatch list'.
+
 Batch Slinging:
   gt sling gt-abc gt-def gt-ghi gastown   # Sling multiple beads to a rig
 
@@ -111,6 +134,8 @@ var (
 	slingAccount  string // --account: Claude Code account handle to use
 	slingQuality  string // --quality: shorthand for polecat workflow (basic|shiny|chrome)
 	slingNoConvoy bool   // --no-convoy: skip auto-convoy creation
+	slingAuto     bool   // --auto: choose the rig from labels, load and capacity
+	slingQueue    bool   // --queue: wait in the dispatch queue instead of spawning now
 )
 
 func init() {
@@ -129,6 +154,8 @@ func init() {
 	slingCmd.Flags().StringVar(&slingAccount, "account", "", "Claude Code account handle to use")
 	slingCmd.Flags().StringVarP(&slingQuality, "quality", "q", "", "Polecat workflow quality level (basic|shiny|chrome)")
 	slingCmd.Flags().BoolVar(&slingNoConvoy, "no-convoy", false, "Skip auto-convoy creation for single-issue sling")
+	slingCmd.Flags().BoolVar(&slingAuto, "auto", false, "Choose a rig from the bead's capability labels and rig load")
+	slingCmd.Flags().BoolVar(&slingQueue, "queue", false, "Add to the dispatch queue; the daemon starts it when a polecat slot is free")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -152,6 +179,10 @@ func runSling(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--var cannot be used with --on (formula-on-bead mode doesn't support variables)")
 	}
 
+	if slingAuto && len(args) > 1 {
+		return fmt.Errorf("--auto chooses the target: give one bead and no target")
+	}
+
 	// Batch mode detection: multiple beads with rig target
 	// Pattern: gt sling gt-abc gt-def gt-ghi gastown
 	// When len(args) > 2 and last arg is a rig, sling each bead to its own polecat
@@ -205,6 +236,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 			// Not a bead - try as standalone formula
 			if err := verifyFormulaExists(firstArg); err == nil {
 				// Standalone formula mode: gt sling <formula> [target]
+				if slingQueue || slingAuto {
+					return fmt.Errorf("--queue and --auto need a bead (use --on <bead> to apply a formula)")
+				}
 				return runSlingFormula(args)
 			}
 			// Neither bead nor formula
@@ -212,6 +246,34 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// --auto: pick a rig from the bead's labels and rig load, then spawn
+	// there as if the rig had been named
+	if slingAuto {
+		if slingQueue {
+			// Route when the daemon starts it, against the load at that time
+			return queueSling(townRoot, beadID, formulaName, "", "queued with --queue")
+		}
+		info, err := getBeadInfo(beadID)
+		if err != nil {
+			return fmt.Errorf("checking bead labels: %w", err)
+		}
+		rigName, err := chooseSlingRig(townRoot, beadID, info.Labels)
+		if errors.Is(err, errNoFreeRig) {
+			return queueSling(townRoot, beadID, formulaName, "", err.Error())
+		}
+		if err != nil {
+			return err
+		}
+		args = append(args, rigName)
+	} else if slingQueue {
+		if len(args) < 2 {
+			return fmt.Errorf("--queue needs a rig target, or --auto")
+		}
+		if _, isRig := IsRigName(args[1]); !isRig {
+			return fmt.Errorf("--queue only applies to rig targets (polecats), not %s", args[1])
+		}
+	}
+
 	// Determine target agent (self or specified)
 	var targetAgent string
 	var targetPane string
@@ -249,6 +311,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 				fmt.Printf("Dispatched to dog %s\n", dispatchInfo.DogName)
 			}
 		} else if rigName, isRig := IsRigName(target); isRig {
+			// Backpressure: wait in the dispatch queue when the rig is full
+			if queued, err := queueSlingIfFull(townRoot, rigName, beadID, formulaName); queued || err != nil {
+				return err
+			}
+
 			// Check if target is a rig name (auto-spawn polecat)
 			if slingDryRun {
 				// Dry run - just indicate what would happen
@@ -602,9 +669,11 @@ func verifyBeadExists(beadID string) error {
 
 // beadInfo holds status and assignee for a bead.
 type beadInfo struct {
-	Title    string `json:"title"`
-	Status   string `json:"status"`
-	Assignee string `json:"assignee"`
+	Title    string   `json:"title"`
+	Status   string   `json:"status"`
+	Assignee string   `json:"assignee"`
+	Priority int      `json:"priority"`
+	Labels   []string `json:"labels"`
 }
 
 // getBeadInfo returns status and assignee for a bead.
@@ -1303,6 +1372,15 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		// Beads beyond the rig's capacity wait in the dispatch queue
+		if queued, err := queueSlingIfFull(filepath.Dir(townBeadsDir), rigName, beadID, ""); err != nil {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
+			continue
+		} else if queued {
+			results = append(results, slingResult{beadID: beadID, polecat: "(queued)", success: true})
+			continue
+		}
+
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
 			Force:    slingForce,
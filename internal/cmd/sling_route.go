@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dispatch"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// capabilityKinds are the label prefixes that gt sling --auto treats as
+// requirements: a bead labelled "lang:go" needs a rig tagged "lang:go".
+var capabilityKinds = []string{"lang:", "area:", "cli:", "cap:"}
+
+// requiredCapabilities returns the bead labels that are capability tags,
+// lowercased.
+func requiredCapabilities(labels []string) []string {
+	var required []string
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		for _, kind := range capabilityKinds {
+			if strings.HasPrefix(label, kind) && len(label) > len(kind) {
+				required = append(required, label)
+				break
+			}
+		}
+	}
+	return required
+}
+
+// slingCandidate is a rig considered by gt sling --auto.
+type slingCandidate struct {
+	Rig          string   `json:"rig"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	Active       int      `json:"active"`        // working polecats
+	Max          int      `json:"max,omitempty"` // max_polecats; 0 for no limit
+	Home         bool     `json:"home,omitempty"`
+	Missing      []string `json:"missing,omitempty"` // required tags the rig lacks
+	Chosen       bool     `json:"chosen,omitempty"`
+	Reason       string   `json:"reason"`
+}
+
+// full reports whether the rig has no free polecat slots.
+func (c *slingCandidate) full() bool {
+	return c.Max > 0 && c.Active >= c.Max
+}
+
+// load is the fraction of capacity in use. Rigs without a limit count
+// as if each working polecat used a tenth of the rig.
+func (c *slingCandidate) load() float64 {
+	if c.Max > 0 {
+		return float64(c.Active) / float64(c.Max)
+	}
+	return float64(c.Active) / 10
+}
+
+func (c *slingCandidate) loadString() string {
+	if c.Max > 0 {
+		return fmt.Sprintf("%d/%d polecats", c.Active, c.Max)
+	}
+	return fmt.Sprintf("%d polecats", c.Active)
+}
+
+// rankSlingCandidates picks a rig for a bead needing the given tags. A rig
+// qualifies if it has every required tag and a free slot. Qualifying rigs
+// are ranked by load, then the bead's home rig (the rig its prefix routes
+// to), then name. Every candidate gets a Reason; the winner is first and
+// marked Chosen. Returns false if no rig qualifies.
+func rankSlingCandidates(required []string, cands []slingCandidate) ([]slingCandidate, bool) {
+	for i := range cands {
+		c := &cands[i]
+		have := make(map[string]bool, len(c.Capabilities))
+		for _, tag := range c.Capabilities {
+			have[tag] = true
+		}
+		c.Missing = nil
+		for _, tag := range required {
+			if !have[tag] {
+				c.Missing = append(c.Missing, tag)
+			}
+		}
+	}
+
+	qualifies := func(c *slingCandidate) bool { return len(c.Missing) == 0 && !c.full() }
+	sort.SliceStable(cands, func(i, j int) bool {
+		a, b := &cands[i], &cands[j]
+		if qa, qb := qualifies(a), qualifies(b); qa != qb {
+			return qa
+		}
+		if a.load() != b.load() {
+			return a.load() < b.load()
+		}
+		if a.Home != b.Home {
+			return a.Home
+		}
+		return a.Rig < b.Rig
+	})
+
+	for i := range cands {
+		c := &cands[i]
+		switch {
+		case len(c.Missing) > 0:
+			c.Reason = "missing " + strings.Join(c.Missing, ", ")
+		case c.full():
+			c.Reason = "at capacity"
+		case i == 0:
+			c.Chosen = true
+			c.Reason = "least loaded"
+			if len(required) > 0 {
+				c.Reason = "has " + strings.Join(required, ", ") + "; least loaded"
+			}
+			if len(cands) > 1 && qualifies(&cands[1]) && cands[1].load() == c.load() && c.Home {
+				c.Reason += "; home rig for the bead"
+			}
+		default:
+			c.Reason = "more loaded"
+		}
+	}
+	return cands, len(cands) > 0 && cands[0].Chosen
+}
+
+// gatherSlingCandidates describes every rig in the town for gt sling
+// --auto. homeRig is the rig the bead's prefix routes to, if any.
+func gatherSlingCandidates(townRoot, homeRig string) ([]slingCandidate, error) {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs: %w", err)
+	}
+	rigMgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+
+	var cands []slingCandidate
+	for _, name := range rigMgr.ListRigNames() {
+		r, err := rigMgr.GetRig(name)
+		if err != nil {
+			continue
+		}
+		active, limit := rigPolecatLoad(r)
+		cands = append(cands, slingCandidate{
+			Rig:          name,
+			Capabilities: config.ResolveRigCapabilities(townRoot, r.Path),
+			Active:       active,
+			Max:          limit,
+			Home:         name == homeRig,
+		})
+	}
+	if len(cands) == 0 {
+		return nil, fmt.Errorf("no rigs in this town")
+	}
+	return cands, nil
+}
+
+// rigPolecatLoad returns how many polecats are working in a rig, and its
+// max_polecats setting (0 for no limit). Polecats that are done and
+// waiting to be nuked don't count.
+func rigPolecatLoad(r *rig.Rig) (active, limit int) {
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path)); err == nil {
+		limit = settings.MaxPolecats
+	}
+	polecats, err := polecat.NewManager(r, git.NewGit(r.Path)).List()
+	if err != nil {
+		return 0, limit
+	}
+	for _, p := range polecats {
+		if p.State != polecat.StateDone {
+			active++
+		}
+	}
+	return active, limit
+}
+
+// townPolecatLoad returns how many polecats are working across the town,
+// and the town's max_polecats setting. Without a limit nothing is counted.
+func townPolecatLoad(townRoot string) (active, limit int) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || settings.MaxPolecats == 0 {
+		return 0, 0
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return 0, settings.MaxPolecats
+	}
+	rigMgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	for _, name := range rigMgr.ListRigNames() {
+		if r, err := rigMgr.GetRig(name); err == nil {
+			n, _ := rigPolecatLoad(r)
+			active += n
+		}
+	}
+	return active, settings.MaxPolecats
+}
+
+// spawnCapacityFull reports whether a new polecat in the rig would exceed
+// the rig's or the town's max_polecats, and which.
+func spawnCapacityFull(townRoot string, r *rig.Rig) (string, bool) {
+	if active, limit := rigPolecatLoad(r); limit > 0 && active >= limit {
+		return fmt.Sprintf("rig '%s' is at capacity (%d/%d polecats working)", r.Name, active, limit), true
+	}
+	if active, limit := townPolecatLoad(townRoot); limit > 0 && active >= limit {
+		return fmt.Sprintf("town is at capacity (%d/%d polecats working)", active, limit), true
+	}
+	return "", false
+}
+
+// checkRigCapacity refuses to spawn a polecat beyond max_polecats.
+func checkRigCapacity(townRoot string, r *rig.Rig) error {
+	if reason, full := spawnCapacityFull(townRoot, r); full {
+		return fmt.Errorf("%s\nUse --queue to wait for a free slot, or --force to spawn anyway", reason)
+	}
+	return nil
+}
+
+// homeRigForBead returns the rig the bead's prefix routes to, or "".
+func homeRigForBead(townRoot, beadID string) string {
+	routes, err := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		return ""
+	}
+	for _, r := range routes {
+		if strings.HasPrefix(beadID, r.Prefix) {
+			return strings.SplitN(r.Path, "/", 2)[0]
+		}
+	}
+	return ""
+}
+
+// errNoFreeRig means some rig could take the bead, but every such rig (or
+// the town) is at max_polecats.
+var errNoFreeRig = errors.New("every rig that can take the bead is at capacity")
+
+// chooseSlingRig picks a rig for gt sling --auto and explains the choice.
+func chooseSlingRig(townRoot, beadID string, labels []string) (string, error) {
+	required := requiredCapabilities(labels)
+	cands, err := gatherSlingCandidates(townRoot, homeRigForBead(townRoot, beadID))
+	if err != nil {
+		return "", err
+	}
+	ranked, ok := rankSlingCandidates(required, cands)
+
+	needs := "no capability labels"
+	if len(required) > 0 {
+		needs = "needs " + strings.Join(required, ", ")
+	}
+	fmt.Printf("%s Auto-routing %s (%s)\n", style.Bold.Render("🧭"), beadID, needs)
+	width := 0
+	for _, c := range ranked {
+		width = max(width, len(c.Rig))
+	}
+	for _, c := range ranked {
+		mark := style.Dim.Render("·")
+		switch {
+		case c.Chosen:
+			mark = style.SuccessPrefix
+		case len(c.Missing) > 0 || c.full():
+			mark = style.ErrorPrefix
+		}
+		fmt.Printf("  %s %-*s  %-14s  %s\n", mark, width, c.Rig, c.loadString(), c.Reason)
+	}
+
+	if !ok {
+		for _, c := range ranked {
+			if len(c.Missing) == 0 {
+				return "", errNoFreeRig
+			}
+		}
+		return "", fmt.Errorf("no rig can take %s: tag a rig with 'capabilities' in its settings/config.json, or name a target", beadID)
+	}
+	if active, limit := townPolecatLoad(townRoot); limit > 0 && active >= limit {
+		fmt.Printf("  %s town is at capacity (%d/%d polecats working)\n", style.ErrorPrefix, active, limit)
+		return "", errNoFreeRig
+	}
+	fmt.Printf("  %s %s\n\n", style.ArrowPrefix, ranked[0].Rig)
+	return ranked[0].Rig, nil
+}
+
+// queueSlingIfFull queues the sling instead of spawning when --queue is
+// set or the rig (or town) is at max_polecats. --force skips the check.
+func queueSlingIfFull(townRoot, rigName, beadID, formulaName string) (bool, error) {
+	reason := "queued with --queue"
+	if !slingQueue {
+		if slingForce {
+			return false, nil
+		}
+		_, r, err := getRig(rigName)
+		if err != nil {
+			return false, nil // Let the spawn report it
+		}
+		full, isFull := spawnCapacityFull(townRoot, r)
+		if !isFull {
+			return false, nil
+		}
+		reason = full
+	}
+	return true, queueSling(townRoot, beadID, formulaName, rigName, reason)
+}
+
+// queueSling adds the sling to the town dispatch queue. rigName is empty
+// to route with --auto when the item starts.
+func queueSling(townRoot, beadID, formulaName, rigName, reason string) error {
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return fmt.Errorf("checking bead: %w", err)
+	}
+	item := &dispatch.Item{
+		BeadID:     beadID,
+		Title:      info.Title,
+		Formula:    formulaName,
+		Rig:        rigName,
+		Priority:   info.Priority,
+		Flags:      queuedSlingFlags(),
+		EnqueuedBy: detectActor(),
+	}
+
+	if slingDryRun {
+		fmt.Printf("Would queue %s for %s (%s)\n", beadID, item.Target(), reason)
+		return nil
+	}
+
+	q := dispatch.New(townRoot)
+	item, err = q.Enqueue(item)
+	if errors.Is(err, dispatch.ErrAlreadyQueued) {
+		fmt.Printf("%s %s is already queued as %s for %s\n", style.Dim.Render("○"), beadID, item.ID, item.Target())
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("queueing sling: %w", err)
+	}
+
+	position, total := 0, 0
+	if items, err := q.List(); err == nil {
+		total = len(items)
+		for i, queued := range items {
+			if queued.ID == item.ID {
+				position = i + 1
+			}
+		}
+	}
+	fmt.Printf("%s Queued %s for %s as %s (%s)\n", style.Bold.Render("⏳"), beadID, item.Target(), item.ID, reason)
+	fmt.Printf("  Position %d of %d. The daemon starts it when a polecat slot frees up.\n", position, total)
+	fmt.Printf("  %s\n", style.Dim.Render("See 'gt dispatch list'; 'gt dispatch promote "+item.ID+"' to move it up."))
+	return nil
+}
+
+// queuedSlingFlags returns the gt sling flags to replay when a queued item
+// starts. --quality was already turned into the item's formula, and --force
+// is not replayed so a queued item never bypasses capacity.
+func queuedSlingFlags() []string {
+	var flags []string
+	if slingNaked {
+		flags = append(flags, "--naked")
+	}
+	if slingCreate {
+		flags = append(flags, "--create")
+	}
+	if slingNoConvoy {
+		flags = append(flags, "--no-convoy")
+	}
+	for _, f := range []struct{ name, value string }{
+		{"--molecule", slingMolecule},
+		{"--account", slingAccount},
+		{"--args", slingArgs},
+		{"--subject", slingSubject},
+		{"--message", slingMessage},
+	} {
+		if f.value != "" {
+			flags = append(flags, f.name, f.value)
+		}
+	}
+	return flags
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequiredCapabilities(t *testing.T) {
+	got := requiredCapabilities([]string{"bug", "Lang:Go", "area:cli", "lang:", "cap:vision", "priority:high"})
+	want := []string{"lang:go", "area:cli", "cap:vision"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requiredCapabilities = %v, want %v", got, want)
+	}
+}
+
+func TestRankSlingCandidates(t *testing.T) {
+	cands := []slingCandidate{
+		{Rig: "frontend", Capabilities: []string{"lang:ts", "cli:claude"}},
+		{Rig: "busy", Capabilities: []string{"lang:go", "cli:claude"}, Active: 3, Max: 4},
+		{Rig: "full", Capabilities: []string{"lang:go", "cli:claude"}, Active: 2, Max: 2},
+		{Rig: "gastown", Capabilities: []string{"lang:go", "cli:claude"}, Active: 1, Max: 4},
+	}
+
+	ranked, ok := rankSlingCandidates([]string{"lang:go"}, cands)
+	if !ok {
+		t.Fatal("expected a rig to qualify")
+	}
+	var order []string
+	for _, c := range ranked {
+		order = append(order, c.Rig)
+	}
+	if want := []string{"gastown", "busy", "frontend", "full"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if !ranked[0].Chosen || ranked[0].Reason != "has lang:go; least loaded" {
+		t.Errorf("winner = %+v", ranked[0])
+	}
+	for _, c := range ranked[1:] {
+		if c.Chosen {
+			t.Errorf("%s also chosen", c.Rig)
+		}
+	}
+	if ranked[2].Reason != "missing lang:go" || ranked[3].Reason != "at capacity" {
+		t.Errorf("reasons = %q, %q", ranked[2].Reason, ranked[3].Reason)
+	}
+}
+
+func TestRankSlingCandidates_HomeRigBreaksTies(t *testing.T) {
+	cands := []slingCandidate{
+		{Rig: "alpha", Capabilities: []string{"cli:claude"}},
+		{Rig: "beads", Capabilities: []string{"cli:claude"}, Home: true},
+	}
+	ranked, ok := rankSlingCandidates(nil, cands)
+	if !ok || ranked[0].Rig != "beads" {
+		t.Fatalf("winner = %+v, want beads", ranked[0])
+	}
+	if ranked[0].Reason != "least loaded; home rig for the bead" {
+		t.Errorf("Reason = %q", ranked[0].Reason)
+	}
+}
+
+func TestRankSlingCandidates_NoneQualify(t *testing.T) {
+	cands := []slingCandidate{{Rig: "gastown", Capabilities: []string{"cli:claude"}}}
+	if _, ok := rankSlingCandidates([]string{"cli:codex"}, cands); ok {
+		t.Error("expected no rig to qualify")
+	}
+}
@@ -58,6 +58,10 @@ type AgentPresetInfo struct {
 
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
+
+	// Capabilities are routing tags added to every rig using this agent
+	// (e.g., "cap:vision"), matched against bead labels by 'gt sling --auto'.
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"sort"
@@ -209,6 +210,9 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.MaxPolecats < 0 {
+		return fmt.Errorf("%w: max_polecats must be non-negative", ErrMissingField)
+	}
 	return nil
 }
 
@@ -459,6 +463,22 @@ func (c *AccountsConfig) GetDefaultAccount() *Account {
 	return c.GetAccount(c.Default)
 }
 
+// ConfigDirs returns every account's config directory with ~ expanded,
+// ordered by handle.
+func (c *AccountsConfig) ConfigDirs() []string {
+	handles := make([]string, 0, len(c.Accounts))
+	for handle := range c.Accounts {
+		handles = append(handles, handle)
+	}
+	sort.Strings(handles)
+
+	dirs := make([]string, 0, len(handles))
+	for _, handle := range handles {
+		dirs = append(dirs, expandPath(c.Accounts[handle].ConfigDir))
+	}
+	return dirs
+}
+
 // ResolveAccountConfigDir resolves the CLAUDE_CONFIG_DIR for account selection.
 // Priority order:
 //  1. GT_ACCOUNT environment variable
@@ -675,6 +695,9 @@ func RigSettingsPath(rigPath string) string {
 }
 
 // LoadOrCreateTownSettings loads town settings or creates defaults if missing.
+// The settings are not validated, so a bad notifier or daemon block doesn't
+// cost every caller the rest of the file (agents, default CLI). Commands
+// that set or check the settings call ValidateTownSettings.
 func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
 	if err != nil {
@@ -691,6 +714,102 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	return &settings, nil
 }
 
+// ValidateTownSettings validates a TownSettings.
+func ValidateTownSettings(c *TownSettings) error {
+	if c.Type != "town-settings" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'town-settings', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentTownSettingsVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentTownSettingsVersion)
+	}
+	for i, n := range c.Notifiers {
+		if n == nil {
+			return fmt.Errorf("%w: notifiers[%d] is null", ErrMissingField, i)
+		}
+		if err := validateNotifierConfig(n); err != nil {
+			return fmt.Errorf("notifier '%s': %w", n.DisplayName(), err)
+		}
+	}
+	if c.Daemon != nil {
+		if err := validateDaemonConfig(c.Daemon); err != nil {
+			return err
+		}
+	}
+	if c.MaxPolecats < 0 {
+		return fmt.Errorf("%w: max_polecats must be non-negative", ErrMissingField)
+	}
+	return nil
+}
+
+// validateDaemonConfig validates a DaemonConfig.
+func validateDaemonConfig(c *DaemonConfig) error {
+	for name, value := range map[string]string{
+		"heartbeat_interval":   c.HeartbeatInterval,
+		"poll_interval":        c.PollInterval,
+		"config_poll_interval": c.ConfigPollInterval,
+	} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid daemon %s: %w", name, err)
+		}
+		if d < time.Second {
+			return fmt.Errorf("invalid daemon %s: %s is less than 1s", name, value)
+		}
+	}
+	if c.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddr); err != nil {
+			return fmt.Errorf("invalid daemon metrics_addr: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateNotifierConfig validates a NotifierConfig.
+func validateNotifierConfig(n *NotifierConfig) error {
+	switch strings.ToUpper(n.MinSeverity) {
+	case "", "MEDIUM", "HIGH", "CRITICAL":
+	default:
+		return fmt.Errorf("invalid min_severity '%s': must be MEDIUM, HIGH, or CRITICAL", n.MinSeverity)
+	}
+	if n.Timeout != "" {
+		if _, err := time.ParseDuration(n.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout '%s': %w", n.Timeout, err)
+		}
+	}
+
+	switch n.Type {
+	case NotifierWebhook, NotifierNtfy:
+		if n.URL == "" {
+			return fmt.Errorf("%w: url", ErrMissingField)
+		}
+		if !strings.HasPrefix(n.URL, "http://") && !strings.HasPrefix(n.URL, "https://") {
+			return fmt.Errorf("invalid url '%s': must be http:// or https://", n.URL)
+		}
+	case NotifierSMTP:
+		if n.Host == "" {
+			return fmt.Errorf("%w: host", ErrMissingField)
+		}
+		if n.From == "" {
+			return fmt.Errorf("%w: from", ErrMissingField)
+		}
+		if len(n.To) == 0 {
+			return fmt.Errorf("%w: to", ErrMissingField)
+		}
+		if n.Port < 0 || n.Port > 65535 {
+			return fmt.Errorf("invalid port %d", n.Port)
+		}
+	case NotifierDesktop:
+	case "":
+		return fmt.Errorf("%w: type", ErrMissingField)
+	default:
+		return fmt.Errorf("unknown notifier type '%s' (want webhook, smtp, desktop, or ntfy)", n.Type)
+	}
+	return nil
+}
+
 // ResolveAgentConfig resolves the agent configuration for a rig.
 // It looks up the agent by name in town settings (custom agents) and built-in presets.
 //
@@ -726,18 +845,61 @@ func ResolveAgentConfig(townRoot, rigPath string) *RuntimeConfig {
 	// Load custom agent registry if it exists
 	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
 
-	// Determine which agent name to use
-	agentName := ""
+	// Look up the agent configuration
+	return lookupAgentConfig(agentName(rigSettings, townSettings), townSettings)
+}
+
+// agentName returns the agent a rig uses: its own agent setting, else the
+// town's default_agent, else claude.
+func agentName(rigSettings *RigSettings, townSettings *TownSettings) string {
 	if rigSettings != nil && rigSettings.Agent != "" {
-		agentName = rigSettings.Agent
-	} else if townSettings.DefaultAgent != "" {
-		agentName = townSettings.DefaultAgent
-	} else {
-		agentName = "claude" // ultimate fallback
+		return rigSettings.Agent
 	}
+	if townSettings != nil && townSettings.DefaultAgent != "" {
+		return townSettings.DefaultAgent
+	}
+	return "claude" // ultimate fallback
+}
 
-	// Look up the agent configuration
-	return lookupAgentConfig(agentName, townSettings)
+// ResolveRigCapabilities returns a rig's routing tags for 'gt sling --auto':
+// its capabilities setting, "cli:<agent>" for the agent it runs, and that
+// agent's own capabilities. Tags are lowercased and deduplicated.
+func ResolveRigCapabilities(townRoot, rigPath string) []string {
+	rigSettings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil {
+		rigSettings = nil
+	}
+	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		townSettings = NewTownSettings()
+	}
+	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
+
+	var tags []string
+	if rigSettings != nil {
+		tags = append(tags, rigSettings.Capabilities...)
+	}
+	name := agentName(rigSettings, townSettings)
+	// As in ResolveAgentConfig, a legacy runtime setting wins
+	if rigSettings != nil && rigSettings.Runtime != nil && rigSettings.Runtime.Command != "" {
+		name = filepath.Base(rigSettings.Runtime.Command)
+	}
+	tags = append(tags, "cli:"+name)
+	if preset := GetAgentPresetByName(name); preset != nil {
+		tags = append(tags, preset.Capabilities...)
+	}
+
+	seen := make(map[string]bool, len(tags))
+	var out []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
 }
 
 // lookupAgentConfig looks up an agent by name.
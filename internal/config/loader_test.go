@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -524,6 +525,22 @@ func TestLoadAccountsConfigNotFound(t *testing.T) {
 	}
 }
 
+func TestAccountsConfigDirs(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	cfg := NewAccountsConfig()
+	cfg.Accounts["work"] = Account{Email: "w@example.com", ConfigDir: "~/.claude-accounts/work"}
+	cfg.Accounts["alt"] = Account{Email: "a@example.com", ConfigDir: "/srv/claude/alt"}
+
+	got := cfg.ConfigDirs()
+	want := []string{"/srv/claude/alt", filepath.Join(home, ".claude-accounts", "work")}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("ConfigDirs() = %v, want %v", got, want)
+	}
+}
+
 func TestMessagingConfigRoundTrip(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "config", "messaging.json")
@@ -715,6 +732,81 @@ func TestMessagingConfigValidation(t *testing.T) {
 	}
 }
 
+func TestTownSettingsNotifierValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier *NotifierConfig
+		wantErr  bool
+	}{
+		{"valid webhook", &NotifierConfig{Type: NotifierWebhook, URL: "https://example.com/hook"}, false},
+		{"valid ntfy", &NotifierConfig{Type: NotifierNtfy, URL: "http://localhost:8080/town", MinSeverity: "critical"}, false},
+		{"valid smtp", &NotifierConfig{Type: NotifierSMTP, Host: "smtp.example.com", From: "gt@example.com", To: []string{"me@example.com"}}, false},
+		{"valid desktop", &NotifierConfig{Type: NotifierDesktop, Timeout: "5s"}, false},
+		{"missing type", &NotifierConfig{URL: "https://example.com"}, true},
+		{"unknown type", &NotifierConfig{Type: "pager"}, true},
+		{"webhook without url", &NotifierConfig{Type: NotifierWebhook}, true},
+		{"webhook bad scheme", &NotifierConfig{Type: NotifierWebhook, URL: "ftp://example.com"}, true},
+		{"smtp without recipients", &NotifierConfig{Type: NotifierSMTP, Host: "smtp.example.com", From: "gt@example.com"}, true},
+		{"bad severity", &NotifierConfig{Type: NotifierDesktop, MinSeverity: "LOW"}, true},
+		{"bad timeout", &NotifierConfig{Type: NotifierDesktop, Timeout: "soon"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := NewTownSettings()
+			settings.Notifiers = []*NotifierConfig{tt.notifier}
+			err := ValidateTownSettings(settings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTownSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTownSettingsRejectsInvalidNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"type": "town-settings", "version": 1, "notifiers": [{"type": "webhook"}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := LoadOrCreateTownSettings(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateTownSettings() should not validate: %v", err)
+	}
+	if err := ValidateTownSettings(settings); err == nil {
+		t.Error("expected error for webhook notifier without url")
+	}
+}
+
+func TestTownSettingsDaemonValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		daemon  *DaemonConfig
+		wantErr bool
+	}{
+		{"empty", &DaemonConfig{}, false},
+		{"valid intervals", &DaemonConfig{HeartbeatInterval: "90s", ConfigPollInterval: "10s"}, false},
+		{"bad heartbeat", &DaemonConfig{HeartbeatInterval: "often"}, true},
+		{"too short", &DaemonConfig{HeartbeatInterval: "500ms"}, true},
+		{"negative poll", &DaemonConfig{ConfigPollInterval: "-5s"}, true},
+		{"bad legacy poll", &DaemonConfig{PollInterval: "often"}, true},
+		{"metrics addr", &DaemonConfig{MetricsAddr: "127.0.0.1:9464"}, false},
+		{"metrics port only", &DaemonConfig{MetricsAddr: ":9464"}, false},
+		{"metrics addr without port", &DaemonConfig{MetricsAddr: "localhost"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := NewTownSettings()
+			settings.Daemon = tt.daemon
+			err := ValidateTownSettings(settings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTownSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoadMessagingConfigNotFound(t *testing.T) {
 	_, err := LoadMessagingConfig("/nonexistent/path.json")
 	if err == nil {
@@ -970,3 +1062,66 @@ func TestLoadRuntimeConfigFallsBackToDefaults(t *testing.T) {
 		t.Errorf("Command = %q, want %q (default)", rc.Command, "claude")
 	}
 }
+
+func TestResolveRigCapabilities(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+
+	settings := NewRigSettings()
+	settings.Agent = "codex"
+	settings.Capabilities = []string{"Lang:Go", "area:cli", "lang:go"}
+	settings.MaxPolecats = 4
+	if err := SaveRigSettings(RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatalf("saving settings: %v", err)
+	}
+
+	got := ResolveRigCapabilities(townRoot, rigPath)
+	want := []string{"lang:go", "area:cli", "cli:codex"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ResolveRigCapabilities = %v, want %v", got, want)
+	}
+
+	// A rig without settings runs the default agent
+	got = ResolveRigCapabilities(townRoot, filepath.Join(townRoot, "other"))
+	if strings.Join(got, ",") != "cli:claude" {
+		t.Errorf("ResolveRigCapabilities (no settings) = %v, want [cli:claude]", got)
+	}
+}
+
+func TestResolveAgentConfigIgnoresInvalidDaemonBlock(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+
+	// A bad daemon block must not cost the rest of the town settings
+	path := TownSettingsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type": "town-settings", "version": 1, "default_agent": "codex", "daemon": {"heartbeat_interval": "often"}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if rc := ResolveAgentConfig(townRoot, rigPath); rc.Command != "codex" {
+		t.Errorf("ResolveAgentConfig().Command = %q, want codex", rc.Command)
+	}
+	if got := ResolveRigCapabilities(townRoot, rigPath); !slices.Contains(got, "cli:codex") {
+		t.Errorf("ResolveRigCapabilities() = %v, want cli:codex", got)
+	}
+}
+
+func TestRigSettingsRejectsNegativeMaxPolecats(t *testing.T) {
+	settings := NewRigSettings()
+	settings.MaxPolecats = -1
+	if err := validateRigSettings(settings); err == nil {
+		t.Error("expected an error for negative max_polecats")
+	}
+}
+
+func TestTownSettingsRejectsNegativeMaxPolecats(t *testing.T) {
+	settings := NewTownSettings()
+	settings.MaxPolecats = -1
+	if err := ValidateTownSettings(settings); err == nil {
+		t.Error("expected an error for negative max_polecats")
+	}
+}
@@ -23,7 +23,7 @@ type MayorConfig struct {
 	Type            string           `json:"type"`                        // "mayor-config"
 	Version         int              `json:"version"`                     // schema version
 	Theme           *TownThemeConfig `json:"theme,omitempty"`             // global theme settings
-	Daemon          *DaemonConfig    `json:"daemon,omitempty"`            // daemon settings
+	Daemon          *DaemonConfig    `json:"daemon,omitempty"`            // daemon settings (deprecated: use town settings)
 	Deacon          *DeaconConfig    `json:"deacon,omitempty"`            // deacon settings
 	DefaultCrewName string           `json:"default_crew_name,omitempty"` // default crew name for new rigs
 }
@@ -53,6 +53,83 @@ type TownSettings struct {
 	// Values override or extend the built-in presets.
 	// Example: {"gemini": {"command": "/custom/path/to/gemini"}}
 	Agents map[string]*RuntimeConfig `json:"agents,omitempty"`
+
+	// Notifiers are outbound bridges that deliver escalations outside the
+	// terminal (webhook, email, desktop, ntfy). Each fires for escalations
+	// at or above its MinSeverity.
+	Notifiers []*NotifierConfig `json:"notifiers,omitempty"`
+
+	// Daemon tunes the town daemon. The daemon watches this file and
+	// applies changes without a restart. A daemon block in
+	// mayor/config.json is still read when this one is unset.
+	Daemon *DaemonConfig `json:"daemon,omitempty"`
+
+	// MaxPolecats caps working polecats across all rigs, to keep the host
+	// from being overloaded. Slings beyond it wait in the dispatch queue.
+	// 0 means no limit.
+	MaxPolecats int `json:"max_polecats,omitempty"`
+}
+
+// Notifier types for NotifierConfig.Type.
+const (
+	NotifierWebhook = "webhook" // HTTP POST of a templated JSON body
+	NotifierSMTP    = "smtp"    // Email via an SMTP relay
+	NotifierDesktop = "desktop" // Local desktop notification (notify-send)
+	NotifierNtfy    = "ntfy"    // ntfy-style HTTP push (POST text to server/topic)
+)
+
+// NotifierConfig configures one outbound escalation notifier.
+type NotifierConfig struct {
+	// Name identifies the notifier in logs and events. Defaults to Type.
+	Name string `json:"name,omitempty"`
+
+	// Type is one of: webhook, smtp, desktop, ntfy.
+	Type string `json:"type"`
+
+	// MinSeverity is the lowest escalation severity that triggers this
+	// notifier: MEDIUM, HIGH or CRITICAL. Default: HIGH.
+	MinSeverity string `json:"min_severity,omitempty"`
+
+	// Disabled turns the notifier off without removing its config.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Timeout bounds a single delivery (e.g., "10s"). Default: 10s.
+	Timeout string `json:"timeout,omitempty"`
+
+	// URL is the webhook endpoint, or the ntfy topic URL (https://ntfy.sh/mytopic).
+	URL string `json:"url,omitempty"`
+
+	// Headers are extra HTTP headers for webhook and ntfy requests.
+	// Values may reference environment variables as $VAR or ${VAR}.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Template is a Go text/template rendering the webhook JSON body.
+	// Fields: .Severity .Topic .Message .From .BeadID .Town .Time.
+	// The json function quotes a value (e.g. {"text": {{json .Topic}}}).
+	// Default: a flat JSON object with all fields.
+	Template string `json:"template,omitempty"`
+
+	// TokenEnv names an environment variable holding a bearer token (ntfy).
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// SMTP settings.
+	Host        string   `json:"host,omitempty"`
+	Port        int      `json:"port,omitempty"` // Default: 587
+	Username    string   `json:"username,omitempty"`
+	PasswordEnv string   `json:"password_env,omitempty"` // Env var holding the SMTP password
+	From        string   `json:"from,omitempty"`
+	To          []string `json:"to,omitempty"`
+
+	// Command overrides the desktop notification command. Default: notify-send.
+	Command string `json:"command,omitempty"`
+}
+
+// DisplayName returns the notifier's name, defaulting to its type.
+func (n *NotifierConfig) DisplayName() string {
+	if n.Name != "" {
+		return n.Name
+	}
+	return n.Type
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -68,8 +145,10 @@ func NewTownSettings() *TownSettings {
 
 // DaemonConfig represents daemon process settings.
 type DaemonConfig struct {
-	HeartbeatInterval string `json:"heartbeat_interval,omitempty"` // e.g., "30s"
-	PollInterval      string `json:"poll_interval,omitempty"`      // e.g., "10s"
+	HeartbeatInterval  string `json:"heartbeat_interval,omitempty"`   // e.g., "30s"
+	PollInterval       string `json:"poll_interval,omitempty"`        // e.g., "10s"; config_poll_interval takes precedence
+	ConfigPollInterval string `json:"config_poll_interval,omitempty"` // how often to check config files for edits, e.g., "5s"
+	MetricsAddr        string `json:"metrics_addr,omitempty"`         // e.g., "127.0.0.1:9464"; empty disables /metrics
 }
 
 // DeaconConfig represents deacon process settings.
@@ -144,6 +223,16 @@ type RigSettings struct {
 	// If empty, uses the town's default_agent setting.
 	// Takes precedence over Runtime if both are set.
 	Agent string `json:"agent,omitempty"`
+
+	// Capabilities tags what work suits this rig, as kind:value tags matched
+	// against bead labels by 'gt sling --auto' (e.g., "lang:go", "area:cli").
+	// The rig's agent adds "cli:<agent>" and the agent's own capabilities.
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// MaxPolecats caps how many polecats may work in this rig at once.
+	// Slings to a full rig wait in the dispatch queue unless forced.
+	// 0 means no limit.
+	MaxPolecats int `json:"max_polecats,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
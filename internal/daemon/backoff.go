@@ -0,0 +1,255 @@
+package daemon
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Restart backoff policy for agent sessions the daemon supervises.
+// After each restart the next one waits twice as long, up to the cap.
+// An agent restarted crashLoopThreshold times within crashLoopWindow is in
+// a crash loop: the daemon stops restarting it and escalates to the overseer
+// until the history is cleared with 'gt daemon health-state --clear'.
+// Restarts older than the window are forgotten, so an agent that stays up
+// for a while starts again from a clean slate.
+const (
+	restartBackoffBase = time.Minute
+	restartBackoffMax  = 30 * time.Minute
+	crashLoopThreshold = 5
+	crashLoopWindow    = time.Hour
+)
+
+// RestartHistory records the daemon's recent restarts of one agent session.
+type RestartHistory struct {
+	Session        string      `json:"session"`
+	Role           string      `json:"role"`
+	Rig            string      `json:"rig,omitempty"`
+	Restarts       []time.Time `json:"restarts"`
+	LastError      string      `json:"last_error,omitempty"`
+	NextAllowed    time.Time   `json:"next_allowed"`
+	CrashLoop      bool        `json:"crash_loop,omitempty"`
+	CrashLoopSince time.Time   `json:"crash_loop_since,omitempty"`
+}
+
+// prune forgets restarts that fell out of the crash-loop window.
+func (h *RestartHistory) prune(now time.Time) {
+	cutoff := now.Add(-crashLoopWindow)
+	kept := h.Restarts[:0]
+	for _, t := range h.Restarts {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	h.Restarts = kept
+}
+
+// expired reports whether the history has nothing left worth remembering.
+func (h *RestartHistory) expired() bool {
+	return !h.CrashLoop && len(h.Restarts) == 0
+}
+
+// blocked returns why a restart is not allowed at now, or "" if it is.
+func (h *RestartHistory) blocked(now time.Time) string {
+	if h.CrashLoop {
+		return fmt.Sprintf("crash loop since %s", h.CrashLoopSince.Format("15:04:05"))
+	}
+	if now.Before(h.NextAllowed) {
+		return fmt.Sprintf("backing off for %s", h.NextAllowed.Sub(now).Round(time.Second))
+	}
+	return ""
+}
+
+// record notes a restart at now and schedules the earliest next one.
+// It returns true when this restart tips the agent into a crash loop.
+func (h *RestartHistory) record(now time.Time, restartErr error) bool {
+	h.prune(now)
+	h.Restarts = append(h.Restarts, now)
+	h.LastError = ""
+	if restartErr != nil {
+		h.LastError = restartErr.Error()
+	}
+	h.NextAllowed = now.Add(restartBackoff(len(h.Restarts)))
+
+	if !h.CrashLoop && len(h.Restarts) >= crashLoopThreshold {
+		h.CrashLoop = true
+		h.CrashLoopSince = now
+		return true
+	}
+	return false
+}
+
+// restartBackoff returns the wait after the nth restart in the window.
+func restartBackoff(n int) time.Duration {
+	backoff := restartBackoffBase
+	for i := 1; i < n; i++ {
+		backoff *= 2
+		if backoff >= restartBackoffMax {
+			return restartBackoffMax
+		}
+	}
+	return backoff
+}
+
+// restartAllowed reports whether the daemon may restart a session now,
+// logging why not when it is backing off or the agent is crash-looping.
+func (d *Daemon) restartAllowed(sessionName string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.state == nil {
+		return true
+	}
+	h := d.state.Restarts[sessionName]
+	if h == nil {
+		return true
+	}
+	now := time.Now()
+	h.prune(now)
+	if h.expired() {
+		delete(d.state.Restarts, sessionName)
+		return true
+	}
+	if reason := h.blocked(now); reason != "" {
+		d.logger.Printf("Not restarting %s: %s (%d restarts in the last %s)",
+			sessionName, reason, len(h.Restarts), crashLoopWindow)
+		return false
+	}
+	return true
+}
+
+// recordRestart adds a restart attempt to a session's history. restartErr is
+// the launch error, if any; failed launches count toward the crash loop too.
+// Entering a crash loop escalates to the overseer.
+func (d *Daemon) recordRestart(sessionName, role, rig string, restartErr error) {
+	d.mu.Lock()
+	if d.state == nil {
+		d.mu.Unlock()
+		return
+	}
+	if d.state.Restarts == nil {
+		d.state.Restarts = make(map[string]*RestartHistory)
+	}
+	h := d.state.Restarts[sessionName]
+	if h == nil {
+		h = &RestartHistory{Session: sessionName, Role: role, Rig: rig}
+		d.state.Restarts[sessionName] = h
+	}
+	looping := h.record(time.Now(), restartErr)
+	snapshot := *h
+	snapshot.Restarts = append([]time.Time(nil), h.Restarts...)
+	d.mu.Unlock()
+
+	if looping {
+		d.logger.Printf("CRASH LOOP: %s restarted %d times in %s, no further automatic restarts",
+			sessionName, len(snapshot.Restarts), crashLoopWindow)
+		d.escalateCrashLoop(&snapshot)
+	}
+}
+
+// escalateCrashLoop tells the overseer an agent is crash-looping.
+func (d *Daemon) escalateCrashLoop(h *RestartHistory) {
+	agent := h.Role
+	if h.Rig != "" {
+		agent = h.Rig + "/" + h.Role
+	}
+	topic := fmt.Sprintf("Crash loop: %s (%s)", agent, h.Session)
+
+	var times []string
+	for _, t := range h.Restarts {
+		times = append(times, t.Format("15:04:05"))
+	}
+	lastErr := h.LastError
+	if lastErr == "" {
+		lastErr = "(launch succeeded, session died again)"
+	}
+	body := fmt.Sprintf(`The daemon restarted session %s %d times within %s and has stopped restarting it.
+
+role: %s
+rig: %s
+restarts: %s
+last error: %s
+
+Investigate the session, then resume automatic restarts with:
+  gt daemon health-state --clear %s`,
+		h.Session, len(h.Restarts), crashLoopWindow,
+		h.Role, h.Rig, strings.Join(times, ", "), lastErr, h.Session)
+
+	cmd := exec.Command("gt", "escalate", "-s", "HIGH", topic, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: failed to escalate crash loop of %s: %v", h.Session, err)
+	} else {
+		d.logger.Printf("Escalated crash loop of %s to overseer", h.Session)
+	}
+}
+
+// HealthState is the daemon's restart bookkeeping for the agents it supervises.
+type HealthState struct {
+	At     time.Time         `json:"at"`
+	Agents []*RestartHistory `json:"agents"`
+}
+
+// HealthStateParams selects a session for the clear-backoff method.
+type HealthStateParams struct {
+	Session string `json:"session"`
+}
+
+// healthState returns the current restart histories, sorted by session,
+// dropping any that have expired.
+func (d *Daemon) healthState() *HealthState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	hs := &HealthState{At: time.Now(), Agents: []*RestartHistory{}}
+	if d.state == nil {
+		return hs
+	}
+	hs.Agents = collectRestartHistory(d.state.Restarts, hs.At)
+	return hs
+}
+
+// collectRestartHistory prunes histories in place and returns copies of the
+// live ones sorted by session name.
+func collectRestartHistory(restarts map[string]*RestartHistory, now time.Time) []*RestartHistory {
+	agents := []*RestartHistory{}
+	for name, h := range restarts {
+		h.prune(now)
+		if h.expired() {
+			delete(restarts, name)
+			continue
+		}
+		c := *h
+		c.Restarts = append([]time.Time(nil), h.Restarts...)
+		agents = append(agents, &c)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].Session < agents[j].Session })
+	return agents
+}
+
+// clearRestartHistory forgets a session's restarts, lifting backoff and any
+// crash loop so the next heartbeat restarts it if needed.
+func (d *Daemon) clearRestartHistory(sessionName string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.state == nil || d.state.Restarts[sessionName] == nil {
+		return fmt.Errorf("no restart history for %q", sessionName)
+	}
+	delete(d.state.Restarts, sessionName)
+	if err := SaveState(d.config.TownRoot, d.state); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	d.logger.Printf("Restart history for %s cleared via control socket", sessionName)
+	return nil
+}
+
+// LoadHealthState reads restart histories from the state file, for when the
+// daemon isn't running to answer over the control socket.
+func LoadHealthState(townRoot string) (*HealthState, error) {
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &HealthState{At: now, Agents: collectRestartHistory(state.Restarts, now)}, nil
+}
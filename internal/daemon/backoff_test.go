@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestartBackoff(t *testing.T) {
+	tests := []struct {
+		n    int
+		want time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{5, 16 * time.Minute},
+		{6, 30 * time.Minute},
+		{20, 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := restartBackoff(tt.n); got != tt.want {
+			t.Errorf("restartBackoff(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRestartHistoryCrashLoop(t *testing.T) {
+	h := &RestartHistory{Session: "gt-gastown-witness", Role: "witness", Rig: "gastown"}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 1; i < crashLoopThreshold; i++ {
+		if h.record(now, nil) {
+			t.Fatalf("crash loop after %d restarts", i)
+		}
+		if reason := h.blocked(now); !strings.Contains(reason, "backing off") {
+			t.Errorf("restart %d: blocked = %q, want backoff", i, reason)
+		}
+		now = h.NextAllowed
+		if reason := h.blocked(now); reason != "" {
+			t.Errorf("restart %d: still blocked at NextAllowed: %q", i, reason)
+		}
+	}
+
+	if !h.record(now, errors.New("no such directory")) {
+		t.Fatal("expected crash loop on threshold restart")
+	}
+	if h.record(now.Add(time.Minute), nil) {
+		t.Error("crash loop should only be reported once")
+	}
+	if reason := h.blocked(now.Add(24 * time.Hour)); !strings.Contains(reason, "crash loop") {
+		t.Errorf("crash loop should persist, blocked = %q", reason)
+	}
+}
+
+func TestRestartHistoryExpires(t *testing.T) {
+	h := &RestartHistory{Session: "gt-gastown-toast", Role: "polecat"}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	h.record(now, nil)
+	h.record(now.Add(10*time.Minute), nil)
+
+	h.prune(now.Add(10*time.Minute + crashLoopWindow))
+	if !h.expired() {
+		t.Errorf("history should expire after a quiet window, restarts = %v", h.Restarts)
+	}
+}
+
+func TestControlHealthState(t *testing.T) {
+	d, client := newControlTestDaemon(t)
+
+	if !d.restartAllowed("gt-gastown-refinery") {
+		t.Fatal("restart should be allowed with no history")
+	}
+	d.recordRestart("gt-gastown-refinery", "refinery", "gastown", nil)
+	if d.restartAllowed("gt-gastown-refinery") {
+		t.Error("restart should be blocked right after a restart")
+	}
+
+	hs, err := client.HealthState()
+	if err != nil {
+		t.Fatalf("HealthState: %v", err)
+	}
+	if len(hs.Agents) != 1 || hs.Agents[0].Session != "gt-gastown-refinery" || !hs.Agents[0].NextAllowed.After(hs.At) {
+		t.Errorf("HealthState = %+v", hs)
+	}
+
+	if _, err := client.ClearBackoff("gt-nosuch"); err == nil {
+		t.Error("clearing unknown session should fail")
+	}
+	hs, err = client.ClearBackoff("gt-gastown-refinery")
+	if err != nil {
+		t.Fatalf("ClearBackoff: %v", err)
+	}
+	if len(hs.Agents) != 0 || !d.restartAllowed("gt-gastown-refinery") {
+		t.Errorf("history not cleared: %+v", hs)
+	}
+
+	saved, err := LoadHealthState(d.config.TownRoot)
+	if err != nil || len(saved.Agents) != 0 {
+		t.Errorf("LoadHealthState = %+v (err=%v)", saved, err)
+	}
+}
@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ErrNotRunning is returned by Client when no daemon is listening on the
+// control socket.
+var ErrNotRunning = errors.New("daemon is not running")
+
+// DefaultClientTimeout bounds a control request. Forced heartbeats restart
+// agents and can take a while, so they use HeartbeatTimeout instead.
+const (
+	DefaultClientTimeout = 10 * time.Second
+	HeartbeatTimeout     = 5 * time.Minute
+)
+
+// Client talks to a running daemon over its control socket.
+type Client struct {
+	socketPath string
+	timeout    time.Duration
+}
+
+// NewClient returns a client for the daemon of the given town.
+func NewClient(townRoot string) *Client {
+	return &Client{socketPath: SocketPath(townRoot), timeout: DefaultClientTimeout}
+}
+
+// Status returns the daemon's live status.
+func (c *Client) Status() (*Status, error) {
+	var s Status
+	if err := c.call(MethodStatus, nil, &s, c.timeout); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Heartbeat forces an immediate heartbeat and waits for it to complete.
+func (c *Client) Heartbeat() (*HeartbeatResult, error) {
+	var r HeartbeatResult
+	if err := c.call(MethodHeartbeat, nil, &r, HeartbeatTimeout); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Reload asks the daemon to re-read its configuration.
+func (c *Client) Reload() (*ReloadResult, error) {
+	var r ReloadResult
+	if err := c.call(MethodReload, nil, &r, c.timeout); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// PauseRig stops the daemon supervising a rig's witness, refinery and polecats.
+func (c *Client) PauseRig(rig, reason string) (*RigStatus, error) {
+	var r RigStatus
+	if err := c.call(MethodPauseRig, &RigParams{Rig: rig, Reason: reason}, &r, c.timeout); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ResumeRig restores supervision of a paused rig.
+func (c *Client) ResumeRig(rig string) (*RigStatus, error) {
+	var r RigStatus
+	if err := c.call(MethodResumeRig, &RigParams{Rig: rig}, &r, c.timeout); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Sessions lists the Gas Town tmux sessions the daemon can see.
+func (c *Client) Sessions() ([]*ManagedSession, error) {
+	var sessions []*ManagedSession
+	if err := c.call(MethodSessions, nil, &sessions, c.timeout); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// HealthState returns restart backoff and crash-loop state per agent.
+func (c *Client) HealthState() (*HealthState, error) {
+	var hs HealthState
+	if err := c.call(MethodHealthState, nil, &hs, c.timeout); err != nil {
+		return nil, err
+	}
+	return &hs, nil
+}
+
+// ClearBackoff forgets a session's restart history, lifting its backoff or
+// crash loop.
+func (c *Client) ClearBackoff(sessionName string) (*HealthState, error) {
+	var hs HealthState
+	if err := c.call(MethodClearBackoff, &HealthStateParams{Session: sessionName}, &hs, c.timeout); err != nil {
+		return nil, err
+	}
+	return &hs, nil
+}
+
+// call sends one request and decodes the result into out.
+func (c *Client) call(method string, params, out interface{}, timeout time.Duration) error {
+	conn, err := net.DialTimeout("unix", c.socketPath, timeout)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+			return ErrNotRunning
+		}
+		return fmt.Errorf("connecting to daemon: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	req := Request{Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("encoding params: %w", err)
+		}
+		req.Params = data
+	}
+	if err := json.NewEncoder(conn).Encode(&req); err != nil {
+		return fmt.Errorf("sending %s request: %w", method, err)
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("reading %s response: %w", method, err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("decoding %s response: %w", method, err)
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+	if out != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, out); err != nil {
+			return fmt.Errorf("decoding %s result: %w", method, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,420 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/boot"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// Control socket methods.
+const (
+	MethodStatus    = "status"
+	MethodHeartbeat = "heartbeat"
+	MethodReload    = "reload"
+	MethodPauseRig  = "pause"
+	MethodResumeRig = "resume"
+	MethodSessions  = "sessions"
+
+	MethodHealthState  = "health-state"
+	MethodClearBackoff = "clear-backoff"
+)
+
+// SocketPath returns the path to the daemon control socket.
+func SocketPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "daemon.sock")
+}
+
+// Request is one control request. Requests and responses are
+// newline-delimited JSON; a connection may carry several in sequence.
+type Request struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response answers one control request.
+type Response struct {
+	OK     bool            `json:"ok"`
+	Error  string          `json:"error,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// RigParams names the rig for pause/resume requests.
+type RigParams struct {
+	Rig    string `json:"rig"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// RigPause records why and when supervision of a rig was paused.
+type RigPause struct {
+	Reason   string    `json:"reason,omitempty"`
+	PausedAt time.Time `json:"paused_at"`
+}
+
+// RigStatus is the daemon's view of one registered rig.
+type RigStatus struct {
+	Name     string    `json:"name"`
+	Paused   bool      `json:"paused"`
+	Reason   string    `json:"reason,omitempty"`
+	PausedAt time.Time `json:"paused_at,omitempty"`
+}
+
+// Status is the live answer to a status request.
+type Status struct {
+	PID               int           `json:"pid"`
+	StartedAt         time.Time     `json:"started_at"`
+	LastHeartbeat     time.Time     `json:"last_heartbeat"`
+	HeartbeatCount    int64         `json:"heartbeat_count"`
+	HeartbeatInterval time.Duration `json:"heartbeat_interval"`
+	NextHeartbeat     time.Time     `json:"next_heartbeat"`
+	InHeartbeat       bool          `json:"in_heartbeat"`
+	LastReload        time.Time     `json:"last_reload,omitempty"`
+	MetricsURL        string        `json:"metrics_url,omitempty"`
+	Rigs              []RigStatus   `json:"rigs"`
+}
+
+// HeartbeatResult reports a forced heartbeat.
+type HeartbeatResult struct {
+	HeartbeatCount int64         `json:"heartbeat_count"`
+	Duration       time.Duration `json:"duration"`
+}
+
+// ManagedSession is one Gas Town tmux session seen by the daemon.
+type ManagedSession struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Rig      string `json:"rig,omitempty"`
+	Agent    string `json:"agent,omitempty"`
+	Address  string `json:"address"`
+	Windows  int    `json:"windows"`
+	Attached bool   `json:"attached"`
+	Created  string `json:"created,omitempty"`
+	Activity string `json:"activity,omitempty"`
+	Paused   bool   `json:"paused,omitempty"`
+}
+
+// errShuttingDown is returned for requests that race with daemon shutdown.
+var errShuttingDown = errors.New("daemon is shutting down")
+
+// startControlServer listens on the control socket and serves requests
+// until the listener is closed by shutdown.
+func (d *Daemon) startControlServer() error {
+	path := SocketPath(d.config.TownRoot)
+
+	// A socket left behind by a crashed daemon blocks Listen. We hold the
+	// daemon lock, so no live daemon can own it.
+	_ = os.Remove(path)
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("listening on control socket: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = ln.Close()
+		return fmt.Errorf("securing control socket: %w", err)
+	}
+
+	d.control = ln
+	go d.serveControl(ln)
+	return nil
+}
+
+// stopControlServer closes the control listener and removes the socket.
+func (d *Daemon) stopControlServer() {
+	if d.control == nil {
+		return
+	}
+	_ = d.control.Close()
+	_ = os.Remove(SocketPath(d.config.TownRoot))
+	d.control = nil
+}
+
+func (d *Daemon) serveControl(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return // listener closed
+		}
+		go d.handleControlConn(conn)
+	}
+}
+
+func (d *Daemon) handleControlConn(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		var resp *Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = &Response{Error: fmt.Sprintf("invalid request: %v", err)}
+		} else {
+			resp = d.handleControlRequest(&req)
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handleControlRequest dispatches one request to its handler.
+func (d *Daemon) handleControlRequest(req *Request) *Response {
+	var result interface{}
+	var err error
+
+	switch req.Method {
+	case MethodStatus:
+		result = d.status()
+	case MethodHeartbeat:
+		result, err = d.forceHeartbeat()
+	case MethodReload:
+		result = d.reloadConfig()
+	case MethodPauseRig, MethodResumeRig:
+		var p RigParams
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &p); err != nil {
+				return &Response{Error: fmt.Sprintf("invalid params: %v", err)}
+			}
+		}
+		if req.Method == MethodPauseRig {
+			result, err = d.pauseRig(p.Rig, p.Reason)
+		} else {
+			result, err = d.resumeRig(p.Rig)
+		}
+	case MethodSessions:
+		result, err = d.managedSessions()
+	case MethodHealthState:
+		result = d.healthState()
+	case MethodClearBackoff:
+		var p HealthStateParams
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &p); err != nil {
+				return &Response{Error: fmt.Sprintf("invalid params: %v", err)}
+			}
+		}
+		err = d.clearRestartHistory(p.Session)
+		result = d.healthState()
+	default:
+		err = fmt.Errorf("unknown method %q", req.Method)
+	}
+
+	if err != nil {
+		return &Response{Error: err.Error()}
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &Response{Error: fmt.Sprintf("encoding result: %v", err)}
+	}
+	return &Response{OK: true, Result: data}
+}
+
+// status snapshots the daemon's runtime state.
+func (d *Daemon) status() *Status {
+	rigs := d.getKnownRigs()
+	sort.Strings(rigs)
+	interval := d.heartbeatInterval()
+
+	d.metricsMu.Lock()
+	metricsURL := ""
+	if d.metricsServer != nil {
+		metricsURL = "http://" + d.metricsBound + "/metrics"
+	}
+	d.metricsMu.Unlock()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s := &Status{
+		HeartbeatInterval: interval,
+		MetricsURL:        metricsURL,
+		NextHeartbeat:     d.nextHeartbeat,
+		InHeartbeat:       d.inHeartbeat,
+	}
+	if d.state != nil {
+		s.PID = d.state.PID
+		s.StartedAt = d.state.StartedAt
+		s.LastHeartbeat = d.state.LastHeartbeat
+		s.HeartbeatCount = d.state.HeartbeatCount
+		s.LastReload = d.state.LastReload
+	}
+	for _, name := range rigs {
+		rs := RigStatus{Name: name}
+		if p := d.pausedLocked(name); p != nil {
+			rs.Paused = true
+			rs.Reason = p.Reason
+			rs.PausedAt = p.PausedAt
+		}
+		s.Rigs = append(s.Rigs, rs)
+	}
+	return s
+}
+
+// forceHeartbeat asks the main loop to run a heartbeat now and waits for it.
+func (d *Daemon) forceHeartbeat() (*HeartbeatResult, error) {
+	start := time.Now()
+	done := make(chan struct{})
+	select {
+	case d.heartbeatReq <- done:
+	case <-d.ctx.Done():
+		return nil, errShuttingDown
+	}
+	select {
+	case <-done:
+	case <-d.ctx.Done():
+		return nil, errShuttingDown
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	res := &HeartbeatResult{Duration: time.Since(start)}
+	if d.state != nil {
+		res.HeartbeatCount = d.state.HeartbeatCount
+	}
+	return res, nil
+}
+
+// pauseRig stops witness, refinery and polecat supervision for a rig.
+// The pause is persisted in the state file and survives daemon restarts.
+func (d *Daemon) pauseRig(rig, reason string) (*RigStatus, error) {
+	if err := d.checkKnownRig(rig); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	if d.state == nil {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("daemon state not loaded")
+	}
+	if d.state.PausedRigs == nil {
+		d.state.PausedRigs = make(map[string]*RigPause)
+	}
+	p := &RigPause{Reason: reason, PausedAt: time.Now()}
+	d.state.PausedRigs[rig] = p
+	err := SaveState(d.config.TownRoot, d.state)
+	d.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("saving state: %w", err)
+	}
+
+	d.logger.Printf("Supervision paused for rig %s (%s)", rig, reason)
+	return &RigStatus{Name: rig, Paused: true, Reason: p.Reason, PausedAt: p.PausedAt}, nil
+}
+
+// resumeRig restores supervision for a paused rig.
+func (d *Daemon) resumeRig(rig string) (*RigStatus, error) {
+	if rig == "" {
+		return nil, fmt.Errorf("rig name required")
+	}
+
+	d.mu.Lock()
+	if d.pausedLocked(rig) == nil {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("rig %q is not paused", rig)
+	}
+	delete(d.state.PausedRigs, rig)
+	err := SaveState(d.config.TownRoot, d.state)
+	d.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("saving state: %w", err)
+	}
+
+	d.logger.Printf("Supervision resumed for rig %s", rig)
+	return &RigStatus{Name: rig}, nil
+}
+
+func (d *Daemon) checkKnownRig(rig string) error {
+	if rig == "" {
+		return fmt.Errorf("rig name required")
+	}
+	for _, name := range d.getKnownRigs() {
+		if name == rig {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown rig %q", rig)
+}
+
+// pausedLocked returns the pause record for a rig, or nil. Caller holds d.mu.
+func (d *Daemon) pausedLocked(rig string) *RigPause {
+	if d.state == nil {
+		return nil
+	}
+	return d.state.PausedRigs[rig]
+}
+
+// isRigPaused reports whether supervision of a rig is paused.
+func (d *Daemon) isRigPaused(rig string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pausedLocked(rig) != nil
+}
+
+// supervisedRigs returns the known rigs whose supervision is not paused.
+func (d *Daemon) supervisedRigs() []string {
+	var rigs []string
+	for _, name := range d.getKnownRigs() {
+		if d.isRigPaused(name) {
+			continue
+		}
+		rigs = append(rigs, name)
+	}
+	return rigs
+}
+
+// managedSessions lists the Gas Town tmux sessions currently alive.
+func (d *Daemon) managedSessions() ([]*ManagedSession, error) {
+	names, err := d.tmux.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing tmux sessions: %w", err)
+	}
+	sort.Strings(names)
+
+	sessions := []*ManagedSession{}
+	for _, name := range names {
+		if !strings.HasPrefix(name, session.Prefix) {
+			continue
+		}
+		if name == boot.SessionName {
+			sessions = append(sessions, d.describeSession(&ManagedSession{Name: name, Role: "boot", Address: "boot"}))
+			continue
+		}
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue // gt- prefixed but not an agent session
+		}
+		ms := &ManagedSession{
+			Name:    name,
+			Role:    string(id.Role),
+			Rig:     id.Rig,
+			Agent:   id.Name,
+			Address: id.Address(),
+		}
+		if id.Rig != "" {
+			ms.Paused = d.isRigPaused(id.Rig)
+		}
+		sessions = append(sessions, d.describeSession(ms))
+	}
+	return sessions, nil
+}
+
+// describeSession fills in tmux details for a managed session (best-effort).
+func (d *Daemon) describeSession(ms *ManagedSession) *ManagedSession {
+	if info, err := d.tmux.GetSessionInfo(ms.Name); err == nil {
+		ms.Windows = info.Windows
+		ms.Attached = info.Attached
+		ms.Created = info.Created
+		ms.Activity = info.Activity
+	}
+	return ms
+}
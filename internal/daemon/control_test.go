@@ -0,0 +1,161 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// newControlTestDaemon starts a control server for a daemon whose main loop
+// is replaced by a goroutine that only services forced heartbeats.
+func newControlTestDaemon(t *testing.T) (*Daemon, *Client) {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"),
+		[]byte(`{"version": 1, "rigs": {"gastown": {}, "beads": {}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "daemon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Daemon{
+		config:       DefaultConfig(townRoot),
+		tmux:         tmux.NewTmux(),
+		logger:       log.New(io.Discard, "", 0),
+		ctx:          ctx,
+		cancel:       cancel,
+		heartbeatReq: make(chan chan struct{}),
+		state:        &State{Running: true, PID: os.Getpid(), StartedAt: time.Now()},
+	}
+	if err := d.startControlServer(); err != nil {
+		t.Fatalf("startControlServer: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		d.stopControlServer()
+	})
+
+	go func() {
+		for {
+			select {
+			case done := <-d.heartbeatReq:
+				d.mu.Lock()
+				d.state.HeartbeatCount++
+				d.state.LastHeartbeat = time.Now()
+				d.mu.Unlock()
+				close(done)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return d, NewClient(townRoot)
+}
+
+func TestControlStatusAndHeartbeat(t *testing.T) {
+	d, client := newControlTestDaemon(t)
+
+	info, err := os.Stat(SocketPath(d.config.TownRoot))
+	if err != nil {
+		t.Fatalf("socket missing: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("socket mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	s, err := client.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if s.PID != os.Getpid() || s.HeartbeatCount != 0 || len(s.Rigs) != 2 || s.Rigs[0].Name != "beads" {
+		t.Errorf("Status = %+v", s)
+	}
+
+	hb, err := client.Heartbeat()
+	if err != nil {
+		t.Fatalf("Heartbeat: %v", err)
+	}
+	if hb.HeartbeatCount != 1 {
+		t.Errorf("HeartbeatCount = %d, want 1", hb.HeartbeatCount)
+	}
+}
+
+func TestControlPauseResume(t *testing.T) {
+	d, client := newControlTestDaemon(t)
+
+	if _, err := client.PauseRig("nosuchrig", ""); err == nil || !strings.Contains(err.Error(), "unknown rig") {
+		t.Errorf("PauseRig(unknown) err = %v", err)
+	}
+	if _, err := client.PauseRig("gastown", "manual rebase"); err != nil {
+		t.Fatalf("PauseRig: %v", err)
+	}
+
+	if got := d.supervisedRigs(); len(got) != 1 || got[0] != "beads" {
+		t.Errorf("supervisedRigs = %v, want [beads]", got)
+	}
+	saved, err := LoadState(d.config.TownRoot)
+	if err != nil || saved.PausedRigs["gastown"] == nil || saved.PausedRigs["gastown"].Reason != "manual rebase" {
+		t.Errorf("pause not persisted: %+v (err=%v)", saved, err)
+	}
+	s, _ := client.Status()
+	if s == nil || !s.Rigs[1].Paused {
+		t.Errorf("status does not report pause: %+v", s)
+	}
+
+	if _, err := client.ResumeRig("gastown"); err != nil {
+		t.Fatalf("ResumeRig: %v", err)
+	}
+	if _, err := client.ResumeRig("gastown"); err == nil {
+		t.Error("resuming an unpaused rig should fail")
+	}
+	if len(d.supervisedRigs()) != 2 {
+		t.Errorf("supervisedRigs after resume = %v", d.supervisedRigs())
+	}
+}
+
+func TestControlReload(t *testing.T) {
+	_, client := newControlTestDaemon(t)
+
+	res, err := client.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if len(res.Rigs) != 2 || len(res.Rejected) != 0 {
+		t.Errorf("Reload = %+v", res)
+	}
+}
+
+func TestPauseRigWithoutState(t *testing.T) {
+	d, _ := newControlTestDaemon(t)
+	d.mu.Lock()
+	d.state = nil
+	d.mu.Unlock()
+
+	if _, err := d.pauseRig("gastown", "maintenance"); err == nil {
+		t.Error("pauseRig() without state should fail, not panic")
+	}
+	if d.isRigPaused("gastown") {
+		t.Error("rig should not be paused")
+	}
+}
+
+func TestClientNotRunning(t *testing.T) {
+	client := NewClient(t.TempDir())
+	if _, err := client.Status(); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Status err = %v, want ErrNotRunning", err)
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
@@ -35,6 +39,29 @@ type Daemon struct {
 	ctx     context.Context
 	cancel  context.CancelFunc
 	curator *feed.Curator
+
+	// Control socket state. mu guards state, inHeartbeat, nextHeartbeat and
+	// cfg, which control requests and the config watcher use from other goroutines.
+	mu               sync.Mutex
+	state            *State
+	inHeartbeat      bool
+	heartbeatStarted time.Time
+	nextHeartbeat    time.Time
+	heartbeatReq     chan chan struct{} // forced heartbeats; closed chan signals completion
+	control          net.Listener
+
+	// Hot-reloaded configuration (see reload.go)
+	cfg             liveConfig
+	reloadMu        sync.Mutex // serializes reloads
+	configStamps    map[string]fileStamp
+	intervalChanged chan struct{}
+
+	// Metrics endpoint (see metrics.go)
+	counters      counters
+	metricsMu     sync.Mutex
+	metricsServer *http.Server
+	metricsAddr   string // configured address
+	metricsBound  string // address actually listened on
 }
 
 // New creates a new daemon instance.
@@ -45,21 +72,28 @@ func New(config *Config) (*Daemon, error) {
 		return nil, fmt.Errorf("creating daemon directory: %w", err)
 	}
 
-	// Open log file
-	logFile, err := os.OpenFile(config.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		return nil, fmt.Errorf("opening log file: %w", err)
+	// Log to stdout under a supervisor (journald captures it), else to the log file
+	var logOut io.Writer = os.Stdout
+	if !config.Foreground {
+		logFile, err := os.OpenFile(config.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("opening log file: %w", err)
+		}
+		logOut = logFile
 	}
 
-	logger := log.New(logFile, "", log.LstdFlags)
+	logger := newLogger(logOut, config.LogFormat)
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Daemon{
-		config: config,
-		tmux:   tmux.NewTmux(),
-		logger: logger,
-		ctx:    ctx,
-		cancel: cancel,
+		config:       config,
+		tmux:         tmux.NewTmux(),
+		logger:       logger,
+		ctx:          ctx,
+		cancel:       cancel,
+		heartbeatReq: make(chan chan struct{}),
+
+		intervalChanged: make(chan struct{}, 1),
 	}, nil
 }
 
@@ -89,26 +123,45 @@ func (d *Daemon) Run() error {
 	}
 	defer func() { _ = os.Remove(d.config.PidFile) }() // best-effort cleanup
 
-	// Update state
+	// Update state. Paused rigs and restart history carry over from the
+	// previous run so a restart doesn't silently resume supervision or
+	// forget a crash loop.
 	state := &State{
 		Running:   true,
 		PID:       os.Getpid(),
 		StartedAt: time.Now(),
 	}
+	if prev, err := LoadState(d.config.TownRoot); err == nil {
+		state.PausedRigs = prev.PausedRigs
+		state.Restarts = prev.Restarts
+	}
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
+	d.state = state
+
+	// Start control socket (gt daemon status/heartbeat/reload/pause/resume)
+	if err := d.startControlServer(); err != nil {
+		d.logger.Printf("Warning: control socket unavailable: %v", err)
+	}
 
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR1)
 
-	// Fixed recovery-focused heartbeat (no activity-based backoff)
+	// Load configuration, then watch it for edits so changes apply without
+	// a restart. Invalid files are rejected and the last good config kept.
+	for _, r := range d.reloadConfig().Rejected {
+		d.logger.Printf("Warning: invalid config at startup, using defaults: %s: %s", r.Path, r.Error)
+	}
+	go d.watchConfig()
+
+	// Recovery-focused heartbeat (no activity-based backoff)
 	// Normal wake is handled by feed subscription (bd activity --follow)
-	timer := time.NewTimer(recoveryHeartbeatInterval)
+	timer := time.NewTimer(d.heartbeatInterval())
 	defer timer.Stop()
 
-	d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
+	d.logger.Printf("Daemon running, recovery heartbeat interval %v", d.heartbeatInterval())
 
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
@@ -120,6 +173,18 @@ func (d *Daemon) Run() error {
 
 	// Initial heartbeat
 	d.heartbeat(state)
+	d.scheduleHeartbeat(timer)
+
+	// Tell a supervising service manager we're up (no-op otherwise)
+	if ok, err := sdNotify(sdReady, sdStatus("Supervising %d rig(s)", len(d.supervisedRigs()))); err != nil {
+		d.logger.Printf("Warning: sd_notify failed: %v", err)
+	} else if ok {
+		d.logger.Println("Notified service manager of readiness")
+	}
+	if interval := watchdogInterval(); interval > 0 {
+		d.logger.Printf("Service manager watchdog enabled, pinging every %v", interval)
+		go d.runWatchdog(interval)
+	}
 
 	for {
 		select {
@@ -128,30 +193,59 @@ func (d *Daemon) Run() error {
 			return d.shutdown(state)
 
 		case sig := <-sigChan:
-			if sig == syscall.SIGUSR1 {
+			switch sig {
+			case syscall.SIGUSR1:
 				// SIGUSR1: immediate lifecycle processing (from gt handoff)
 				d.logger.Println("Received SIGUSR1, processing lifecycle requests immediately")
 				d.processLifecycleRequests()
-			} else {
+			case syscall.SIGHUP:
+				// SIGHUP: reload configuration (systemctl reload)
+				d.logger.Println("Received SIGHUP, reloading configuration")
+				_, _ = sdNotify(sdReloading)
+				d.reloadConfig()
+				_, _ = sdNotify(sdReady)
+			default:
 				d.logger.Printf("Received signal %v, shutting down", sig)
 				return d.shutdown(state)
 			}
 
+		case done := <-d.heartbeatReq:
+			// Forced heartbeat from the control socket
+			d.logger.Println("Heartbeat requested via control socket")
+			d.heartbeat(state)
+			d.scheduleHeartbeat(timer)
+			close(done)
+
+		case <-d.intervalChanged:
+			// Heartbeat interval edited in town settings
+			d.logger.Printf("Heartbeat interval now %v", d.heartbeatInterval())
+			d.scheduleHeartbeat(timer)
+
 		case <-timer.C:
 			d.heartbeat(state)
 
-			// Fixed recovery interval (no activity-based backoff)
-			timer.Reset(recoveryHeartbeatInterval)
+			// Recovery interval (no activity-based backoff)
+			d.scheduleHeartbeat(timer)
 		}
 	}
 }
 
-// recoveryHeartbeatInterval is the fixed interval for recovery-focused daemon.
+// recoveryHeartbeatInterval is the default interval for recovery-focused daemon.
+// Town settings can override it with daemon.heartbeat_interval.
 // Normal wake is handled by feed subscription (bd activity --follow).
 // The daemon is a safety net for dead sessions, GUPP violations, and orphaned work.
 // 3 minutes is fast enough to detect stuck agents promptly while avoiding excessive overhead.
 const recoveryHeartbeatInterval = 3 * time.Minute
 
+// scheduleHeartbeat resets the heartbeat timer and records when it will fire.
+func (d *Daemon) scheduleHeartbeat(timer *time.Timer) {
+	interval := d.heartbeatInterval()
+	timer.Reset(interval)
+	d.mu.Lock()
+	d.nextHeartbeat = time.Now().Add(interval)
+	d.mu.Unlock()
+}
+
 // heartbeat performs one heartbeat cycle.
 // The daemon is recovery-focused: it ensures agents are running and detects failures.
 // Normal wake is handled by feed subscription (bd activity --follow).
@@ -161,6 +255,10 @@ const recoveryHeartbeatInterval = 3 * time.Minute
 // - Orphaned work (assigned to dead agents)
 func (d *Daemon) heartbeat(state *State) {
 	d.logger.Println("Heartbeat starting (recovery-focused)")
+	d.mu.Lock()
+	d.inHeartbeat = true
+	d.heartbeatStarted = time.Now()
+	d.mu.Unlock()
 
 	// 1. Poke Boot (the Deacon's watchdog) instead of Deacon directly
 	// Boot handles the "when to wake Deacon" decision via triage logic
@@ -198,14 +296,28 @@ func (d *Daemon) heartbeat(state *State) {
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
 
+	// 9. Follow up on unacknowledged --require-ack mail (re-notify, then escalate)
+	d.checkUnackedMail()
+
+	// 10. Start queued slings now that polecats may have finished (backpressure)
+	d.processDispatchQueue()
+
+	// 11. Open gates whose condition holds (timer, bead, command, mrqueue) and wake waiters
+	d.evaluateGates()
+
 	// Update state
+	d.mu.Lock()
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
-	if err := SaveState(d.config.TownRoot, state); err != nil {
+	d.inHeartbeat = false
+	err := SaveState(d.config.TownRoot, state)
+	count := state.HeartbeatCount
+	d.mu.Unlock()
+	if err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
-	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
+	d.logger.Printf("Heartbeat complete (#%d)", count)
 }
 
 // DeaconRole is the role name for the Deacon's handoff bead.
@@ -326,15 +438,20 @@ func (d *Daemon) ensureDeaconRunning() {
 		}
 	}
 
-	// Agent not running (or bead not found) AND session is not healthy - start it
+	// Agent not running (or bead not found) AND session is not healthy - start it,
+	// unless recent restarts put it in backoff or a crash loop
+	sessionName := d.getDeaconSessionName()
+	if !d.restartAllowed(sessionName) {
+		return
+	}
 	d.logger.Println("Deacon not running per agent bead, starting...")
 
 	// Create session in deacon directory (ensures correct CLAUDE.md is loaded)
 	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
 	deaconDir := filepath.Join(d.config.TownRoot, "deacon")
-	sessionName := d.getDeaconSessionName()
 	if err := d.tmux.EnsureSessionFresh(sessionName, deaconDir); err != nil {
 		d.logger.Printf("Error creating Deacon session: %v", err)
+		d.recordRestart(sessionName, "deacon", "", err)
 		return
 	}
 
@@ -347,9 +464,12 @@ func (d *Daemon) ensureDeaconRunning() {
 	// Export GT_ROLE and BD_ACTOR so Claude inherits them (tmux SetEnvironment doesn't export to processes)
 	if err := d.tmux.SendKeys(sessionName, config.BuildAgentStartupCommand("deacon", "deacon", "", "")); err != nil {
 		d.logger.Printf("Error launching Claude in Deacon session: %v", err)
+		d.recordRestart(sessionName, "deacon", "", err)
 		return
 	}
 
+	d.recordRestart(sessionName, "deacon", "", nil)
+	d.countRestart("deacon")
 	d.logger.Println("Deacon session started successfully")
 }
 
@@ -406,8 +526,9 @@ func (d *Daemon) checkDeaconHeartbeat() {
 
 // ensureWitnessesRunning ensures witnesses are running for all rigs.
 // Called on each heartbeat to maintain witness patrol loops.
+// Rigs paused via 'gt daemon pause' are skipped.
 func (d *Daemon) ensureWitnessesRunning() {
-	rigs := d.getKnownRigs()
+	rigs := d.supervisedRigs()
 	for _, rigName := range rigs {
 		d.ensureWitnessRunning(rigName)
 	}
@@ -458,7 +579,11 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 		}
 	}
 
-	// Agent not running (or bead not found) AND session is not healthy - start it
+	// Agent not running (or bead not found) AND session is not healthy - start it,
+	// unless recent restarts put it in backoff or a crash loop
+	if !d.restartAllowed(sessionName) {
+		return
+	}
 	d.logger.Printf("Witness for %s not running per agent bead, starting...", rigName)
 
 	// Create session in witness directory
@@ -466,6 +591,7 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 	witnessDir := filepath.Join(d.config.TownRoot, rigName, "witness")
 	if err := d.tmux.EnsureSessionFresh(sessionName, witnessDir); err != nil {
 		d.logger.Printf("Error creating witness session for %s: %v", rigName, err)
+		d.recordRestart(sessionName, "witness", rigName, err)
 		return
 	}
 
@@ -484,17 +610,24 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 	}
 	if err := d.tmux.SendKeys(sessionName, config.BuildStartupCommand(envVars, "", "")); err != nil {
 		d.logger.Printf("Error launching Claude in witness session for %s: %v", rigName, err)
+		d.recordRestart(sessionName, "witness", rigName, err)
 		return
 	}
 
+	d.recordRestart(sessionName, "witness", rigName, nil)
+	d.countRestart("witness")
 	d.logger.Printf("Witness session for %s started successfully", rigName)
 }
 
 // ensureRefineriesRunning ensures refineries are running for all rigs.
 // Called on each heartbeat to maintain refinery merge queue processing.
+// Rigs paused via 'gt daemon pause' are skipped.
 func (d *Daemon) ensureRefineriesRunning() {
-	rigs := d.getKnownRigs()
+	rigs := d.supervisedRigs()
 	for _, rigName := range rigs {
+		if !d.mergeQueueEnabled(rigName) {
+			continue // merge_queue.enabled=false in rig settings
+		}
 		d.ensureRefineryRunning(rigName)
 	}
 }
@@ -544,7 +677,11 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 		}
 	}
 
-	// Agent not running (or bead not found) AND session is not healthy - start it
+	// Agent not running (or bead not found) AND session is not healthy - start it,
+	// unless recent restarts put it in backoff or a crash loop
+	if !d.restartAllowed(sessionName) {
+		return
+	}
 	d.logger.Printf("Refinery for %s not running per agent bead, starting...", rigName)
 
 	// Determine working directory
@@ -559,6 +696,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
 	if err := d.tmux.EnsureSessionFresh(sessionName, refineryDir); err != nil {
 		d.logger.Printf("Error creating refinery session for %s: %v", rigName, err)
+		d.recordRestart(sessionName, "refinery", rigName, err)
 		return
 	}
 
@@ -587,6 +725,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 	}
 	if err := d.tmux.SendKeys(sessionName, config.BuildStartupCommand(envVars, "", "")); err != nil {
 		d.logger.Printf("Error launching Claude in refinery session for %s: %v", rigName, err)
+		d.recordRestart(sessionName, "refinery", rigName, err)
 		return
 	}
 
@@ -596,11 +735,22 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 	}
 	_ = d.tmux.AcceptBypassPermissionsWarning(sessionName)
 
+	d.recordRestart(sessionName, "refinery", rigName, nil)
+	d.countRestart("refinery")
 	d.logger.Printf("Refinery session for %s started successfully", rigName)
 }
 
 // getKnownRigs returns list of registered rig names.
+// Once config has loaded, this is the last good rig registry; edits to
+// mayor/rigs.json are picked up by the config watcher.
 func (d *Daemon) getKnownRigs() []string {
+	d.mu.Lock()
+	loaded := d.cfg.rigs
+	d.mu.Unlock()
+	if loaded != nil {
+		return append([]string(nil), loaded...)
+	}
+
 	rigsPath := filepath.Join(d.config.TownRoot, "mayor", "rigs.json")
 	data, err := os.ReadFile(rigsPath)
 	if err != nil {
@@ -678,6 +828,12 @@ func (d *Daemon) processLifecycleRequests() {
 // shutdown performs graceful shutdown.
 func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return kept for future use
 	d.logger.Println("Daemon shutting down")
+	_, _ = sdNotify(sdStopping)
+
+	// Stop accepting control requests and release any waiting on a heartbeat
+	d.stopControlServer()
+	d.cancel()
+	d.configureMetrics("")
 
 	// Stop feed curator
 	if d.curator != nil {
@@ -685,8 +841,11 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Feed curator stopped")
 	}
 
+	d.mu.Lock()
 	state.Running = false
-	if err := SaveState(d.config.TownRoot, state); err != nil {
+	err := SaveState(d.config.TownRoot, state)
+	d.mu.Unlock()
+	if err != nil {
 		d.logger.Printf("Warning: failed to save final state: %v", err)
 	}
 
@@ -782,7 +941,7 @@ func StopDaemon(townRoot string) error {
 // When a crash is detected, the polecat is automatically restarted.
 // This provides faster recovery than waiting for GUPP timeout or Witness detection.
 func (d *Daemon) checkPolecatSessionHealth() {
-	rigs := d.getKnownRigs()
+	rigs := d.supervisedRigs()
 	for _, rigName := range rigs {
 		d.checkRigPolecatHealth(rigName)
 	}
@@ -842,12 +1001,19 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	d.logger.Printf("CRASH DETECTED: polecat %s/%s has hook_bead=%s but session %s is dead",
 		rigName, polecatName, info.HookBead, sessionName)
 
-	// Auto-restart the polecat
-	if err := d.restartPolecatSession(rigName, polecatName, sessionName); err != nil {
+	// Auto-restart the polecat, unless recent restarts put it in backoff
+	// or a crash loop
+	if !d.restartAllowed(sessionName) {
+		return
+	}
+	err = d.restartPolecatSession(rigName, polecatName, sessionName)
+	d.recordRestart(sessionName, "polecat", rigName, err)
+	if err != nil {
 		d.logger.Printf("Error restarting polecat %s/%s: %v", rigName, polecatName, err)
 		// Notify witness as fallback
 		d.notifyWitnessOfCrashedPolecat(rigName, polecatName, info.HookBead, err)
 	} else {
+		d.countRestart("polecat")
 		d.logger.Printf("Successfully restarted crashed polecat %s/%s", rigName, polecatName)
 	}
 }
@@ -0,0 +1,30 @@
+package daemon
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/dispatch"
+)
+
+// processDispatchQueue starts queued slings that now have polecat capacity.
+// The queue logic lives in 'gt dispatch run'; the daemon just calls it each
+// heartbeat while anything is queued.
+func (d *Daemon) processDispatchQueue() {
+	if dispatch.New(d.config.TownRoot).Count() == 0 {
+		return
+	}
+
+	cmd := exec.Command("gt", "dispatch", "run")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("Warning: gt dispatch run failed: %v (output: %s)", err, strings.TrimSpace(string(output)))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if strings.Contains(line, "Started") || strings.Contains(line, "failed to start") {
+			d.logger.Printf("Dispatch: %s", strings.TrimSpace(line))
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package daemon
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// evaluateGates opens gates whose condition holds and wakes the work parked
+// on them. The evaluation lives in 'gt gate eval'; the daemon just calls it
+// each heartbeat.
+func (d *Daemon) evaluateGates() {
+	cmd := exec.Command("gt", "gate", "eval")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("Warning: gt gate eval failed: %v (output: %s)", err, strings.TrimSpace(string(output)))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if strings.Contains(line, "Opened gate") || strings.Contains(line, "Gate ") {
+			d.logger.Printf("Gates: %s", strings.TrimSpace(line))
+		}
+	}
+}
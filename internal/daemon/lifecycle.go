@@ -200,6 +200,9 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 		if err := d.restartSession(sessionName, request.From); err != nil {
 			return fmt.Errorf("restarting session: %w", err)
 		}
+		if id, err := session.ParseSessionName(sessionName); err == nil {
+			d.countRestart(string(id.Role))
+		}
 		d.logger.Printf("Restarted session %s", sessionName)
 		return nil
 
@@ -759,8 +762,9 @@ const GUPPViolationTimeout = 30 * time.Minute
 // progressing. This is a GUPP violation: agents with hooked work must execute.
 // The daemon detects these and notifies the relevant Witness for remediation.
 func (d *Daemon) checkGUPPViolations() {
-	// Check polecat agents - they're the ones with work-on-hook
-	rigs := d.getKnownRigs()
+	// Check polecat agents - they're the ones with work-on-hook.
+	// Paused rigs are skipped like every other supervision check.
+	rigs := d.supervisedRigs()
 	for _, rigName := range rigs {
 		d.checkRigGUPPViolations(rigName)
 	}
@@ -818,6 +822,7 @@ func (d *Daemon) checkRigGUPPViolations(rigName string) {
 					agent.ID, agent.HookBead, age.Round(time.Minute), GUPPViolationTimeout)
 
 				// Notify the witness for this rig
+				d.countGUPPViolation(rigName)
 				d.notifyWitnessOfGUPP(rigName, agent.ID, agent.HookBead, age)
 			}
 		}
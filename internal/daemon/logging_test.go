@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newJSONLogWriter(&buf)
+	w.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	logger := log.New(w, "", 0)
+
+	logger.Printf("Heartbeat complete (#%d)", 3)
+	logger.Printf("Warning: failed to save state: %v", "disk full")
+	logger.Println("Error creating witness session for gastown: boom")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines: %q", len(lines), buf.String())
+	}
+	wantLevels := []string{"info", "warn", "error"}
+	for i, line := range lines {
+		var e logEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("line %d not JSON: %q", i, line)
+		}
+		if e.Level != wantLevels[i] || e.Time != "2026-01-02T03:04:05Z" || e.PID == 0 {
+			t.Errorf("line %d = %+v", i, e)
+		}
+	}
+	if !strings.Contains(lines[0], `"msg":"Heartbeat complete (#3)"`) {
+		t.Errorf("msg not preserved: %s", lines[0])
+	}
+}
@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// checkUnackedMail follows up on --require-ack messages that recipients
+// haven't acknowledged. Each unacknowledged recipient is re-notified once per
+// ack timeout (up to mail.MaxAckRenotify times); after that the message is
+// escalated to the overseer. Settled receipts are pruned after retention.
+func (d *Daemon) checkUnackedMail() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	store := mail.NewReceiptStore(filepath.Join(d.config.TownRoot, ".beads"))
+
+	followUps, err := store.PendingFollowUps(time.Now())
+	if err != nil {
+		d.logger.Printf("Error checking mail receipts: %v", err)
+		return
+	}
+
+	for _, f := range followUps {
+		switch f.Action {
+		case mail.AckRenotify:
+			for _, rr := range f.Recipients {
+				if err := router.Renotify(rr.Address, f.Receipt.From, f.Receipt.Subject); err != nil {
+					d.logger.Printf("Warning: failed to re-notify %s of %s: %v", rr.Address, f.Receipt.ID, err)
+				}
+				if err := store.RecordRenotify(f.Receipt.ID, rr.Address); err != nil {
+					d.logger.Printf("Warning: failed to record re-notify for %s: %v", f.Receipt.ID, err)
+				}
+				d.logger.Printf("Re-notified %s of unacknowledged mail %s (%q)", rr.Address, f.Receipt.ID, f.Receipt.Subject)
+			}
+
+		case mail.AckEscalate:
+			d.escalateUnackedMail(f)
+			if err := store.RecordEscalation(f.Receipt.ID); err != nil {
+				d.logger.Printf("Warning: failed to record escalation for %s: %v", f.Receipt.ID, err)
+			}
+		}
+	}
+
+	if pruned, err := store.Prune(mail.ReceiptRetention); err == nil && pruned > 0 {
+		d.logger.Printf("Pruned %d settled mail receipt(s)", pruned)
+	}
+}
+
+// escalateUnackedMail escalates a message whose recipients never acknowledged it.
+func (d *Daemon) escalateUnackedMail(f *mail.AckFollowUp) {
+	var addrs []string
+	for _, rr := range f.Recipients {
+		addrs = append(addrs, rr.Address)
+	}
+
+	topic := fmt.Sprintf("Unacknowledged mail from %s: %s", f.Receipt.From, f.Receipt.Subject)
+	body := fmt.Sprintf(`A message requiring acknowledgement was not acknowledged after %d re-notifications.
+
+receipt: %s
+from: %s
+sent: %s
+unacknowledged: %s
+
+Check with: gt mail status %s`,
+		mail.MaxAckRenotify, f.Receipt.ID, f.Receipt.From,
+		f.Receipt.SentAt.Format(time.RFC3339), strings.Join(addrs, ", "), f.Receipt.ID)
+
+	cmd := exec.Command("gt", "escalate", "-s", "HIGH", topic, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	if err := cmd.Run(); err != nil {
+		d.logger.Printf("Warning: failed to escalate unacknowledged mail %s: %v", f.Receipt.ID, err)
+	} else {
+		d.logger.Printf("Escalated unacknowledged mail %s (%s)", f.Receipt.ID, strings.Join(addrs, ", "))
+	}
+}
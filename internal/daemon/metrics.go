@@ -0,0 +1,431 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mrqueue"
+	"github.com/steveyegge/gastown/internal/polecat"
+)
+
+// costRefreshInterval bounds how often the cost ledger is re-read. Summing
+// session costs shells out to bd, which is too slow for every scrape.
+const costRefreshInterval = 5 * time.Minute
+
+// metricsNamespace prefixes every exported metric.
+const metricsNamespace = "gastown"
+
+// Content types for the two exposition formats served on /metrics.
+const (
+	contentTypePrometheus  = "text/plain; version=0.0.4; charset=utf-8"
+	contentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// counters holds the daemon's cumulative event counts since startup.
+type counters struct {
+	mu             sync.Mutex
+	restarts       map[string]int64 // by role
+	gupp           map[string]int64 // by rig
+	costUSD        float64
+	costUpdatedAt  time.Time
+	costRefreshing bool
+}
+
+// countRestart records that the daemon (re)started a session for role.
+func (d *Daemon) countRestart(role string) {
+	d.counters.mu.Lock()
+	defer d.counters.mu.Unlock()
+	if d.counters.restarts == nil {
+		d.counters.restarts = make(map[string]int64)
+	}
+	d.counters.restarts[role]++
+}
+
+// countGUPPViolation records a GUPP violation detected in rig.
+func (d *Daemon) countGUPPViolation(rig string) {
+	d.counters.mu.Lock()
+	defer d.counters.mu.Unlock()
+	if d.counters.gupp == nil {
+		d.counters.gupp = make(map[string]int64)
+	}
+	d.counters.gupp[rig]++
+}
+
+// metricSample is one labelled value of a metric family.
+type metricSample struct {
+	labels []string // alternating name, value
+	value  float64
+}
+
+// metricFamily is a named metric with its samples.
+type metricFamily struct {
+	name    string
+	help    string
+	typ     string // gauge or counter
+	samples []metricSample
+}
+
+func (f *metricFamily) add(value float64, labels ...string) {
+	f.samples = append(f.samples, metricSample{labels: labels, value: value})
+}
+
+func newFamily(name, typ, help string) *metricFamily {
+	return &metricFamily{name: metricsNamespace + "_" + name, typ: typ, help: help}
+}
+
+// collectMetrics gathers a snapshot of town health. Each source is
+// best-effort: a missing file or unavailable tool just omits its samples.
+func (d *Daemon) collectMetrics() []*metricFamily {
+	var families []*metricFamily
+	families = append(families, d.collectDaemonMetrics()...)
+	families = append(families, d.collectSessionMetrics())
+	rigs := d.getKnownRigs()
+	sort.Strings(rigs)
+	families = append(families, d.collectPoolMetrics(rigs)...)
+	families = append(families, d.collectMergeQueueMetrics(rigs)...)
+	families = append(families, d.collectDeaconMetrics())
+	families = append(families, d.collectMailMetrics())
+	families = append(families, d.collectCounterMetrics()...)
+	return families
+}
+
+func (d *Daemon) collectDaemonMetrics() []*metricFamily {
+	up := newFamily("daemon_up", "gauge", "Whether the Gas Town daemon is running.")
+	up.add(1)
+
+	heartbeats := newFamily("daemon_heartbeats_total", "counter", "Daemon heartbeats completed since the daemon started.")
+	lastHB := newFamily("daemon_last_heartbeat_timestamp_seconds", "gauge", "Unix time of the last completed daemon heartbeat.")
+	paused := newFamily("rig_supervision_paused", "gauge", "Whether daemon supervision of a rig is paused.")
+
+	d.mu.Lock()
+	if d.state != nil {
+		heartbeats.add(float64(d.state.HeartbeatCount))
+		if !d.state.LastHeartbeat.IsZero() {
+			lastHB.add(float64(d.state.LastHeartbeat.Unix()))
+		}
+	}
+	d.mu.Unlock()
+
+	for _, rig := range d.getKnownRigs() {
+		v := 0.0
+		if d.isRigPaused(rig) {
+			v = 1
+		}
+		paused.add(v, "rig", rig)
+	}
+	return []*metricFamily{up, heartbeats, lastHB, paused}
+}
+
+func (d *Daemon) collectSessionMetrics() *metricFamily {
+	f := newFamily("sessions", "gauge", "Gas Town tmux sessions by role and state (attached/detached).")
+	sessions, err := d.managedSessions()
+	if err != nil {
+		return f
+	}
+	counts := make(map[[2]string]int)
+	for _, s := range sessions {
+		state := "detached"
+		if s.Attached {
+			state = "attached"
+		}
+		counts[[2]string{s.Role, state}]++
+	}
+	for _, role := range []string{"mayor", "deacon", "boot", "witness", "refinery", "crew", "polecat"} {
+		for _, state := range []string{"attached", "detached"} {
+			f.add(float64(counts[[2]string{role, state}]), "role", role, "state", state)
+		}
+	}
+	return f
+}
+
+func (d *Daemon) collectPoolMetrics(rigs []string) []*metricFamily {
+	active := newFamily("polecat_pool_active", "gauge", "Polecat names in use, from the rig's name pool.")
+	size := newFamily("polecat_pool_size", "gauge", "Themed names in the rig's polecat name pool before overflow.")
+	for _, rig := range rigs {
+		pool := polecat.NewNamePool(filepath.Join(d.config.TownRoot, rig), rig)
+		if err := pool.Load(); err != nil {
+			continue
+		}
+		active.add(float64(pool.ActiveCount()), "rig", rig)
+		size.add(float64(pool.MaxSize), "rig", rig)
+	}
+	return []*metricFamily{active, size}
+}
+
+func (d *Daemon) collectMergeQueueMetrics(rigs []string) []*metricFamily {
+	depth := newFamily("merge_queue_depth", "gauge", "Merge requests waiting in the rig's merge queue.")
+	claimed := newFamily("merge_queue_claimed", "gauge", "Merge requests claimed by a refinery worker.")
+	oldest := newFamily("merge_queue_oldest_age_seconds", "gauge", "Age of the oldest merge request in the queue.")
+	ageSum := newFamily("merge_queue_total_age_seconds", "gauge", "Sum of the ages of queued merge requests.")
+
+	now := time.Now()
+	for _, rig := range rigs {
+		mrs, err := mrqueue.New(filepath.Join(d.config.TownRoot, rig)).List()
+		if err != nil {
+			continue
+		}
+		var nClaimed int
+		var maxAge, sum float64
+		for _, mr := range mrs {
+			if mr.ClaimedBy != "" {
+				nClaimed++
+			}
+			age := now.Sub(mr.CreatedAt).Seconds()
+			sum += age
+			maxAge = math.Max(maxAge, age)
+		}
+		depth.add(float64(len(mrs)), "rig", rig)
+		claimed.add(float64(nClaimed), "rig", rig)
+		oldest.add(maxAge, "rig", rig)
+		ageSum.add(sum, "rig", rig)
+	}
+	return []*metricFamily{depth, claimed, oldest, ageSum}
+}
+
+func (d *Daemon) collectDeaconMetrics() *metricFamily {
+	f := newFamily("deacon_heartbeat_age_seconds", "gauge", "Seconds since the Deacon last wrote its patrol heartbeat.")
+	if hb := deacon.ReadHeartbeat(d.config.TownRoot); hb != nil {
+		f.add(hb.Age().Seconds())
+	}
+	return f
+}
+
+func (d *Daemon) collectMailMetrics() *metricFamily {
+	f := newFamily("mail_backlog", "gauge", "Unread messages per mailbox, from the mail search index.")
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	backlog, err := router.SearchIndex().Backlog()
+	if err != nil {
+		return f
+	}
+	addrs := make([]string, 0, len(backlog))
+	for addr := range backlog {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		f.add(float64(backlog[addr]), "address", addr)
+	}
+	return f
+}
+
+func (d *Daemon) collectCounterMetrics() []*metricFamily {
+	restarts := newFamily("daemon_restarts_total", "counter", "Sessions started or restarted by the daemon since it started, by role.")
+	gupp := newFamily("gupp_violations_total", "counter", "GUPP violations detected (hooked work not progressing) since the daemon started.")
+	cost := newFamily("cost_usd_total", "counter", "Cumulative session cost in USD recorded in the cost ledger (gt costs).")
+
+	d.refreshCostIfStale()
+
+	d.counters.mu.Lock()
+	defer d.counters.mu.Unlock()
+	for _, role := range sortedKeys(d.counters.restarts) {
+		restarts.add(float64(d.counters.restarts[role]), "role", role)
+	}
+	for _, rig := range sortedKeys(d.counters.gupp) {
+		gupp.add(float64(d.counters.gupp[rig]), "rig", rig)
+	}
+	if !d.counters.costUpdatedAt.IsZero() {
+		cost.add(d.counters.costUSD)
+	}
+	return []*metricFamily{restarts, gupp, cost}
+}
+
+// refreshCostIfStale re-reads the cost ledger in the background when the
+// cached total is older than costRefreshInterval. Scrapes never wait on bd.
+func (d *Daemon) refreshCostIfStale() {
+	d.counters.mu.Lock()
+	stale := time.Since(d.counters.costUpdatedAt) > costRefreshInterval
+	if !stale || d.counters.costRefreshing {
+		d.counters.mu.Unlock()
+		return
+	}
+	d.counters.costRefreshing = true
+	d.counters.mu.Unlock()
+
+	go func() {
+		total, err := ledgerCostTotal(d.ctx, d.config.TownRoot)
+		d.counters.mu.Lock()
+		defer d.counters.mu.Unlock()
+		d.counters.costRefreshing = false
+		if err != nil {
+			d.logger.Printf("Warning: reading cost ledger for metrics: %v", err)
+			return
+		}
+		d.counters.costUSD = total
+		d.counters.costUpdatedAt = time.Now()
+	}()
+}
+
+// ledgerCostTotal sums cost_usd over the session.ended events recorded by
+// 'gt costs record'.
+func ledgerCostTotal(ctx context.Context, townRoot string) (float64, error) {
+	bd := func(args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "bd", args...) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = townRoot
+		return cmd.Output()
+	}
+
+	out, err := bd("list", "--type=event", "--all", "--limit=0", "--json")
+	if err != nil {
+		return 0, fmt.Errorf("listing events: %w", err)
+	}
+	var items []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(out, &items); err != nil {
+		return 0, fmt.Errorf("parsing event list: %w", err)
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	args := []string{"show", "--json"}
+	for _, item := range items {
+		args = append(args, item.ID)
+	}
+	out, err = bd(args...)
+	if err != nil {
+		return 0, fmt.Errorf("showing events: %w", err)
+	}
+	var events []struct {
+		EventKind string `json:"event_kind"`
+		Payload   string `json:"payload"`
+	}
+	if err := json.Unmarshal(out, &events); err != nil {
+		return 0, fmt.Errorf("parsing events: %w", err)
+	}
+
+	var total float64
+	for _, e := range events {
+		if e.EventKind != "session.ended" || e.Payload == "" {
+			continue
+		}
+		var p struct {
+			CostUSD float64 `json:"cost_usd"`
+		}
+		if json.Unmarshal([]byte(e.Payload), &p) == nil {
+			total += p.CostUSD
+		}
+	}
+	return total, nil
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeMetrics renders families in the Prometheus text format, or in
+// OpenMetrics format when openMetrics is set.
+func writeMetrics(w io.Writer, families []*metricFamily, openMetrics bool) error {
+	var b bytes.Buffer
+	for _, f := range families {
+		name := f.name
+		if openMetrics && f.typ == "counter" {
+			// OpenMetrics names the family without the _total suffix
+			name = strings.TrimSuffix(name, "_total")
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, escapeHelp(f.help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, f.typ)
+		for _, s := range f.samples {
+			b.WriteString(f.name)
+			if len(s.labels) > 0 {
+				b.WriteByte('{')
+				for i := 0; i+1 < len(s.labels); i += 2 {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					fmt.Fprintf(&b, "%s=\"%s\"", s.labels[i], escapeLabel(s.labels[i+1]))
+				}
+				b.WriteByte('}')
+			}
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(s.value, 'g', -1, 64))
+			b.WriteByte('\n')
+		}
+	}
+	if openMetrics {
+		b.WriteString("# EOF\n")
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+// serveMetrics handles GET /metrics.
+func (d *Daemon) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", contentTypeOpenMetrics)
+	} else {
+		w.Header().Set("Content-Type", contentTypePrometheus)
+	}
+	if err := writeMetrics(w, d.collectMetrics(), openMetrics); err != nil {
+		d.logger.Printf("Warning: writing metrics: %v", err)
+	}
+}
+
+// configureMetrics starts, stops or moves the metrics endpoint so it
+// listens on addr. An empty addr disables it.
+func (d *Daemon) configureMetrics(addr string) {
+	d.metricsMu.Lock()
+	defer d.metricsMu.Unlock()
+
+	if d.metricsServer != nil {
+		if d.metricsAddr == addr {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = d.metricsServer.Shutdown(ctx)
+		cancel()
+		d.logger.Printf("Metrics endpoint on %s stopped", d.metricsBound)
+		d.metricsServer = nil
+		d.metricsAddr, d.metricsBound = "", ""
+	}
+	if addr == "" {
+		return
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		d.logger.Printf("Warning: metrics endpoint unavailable: %v", err)
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", d.serveMetrics)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			d.logger.Printf("Metrics endpoint stopped: %v", err)
+		}
+	}()
+	d.metricsServer = srv
+	d.metricsAddr = addr
+	d.metricsBound = ln.Addr().String()
+	d.logger.Printf("Metrics endpoint listening on http://%s/metrics", d.metricsBound)
+}
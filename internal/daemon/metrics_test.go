@@ -0,0 +1,139 @@
+package daemon
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mrqueue"
+)
+
+func TestWriteMetricsFormats(t *testing.T) {
+	restarts := newFamily("daemon_restarts_total", "counter", "Restarts.\nBy role.")
+	restarts.add(3, "role", "polecat")
+	backlog := newFamily("mail_backlog", "gauge", "Unread mail.")
+	backlog.add(2, "address", `gastown/"odd"\name`)
+	families := []*metricFamily{restarts, backlog}
+
+	var prom bytes.Buffer
+	if err := writeMetrics(&prom, families, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# HELP gastown_daemon_restarts_total Restarts.\\nBy role.\n",
+		"# TYPE gastown_daemon_restarts_total counter\n",
+		`gastown_daemon_restarts_total{role="polecat"} 3` + "\n",
+		`gastown_mail_backlog{address="gastown/\"odd\"\\name"} 2` + "\n",
+	} {
+		if !strings.Contains(prom.String(), want) {
+			t.Errorf("Prometheus output missing %q:\n%s", want, prom.String())
+		}
+	}
+	if strings.Contains(prom.String(), "# EOF") {
+		t.Error("Prometheus output should not end with # EOF")
+	}
+
+	var om bytes.Buffer
+	if err := writeMetrics(&om, families, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(om.String(), "# TYPE gastown_daemon_restarts counter\n") ||
+		!strings.Contains(om.String(), `gastown_daemon_restarts_total{role="polecat"} 3`) ||
+		!strings.HasSuffix(om.String(), "# EOF\n") {
+		t.Errorf("unexpected OpenMetrics output:\n%s", om.String())
+	}
+}
+
+func TestCollectMetrics(t *testing.T) {
+	d, _ := newControlTestDaemon(t)
+	town := d.config.TownRoot
+
+	// Two queued MRs in gastown, one claimed
+	q := mrqueue.New(filepath.Join(town, "gastown"))
+	if err := q.Submit(&mrqueue.MR{ID: "mr-1", Branch: "polecat/nux", CreatedAt: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Submit(&mrqueue.MR{ID: "mr-2", Branch: "polecat/toast", ClaimedBy: "refinery-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Name pool with two polecats active
+	writeConfigFile(t, filepath.Join(town, "gastown", ".runtime", "namepool-state.json"),
+		`{"in_use": {"nux": true, "toast": true}, "max_size": 50}`)
+
+	// Mail index with one unread and one archived message for the witness
+	idx := mail.NewSearchIndex(filepath.Join(town, ".beads"))
+	if err := idx.Add(&mail.Message{ID: "hq-1", To: "gastown/witness", Subject: "hi"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Add(&mail.Message{ID: "hq-2", To: "gastown/witness", Subject: "old"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.SetArchived("hq-2", true); err != nil {
+		t.Fatal(err)
+	}
+
+	d.countRestart("witness")
+	d.countRestart("witness")
+	d.countGUPPViolation("gastown")
+	d.counters.costUSD = 12.5
+	d.counters.costUpdatedAt = time.Now() // skip the bd ledger query
+
+	rec := httptest.NewRecorder()
+	d.serveMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	out := rec.Body.String()
+	for _, want := range []string{
+		"gastown_daemon_up 1\n",
+		`gastown_merge_queue_depth{rig="gastown"} 2`,
+		`gastown_merge_queue_claimed{rig="gastown"} 1`,
+		`gastown_polecat_pool_active{rig="gastown"} 2`,
+		`gastown_polecat_pool_size{rig="gastown"} 50`,
+		`gastown_mail_backlog{address="gastown/witness"} 1`,
+		`gastown_daemon_restarts_total{role="witness"} 2`,
+		`gastown_gupp_violations_total{rig="gastown"} 1`,
+		"gastown_cost_usd_total 12.5\n",
+		`gastown_rig_supervision_paused{rig="beads"} 0`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+	if !strings.Contains(out, `gastown_merge_queue_oldest_age_seconds{rig="gastown"} 3`) {
+		t.Errorf("oldest MR age should be about an hour:\n%s", out)
+	}
+}
+
+func TestConfigureMetrics(t *testing.T) {
+	d, _ := newControlTestDaemon(t)
+	d.counters.costUpdatedAt = time.Now()
+
+	d.configureMetrics("127.0.0.1:0")
+	if d.metricsServer == nil {
+		t.Fatal("metrics server not started")
+	}
+	t.Cleanup(func() { d.configureMetrics("") })
+
+	resp, err := http.Get("http://" + d.metricsBound + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.Contains(body, []byte("gastown_daemon_up 1")) {
+		t.Errorf("GET /metrics = %d:\n%s", resp.StatusCode, body)
+	}
+
+	d.configureMetrics("")
+	if d.metricsServer != nil {
+		t.Error("metrics server still running after disable")
+	}
+}
@@ -0,0 +1,384 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// defaultConfigPollInterval is how often the daemon checks its config files
+// for edits when town settings don't set daemon.config_poll_interval (or
+// the older daemon.poll_interval).
+const defaultConfigPollInterval = 5 * time.Second
+
+// liveConfig is the last good configuration the daemon is running with.
+// Each file is replaced only when its new contents validate, so a bad edit
+// to one file never discards the others.
+type liveConfig struct {
+	settings    *config.TownSettings
+	messaging   *config.MessagingConfig // nil when config/messaging.json doesn't exist
+	rigs        []string                // nil until mayor/rigs.json has been loaded
+	rigSettings map[string]*config.RigSettings
+}
+
+// ReloadResult reports the outcome of a configuration reload.
+type ReloadResult struct {
+	Files    []string       `json:"files"`
+	Rigs     []string       `json:"rigs"`
+	Changes  []string       `json:"changes,omitempty"`
+	Rejected []RejectedFile `json:"rejected,omitempty"`
+	At       time.Time      `json:"at"`
+}
+
+// RejectedFile is a config file that failed validation. The daemon keeps
+// running with its previous contents.
+type RejectedFile struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+func (r *ReloadResult) reject(path string, err error) {
+	r.Rejected = append(r.Rejected, RejectedFile{Path: path, Error: err.Error()})
+}
+
+// fileStamp identifies a version of a watched file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// rigsConfigPath returns the path to the rig registry.
+func (d *Daemon) rigsConfigPath() string {
+	return filepath.Join(d.config.TownRoot, "mayor", "rigs.json")
+}
+
+// rigSettingsPath returns the path to a rig's settings file.
+func (d *Daemon) rigSettingsPath(rig string) string {
+	return config.RigSettingsPath(filepath.Join(d.config.TownRoot, rig))
+}
+
+// reloadConfig re-reads and validates town settings, messaging config, the
+// rig registry and every rig's settings, applying whatever validates.
+// Rejected files are logged and keep their last good contents.
+func (d *Daemon) reloadConfig() *ReloadResult {
+	d.reloadMu.Lock()
+	defer d.reloadMu.Unlock()
+
+	townRoot := d.config.TownRoot
+	res := &ReloadResult{At: time.Now()}
+
+	d.mu.Lock()
+	prev := d.cfg
+	d.mu.Unlock()
+
+	next := prev
+	next.rigSettings = make(map[string]*config.RigSettings, len(prev.rigSettings))
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	if settings, err := config.LoadOrCreateTownSettings(settingsPath); err != nil {
+		res.reject(settingsPath, err)
+	} else if err := config.ValidateTownSettings(settings); err != nil {
+		res.reject(settingsPath, err)
+	} else {
+		next.settings = settings
+		res.Files = append(res.Files, settingsPath)
+		if settings.Daemon == nil {
+			next.settings = d.withLegacyDaemonConfig(settings, res)
+		}
+	}
+
+	messagingPath := config.MessagingConfigPath(townRoot)
+	messaging, err := config.LoadMessagingConfig(messagingPath)
+	switch {
+	case errors.Is(err, config.ErrNotFound):
+		next.messaging = nil
+	case err != nil:
+		res.reject(messagingPath, err)
+	default:
+		next.messaging = messaging
+		res.Files = append(res.Files, messagingPath)
+	}
+
+	rigsPath := d.rigsConfigPath()
+	rigsCfg, err := config.LoadRigsConfig(rigsPath)
+	switch {
+	case errors.Is(err, config.ErrNotFound):
+		next.rigs = []string{}
+	case err != nil:
+		res.reject(rigsPath, err)
+	default:
+		next.rigs = make([]string, 0, len(rigsCfg.Rigs))
+		for name := range rigsCfg.Rigs {
+			next.rigs = append(next.rigs, name)
+		}
+		sort.Strings(next.rigs)
+		res.Files = append(res.Files, rigsPath)
+	}
+
+	for _, rig := range next.rigs {
+		path := d.rigSettingsPath(rig)
+		rs, err := config.LoadRigSettings(path)
+		switch {
+		case errors.Is(err, config.ErrNotFound):
+			// No settings file: the rig runs with defaults
+		case err != nil:
+			res.reject(path, err)
+			if old := prev.rigSettings[rig]; old != nil {
+				next.rigSettings[rig] = old
+			}
+		default:
+			next.rigSettings[rig] = rs
+			res.Files = append(res.Files, path)
+		}
+	}
+	res.Rigs = next.rigs
+	res.Changes = describeConfigChanges(&prev, &next)
+
+	d.mu.Lock()
+	d.cfg = next
+	if d.state != nil {
+		d.state.LastReload = res.At
+	}
+	d.mu.Unlock()
+
+	for _, r := range res.Rejected {
+		d.logger.Printf("Config rejected, keeping last good: %s: %s", r.Path, r.Error)
+	}
+	for _, c := range res.Changes {
+		d.logger.Printf("Config applied: %s", c)
+	}
+
+	d.configureMetrics(metricsAddrOf(next.settings))
+
+	if heartbeatIntervalOf(prev.settings) != heartbeatIntervalOf(next.settings) {
+		// Wake the main loop so the new interval takes effect now
+		select {
+		case d.intervalChanged <- struct{}{}:
+		default:
+		}
+	}
+
+	return res
+}
+
+// withLegacyDaemonConfig returns settings with the daemon block from
+// mayor/config.json, where daemon settings lived before town settings had
+// one. Town settings win whenever they set a daemon block.
+func (d *Daemon) withLegacyDaemonConfig(settings *config.TownSettings, res *ReloadResult) *config.TownSettings {
+	mayorPath := constants.MayorConfigPath(d.config.TownRoot)
+	mayorCfg, err := config.LoadMayorConfig(mayorPath)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			res.reject(mayorPath, err)
+		}
+		return settings
+	}
+	if mayorCfg.Daemon == nil {
+		return settings
+	}
+
+	merged := *settings
+	merged.Daemon = mayorCfg.Daemon
+	if err := config.ValidateTownSettings(&merged); err != nil {
+		res.reject(mayorPath, err)
+		return settings
+	}
+	d.logger.Printf("Warning: daemon settings in %s are deprecated; move them to the daemon block of %s",
+		mayorPath, config.TownSettingsPath(d.config.TownRoot))
+	res.Files = append(res.Files, mayorPath)
+	return &merged
+}
+
+// describeConfigChanges summarizes what a reload changed, for the log.
+// The first load (no previous settings) reports nothing.
+func describeConfigChanges(prev, next *liveConfig) []string {
+	if prev.settings == nil {
+		return nil
+	}
+
+	var changes []string
+	if a, b := heartbeatIntervalOf(prev.settings), heartbeatIntervalOf(next.settings); a != b {
+		changes = append(changes, fmt.Sprintf("heartbeat interval %s -> %s", a, b))
+	}
+	if a, b := configPollIntervalOf(prev.settings), configPollIntervalOf(next.settings); a != b {
+		changes = append(changes, fmt.Sprintf("config poll interval %s -> %s", a, b))
+	}
+	if a, b := metricsAddrOf(prev.settings), metricsAddrOf(next.settings); a != b {
+		changes = append(changes, fmt.Sprintf("metrics address %q -> %q", a, b))
+	}
+
+	if !reflect.DeepEqual(prev.messaging, next.messaging) {
+		changes = append(changes, "messaging config changed")
+	}
+
+	added, removed := diffRigs(prev.rigs, next.rigs)
+	if len(added) > 0 {
+		changes = append(changes, "rigs added: "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, "rigs removed: "+strings.Join(removed, ", "))
+	}
+
+	for _, rig := range next.rigs {
+		a, b := mergeQueueOf(prev.rigSettings[rig]), mergeQueueOf(next.rigSettings[rig])
+		if a == nil && b == nil {
+			continue
+		}
+		if a == nil || b == nil || *a != *b {
+			state := "enabled"
+			if b != nil && !b.Enabled {
+				state = "disabled"
+			}
+			changes = append(changes, fmt.Sprintf("merge queue config for %s changed (%s)", rig, state))
+		}
+	}
+	return changes
+}
+
+// diffRigs returns rigs present only in next (added) and only in prev (removed).
+func diffRigs(prev, next []string) (added, removed []string) {
+	seen := make(map[string]bool, len(prev))
+	for _, r := range prev {
+		seen[r] = true
+	}
+	for _, r := range next {
+		if !seen[r] {
+			added = append(added, r)
+		}
+		delete(seen, r)
+	}
+	for r := range seen {
+		removed = append(removed, r)
+	}
+	sort.Strings(removed)
+	return added, removed
+}
+
+func mergeQueueOf(rs *config.RigSettings) *config.MergeQueueConfig {
+	if rs == nil {
+		return nil
+	}
+	return rs.MergeQueue
+}
+
+// heartbeatIntervalOf returns the configured heartbeat interval, falling
+// back to the fixed recovery interval.
+func heartbeatIntervalOf(settings *config.TownSettings) time.Duration {
+	if settings != nil && settings.Daemon != nil && settings.Daemon.HeartbeatInterval != "" {
+		if d, err := time.ParseDuration(settings.Daemon.HeartbeatInterval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return recoveryHeartbeatInterval
+}
+
+// configPollIntervalOf returns how often to check config files for edits.
+// The older poll_interval key is used when config_poll_interval is unset.
+func configPollIntervalOf(settings *config.TownSettings) time.Duration {
+	if settings == nil || settings.Daemon == nil {
+		return defaultConfigPollInterval
+	}
+	value := settings.Daemon.ConfigPollInterval
+	if value == "" {
+		value = settings.Daemon.PollInterval
+	}
+	if value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultConfigPollInterval
+}
+
+// metricsAddrOf returns the configured metrics listen address, if any.
+func metricsAddrOf(settings *config.TownSettings) string {
+	if settings != nil && settings.Daemon != nil {
+		return settings.Daemon.MetricsAddr
+	}
+	return ""
+}
+
+// heartbeatInterval returns the live heartbeat interval.
+func (d *Daemon) heartbeatInterval() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return heartbeatIntervalOf(d.cfg.settings)
+}
+
+// mergeQueueEnabled reports whether a rig's merge queue is enabled in its
+// live settings. Rigs without settings use the default (enabled).
+func (d *Daemon) mergeQueueEnabled(rig string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	mq := mergeQueueOf(d.cfg.rigSettings[rig])
+	return mq == nil || mq.Enabled
+}
+
+// watchedConfigFiles lists the files whose edits trigger a reload.
+func (d *Daemon) watchedConfigFiles() []string {
+	files := []string{
+		config.TownSettingsPath(d.config.TownRoot),
+		constants.MayorConfigPath(d.config.TownRoot),
+		config.MessagingConfigPath(d.config.TownRoot),
+		d.rigsConfigPath(),
+	}
+	for _, rig := range d.getKnownRigs() {
+		files = append(files, d.rigSettingsPath(rig))
+	}
+	return files
+}
+
+// configFilesChanged stats the watched files and reports whether any was
+// created, modified or removed since the last call. Only the watcher
+// goroutine calls this, so configStamps needs no lock.
+func (d *Daemon) configFilesChanged() bool {
+	stamps := make(map[string]fileStamp)
+	for _, path := range d.watchedConfigFiles() {
+		var st fileStamp
+		if info, err := os.Stat(path); err == nil {
+			st = fileStamp{modTime: info.ModTime(), size: info.Size(), exists: true}
+		}
+		stamps[path] = st
+	}
+
+	changed := len(stamps) != len(d.configStamps)
+	for path, st := range stamps {
+		if old, ok := d.configStamps[path]; !ok || old != st {
+			changed = true
+		}
+	}
+	d.configStamps = stamps
+	return changed
+}
+
+// watchConfig polls the config files and reloads when they change, until
+// the daemon stops. Polling keeps the daemon free of platform-specific
+// file notification and copes with editors that replace files on save.
+func (d *Daemon) watchConfig() {
+	d.configFilesChanged() // baseline for the config loaded at startup
+	for {
+		d.mu.Lock()
+		interval := configPollIntervalOf(d.cfg.settings)
+		d.mu.Unlock()
+
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if d.configFilesChanged() {
+			d.logger.Println("Config change detected, reloading")
+			d.reloadConfig()
+		}
+	}
+}
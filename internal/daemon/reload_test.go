@@ -0,0 +1,158 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadConfigKeepsLastGood(t *testing.T) {
+	d, _ := newControlTestDaemon(t)
+	d.intervalChanged = make(chan struct{}, 1)
+	town := d.config.TownRoot
+	settingsPath := filepath.Join(town, "settings", "config.json")
+	rigSettingsPath := filepath.Join(town, "gastown", "settings", "config.json")
+
+	writeConfigFile(t, settingsPath, `{"type": "town-settings", "version": 1, "daemon": {"heartbeat_interval": "1m"}}`)
+	writeConfigFile(t, rigSettingsPath, `{"type": "rig-settings", "version": 1, "merge_queue": {"enabled": true}}`)
+	if res := d.reloadConfig(); len(res.Rejected) != 0 {
+		t.Fatalf("initial reload rejected %+v", res.Rejected)
+	}
+	if got := d.heartbeatInterval(); got != time.Minute {
+		t.Errorf("heartbeatInterval = %v, want 1m", got)
+	}
+	select {
+	case <-d.intervalChanged:
+	default:
+		t.Error("interval change did not wake the main loop")
+	}
+
+	// Valid edits apply: new rig, new interval, merge queue disabled
+	writeConfigFile(t, filepath.Join(town, "mayor", "rigs.json"),
+		`{"version": 1, "rigs": {"gastown": {}, "beads": {}, "wyvern": {}}}`)
+	writeConfigFile(t, settingsPath, `{"type": "town-settings", "version": 1, "daemon": {"heartbeat_interval": "2m"}}`)
+	writeConfigFile(t, rigSettingsPath, `{"type": "rig-settings", "version": 1, "merge_queue": {"enabled": false}}`)
+	res := d.reloadConfig()
+	if len(res.Rejected) != 0 {
+		t.Fatalf("reload rejected %+v", res.Rejected)
+	}
+	changes := strings.Join(res.Changes, "\n")
+	for _, want := range []string{"heartbeat interval 1m0s -> 2m0s", "rigs added: wyvern", "merge queue config for gastown changed (disabled)"} {
+		if !strings.Contains(changes, want) {
+			t.Errorf("changes missing %q:\n%s", want, changes)
+		}
+	}
+	if len(d.getKnownRigs()) != 3 || d.mergeQueueEnabled("gastown") || !d.mergeQueueEnabled("beads") {
+		t.Errorf("rigs=%v gastown mq=%v", d.getKnownRigs(), d.mergeQueueEnabled("gastown"))
+	}
+
+	// Invalid edits are rejected file by file; the rest still applies
+	writeConfigFile(t, settingsPath, `{"type": "town-settings", "daemon": {"heartbeat_interval": "soon"}}`)
+	writeConfigFile(t, rigSettingsPath, `{"type": "rig-settings", "merge_queue": {"on_conflict": "yolo"}}`)
+	writeConfigFile(t, filepath.Join(town, "mayor", "rigs.json"), `{"version": 1, "rigs": {"gastown": {}}}`)
+	res = d.reloadConfig()
+	if len(res.Rejected) != 2 {
+		t.Fatalf("rejected = %+v, want town and rig settings", res.Rejected)
+	}
+	if got := d.heartbeatInterval(); got != 2*time.Minute {
+		t.Errorf("heartbeatInterval after bad edit = %v, want last good 2m", got)
+	}
+	if d.mergeQueueEnabled("gastown") {
+		t.Error("bad rig settings edit discarded the last good merge queue config")
+	}
+	if rigs := d.getKnownRigs(); len(rigs) != 1 {
+		t.Errorf("valid rigs.json edit not applied: %v", rigs)
+	}
+}
+
+func TestConfigFilesChanged(t *testing.T) {
+	d, _ := newControlTestDaemon(t)
+	d.reloadConfig()
+
+	if !d.configFilesChanged() {
+		t.Error("first scan should report a change (baseline)")
+	}
+	if d.configFilesChanged() {
+		t.Error("no edits, but change reported")
+	}
+
+	writeConfigFile(t, filepath.Join(d.config.TownRoot, "config", "messaging.json"), `{"type": "messaging"}`)
+	if !d.configFilesChanged() {
+		t.Error("new messaging.json not detected")
+	}
+
+	rigSettings := filepath.Join(d.config.TownRoot, "beads", "settings", "config.json")
+	writeConfigFile(t, rigSettings, `{"type": "rig-settings"}`)
+	if !d.configFilesChanged() {
+		t.Error("new rig settings not detected")
+	}
+	if err := os.Remove(rigSettings); err != nil {
+		t.Fatal(err)
+	}
+	if !d.configFilesChanged() {
+		t.Error("removed rig settings not detected")
+	}
+}
+
+func TestReloadConfigMessaging(t *testing.T) {
+	d, _ := newControlTestDaemon(t)
+	messagingPath := filepath.Join(d.config.TownRoot, "config", "messaging.json")
+
+	writeConfigFile(t, messagingPath, `{"type": "messaging", "version": 1, "lists": {"ops": ["mayor/"]}}`)
+	if res := d.reloadConfig(); len(res.Rejected) != 0 {
+		t.Fatalf("initial reload rejected %+v", res.Rejected)
+	}
+
+	writeConfigFile(t, messagingPath, `{"type": "not-messaging"}`)
+	res := d.reloadConfig()
+	if len(res.Rejected) != 1 || res.Rejected[0].Path != messagingPath {
+		t.Fatalf("rejected = %+v, want messaging.json", res.Rejected)
+	}
+	if m := d.cfg.messaging; m == nil || len(m.Lists["ops"]) != 1 {
+		t.Errorf("bad messaging.json edit discarded the last good config: %+v", m)
+	}
+
+	writeConfigFile(t, messagingPath, `{"type": "messaging", "version": 1, "lists": {"ops": ["mayor/", "deacon/"]}}`)
+	res = d.reloadConfig()
+	if !strings.Contains(strings.Join(res.Changes, "\n"), "messaging config changed") {
+		t.Errorf("changes = %v, want messaging config changed", res.Changes)
+	}
+}
+
+func TestReloadConfigLegacyDaemonSettings(t *testing.T) {
+	d, _ := newControlTestDaemon(t)
+	town := d.config.TownRoot
+
+	// Daemon settings from before town settings had a daemon block
+	writeConfigFile(t, filepath.Join(town, "mayor", "config.json"),
+		`{"type": "mayor-config", "version": 1, "daemon": {"heartbeat_interval": "4m", "poll_interval": "20s"}}`)
+	if res := d.reloadConfig(); len(res.Rejected) != 0 {
+		t.Fatalf("reload rejected %+v", res.Rejected)
+	}
+	if got := d.heartbeatInterval(); got != 4*time.Minute {
+		t.Errorf("heartbeatInterval = %v, want 4m from mayor/config.json", got)
+	}
+	if got := configPollIntervalOf(d.cfg.settings); got != 20*time.Second {
+		t.Errorf("config poll interval = %v, want 20s from poll_interval", got)
+	}
+
+	// A daemon block in town settings takes precedence
+	writeConfigFile(t, filepath.Join(town, "settings", "config.json"),
+		`{"type": "town-settings", "version": 1, "daemon": {"heartbeat_interval": "1m"}}`)
+	d.reloadConfig()
+	if got := d.heartbeatInterval(); got != time.Minute {
+		t.Errorf("heartbeatInterval = %v, want 1m from town settings", got)
+	}
+}
@@ -0,0 +1,97 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sd_notify(3) state strings.
+const (
+	sdReady     = "READY=1"
+	sdStopping  = "STOPPING=1"
+	sdReloading = "RELOADING=1"
+	sdWatchdog  = "WATCHDOG=1"
+)
+
+// sdNotify sends state to the service manager over $NOTIFY_SOCKET. It is a
+// no-op (returning false) when the daemon isn't run by a supervisor that
+// speaks the protocol.
+func sdNotify(states ...string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:] // abstract namespace
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("connecting to notify socket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.Write([]byte(strings.Join(states, "\n"))); err != nil {
+		return false, fmt.Errorf("writing to notify socket: %w", err)
+	}
+	return true, nil
+}
+
+// sdStatus formats a free-form STATUS= line shown by 'systemctl status'.
+func sdStatus(format string, args ...interface{}) string {
+	return "STATUS=" + fmt.Sprintf(format, args...)
+}
+
+// watchdogInterval returns how often to ping the service manager's watchdog,
+// or 0 when no watchdog is configured for this process. Pings go out at half
+// the configured timeout, as sd_watchdog_enabled(3) recommends.
+func watchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0 // watchdog is meant for another process
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// runWatchdog pings the service manager's watchdog until the daemon stops.
+// Pings stop while a heartbeat overruns, so a wedged daemon gets restarted
+// by its supervisor.
+func (d *Daemon) runWatchdog(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !d.loopResponsive() {
+			d.logger.Printf("Warning: main loop unresponsive, withholding watchdog ping")
+			continue
+		}
+		if _, err := sdNotify(sdWatchdog); err != nil {
+			d.logger.Printf("Warning: watchdog ping failed: %v", err)
+		}
+	}
+}
+
+// heartbeatOverrun is how long a single heartbeat may run before the
+// watchdog treats the daemon as wedged. Heartbeats restart sessions and wait
+// for agents to start, so they can legitimately take several minutes.
+const heartbeatOverrun = 15 * time.Minute
+
+// loopResponsive reports whether the main loop is idle waiting for work or
+// inside a heartbeat that hasn't overrun.
+func (d *Daemon) loopResponsive() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return !d.inHeartbeat || time.Since(d.heartbeatStarted) < heartbeatOverrun
+}
@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSdNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if ok, err := sdNotify(sdReady); ok || err != nil {
+		t.Errorf("without NOTIFY_SOCKET: ok=%v err=%v, want no-op", ok, err)
+	}
+
+	sock := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sock, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", sock)
+
+	ok, err := sdNotify(sdReady, sdStatus("Supervising %d rig(s)", 2))
+	if !ok || err != nil {
+		t.Fatalf("sdNotify: ok=%v err=%v", ok, err)
+	}
+	buf := make([]byte, 256)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(buf[:n]), "READY=1\nSTATUS=Supervising 2 rig(s)"; got != want {
+		t.Errorf("datagram = %q, want %q", got, want)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	t.Setenv("WATCHDOG_PID", "")
+	if got := watchdogInterval(); got != 0 {
+		t.Errorf("unset: %v, want 0", got)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "300000000")
+	if got := watchdogInterval(); got != 150*time.Second {
+		t.Errorf("5min watchdog: %v, want 2m30s", got)
+	}
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if got := watchdogInterval(); got != 0 {
+		t.Errorf("other PID: %v, want 0", got)
+	}
+}
+
+func TestLoopResponsive(t *testing.T) {
+	d := &Daemon{}
+	if !d.loopResponsive() {
+		t.Error("idle loop should be responsive")
+	}
+	d.inHeartbeat = true
+	d.heartbeatStarted = time.Now().Add(-time.Minute)
+	if !d.loopResponsive() {
+		t.Error("short heartbeat should be responsive")
+	}
+	d.heartbeatStarted = time.Now().Add(-heartbeatOverrun - time.Minute)
+	if d.loopResponsive() {
+		t.Error("overrun heartbeat should not be responsive")
+	}
+}
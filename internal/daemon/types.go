@@ -30,6 +30,14 @@ type Config struct {
 
 	// PidFile is the path to the PID file.
 	PidFile string `json:"pid_file"`
+
+	// Foreground runs the daemon under a process supervisor: logs go to
+	// stdout instead of LogFile, and readiness, reloads and watchdog pings
+	// are reported via sd_notify when $NOTIFY_SOCKET is set.
+	Foreground bool `json:"foreground,omitempty"`
+
+	// LogFormat is LogFormatText (default) or LogFormatJSON.
+	LogFormat string `json:"log_format,omitempty"`
 }
 
 // DefaultConfig returns the default daemon configuration.
@@ -59,6 +67,16 @@ type State struct {
 
 	// HeartbeatCount is how many heartbeats have completed.
 	HeartbeatCount int64 `json:"heartbeat_count"`
+
+	// LastReload is when configuration was last reloaded.
+	LastReload time.Time `json:"last_reload,omitempty"`
+
+	// PausedRigs lists rigs whose supervision is paused, keyed by rig name.
+	PausedRigs map[string]*RigPause `json:"paused_rigs,omitempty"`
+
+	// Restarts is recent automatic restart history, keyed by session name.
+	// It drives restart backoff and crash-loop detection.
+	Restarts map[string]*RestartHistory `json:"restarts,omitempty"`
 }
 
 // StateFile returns the path to the state file.
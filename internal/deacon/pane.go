@@ -0,0 +1,327 @@
+package deacon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PaneState classifies what an agent's tmux pane shows.
+type PaneState string
+
+// Pane states, from least to most in need of attention.
+const (
+	PaneWorking          PaneState = "working"           // busy or output changing
+	PaneIdle             PaneState = "idle"              // at prompt, nothing hooked
+	PaneIdleWithWork     PaneState = "idle_with_work"    // at prompt with work on hook (GUPP)
+	PaneErrorLoop        PaneState = "error_loop"        // repeating the same error
+	PaneContextLimit     PaneState = "context_limit"     // context nearly exhausted
+	PanePermissionPrompt PaneState = "permission_prompt" // blocked on a permission dialog
+)
+
+// Remediation is an action the Deacon can take for a pane state.
+type Remediation string
+
+// Remediations, from least to most disruptive.
+const (
+	RemedyNone         Remediation = ""
+	RemedyNudge        Remediation = "nudge"         // remind the agent of its work
+	RemedyAcceptPrompt Remediation = "accept_prompt" // confirm the highlighted (default) option
+	RemedyHandoff      Remediation = "handoff"       // ask the agent to hand off to a fresh session
+	RemedyEscalate     Remediation = "escalate"      // tell the overseer; a human has to look
+)
+
+// Default parameters for pane analysis.
+const (
+	DefaultIdleThreshold       = 10 * time.Minute // unchanged prompt before idle counts
+	DefaultErrorRepeats        = 3                // same error lines before it's a loop
+	DefaultRemediationCooldown = 10 * time.Minute // between remediations of one state
+	DefaultNudgesBeforeHandoff = 2                // nudges before escalating to handoff
+	DefaultContextLowPercent   = 10               // auto-compact percent that counts as low
+)
+
+// PaneConfig holds configurable parameters for pane analysis.
+type PaneConfig struct {
+	IdleThreshold       time.Duration `json:"idle_threshold"`
+	ErrorRepeats        int           `json:"error_repeats"`
+	RemediationCooldown time.Duration `json:"remediation_cooldown"`
+	NudgesBeforeHandoff int           `json:"nudges_before_handoff"`
+	ContextLowPercent   int           `json:"context_low_percent"`
+
+	// AutoAcceptPrompts confirms permission dialogs instead of escalating
+	// them. Off by default: accepting runs whatever the agent asked for.
+	AutoAcceptPrompts bool `json:"auto_accept_prompts"`
+}
+
+// DefaultPaneConfig returns the default pane analysis config.
+func DefaultPaneConfig() *PaneConfig {
+	return &PaneConfig{
+		IdleThreshold:       DefaultIdleThreshold,
+		ErrorRepeats:        DefaultErrorRepeats,
+		RemediationCooldown: DefaultRemediationCooldown,
+		NudgesBeforeHandoff: DefaultNudgesBeforeHandoff,
+		ContextLowPercent:   DefaultContextLowPercent,
+	}
+}
+
+// PaneAnalysis is the outcome of observing an agent's pane.
+type PaneAnalysis struct {
+	AgentID     string      `json:"agent_id"`
+	State       PaneState   `json:"state"`
+	Since       time.Time   `json:"since"`
+	Evidence    string      `json:"evidence,omitempty"`
+	Remediation Remediation `json:"remediation,omitempty"`
+}
+
+var (
+	// Claude Code permission dialogs: the question, then a numbered option
+	// list with the cursor on "1. Yes", at the bottom of the pane
+	permissionQuestionRe = regexp.MustCompile(`(?i)^do you want to (proceed|make this edit|create|allow|run|fetch)\b.*\?$`)
+	permissionYesRe      = regexp.MustCompile(`(?i)^❯\s*1\.\s+yes\b`)
+	dialogOptionRe       = regexp.MustCompile(`^(❯\s*)?\d+\.\s`)
+	dialogFooterRe       = regexp.MustCompile(`(?i)^esc to (cancel|exit)\b`)
+
+	// Context warnings shown in the Claude Code status area
+	autoCompactRe  = regexp.MustCompile(`(?i)context left until auto-compact:\s*(\d+)%`)
+	contextLimitRe = regexp.MustCompile(`(?i)(context low|prompt is too long|conversation is too long|context window (is )?(full|exceeded))`)
+
+	// Busy indicator while Claude is generating or running tools
+	busyRe = regexp.MustCompile(`(?i)esc to interrupt`)
+
+	errorLineRe = regexp.MustCompile(`(?i)\b(error|failed|fatal|exception|panic)\b`)
+
+	// Volatile parts of error lines, masked so repeats compare equal
+	hexRe    = regexp.MustCompile(`\b[0-9a-f]{7,}\b`)
+	numberRe = regexp.MustCompile(`\d+`)
+	spaceRe  = regexp.MustCompile(`\s+`)
+)
+
+// Trailing lines searched for dialogs and the input prompt, and for
+// repeated errors. Errors further back have likely been dealt with.
+const (
+	promptTail = 15
+	errorTail  = 80
+)
+
+// paneView is the content-only classification of one capture.
+type paneView struct {
+	permission string // matching dialog line
+	context    string // matching context warning
+	busy       bool
+	atPrompt   bool
+	errorSig   string // most repeated error signature
+	errorCount int
+}
+
+// inspectPane classifies a single pane capture, without history.
+func inspectPane(content string, cfg *PaneConfig) paneView {
+	var v paneView
+	lines := nonEmptyLines(content)
+	tail := lines
+	if len(tail) > promptTail {
+		tail = tail[len(tail)-promptTail:]
+	}
+
+	v.permission = permissionDialog(tail)
+	for _, line := range tail {
+		if busyRe.MatchString(line) {
+			v.busy = true
+		}
+		if isPromptLine(line) {
+			v.atPrompt = true
+		}
+		if m := autoCompactRe.FindStringSubmatch(line); m != nil {
+			if pct, err := strconv.Atoi(m[1]); err == nil && pct <= cfg.ContextLowPercent {
+				v.context = strings.TrimSpace(line)
+			}
+		} else if v.context == "" && contextLimitRe.MatchString(line) {
+			v.context = strings.TrimSpace(line)
+		}
+	}
+
+	if len(lines) > errorTail {
+		lines = lines[len(lines)-errorTail:]
+	}
+	counts := make(map[string]int)
+	for _, line := range lines {
+		if !errorLineRe.MatchString(line) {
+			continue
+		}
+		sig := errorSignature(line)
+		counts[sig]++
+		if counts[sig] > v.errorCount {
+			v.errorSig, v.errorCount = sig, counts[sig]
+		}
+	}
+	return v
+}
+
+// permissionDialog returns the question of the permission dialog the pane
+// ends with, or "". The dialog must be the last thing on screen: the
+// question, directly followed by the option list with the cursor on
+// "1. Yes", followed only by the dialog's footer and box borders. Dialog
+// text quoted earlier in the scrollback doesn't count.
+func permissionDialog(tail []string) string {
+	i := len(tail) - 1
+	for i >= 0 && (dialogLine(tail[i]) == "" || dialogFooterRe.MatchString(dialogLine(tail[i]))) {
+		i--
+	}
+	options := 0
+	for i >= 0 && dialogOptionRe.MatchString(dialogLine(tail[i])) {
+		options++
+		i--
+	}
+	if options < 2 || i < 0 || !permissionYesRe.MatchString(dialogLine(tail[i+1])) {
+		return ""
+	}
+	if !permissionQuestionRe.MatchString(dialogLine(tail[i])) {
+		return ""
+	}
+	return dialogLine(tail[i])
+}
+
+// dialogLine strips indentation and box drawing from a dialog line. Lines
+// that are only box borders come back empty.
+func dialogLine(line string) string {
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(line), "│╭╮╰╯─ "))
+}
+
+// isPromptLine reports whether a line is Claude's empty input prompt,
+// either bare ("> ") or inside the input box ("│ > │").
+func isPromptLine(line string) bool {
+	trimmed := strings.Trim(strings.TrimSpace(line), "│ ")
+	return trimmed == ">" || strings.HasPrefix(trimmed, "> ")
+}
+
+// errorSignature normalizes an error line so repeats with different
+// counters, IDs or timestamps compare equal.
+func errorSignature(line string) string {
+	sig := strings.ToLower(strings.TrimSpace(line))
+	sig = hexRe.ReplaceAllString(sig, "<id>")
+	sig = numberRe.ReplaceAllString(sig, "#")
+	sig = spaceRe.ReplaceAllString(sig, " ")
+	if len(sig) > 120 {
+		sig = sig[:120]
+	}
+	return sig
+}
+
+func nonEmptyLines(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func paneHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ObservePane records a capture of the agent's pane taken at now and
+// classifies it. hookedWork reports whether the agent has work on its hook.
+// The pane history kept in the health state lets idleness be judged over
+// time; the returned analysis carries the remediation the Deacon should
+// apply, if one is due (see RecordRemediation).
+func (s *AgentHealthState) ObservePane(content string, hookedWork bool, now time.Time, cfg *PaneConfig) *PaneAnalysis {
+	if cfg == nil {
+		cfg = DefaultPaneConfig()
+	}
+	v := inspectPane(content, cfg)
+
+	hash := paneHash(content)
+	if hash != s.PaneHash || s.PaneUnchangedSince.IsZero() {
+		s.PaneHash = hash
+		s.PaneUnchangedSince = now
+	}
+	unchanged := now.Sub(s.PaneUnchangedSince)
+
+	state, evidence := PaneWorking, ""
+	switch {
+	case v.permission != "":
+		state, evidence = PanePermissionPrompt, v.permission
+	case v.context != "":
+		state, evidence = PaneContextLimit, v.context
+	case v.errorCount >= cfg.ErrorRepeats:
+		state, evidence = PaneErrorLoop, fmt.Sprintf("%dx %s", v.errorCount, v.errorSig)
+	case v.atPrompt && !v.busy && unchanged >= cfg.IdleThreshold:
+		state = PaneIdle
+		evidence = fmt.Sprintf("at prompt, unchanged for %s", unchanged.Round(time.Second))
+		if hookedWork {
+			state = PaneIdleWithWork
+		}
+	}
+
+	if state != s.PaneState {
+		s.PaneState = state
+		s.PaneStateSince = now
+		s.PaneRemediations = 0
+		s.LastRemediation = RemedyNone
+		s.LastRemediationTime = time.Time{}
+	}
+	s.PaneEvidence = evidence
+
+	return &PaneAnalysis{
+		AgentID:     s.AgentID,
+		State:       state,
+		Since:       s.PaneStateSince,
+		Evidence:    evidence,
+		Remediation: s.nextRemediation(now, cfg),
+	}
+}
+
+// nextRemediation picks the remediation for the current pane state, or
+// none while the last one is cooling down.
+func (s *AgentHealthState) nextRemediation(now time.Time, cfg *PaneConfig) Remediation {
+	if !s.LastRemediationTime.IsZero() && now.Sub(s.LastRemediationTime) < cfg.RemediationCooldown {
+		return RemedyNone
+	}
+	switch s.PaneState {
+	case PanePermissionPrompt:
+		if cfg.AutoAcceptPrompts {
+			return RemedyAcceptPrompt
+		}
+		return RemedyEscalate
+	case PaneContextLimit:
+		return RemedyHandoff
+	case PaneErrorLoop, PaneIdleWithWork:
+		if s.PaneRemediations >= cfg.NudgesBeforeHandoff {
+			return RemedyHandoff
+		}
+		return RemedyNudge
+	}
+	return RemedyNone
+}
+
+// RecordRemediation records that a remediation was applied for the current
+// pane state.
+func (s *AgentHealthState) RecordRemediation(r Remediation, now time.Time) {
+	s.LastRemediation = r
+	s.LastRemediationTime = now
+	s.PaneRemediations++
+}
+
+// RemediationMessage returns the nudge text for a remediation, or "" for
+// remediations that don't send a message.
+func RemediationMessage(a *PaneAnalysis) string {
+	switch a.Remediation {
+	case RemedyNudge:
+		if a.State == PaneErrorLoop {
+			return fmt.Sprintf("DEACON: you appear to be repeating the same error (%s). Step back, try a different approach, or escalate with 'gt escalate'.", a.Evidence)
+		}
+		return "DEACON: you have work on your hook but are sitting idle. Run 'gt hook' and continue (GUPP)."
+	case RemedyHandoff:
+		if a.State == PaneContextLimit {
+			return "DEACON: your context is nearly exhausted. Run 'gt handoff -c' now so a fresh session continues your hooked work."
+		}
+		return "DEACON: you still appear stuck (" + string(a.State) + "). Run 'gt handoff -c' so a fresh session picks up your hooked work."
+	}
+	return ""
+}
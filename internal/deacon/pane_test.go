@@ -0,0 +1,162 @@
+package deacon
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const idlePane = `● Finished updating the parser tests.
+
+╭──────────────────────────────────────────╮
+│ >                                        │
+╰──────────────────────────────────────────╯
+  ? for shortcuts`
+
+func TestObservePane_States(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		hooked  bool
+		want    PaneState
+		remedy  Remediation
+	}{
+		{
+			name:    "permission prompt",
+			content: "Bash command\n  rm -rf build/\n\nDo you want to proceed?\n❯ 1. Yes\n  2. No, and tell Claude what to do differently",
+			want:    PanePermissionPrompt,
+			remedy:  RemedyEscalate,
+		},
+		{
+			name:    "permission dialog in a box",
+			content: "╭──────────────────────────────╮\n│ Bash command                 │\n│   make deploy                │\n│ Do you want to proceed?      │\n│ ❯ 1. Yes                     │\n│   2. No                      │\n╰──────────────────────────────╯\n  Esc to cancel",
+			want:    PanePermissionPrompt,
+			remedy:  RemedyEscalate,
+		},
+		{
+			name:    "dialog text quoted in scrollback",
+			content: "● The tool asks \"Do you want to proceed?\" and shows:\n❯ 1. Yes\n  2. No\n● Done, waiting for review.\n\n│ >  │",
+			want:    PaneWorking,
+		},
+		{
+			name:    "cursor not on yes",
+			content: "Do you want to proceed?\n  1. Yes\n❯ 2. No, and tell Claude what to do differently",
+			want:    PaneWorking,
+		},
+		{
+			name:    "context limit",
+			content: "● Working on it\n\n│ >  │\nContext left until auto-compact: 4%",
+			want:    PaneContextLimit,
+			remedy:  RemedyHandoff,
+		},
+		{
+			name:    "plenty of context left",
+			content: "✻ Thinking… (esc to interrupt)\nContext left until auto-compact: 40%",
+			want:    PaneWorking,
+		},
+		{
+			name: "error loop",
+			content: strings.Repeat("● Bash(go test ./...)\n  FAIL: TestParse (0.01s) error: unexpected token at 12\n", 2) +
+				"  FAIL: TestParse (0.02s) error: unexpected token at 31\n",
+			want:   PaneErrorLoop,
+			remedy: RemedyNudge,
+		},
+		{
+			name:    "busy",
+			content: "✻ Compiling… (12s · esc to interrupt)",
+			want:    PaneWorking,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AgentHealthState{AgentID: "gastown/polecats/max"}
+			a := s.ObservePane(tt.content, tt.hooked, time.Now(), nil)
+			if a.State != tt.want {
+				t.Errorf("State = %q, want %q (evidence %q)", a.State, tt.want, a.Evidence)
+			}
+			if a.Remediation != tt.remedy {
+				t.Errorf("Remediation = %q, want %q", a.Remediation, tt.remedy)
+			}
+		})
+	}
+}
+
+func TestObservePane_AutoAcceptPrompts(t *testing.T) {
+	cfg := DefaultPaneConfig()
+	cfg.AutoAcceptPrompts = true
+	s := &AgentHealthState{AgentID: "gastown/polecats/max"}
+	a := s.ObservePane("Do you want to make this edit to main.go?\n❯ 1. Yes\n  2. No", false, time.Now(), cfg)
+	if a.State != PanePermissionPrompt || a.Remediation != RemedyAcceptPrompt {
+		t.Errorf("got %q/%q, want permission_prompt/accept_prompt", a.State, a.Remediation)
+	}
+}
+
+func TestObservePane_IdleOverTime(t *testing.T) {
+	cfg := DefaultPaneConfig()
+	s := &AgentHealthState{AgentID: "gastown/witness"}
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// First sighting of the prompt isn't idle yet
+	if a := s.ObservePane(idlePane, true, start, cfg); a.State != PaneWorking {
+		t.Fatalf("first observation State = %q, want working", a.State)
+	}
+
+	// Unchanged past the threshold with hooked work: GUPP violation
+	later := start.Add(cfg.IdleThreshold + time.Minute)
+	a := s.ObservePane(idlePane, true, later, cfg)
+	if a.State != PaneIdleWithWork || a.Remediation != RemedyNudge {
+		t.Fatalf("idle with work: %+v", a)
+	}
+
+	// Without hooked work the agent is just idle
+	s2 := &AgentHealthState{}
+	s2.ObservePane(idlePane, false, start, cfg)
+	if a := s2.ObservePane(idlePane, false, later, cfg); a.State != PaneIdle || a.Remediation != RemedyNone {
+		t.Errorf("idle without work: %+v", a)
+	}
+
+	// Changed content resets the idle clock
+	if a := s.ObservePane(idlePane+"\nnew output", true, later.Add(time.Minute), cfg); a.State != PaneWorking {
+		t.Errorf("after change State = %q, want working", a.State)
+	}
+}
+
+func TestObservePane_RemediationEscalates(t *testing.T) {
+	cfg := DefaultPaneConfig()
+	s := &AgentHealthState{AgentID: "gastown/polecats/max"}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.ObservePane(idlePane, true, now, cfg)
+	now = now.Add(cfg.IdleThreshold)
+
+	var got []Remediation
+	for i := 0; i < cfg.NudgesBeforeHandoff+1; i++ {
+		a := s.ObservePane(idlePane, true, now, cfg)
+		got = append(got, a.Remediation)
+		s.RecordRemediation(a.Remediation, now)
+
+		// Within the cooldown nothing further is suggested
+		if a := s.ObservePane(idlePane, true, now.Add(time.Minute), cfg); a.Remediation != RemedyNone {
+			t.Errorf("remediation %q during cooldown", a.Remediation)
+		}
+		now = now.Add(cfg.RemediationCooldown)
+	}
+
+	want := []Remediation{RemedyNudge, RemedyNudge, RemedyHandoff}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("remediations = %v, want %v", got, want)
+			break
+		}
+	}
+	if msg := RemediationMessage(&PaneAnalysis{State: PaneIdleWithWork, Remediation: RemedyHandoff}); !strings.Contains(msg, "gt handoff") {
+		t.Errorf("handoff message = %q", msg)
+	}
+}
+
+func TestErrorSignature(t *testing.T) {
+	a := errorSignature("  Error: bead gt-abc1234 not found after 3 attempts")
+	b := errorSignature("Error: bead gt-def5678 not found after 12 attempts")
+	if a != b {
+		t.Errorf("signatures differ: %q vs %q", a, b)
+	}
+}
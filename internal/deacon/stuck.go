@@ -52,6 +52,30 @@ type AgentHealthState struct {
 
 	// ForceKillCount is total number of force-kills for this agent
 	ForceKillCount int `json:"force_kill_count"`
+
+	// PaneState is the last classification of the agent's tmux pane (see pane.go)
+	PaneState PaneState `json:"pane_state,omitempty"`
+
+	// PaneStateSince is when the pane entered PaneState
+	PaneStateSince time.Time `json:"pane_state_since,omitempty"`
+
+	// PaneEvidence is the pane content that led to PaneState
+	PaneEvidence string `json:"pane_evidence,omitempty"`
+
+	// PaneHash identifies the last captured pane content
+	PaneHash string `json:"pane_hash,omitempty"`
+
+	// PaneUnchangedSince is when the pane content last changed
+	PaneUnchangedSince time.Time `json:"pane_unchanged_since,omitempty"`
+
+	// LastRemediation is the last action taken for PaneState
+	LastRemediation Remediation `json:"last_remediation,omitempty"`
+
+	// LastRemediationTime is when LastRemediation was applied
+	LastRemediationTime time.Time `json:"last_remediation_time,omitempty"`
+
+	// PaneRemediations counts remediations applied since entering PaneState
+	PaneRemediations int `json:"pane_remediations,omitempty"`
 }
 
 // HealthCheckState holds health check state for all monitored agents.
@@ -0,0 +1,370 @@
+// Package dispatch provides the town dispatch queue: slings waiting for
+// polecat capacity. Items are stored in <town>/.beads/dispatch/ and started
+// in order by 'gt dispatch run', which the daemon calls every heartbeat.
+package dispatch
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/lockfile"
+)
+
+// MaxAttempts is how many failed starts stall an item. Stalled items stay
+// queued but are skipped until promoted.
+const MaxAttempts = 3
+
+// StartTimeout is how long an item may stay in flight. An item still
+// starting after that was left behind by a run that died, and is retried.
+const StartTimeout = 10 * time.Minute
+
+// Item is a sling waiting for capacity.
+type Item struct {
+	ID         string     `json:"id"`
+	BeadID     string     `json:"bead_id"`
+	Title      string     `json:"title,omitempty"`
+	Formula    string     `json:"formula,omitempty"` // formula to apply to the bead (--on mode)
+	Rig        string     `json:"rig,omitempty"`     // target rig; empty to route with --auto
+	Priority   int        `json:"priority"`          // bead priority, 0 (highest) to 4
+	Flags      []string   `json:"flags,omitempty"`   // extra gt sling flags
+	EnqueuedBy string     `json:"enqueued_by,omitempty"`
+	EnqueuedAt time.Time  `json:"enqueued_at"`
+	PromotedAt *time.Time `json:"promoted_at,omitempty"`
+	Attempts   int        `json:"attempts,omitempty"` // failed starts
+	LastError  string     `json:"last_error,omitempty"`
+	StartingAt *time.Time `json:"starting_at,omitempty"` // set while gt dispatch run slings it
+}
+
+// Starting reports whether a dispatch run is slinging the item.
+func (i *Item) Starting() bool {
+	return i.StartingAt != nil
+}
+
+// Interrupted reports whether the item has been in flight longer than
+// StartTimeout, i.e. the run starting it died.
+func (i *Item) Interrupted(now time.Time) bool {
+	return i.Starting() && now.Sub(*i.StartingAt) >= StartTimeout
+}
+
+// Stalled reports whether the item has failed too often to retry.
+func (i *Item) Stalled() bool {
+	return i.Attempts >= MaxAttempts
+}
+
+// Target returns the rig, or "auto" for items routed when started.
+func (i *Item) Target() string {
+	if i.Rig == "" {
+		return "auto"
+	}
+	return i.Rig
+}
+
+// SlingArgs returns the gt sling arguments that start the item.
+func (i *Item) SlingArgs() []string {
+	var args []string
+	if i.Formula != "" {
+		args = append(args, i.Formula, "--on", i.BeadID)
+	} else {
+		args = append(args, i.BeadID)
+	}
+	if i.Rig != "" {
+		args = append(args, i.Rig)
+	} else {
+		args = append(args, "--auto")
+	}
+	return append(args, i.Flags...)
+}
+
+// Less orders items for dispatch: promoted items first (most recently
+// promoted first), then by priority, then oldest first.
+func Less(a, b *Item) bool {
+	if (a.PromotedAt != nil) != (b.PromotedAt != nil) {
+		return a.PromotedAt != nil
+	}
+	if a.PromotedAt != nil && !a.PromotedAt.Equal(*b.PromotedAt) {
+		return a.PromotedAt.After(*b.PromotedAt)
+	}
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return a.EnqueuedAt.Before(b.EnqueuedAt)
+}
+
+// Common errors
+var (
+	ErrNotFound      = errors.New("dispatch item not found")
+	ErrAlreadyQueued = errors.New("bead is already queued")
+	ErrStarting      = errors.New("dispatch item is already starting")
+)
+
+// Queue manages the dispatch queue storage.
+type Queue struct {
+	dir string // <town>/.beads/dispatch/
+}
+
+// New returns the dispatch queue for a town.
+func New(townRoot string) *Queue {
+	return &Queue{dir: filepath.Join(townRoot, ".beads", "dispatch")}
+}
+
+// Dir returns the queue directory path.
+func (q *Queue) Dir() string {
+	return q.dir
+}
+
+// generateID creates a unique item ID.
+func generateID() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("dq-%d-%s", time.Now().Unix(), hex.EncodeToString(b))
+}
+
+// lock takes the queue lock, serializing changes that read the queue
+// before writing it. Returns an unlock function.
+func (q *Queue) lock() (func(), error) {
+	if err := os.MkdirAll(q.dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating dispatch directory: %w", err)
+	}
+	unlock, err := lockfile.Lock(filepath.Join(q.dir, ".lock"))
+	if err != nil {
+		return nil, fmt.Errorf("locking dispatch queue: %w", err)
+	}
+	return unlock, nil
+}
+
+// Enqueue adds an item. A bead can be queued once; enqueuing it again
+// returns the existing item and ErrAlreadyQueued. A bead whose item is
+// starting goes back in the queue: the sling starting it found no capacity
+// and queued it again.
+func (q *Queue) Enqueue(item *Item) (*Item, error) {
+	unlock, err := q.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	items, err := q.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range items {
+		if existing.BeadID != item.BeadID {
+			continue
+		}
+		if !existing.Starting() {
+			return existing, ErrAlreadyQueued
+		}
+		existing.StartingAt = nil
+		if err := q.save(existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	if item.ID == "" {
+		item.ID = generateID()
+	}
+	if item.EnqueuedAt.IsZero() {
+		item.EnqueuedAt = time.Now()
+	}
+	if err := q.save(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// List returns all queued items in dispatch order.
+func (q *Queue) List() ([]*Item, error) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // Empty queue
+		}
+		return nil, fmt.Errorf("reading dispatch directory: %w", err)
+	}
+
+	var items []*Item
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		item, err := q.load(filepath.Join(q.dir, entry.Name()))
+		if err != nil {
+			continue // Skip malformed files
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return Less(items[i], items[j]) })
+	return items, nil
+}
+
+// Count returns the number of queued items.
+func (q *Queue) Count() int {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			count++
+		}
+	}
+	return count
+}
+
+// Find returns the item with the given item ID or bead ID.
+func (q *Queue) Find(ref string) (*Item, error) {
+	if item, err := q.load(filepath.Join(q.dir, ref+".json")); err == nil {
+		return item, nil
+	}
+	items, err := q.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.BeadID == ref {
+			return item, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Remove deletes an item (started or cancelled).
+func (q *Queue) Remove(id string) error {
+	err := os.Remove(filepath.Join(q.dir, id+".json"))
+	if os.IsNotExist(err) {
+		return nil // Already removed
+	}
+	return err
+}
+
+// Promote moves an item to the front of the queue and clears its failures,
+// so a stalled item is retried.
+func (q *Queue) Promote(id string) (*Item, error) {
+	return q.update(id, func(item *Item) (bool, error) {
+		now := time.Now()
+		item.PromotedAt = &now
+		item.Attempts = 0
+		item.LastError = ""
+		return true, nil
+	})
+}
+
+// Save writes an item back.
+func (q *Queue) Save(item *Item) error {
+	return q.save(item)
+}
+
+// Start marks an item as in flight before it is slung. The item stays in
+// the queue until Started, so a run that dies mid-sling doesn't lose it.
+// Returns ErrStarting if another run is already starting it.
+func (q *Queue) Start(id string) (*Item, error) {
+	return q.update(id, func(item *Item) (bool, error) {
+		now := time.Now()
+		if item.Starting() && !item.Interrupted(now) {
+			return false, ErrStarting
+		}
+		item.StartingAt = &now
+		return true, nil
+	})
+}
+
+// Started removes an item once its sling succeeded. If the sling queued
+// the bead again instead (see Enqueue), the item is kept and requeued is
+// true.
+func (q *Queue) Started(id string) (requeued bool, err error) {
+	unlock, err := q.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	item, err := q.load(filepath.Join(q.dir, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("loading dispatch item: %w", err)
+	}
+	if !item.Starting() {
+		return true, nil
+	}
+	return false, q.Remove(id)
+}
+
+// Fail records a failed start and puts the item back in the queue.
+func (q *Queue) Fail(id, reason string) (*Item, error) {
+	return q.update(id, func(item *Item) (bool, error) {
+		item.Attempts++
+		item.LastError = reason
+		item.StartingAt = nil
+		return true, nil
+	})
+}
+
+// update loads an item under the queue lock, applies fn and saves the item
+// if fn reports a change.
+func (q *Queue) update(id string, fn func(*Item) (bool, error)) (*Item, error) {
+	unlock, err := q.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	item, err := q.load(filepath.Join(q.dir, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("loading dispatch item: %w", err)
+	}
+	changed, err := fn(item)
+	if err != nil || !changed {
+		return item, err
+	}
+	return item, q.save(item)
+}
+
+// load reads an item from a file path.
+func (q *Queue) load(path string) (*Item, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var item Item
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// save writes an item atomically (temp file, then rename).
+func (q *Queue) save(item *Item) error {
+	if err := os.MkdirAll(q.dir, 0755); err != nil {
+		return fmt.Errorf("creating dispatch directory: %w", err)
+	}
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling dispatch item: %w", err)
+	}
+	path := filepath.Join(q.dir, item.ID+".json")
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath) // cleanup
+		return fmt.Errorf("renaming temp file: %w", err)
+	}
+	return nil
+}
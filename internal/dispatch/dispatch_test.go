@@ -0,0 +1,214 @@
+package dispatch
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEnqueueDedupesByBead(t *testing.T) {
+	q := New(t.TempDir())
+
+	first, err := q.Enqueue(&Item{BeadID: "gt-abc", Rig: "gastown"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if first.ID == "" || first.EnqueuedAt.IsZero() {
+		t.Fatalf("Enqueue did not fill ID and EnqueuedAt: %+v", first)
+	}
+
+	again, err := q.Enqueue(&Item{BeadID: "gt-abc", Rig: "beads"})
+	if !errors.Is(err, ErrAlreadyQueued) {
+		t.Fatalf("second Enqueue error = %v, want ErrAlreadyQueued", err)
+	}
+	if again.ID != first.ID || again.Rig != "gastown" {
+		t.Errorf("second Enqueue returned %+v, want the existing item", again)
+	}
+	if got := q.Count(); got != 1 {
+		t.Errorf("Count = %d, want 1", got)
+	}
+}
+
+func TestEnqueueConcurrent(t *testing.T) {
+	q := New(t.TempDir())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := q.Enqueue(&Item{BeadID: "gt-abc"}); err != nil && !errors.Is(err, ErrAlreadyQueued) {
+				t.Errorf("Enqueue: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := q.Count(); got != 1 {
+		t.Errorf("Count = %d after concurrent enqueues, want 1", got)
+	}
+}
+
+func TestStartLifecycle(t *testing.T) {
+	q := New(t.TempDir())
+	item, err := q.Enqueue(&Item{BeadID: "gt-abc"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// In flight: still queued, and can't be started twice
+	if _, err := q.Start(item.ID); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if found, err := q.Find(item.ID); err != nil || !found.Starting() {
+		t.Fatalf("item after Start = %+v, %v; want queued and starting", found, err)
+	}
+	if _, err := q.Start(item.ID); !errors.Is(err, ErrStarting) {
+		t.Errorf("second Start error = %v, want ErrStarting", err)
+	}
+
+	// A failed sling puts it back with the error
+	failed, err := q.Fail(item.ID, "rig not found")
+	if err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	if failed.Starting() || failed.Attempts != 1 || failed.LastError != "rig not found" {
+		t.Errorf("item after Fail = %+v", failed)
+	}
+
+	// The sling found no capacity and queued the bead again: kept
+	if _, err := q.Start(item.ID); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if again, err := q.Enqueue(&Item{BeadID: "gt-abc"}); err != nil || again.ID != item.ID || again.Starting() {
+		t.Errorf("Enqueue while starting = %+v, %v; want the item back in the queue", again, err)
+	}
+	if requeued, err := q.Started(item.ID); err != nil || !requeued {
+		t.Errorf("Started = %v, %v; want requeued", requeued, err)
+	}
+
+	// A successful sling removes it
+	if _, err := q.Start(item.ID); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if requeued, err := q.Started(item.ID); err != nil || requeued {
+		t.Errorf("Started = %v, %v; want removed", requeued, err)
+	}
+	if got := q.Count(); got != 0 {
+		t.Errorf("Count = %d after Started, want 0", got)
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	now := time.Now()
+	recent, stale := now.Add(-time.Minute), now.Add(-StartTimeout)
+	if (&Item{}).Interrupted(now) || (&Item{StartingAt: &recent}).Interrupted(now) {
+		t.Error("Interrupted before StartTimeout")
+	}
+	if !(&Item{StartingAt: &stale}).Interrupted(now) {
+		t.Error("not Interrupted after StartTimeout")
+	}
+}
+
+func TestListOrderAndPromote(t *testing.T) {
+	q := New(t.TempDir())
+	base := time.Now().Add(-time.Hour)
+	for i, item := range []*Item{
+		{ID: "dq-old-low", BeadID: "gt-1", Priority: 3, EnqueuedAt: base},
+		{ID: "dq-new-high", BeadID: "gt-2", Priority: 1, EnqueuedAt: base.Add(2 * time.Minute)},
+		{ID: "dq-old-high", BeadID: "gt-3", Priority: 1, EnqueuedAt: base.Add(time.Minute)},
+	} {
+		if _, err := q.Enqueue(item); err != nil {
+			t.Fatalf("Enqueue %d: %v", i, err)
+		}
+	}
+
+	if got, want := listIDs(t, q), []string{"dq-old-high", "dq-new-high", "dq-old-low"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List order = %v, want %v", got, want)
+	}
+
+	stalled, err := q.Find("gt-1")
+	if err != nil {
+		t.Fatalf("Find by bead: %v", err)
+	}
+	stalled.Attempts = MaxAttempts
+	stalled.LastError = "boom"
+	if err := q.Save(stalled); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	promoted, err := q.Promote("dq-old-low")
+	if err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	if promoted.Stalled() || promoted.LastError != "" {
+		t.Errorf("Promote kept failures: %+v", promoted)
+	}
+	if got, want := listIDs(t, q), []string{"dq-old-low", "dq-old-high", "dq-new-high"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List order after promote = %v, want %v", got, want)
+	}
+
+	if _, err := q.Promote("dq-missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Promote missing error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFindAndRemove(t *testing.T) {
+	q := New(t.TempDir())
+	item, err := q.Enqueue(&Item{BeadID: "gt-abc"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	for _, ref := range []string{item.ID, "gt-abc"} {
+		found, err := q.Find(ref)
+		if err != nil || found.ID != item.ID {
+			t.Errorf("Find(%q) = %v, %v", ref, found, err)
+		}
+	}
+	if _, err := q.Find("gt-zzz"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Find missing error = %v, want ErrNotFound", err)
+	}
+
+	if err := q.Remove(item.ID); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := q.Remove(item.ID); err != nil {
+		t.Errorf("Remove twice: %v", err)
+	}
+	if got := q.Count(); got != 0 {
+		t.Errorf("Count after Remove = %d, want 0", got)
+	}
+}
+
+func TestSlingArgs(t *testing.T) {
+	tests := []struct {
+		item Item
+		want []string
+	}{
+		{Item{BeadID: "gt-abc", Rig: "gastown"}, []string{"gt-abc", "gastown"}},
+		{Item{BeadID: "gt-abc"}, []string{"gt-abc", "--auto"}},
+		{
+			Item{BeadID: "gt-abc", Formula: "mol-polecat-shiny", Rig: "gastown", Flags: []string{"--naked"}},
+			[]string{"mol-polecat-shiny", "--on", "gt-abc", "gastown", "--naked"},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.item.SlingArgs(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SlingArgs(%+v) = %v, want %v", tt.item, got, tt.want)
+		}
+	}
+}
+
+func listIDs(t *testing.T, q *Queue) []string {
+	t.Helper()
+	items, err := q.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
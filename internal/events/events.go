@@ -57,6 +57,10 @@ const (
 	TypeEscalationSent  = "escalation_sent"
 	TypePatrolComplete  = "patrol_complete"
 
+	// Outbound escalation notifier deliveries
+	TypeEscalationNotified     = "escalation_notified"
+	TypeEscalationNotifyFailed = "escalation_notify_failed"
+
 	// Merge queue events (emitted by refinery)
 	TypeMergeStarted = "merge_started"
 	TypeMerged       = "merged"
@@ -251,6 +255,22 @@ func EscalationPayload(rig, target, to, reason string) map[string]interface{} {
 	}
 }
 
+// NotifyPayload creates a payload for escalation notifier delivery events.
+// errMsg is empty for successful deliveries.
+func NotifyPayload(notifier, kind, severity, topic, errMsg string, elapsed time.Duration) map[string]interface{} {
+	p := map[string]interface{}{
+		"notifier":    notifier,
+		"kind":        kind,
+		"severity":    severity,
+		"topic":       topic,
+		"duration_ms": elapsed.Milliseconds(),
+	}
+	if errMsg != "" {
+		p["error"] = errMsg
+	}
+	return p
+}
+
 // UnhookPayload creates a payload for unhook events.
 func UnhookPayload(beadID string) map[string]interface{} {
 	return map[string]interface{}{
@@ -0,0 +1,142 @@
+// Package forecast projects when a batch of work lands from historical
+// time-to-close samples, and builds burndown series for convoys.
+package forecast
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MinSamples is how many historical closes a forecast needs.
+const MinSamples = 3
+
+// Quantiles used for the ETA range.
+const (
+	EarlyQuantile  = 0.25
+	LikelyQuantile = 0.50
+	LateQuantile   = 0.85
+)
+
+// Forecast is a projected landing time range for the remaining work.
+type Forecast struct {
+	Remaining      int       `json:"remaining"`
+	Workers        int       `json:"workers"`
+	AssumedWorkers bool      `json:"assumed_workers,omitempty"` // no workers now; one was assumed
+	Samples        int       `json:"samples"`
+	Early          time.Time `json:"early"`
+	Likely         time.Time `json:"likely"`
+	Late           time.Time `json:"late"`
+}
+
+// New forecasts when remaining issues land with the given number of
+// workers, each closing issues one at a time at the historical pace in
+// samples. ok is false if there are too few samples to say.
+func New(samples []time.Duration, remaining, workers int, now time.Time) (*Forecast, bool) {
+	if len(samples) < MinSamples {
+		return nil, false
+	}
+	f := &Forecast{Remaining: remaining, Workers: workers, Samples: len(samples)}
+	if f.Workers < 1 {
+		f.Workers = 1
+		f.AssumedWorkers = true
+	}
+
+	// Work proceeds in waves of one issue per worker
+	waves := time.Duration(math.Ceil(float64(remaining) / float64(f.Workers)))
+	f.Early = now.Add(waves * Quantile(samples, EarlyQuantile))
+	f.Likely = now.Add(waves * Quantile(samples, LikelyQuantile))
+	f.Late = now.Add(waves * Quantile(samples, LateQuantile))
+	return f, true
+}
+
+// Slips reports whether the likely landing is after the due date.
+func (f *Forecast) Slips(due time.Time) bool {
+	return f.Likely.After(due)
+}
+
+// Quantile returns the q-quantile (0..1) of durations, interpolating
+// between the nearest samples, to the second. Returns 0 for no samples.
+func Quantile(durations []time.Duration, q float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	frac := pos - float64(lo)
+	return (sorted[lo] + time.Duration(frac*float64(sorted[hi]-sorted[lo]))).Round(time.Second)
+}
+
+// Span is the lifetime of a tracked issue. Closed is zero while it is open.
+type Span struct {
+	Opened time.Time
+	Closed time.Time
+}
+
+// Point is one sample of a burndown series.
+type Point struct {
+	Time      time.Time `json:"time"`
+	Remaining int       `json:"remaining"`
+}
+
+// Burndown samples how many issues were open at evenly spaced points from
+// start to end (inclusive). Issues count from when they were opened, so
+// added scope shows as a rise.
+func Burndown(spans []Span, start, end time.Time, points int) []Point {
+	if points < 2 || !end.After(start) {
+		points = 1
+	}
+	step := time.Duration(0)
+	if points > 1 {
+		step = end.Sub(start) / time.Duration(points-1)
+	}
+
+	series := make([]Point, 0, points)
+	for i := 0; i < points; i++ {
+		t := start.Add(step * time.Duration(i))
+		if i == points-1 {
+			t = end
+		}
+		remaining := 0
+		for _, s := range spans {
+			if s.Opened.After(t) {
+				continue
+			}
+			if s.Closed.IsZero() || s.Closed.After(t) {
+				remaining++
+			}
+		}
+		series = append(series, Point{Time: t, Remaining: remaining})
+	}
+	return series
+}
+
+// sparkBlocks are the sparkline levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders a burndown series as a one-line bar chart scaled to
+// its highest point.
+func Sparkline(series []Point) string {
+	peak := 0
+	for _, p := range series {
+		peak = max(peak, p.Remaining)
+	}
+	var b strings.Builder
+	for _, p := range series {
+		level := 0
+		if peak > 0 {
+			level = p.Remaining * (len(sparkBlocks) - 1) / peak
+		}
+		if p.Remaining == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+)
+
+func hours(hs ...int) []time.Duration {
+	var out []time.Duration
+	for _, h := range hs {
+		out = append(out, time.Duration(h)*time.Hour)
+	}
+	return out
+}
+
+func TestQuantile(t *testing.T) {
+	samples := hours(4, 1, 3, 2, 5)
+	tests := []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0, time.Hour},
+		{0.5, 3 * time.Hour},
+		{1, 5 * time.Hour},
+		{0.25, 2 * time.Hour},
+		{0.125, 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := Quantile(samples, tt.q); got != tt.want {
+			t.Errorf("Quantile(%v) = %v, want %v", tt.q, got, tt.want)
+		}
+	}
+	if got := Quantile(nil, 0.5); got != 0 {
+		t.Errorf("Quantile(nil) = %v, want 0", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	samples := hours(2, 3, 4, 6, 10)
+
+	f, ok := New(samples, 5, 2, now)
+	if !ok {
+		t.Fatal("New returned !ok with enough samples")
+	}
+	// 5 issues over 2 workers is 3 waves; the median close is 4h
+	if want := now.Add(12 * time.Hour); !f.Likely.Equal(want) {
+		t.Errorf("Likely = %v, want %v", f.Likely, want)
+	}
+	if !f.Early.Before(f.Likely) || !f.Late.After(f.Likely) {
+		t.Errorf("range not ordered: %v %v %v", f.Early, f.Likely, f.Late)
+	}
+	if f.AssumedWorkers {
+		t.Error("AssumedWorkers set with workers")
+	}
+	if !f.Slips(now.Add(6*time.Hour)) || f.Slips(now.Add(24*time.Hour)) {
+		t.Error("Slips disagrees with the likely landing")
+	}
+
+	idle, ok := New(samples, 1, 0, now)
+	if !ok || idle.Workers != 1 || !idle.AssumedWorkers {
+		t.Errorf("New with no workers = %+v, %v; want one assumed worker", idle, ok)
+	}
+
+	if _, ok := New(hours(1, 2), 3, 1, now); ok {
+		t.Error("New returned ok with too few samples")
+	}
+}
+
+func TestBurndown(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	spans := []Span{
+		{Opened: start, Closed: start.Add(day + time.Hour)},
+		{Opened: start, Closed: start.Add(2*day + time.Hour)},
+		{Opened: start.Add(day + 2*time.Hour)}, // added scope, still open
+	}
+
+	series := Burndown(spans, start, start.Add(3*day), 4)
+	want := []int{2, 2, 2, 1}
+	if len(series) != len(want) {
+		t.Fatalf("Burndown returned %d points, want %d", len(series), len(want))
+	}
+	for i, p := range series {
+		if p.Remaining != want[i] {
+			t.Errorf("point %d remaining = %d, want %d", i, p.Remaining, want[i])
+		}
+	}
+	if !series[3].Time.Equal(start.Add(3 * day)) {
+		t.Errorf("last point at %v, want the end", series[3].Time)
+	}
+
+	if got := Burndown(spans, start, start, 10); len(got) != 1 {
+		t.Errorf("Burndown over an empty range returned %d points, want 1", len(got))
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	series := []Point{{Remaining: 8}, {Remaining: 4}, {Remaining: 1}, {Remaining: 0}}
+	if got, want := Sparkline(series), "█▄▁ "; got != want {
+		t.Errorf("Sparkline = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,379 @@
+package formula
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// Conditions are used by step `when` clauses and loop `until` clauses.
+//
+// Grammar:
+//
+//	expr    = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | "(" expr ")" | compare
+//	compare = operand [ ("==" | "!=" | "contains" | "matches") operand ]
+//	operand = vars.NAME | steps.ID.output | steps.ID.status
+//	        | "string" | 'string' | true | false | number
+//
+// A lone operand is true unless it is empty, "false" or "0". `contains`
+// is a substring test; `matches` is true when any item of a list value
+// (split on newlines and commas) matches the glob on the right.
+// steps.ID.status is one of done, failed, skipped, or empty while pending.
+//
+// Examples:
+//
+//	vars.changed_files matches "internal/auth/*"
+//	steps.test.output contains "PASS"
+//	vars.quality == "high" && !vars.skip_review
+
+// Condition is a parsed condition expression.
+type Condition struct {
+	src  string
+	root condNode
+}
+
+// condEnv supplies values to condition evaluation.
+type condEnv struct {
+	vars   map[string]string
+	output func(id string) string
+	status func(id string) string
+}
+
+type condNode interface {
+	eval(env *condEnv) string
+}
+
+// ParseCondition parses a condition expression.
+func ParseCondition(src string) (*Condition, error) {
+	toks, err := lexCondition(src)
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", src, err)
+	}
+	p := &condParser{toks: toks}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.toks) {
+		err = fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", src, err)
+	}
+	return &Condition{src: src, root: root}, nil
+}
+
+// String returns the condition's source text.
+func (c *Condition) String() string { return c.src }
+
+// Vars returns the variable names the condition references.
+func (c *Condition) Vars() []string { return c.refs("vars") }
+
+// Steps returns the step IDs the condition references.
+func (c *Condition) Steps() []string { return c.refs("steps") }
+
+// Outputs returns the step IDs whose output the condition reads.
+func (c *Condition) Outputs() []string {
+	var out []string
+	seen := make(map[string]bool)
+	walkCond(c.root, func(r *condRef) {
+		if r.kind == "steps" && r.field == "output" && !seen[r.name] {
+			seen[r.name] = true
+			out = append(out, r.name)
+		}
+	})
+	return out
+}
+
+func (c *Condition) refs(kind string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	walkCond(c.root, func(r *condRef) {
+		if r.kind == kind && !seen[r.name] {
+			seen[r.name] = true
+			out = append(out, r.name)
+		}
+	})
+	return out
+}
+
+func (c *Condition) eval(env *condEnv) bool {
+	return truthy(c.root.eval(env))
+}
+
+func truthy(s string) bool {
+	return s != "" && s != "false" && s != "0"
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+// splitList splits a list value on newlines and commas.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == ',' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// AST nodes
+
+type condLit struct{ val string }
+
+func (n *condLit) eval(*condEnv) string { return n.val }
+
+// condRef is vars.NAME or steps.ID.(output|status).
+type condRef struct {
+	kind  string // "vars" or "steps"
+	name  string
+	field string // for steps: "output" or "status"
+}
+
+func (n *condRef) eval(env *condEnv) string {
+	if n.kind == "vars" {
+		return env.vars[n.name]
+	}
+	if n.field == "status" {
+		return env.status(n.name)
+	}
+	return env.output(n.name)
+}
+
+type condNot struct{ x condNode }
+
+func (n *condNot) eval(env *condEnv) string { return boolString(!truthy(n.x.eval(env))) }
+
+type condBinary struct {
+	op   string
+	l, r condNode
+}
+
+func (n *condBinary) eval(env *condEnv) string {
+	switch n.op {
+	case "&&":
+		return boolString(truthy(n.l.eval(env)) && truthy(n.r.eval(env)))
+	case "||":
+		return boolString(truthy(n.l.eval(env)) || truthy(n.r.eval(env)))
+	}
+	l, r := n.l.eval(env), n.r.eval(env)
+	switch n.op {
+	case "==":
+		return boolString(l == r)
+	case "!=":
+		return boolString(l != r)
+	case "contains":
+		return boolString(strings.Contains(l, r))
+	case "matches":
+		for _, item := range splitList(l) {
+			if ok, _ := path.Match(r, item); ok {
+				return "true"
+			}
+		}
+	}
+	return "false"
+}
+
+func walkCond(n condNode, fn func(*condRef)) {
+	switch n := n.(type) {
+	case *condRef:
+		fn(n)
+	case *condNot:
+		walkCond(n.x, fn)
+	case *condBinary:
+		walkCond(n.l, fn)
+		walkCond(n.r, fn)
+	}
+}
+
+// Lexer
+
+type condTok struct {
+	kind string // "op", "str", "word"
+	text string
+}
+
+func lexCondition(src string) ([]condTok, error) {
+	var toks []condTok
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			toks = append(toks, condTok{"op", string(c)})
+			i++
+		case strings.HasPrefix(src[i:], "&&"), strings.HasPrefix(src[i:], "||"),
+			strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="):
+			toks = append(toks, condTok{"op", src[i : i+2]})
+			i += 2
+		case c == '!':
+			toks = append(toks, condTok{"op", "!"})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, condTok{"str", src[i+1 : i+1+end]})
+			i += end + 2
+		default:
+			j := i
+			for j < len(src) && isWordChar(rune(src[j])) {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			toks = append(toks, condTok{"word", src[i:j]})
+			i = j
+		}
+	}
+	return toks, nil
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+// Parser
+
+type condParser struct {
+	toks []condTok
+	pos  int
+}
+
+func (p *condParser) peek() *condTok {
+	if p.pos < len(p.toks) {
+		return &p.toks[p.pos]
+	}
+	return nil
+}
+
+func (p *condParser) accept(kind, text string) bool {
+	if t := p.peek(); t != nil && t.kind == kind && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("op", "||") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = &condBinary{op: "||", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("op", "&&") {
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = &condBinary{op: "&&", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *condParser) parseUnary() (condNode, error) {
+	if p.accept("op", "!") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &condNot{x: x}, nil
+	}
+	if p.accept("op", "(") {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept("op", ")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		return x, nil
+	}
+
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t == nil {
+		return l, nil
+	}
+	if (t.kind == "op" && (t.text == "==" || t.text == "!=")) ||
+		(t.kind == "word" && (t.text == "contains" || t.text == "matches")) {
+		p.pos++
+		r, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return &condBinary{op: t.text, l: l, r: r}, nil
+	}
+	return l, nil
+}
+
+func (p *condParser) parseOperand() (condNode, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+	switch t.kind {
+	case "str":
+		return &condLit{val: t.text}, nil
+	case "word":
+		return parseWord(t.text)
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func parseWord(w string) (condNode, error) {
+	switch {
+	case w == "true" || w == "false":
+		return &condLit{val: w}, nil
+	case strings.HasPrefix(w, "vars."):
+		name := strings.TrimPrefix(w, "vars.")
+		if name == "" || strings.Contains(name, ".") {
+			return nil, fmt.Errorf("invalid variable reference %q", w)
+		}
+		return &condRef{kind: "vars", name: name}, nil
+	case strings.HasPrefix(w, "steps."):
+		rest := strings.TrimPrefix(w, "steps.")
+		dot := strings.LastIndex(rest, ".")
+		if dot <= 0 {
+			return nil, fmt.Errorf("invalid step reference %q (want steps.ID.output or steps.ID.status)", w)
+		}
+		field := rest[dot+1:]
+		if field != "output" && field != "status" {
+			return nil, fmt.Errorf("invalid step field %q (want output or status)", field)
+		}
+		return &condRef{kind: "steps", name: rest[:dot], field: field}, nil
+	}
+	for _, r := range w {
+		if !unicode.IsDigit(r) && r != '.' && r != '-' {
+			return nil, fmt.Errorf("unknown identifier %q (use vars.NAME, steps.ID.output or a quoted string)", w)
+		}
+	}
+	return &condLit{val: w}, nil
+}
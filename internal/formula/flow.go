@@ -0,0 +1,384 @@
+package formula
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxIterations bounds retry and loop blocks.
+const MaxIterations = 10
+
+// StepState is the state of a workflow step within a run.
+type StepState string
+
+// Step states computed by StepStates.
+const (
+	StepWaiting StepState = "waiting" // needs not yet done
+	StepReady   StepState = "ready"   // can be dispatched (or re-dispatched by retry/loop)
+	StepDone    StepState = "done"
+	StepSkipped StepState = "skipped" // when condition false; satisfies dependents
+	StepFailed  StepState = "failed"  // failed with no attempts left, or loop exhausted
+	StepBlocked StepState = "blocked" // a need failed
+)
+
+// RunState is the progress of a workflow run, against which conditions,
+// retries and loops are evaluated. Any field may be nil.
+type RunState struct {
+	Vars      map[string]string
+	Completed map[string]bool   // steps whose latest attempt finished
+	Failed    map[string]bool   // steps whose latest attempt failed
+	Outputs   map[string]string // output of each step's latest attempt
+	Attempts  map[string]int    // attempts made so far (at least 1 once completed or failed)
+}
+
+func (s *RunState) attempts(id string) int {
+	n := s.Attempts[id]
+	if n == 0 && (s.Completed[id] || s.Failed[id]) {
+		n = 1
+	}
+	return n
+}
+
+// StepStates computes the state of every workflow step. A step whose needs
+// are all done or skipped is ready, unless its when condition is false, in
+// which case it is skipped. A failed step with retry attempts left, and a
+// completed loop step whose until condition doesn't hold yet with
+// iterations left, are ready again.
+func (f *Formula) StepStates(state *RunState) map[string]StepState {
+	if state == nil {
+		state = &RunState{}
+	}
+	states := make(map[string]StepState, len(f.Steps))
+
+	var resolve func(id string) StepState
+	env := &condEnv{
+		vars:   state.Vars,
+		output: func(id string) string { return state.Outputs[id] },
+		status: func(id string) string {
+			switch s := resolve(id); s {
+			case StepDone, StepFailed, StepSkipped:
+				return string(s)
+			}
+			return ""
+		},
+	}
+	resolve = func(id string) StepState {
+		if s, ok := states[id]; ok {
+			return s
+		}
+		step := f.GetStep(id)
+		if step == nil {
+			return StepWaiting
+		}
+		states[id] = StepWaiting // guards cycles, which Validate rejects
+		s := f.stepState(step, state, env, resolve)
+		states[id] = s
+		return s
+	}
+
+	for _, step := range f.Steps {
+		resolve(step.ID)
+	}
+	return states
+}
+
+func (f *Formula) stepState(step *Step, state *RunState, env *condEnv, resolve func(string) StepState) StepState {
+	attempts := state.attempts(step.ID)
+	switch {
+	case state.Failed[step.ID]:
+		if step.Retry != nil && attempts < step.Retry.Max {
+			return StepReady
+		}
+		return StepFailed
+	case state.Completed[step.ID]:
+		if step.Loop != nil && !evalCondition(step.Loop.Until, env) {
+			if attempts < step.Loop.Max {
+				return StepReady
+			}
+			return StepFailed
+		}
+		return StepDone
+	}
+
+	waiting := false
+	for _, need := range step.Needs {
+		switch resolve(need) {
+		case StepDone, StepSkipped:
+		case StepFailed, StepBlocked:
+			return StepBlocked
+		default:
+			waiting = true
+		}
+	}
+	if waiting {
+		return StepWaiting
+	}
+	if step.When != "" && !evalCondition(step.When, env) {
+		return StepSkipped
+	}
+	return StepReady
+}
+
+// evalCondition evaluates a validated condition; unparseable ones are false.
+func evalCondition(src string, env *condEnv) bool {
+	c, err := ParseCondition(src)
+	if err != nil {
+		return false
+	}
+	return c.eval(env)
+}
+
+// ReadyStepsFor returns the steps that can be dispatched given the run's
+// progress, honouring when conditions, retries and loops for workflows.
+// For other formula types it is ReadySteps(state.Completed).
+func (f *Formula) ReadyStepsFor(state *RunState) []string {
+	if state == nil {
+		state = &RunState{}
+	}
+	if f.Type != TypeWorkflow {
+		return f.ReadySteps(state.Completed)
+	}
+	states := f.StepStates(state)
+	var ready []string
+	for _, step := range f.Steps {
+		if states[step.ID] == StepReady {
+			ready = append(ready, step.ID)
+		}
+	}
+	return ready
+}
+
+// OutputRead reports whether any when or until condition reads the output
+// of a step.
+func (f *Formula) OutputRead(id string) bool {
+	for _, step := range f.Steps {
+		conds := []string{step.When}
+		if step.Loop != nil {
+			conds = append(conds, step.Loop.Until)
+		}
+		for _, src := range conds {
+			c, err := ParseCondition(src)
+			if src == "" || err != nil {
+				continue
+			}
+			for _, ref := range c.Outputs() {
+				if ref == id {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// StepAction is a change a workflow run needs to make to advance a step.
+type StepAction string
+
+// Step actions returned by Advance.
+const (
+	ActionDispatch StepAction = "dispatch" // ready and never run: create and sling its bead
+	ActionRerun    StepAction = "rerun"    // finished, but its loop wants another iteration
+	ActionFail     StepAction = "fail"     // finished, but its loop ran out of iterations
+)
+
+// Advance returns what a workflow run must do next, in step order.
+// dispatched reports the steps that already have a bead. Failed attempts
+// are left alone: the step's failure policy (on_failure, which retry maps
+// to) reopens or escalates them, and StepStates accounts for the outcome.
+func (f *Formula) Advance(state *RunState, dispatched map[string]bool) map[string]StepAction {
+	if state == nil {
+		state = &RunState{}
+	}
+	states := f.StepStates(state)
+	actions := make(map[string]StepAction)
+	for _, step := range f.Steps {
+		id := step.ID
+		switch {
+		case states[id] == StepReady && !dispatched[id]:
+			actions[id] = ActionDispatch
+		case states[id] == StepReady && state.Completed[id]:
+			actions[id] = ActionRerun
+		case states[id] == StepFailed && state.Completed[id]:
+			actions[id] = ActionFail
+		}
+	}
+	return actions
+}
+
+// ExpandForEach returns a copy of a workflow formula with each for_each
+// step replaced by one step per item of its list variable (split on
+// newlines and commas). Instances are named ID.1, ID.2, ... and {{item}}
+// in their title and description is replaced by the item. Steps that
+// needed the fanned-out step need every instance instead.
+func (f *Formula) ExpandForEach(vars map[string]string) (*Formula, error) {
+	if f.Type != TypeWorkflow {
+		return f, nil
+	}
+	fanned := make(map[string][]string) // step ID -> instance IDs
+	var steps []Step
+	for _, step := range f.Steps {
+		if step.ForEach == "" {
+			steps = append(steps, step)
+			continue
+		}
+		instances := []string{}
+		for i, item := range splitList(vars[step.ForEach]) {
+			inst := step
+			inst.ID = fmt.Sprintf("%s.%d", step.ID, i+1)
+			inst.ForEach = ""
+			itemVars := map[string]string{"item": item}
+			inst.Title = ExpandVars(step.Title, itemVars)
+			inst.Description = ExpandVars(step.Description, itemVars)
+			steps = append(steps, inst)
+			instances = append(instances, inst.ID)
+		}
+		fanned[step.ID] = instances
+	}
+	if len(fanned) == 0 {
+		return f, nil
+	}
+
+	for i := range steps {
+		var needs []string
+		for _, need := range steps[i].Needs {
+			if instances, ok := fanned[need]; ok {
+				needs = append(needs, instances...)
+			} else {
+				needs = append(needs, need)
+			}
+		}
+		steps[i].Needs = needs
+	}
+
+	expanded := *f
+	expanded.Steps = steps
+	if err := expanded.Validate(); err != nil {
+		return nil, fmt.Errorf("expanding for_each: %w", err)
+	}
+	return &expanded, nil
+}
+
+// validateStepFlow checks a workflow step's when, for_each, retry and loop,
+// and its failure policy.
+func (f *Formula) validateStepFlow(step *Step) error {
+	if step.ForEach != "" && !f.declaresVar(step.ForEach) {
+		return fmt.Errorf("step %q for_each references undeclared var: %s", step.ID, step.ForEach)
+	}
+	if step.Retry != nil && step.Loop != nil {
+		return fmt.Errorf("step %q cannot have both retry and loop", step.ID)
+	}
+	if step.Retry != nil && (step.Retry.Max < 1 || step.Retry.Max > MaxIterations) {
+		return fmt.Errorf("step %q retry max must be between 1 and %d", step.ID, MaxIterations)
+	}
+	if step.Loop != nil {
+		if step.Loop.Max < 1 || step.Loop.Max > MaxIterations {
+			return fmt.Errorf("step %q loop max must be between 1 and %d", step.ID, MaxIterations)
+		}
+		if step.Loop.Until == "" {
+			return fmt.Errorf("step %q loop requires an until condition", step.ID)
+		}
+		// until may look at the step's own output
+		if err := f.validateCondition(step, step.Loop.Until, true); err != nil {
+			return err
+		}
+	}
+	if step.When != "" {
+		if err := f.validateCondition(step, step.When, false); err != nil {
+			return err
+		}
+	}
+	return validateStepPolicy(step)
+}
+
+// validateStepPolicy checks a step's timeout, max_attempts and on_failure.
+func validateStepPolicy(step *Step) error {
+	if step.Timeout != "" {
+		if d, err := time.ParseDuration(step.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("step %q timeout %q is not a positive duration (like 30m or 2h)", step.ID, step.Timeout)
+		}
+	}
+	switch step.OnFailure {
+	case "", "retry", "skip", "escalate", "burn":
+	default:
+		return fmt.Errorf("step %q on_failure must be retry, skip, escalate or burn, got %q", step.ID, step.OnFailure)
+	}
+	if step.MaxAttempts < 0 || step.MaxAttempts > MaxIterations {
+		return fmt.Errorf("step %q max_attempts must be between 1 and %d", step.ID, MaxIterations)
+	}
+	if step.OnFailure == "retry" && step.MaxAttempts < 2 {
+		return fmt.Errorf("step %q on_failure = \"retry\" requires max_attempts of at least 2", step.ID)
+	}
+	if step.MaxAttempts > 1 && step.OnFailure != "retry" {
+		return fmt.Errorf("step %q max_attempts requires on_failure = \"retry\"", step.ID)
+	}
+	if step.MaxAttempts > 0 && step.Retry != nil {
+		return fmt.Errorf("step %q cannot have both retry and max_attempts", step.ID)
+	}
+	// retry is on_failure = "retry" with its max as the attempt limit, and
+	// a loop that runs out of iterations escalates
+	if step.OnFailure != "" && step.Retry != nil {
+		return fmt.Errorf("step %q cannot have both retry and on_failure", step.ID)
+	}
+	if step.OnFailure != "" && step.Loop != nil {
+		return fmt.Errorf("step %q cannot have both loop and on_failure", step.ID)
+	}
+	return nil
+}
+
+// validateCondition checks that a step's condition parses and references
+// only declared vars and steps the step (transitively) needs, whose
+// outputs will exist by the time it is evaluated.
+func (f *Formula) validateCondition(step *Step, src string, allowSelf bool) error {
+	c, err := ParseCondition(src)
+	if err != nil {
+		return fmt.Errorf("step %q: %w", step.ID, err)
+	}
+	for _, name := range c.Vars() {
+		if !f.declaresVar(name) {
+			return fmt.Errorf("step %q condition references undeclared var: %s", step.ID, name)
+		}
+	}
+	upstream := f.upstream(step.ID)
+	for _, id := range c.Steps() {
+		ref := f.GetStep(id)
+		switch {
+		case ref == nil:
+			return fmt.Errorf("step %q condition references unknown step: %s", step.ID, id)
+		case ref.ForEach != "":
+			return fmt.Errorf("step %q condition references fan-out step: %s", step.ID, id)
+		case id == step.ID && allowSelf:
+		case !upstream[id]:
+			return fmt.Errorf("step %q condition references step %s, which it does not depend on", step.ID, id)
+		}
+	}
+	return nil
+}
+
+// declaresVar reports whether name is a declared var or input.
+func (f *Formula) declaresVar(name string) bool {
+	if _, ok := f.Vars[name]; ok {
+		return true
+	}
+	_, ok := f.Inputs[name]
+	return ok
+}
+
+// upstream returns the steps a step transitively needs.
+func (f *Formula) upstream(id string) map[string]bool {
+	seen := make(map[string]bool)
+	var visit func(string)
+	visit = func(id string) {
+		step := f.GetStep(id)
+		if step == nil {
+			return
+		}
+		for _, need := range step.Needs {
+			if !seen[need] {
+				seen[need] = true
+				visit(need)
+			}
+		}
+	}
+	visit(id)
+	return seen
+}
@@ -0,0 +1,263 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+const flowFormula = `
+formula = "flow"
+type = "workflow"
+
+[vars.changed_files]
+[vars.files]
+
+[[steps]]
+id = "diff"
+
+[[steps]]
+id = "security"
+needs = ["diff"]
+when = 'vars.changed_files matches "auth/*"'
+
+[[steps]]
+id = "test"
+needs = ["security"]
+[steps.loop]
+max = 3
+until = 'steps.test.output contains "PASS"'
+
+[[steps]]
+id = "deploy"
+needs = ["test"]
+[steps.retry]
+max = 2
+`
+
+func TestCondition_Eval(t *testing.T) {
+	env := &condEnv{
+		vars:   map[string]string{"files": "auth/login.go, docs/x.md", "quality": "high"},
+		output: func(id string) string { return "ok: PASS" },
+		status: func(id string) string { return "done" },
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`vars.files matches "auth/*"`, true},
+		{`vars.files matches "cmd/*"`, false},
+		{`vars.quality == "high" && !vars.skip`, true},
+		{`vars.quality != 'high' || steps.test.output contains "PASS"`, true},
+		{`(vars.missing || false) && true`, false},
+		{`steps.test.status == "done"`, true},
+	}
+	for _, tt := range tests {
+		c, err := ParseCondition(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCondition(%q): %v", tt.expr, err)
+		}
+		if got := c.eval(env); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`vars.x ==`, `"open`, `foo == "x"`, `steps.x.title`, `(vars.x`} {
+		if _, err := ParseCondition(bad); err == nil {
+			t.Errorf("ParseCondition(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestReadyStepsFor_WhenSkips(t *testing.T) {
+	f, err := Parse([]byte(flowFormula))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// Diff doesn't touch auth/: security is skipped and test is ready
+	state := &RunState{
+		Vars:      map[string]string{"changed_files": "docs/readme.md"},
+		Completed: map[string]bool{"diff": true},
+	}
+	states := f.StepStates(state)
+	if states["security"] != StepSkipped {
+		t.Errorf("security = %q, want skipped", states["security"])
+	}
+	if ready := f.ReadyStepsFor(state); len(ready) != 1 || ready[0] != "test" {
+		t.Errorf("ReadyStepsFor = %v, want [test]", ready)
+	}
+
+	// Touching auth/ runs security first
+	state.Vars["changed_files"] = "auth/token.go"
+	if ready := f.ReadyStepsFor(state); len(ready) != 1 || ready[0] != "security" {
+		t.Errorf("ReadyStepsFor = %v, want [security]", ready)
+	}
+}
+
+func TestReadyStepsFor_LoopAndRetry(t *testing.T) {
+	f, err := Parse([]byte(flowFormula))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	state := &RunState{
+		Completed: map[string]bool{"diff": true, "security": true, "test": true},
+		Outputs:   map[string]string{"test": "FAIL: TestParse"},
+		Attempts:  map[string]int{"test": 1},
+		Failed:    map[string]bool{},
+	}
+
+	// Loop condition not met: test runs again
+	if ready := f.ReadyStepsFor(state); len(ready) != 1 || ready[0] != "test" {
+		t.Fatalf("ReadyStepsFor = %v, want [test] again", ready)
+	}
+
+	// Out of iterations: the loop fails and blocks deploy
+	state.Attempts["test"] = 3
+	states := f.StepStates(state)
+	if states["test"] != StepFailed || states["deploy"] != StepBlocked {
+		t.Errorf("states = %v, want test failed, deploy blocked", states)
+	}
+
+	// Passing ends the loop; a failed deploy is retried once
+	state.Outputs["test"] = "ok PASS"
+	state.Failed["deploy"] = true
+	state.Attempts["deploy"] = 1
+	if ready := f.ReadyStepsFor(state); len(ready) != 1 || ready[0] != "deploy" {
+		t.Errorf("ReadyStepsFor = %v, want [deploy] retry", ready)
+	}
+	state.Attempts["deploy"] = 2
+	if s := f.StepStates(state)["deploy"]; s != StepFailed {
+		t.Errorf("deploy = %q after max attempts, want failed", s)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	f, err := Parse([]byte(flowFormula))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// Nothing dispatched yet: only diff can start
+	actions := f.Advance(&RunState{}, nil)
+	if len(actions) != 1 || actions["diff"] != ActionDispatch {
+		t.Errorf("Advance = %v, want diff dispatched", actions)
+	}
+
+	// Test finished without passing: it reruns
+	state := &RunState{
+		Vars:      map[string]string{"changed_files": "docs/readme.md"},
+		Completed: map[string]bool{"diff": true, "test": true},
+		Outputs:   map[string]string{"test": "FAIL"},
+		Attempts:  map[string]int{"test": 1},
+	}
+	dispatched := map[string]bool{"diff": true, "test": true}
+	actions = f.Advance(state, dispatched)
+	if len(actions) != 1 || actions["test"] != ActionRerun {
+		t.Errorf("Advance = %v, want test rerun", actions)
+	}
+
+	// Out of iterations: test fails
+	state.Attempts["test"] = 3
+	if actions = f.Advance(state, dispatched); actions["test"] != ActionFail {
+		t.Errorf("Advance = %v, want test failed", actions)
+	}
+
+	// Passing dispatches deploy
+	state.Outputs["test"] = "PASS"
+	actions = f.Advance(state, dispatched)
+	if len(actions) != 1 || actions["deploy"] != ActionDispatch {
+		t.Errorf("Advance = %v, want deploy dispatched", actions)
+	}
+}
+
+func TestReadySteps_IgnoresWhen(t *testing.T) {
+	f, err := Parse([]byte(flowFormula))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	// Without vars the when condition can't be evaluated; ReadySteps only
+	// looks at needs.
+	ready := f.ReadySteps(map[string]bool{"diff": true})
+	if len(ready) != 1 || ready[0] != "security" {
+		t.Errorf("ReadySteps = %v, want [security]", ready)
+	}
+}
+
+func TestValidate_StepFlow(t *testing.T) {
+	tests := []struct {
+		name string
+		step string
+		want string
+	}{
+		{"undeclared var", `when = 'vars.nope == "x"'`, "undeclared var"},
+		{"not upstream", `when = 'steps.other.status == "done"'`, "does not depend on"},
+		{"bad syntax", `when = 'vars.files =='`, "condition"},
+		{"unbounded loop", "[steps.loop]\nmax = 50\nuntil = 'true'", "between 1 and"},
+		{"loop needs until", "[steps.loop]\nmax = 2", "until"},
+		{"retry and loop", "[steps.retry]\nmax = 2\n[steps.loop]\nmax = 2\nuntil = 'true'", "both"},
+		{"for_each undeclared", `for_each = "items"`, "for_each"},
+		{"bad timeout", `timeout = "soon"`, "positive duration"},
+		{"bad on_failure", `on_failure = "ignore"`, "on_failure must be"},
+		{"retry needs attempts", `on_failure = "retry"`, "at least 2"},
+		{"attempts need retry", "max_attempts = 3\non_failure = \"skip\"", "requires on_failure"},
+		{"retry block and attempts", "max_attempts = 2\non_failure = \"retry\"\n[steps.retry]\nmax = 2", "both retry and max_attempts"},
+		{"retry and on_failure", "on_failure = \"skip\"\n[steps.retry]\nmax = 2", "both retry and on_failure"},
+		{"loop and on_failure", "on_failure = \"escalate\"\n[steps.loop]\nmax = 2\nuntil = 'true'", "both loop and on_failure"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := `
+formula = "bad"
+[vars.files]
+[[steps]]
+id = "other"
+[[steps]]
+id = "step"
+` + tt.step + "\n"
+			_, err := Parse([]byte(data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandForEach(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "fan"
+[vars.files]
+[[steps]]
+id = "review"
+title = "Review {{item}}"
+for_each = "files"
+[[steps]]
+id = "summary"
+needs = ["review"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	steps, err := f.Plan(map[string]string{"files": "a.go\nb.go"}, nil)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(steps) != 3 || steps[0].ID != "review.1" || steps[1].Title != "Review b.go" {
+		t.Fatalf("Plan = %+v", steps)
+	}
+	if !steps[0].Ready || !steps[1].Ready || steps[2].Ready {
+		t.Errorf("instances should be ready, summary waiting: %+v", steps)
+	}
+	if got := strings.Join(steps[2].Needs, ","); got != "review.1,review.2" {
+		t.Errorf("summary needs = %s", got)
+	}
+
+	// An empty list fans out to nothing; summary is ready
+	steps, err = f.Plan(map[string]string{}, nil)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(steps) != 1 || !steps[0].Ready {
+		t.Errorf("Plan with no items = %+v", steps)
+	}
+}
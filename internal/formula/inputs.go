@@ -0,0 +1,221 @@
+package formula
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InputType is the type of a formula input.
+type InputType string
+
+// Input types. An empty type is a string; "number" is accepted for int.
+const (
+	InputString InputType = "string"
+	InputInt    InputType = "int"
+	InputBool   InputType = "bool"
+	InputEnum   InputType = "enum"
+	InputPath   InputType = "path"
+	InputBeadID InputType = "bead-id"
+	InputRig    InputType = "rig"
+	InputGlob   InputType = "glob"
+)
+
+// Normalize returns the canonical form of an input type.
+func (t InputType) Normalize() InputType {
+	switch t {
+	case "":
+		return InputString
+	case "number", "integer":
+		return InputInt
+	case "boolean":
+		return InputBool
+	}
+	return t
+}
+
+// IsValid returns true if the input type is recognized.
+func (t InputType) IsValid() bool {
+	switch t.Normalize() {
+	case InputString, InputInt, InputBool, InputEnum, InputPath, InputBeadID, InputRig, InputGlob:
+		return true
+	}
+	return false
+}
+
+var (
+	beadIDRe  = regexp.MustCompile(`^[a-z][a-z0-9]*-[a-z0-9][a-z0-9.-]*$`)
+	rigNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+)
+
+// Check validates a value against the input's type, without looking at
+// the town (see CheckInputs).
+func (in Input) Check(value string) error {
+	switch in.Type.Normalize() {
+	case InputInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+	case InputBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean (use true or false)", value)
+		}
+	case InputEnum:
+		for _, allowed := range in.Enum {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(in.Enum, ", "))
+	case InputPath:
+		if value == "" {
+			return fmt.Errorf("path is empty")
+		}
+	case InputBeadID:
+		if !beadIDRe.MatchString(value) {
+			return fmt.Errorf("%q is not a bead ID (like gt-abc12)", value)
+		}
+	case InputRig:
+		if !rigNameRe.MatchString(value) {
+			return fmt.Errorf("%q is not a rig name", value)
+		}
+	case InputGlob:
+		if _, err := path.Match(value, ""); err != nil {
+			return fmt.Errorf("%q is not a valid glob: %v", value, err)
+		}
+	}
+	return nil
+}
+
+// envDefault returns the value of the input's env var, if set.
+func (in Input) envDefault() string {
+	if in.Env == "" {
+		return ""
+	}
+	return os.Getenv(in.Env)
+}
+
+// validateInputs checks input declarations: known types, enum values and
+// defaults that type-check.
+func (f *Formula) validateInputs() error {
+	for _, name := range sortedKeys(f.Inputs) {
+		in := f.Inputs[name]
+		if !in.Type.IsValid() {
+			return fmt.Errorf("input %q has unknown type %q", name, in.Type)
+		}
+		if in.Type.Normalize() == InputEnum && len(in.Enum) == 0 {
+			return fmt.Errorf("enum input %q requires enum values", name)
+		}
+		if in.Default != "" {
+			if err := in.Check(in.Default); err != nil {
+				return fmt.Errorf("input %q default: %w", name, err)
+			}
+		}
+		for _, alt := range in.RequiredUnless {
+			if _, ok := f.Inputs[alt]; !ok {
+				return fmt.Errorf("input %q required_unless references unknown input: %s", name, alt)
+			}
+		}
+	}
+	return nil
+}
+
+// Town looks up town state when checking bead-id and rig inputs.
+type Town interface {
+	BeadExists(id string) bool
+	RigExists(name string) bool
+}
+
+// CheckInputs type-checks the values given for the formula's inputs and,
+// when town is non-nil, that bead-id inputs name existing beads and rig
+// inputs existing rigs. Path inputs must exist. All problems are reported.
+func (f *Formula) CheckInputs(vars map[string]string, town Town) error {
+	var problems []string
+	for _, name := range sortedKeys(f.Inputs) {
+		value, ok := vars[name]
+		if !ok {
+			continue
+		}
+		in := f.Inputs[name]
+		if err := in.Check(value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		switch in.Type.Normalize() {
+		case InputPath:
+			if _, err := os.Stat(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: path %s does not exist", name, value))
+			}
+		case InputBeadID:
+			if town != nil && !town.BeadExists(value) {
+				problems = append(problems, fmt.Sprintf("%s: bead %s not found", name, value))
+			}
+		case InputRig:
+			if town != nil && !town.RigExists(value) {
+				problems = append(problems, fmt.Sprintf("%s: rig %s not found", name, value))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid inputs: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// InputSpec describes one input or var, for machine-readable schemas.
+type InputSpec struct {
+	Name           string    `json:"name"`
+	Kind           string    `json:"kind"` // "input" or "var"
+	Type           InputType `json:"type"`
+	Description    string    `json:"description,omitempty"`
+	Required       bool      `json:"required"`
+	RequiredUnless []string  `json:"required_unless,omitempty"`
+	Default        string    `json:"default,omitempty"`
+	Env            string    `json:"env,omitempty"`
+	Enum           []string  `json:"enum,omitempty"`
+}
+
+// InputSpecs returns the formula's inputs followed by its vars, each
+// sorted by name. Vars are untyped strings.
+func (f *Formula) InputSpecs() []InputSpec {
+	specs := make([]InputSpec, 0, len(f.Inputs)+len(f.Vars))
+	for _, name := range sortedKeys(f.Inputs) {
+		in := f.Inputs[name]
+		specs = append(specs, InputSpec{
+			Name:           name,
+			Kind:           "input",
+			Type:           in.Type.Normalize(),
+			Description:    in.Description,
+			Required:       in.Required,
+			RequiredUnless: in.RequiredUnless,
+			Default:        in.Default,
+			Env:            in.Env,
+			Enum:           in.Enum,
+		})
+	}
+	for _, name := range sortedKeys(f.Vars) {
+		v := f.Vars[name]
+		specs = append(specs, InputSpec{
+			Name:        name,
+			Kind:        "var",
+			Type:        InputString,
+			Description: v.Description,
+			Required:    v.Required,
+			Default:     v.Default,
+		})
+	}
+	return specs
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,123 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+type fakeTown struct {
+	beads map[string]bool
+	rigs  map[string]bool
+}
+
+func (t *fakeTown) BeadExists(id string) bool  { return t.beads[id] }
+func (t *fakeTown) RigExists(name string) bool { return t.rigs[name] }
+
+func TestInput_Check(t *testing.T) {
+	tests := []struct {
+		in    Input
+		value string
+		ok    bool
+	}{
+		{Input{}, "anything", true},
+		{Input{Type: "number"}, "42", true},
+		{Input{Type: InputInt}, "4x", false},
+		{Input{Type: InputBool}, "true", true},
+		{Input{Type: InputBool}, "yes please", false},
+		{Input{Type: InputEnum, Enum: []string{"low", "high"}}, "high", true},
+		{Input{Type: InputEnum, Enum: []string{"low", "high"}}, "medium", false},
+		{Input{Type: InputBeadID}, "gt-abc12", true},
+		{Input{Type: InputBeadID}, "not a bead", false},
+		{Input{Type: InputRig}, "gastown", true},
+		{Input{Type: InputRig}, "gas/town", false},
+		{Input{Type: InputGlob}, "internal/**/*.go", true},
+		{Input{Type: InputGlob}, "[unclosed", false},
+	}
+	for _, tt := range tests {
+		err := tt.in.Check(tt.value)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s Check(%q) = %v, want ok=%v", tt.in.Type, tt.value, err, tt.ok)
+		}
+	}
+}
+
+func TestValidate_Inputs(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unknown type", `type = "color"`, "unknown type"},
+		{"enum without values", `type = "enum"`, "enum values"},
+		{"bad default", "type = \"int\"\ndefault = \"many\"", "default"},
+		{"unknown alternative", `required_unless = ["nope"]`, "unknown input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "formula = \"f\"\n[[steps]]\nid = \"s\"\n[inputs.x]\n" + tt.input + "\n"
+			_, err := Parse([]byte(data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckInputs_Town(t *testing.T) {
+	f := &Formula{Inputs: map[string]Input{
+		"issue": {Type: InputBeadID},
+		"rig":   {Type: InputRig},
+		"n":     {Type: InputInt},
+	}}
+	town := &fakeTown{beads: map[string]bool{"gt-abc": true}, rigs: map[string]bool{"gastown": true}}
+
+	if err := f.CheckInputs(map[string]string{"issue": "gt-abc", "rig": "gastown", "n": "3"}, town); err != nil {
+		t.Errorf("CheckInputs valid: %v", err)
+	}
+	err := f.CheckInputs(map[string]string{"issue": "gt-zzz", "rig": "beads", "n": "x"}, town)
+	if err == nil {
+		t.Fatal("CheckInputs should fail")
+	}
+	for _, want := range []string{"bead gt-zzz not found", "rig beads not found", "not an integer"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+
+	// Without a town only types are checked
+	if err := f.CheckInputs(map[string]string{"issue": "gt-zzz"}, nil); err != nil {
+		t.Errorf("CheckInputs without town: %v", err)
+	}
+}
+
+func TestApplyDefaults_Env(t *testing.T) {
+	t.Setenv("GT_TEST_RIG", "beads")
+	f := &Formula{
+		Inputs: map[string]Input{
+			"rig":  {Type: InputRig, Env: "GT_TEST_RIG", Default: "gastown"},
+			"mode": {Type: InputEnum, Enum: []string{"fast", "slow"}, Env: "GT_TEST_UNSET", Default: "fast"},
+		},
+	}
+	vars := f.ApplyDefaults(nil)
+	if vars["rig"] != "beads" || vars["mode"] != "fast" {
+		t.Errorf("vars = %v, want env then declared default", vars)
+	}
+	if vars := f.ApplyDefaults(map[string]string{"rig": "gastown"}); vars["rig"] != "gastown" {
+		t.Errorf("provided value should beat env, got %v", vars)
+	}
+	vars, err := f.ResolveDeclaredVars(nil)
+	if err != nil || vars["rig"] != "gastown" {
+		t.Errorf("ResolveDeclaredVars = %v, %v; want declared default, env ignored", vars, err)
+	}
+}
+
+func TestInputSpecs(t *testing.T) {
+	f := &Formula{
+		Inputs: map[string]Input{"pr": {Type: "number", RequiredUnless: []string{"files"}}, "files": {Type: InputGlob}},
+		Vars:   map[string]Var{"issue": {Required: true}},
+	}
+	specs := f.InputSpecs()
+	if len(specs) != 3 || specs[0].Name != "files" || specs[1].Type != InputInt || specs[2].Kind != "var" {
+		t.Errorf("InputSpecs = %+v", specs)
+	}
+}
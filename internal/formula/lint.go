@@ -0,0 +1,212 @@
+package formula
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxPromptChars is the prompt length above which lint warns. Long step
+// descriptions eat into the agent's context before it starts work.
+const MaxPromptChars = 6000
+
+// Lint severities.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// LintIssue is one finding from Lint.
+type LintIssue struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Step     string `json:"step,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	if i.Step != "" {
+		return fmt.Sprintf("%s: %s [%s] %s", i.Severity, i.Step, i.Rule, i.Message)
+	}
+	return fmt.Sprintf("%s: [%s] %s", i.Severity, i.Rule, i.Message)
+}
+
+// Lint checks a resolved formula for likely mistakes that Validate allows:
+//
+//	undefined-var     {{name}} used but not declared in [vars] or [inputs]
+//	unused-var        declared but never referenced
+//	unreachable-step  a step whose own when condition is always false
+//	                  (steps that need it still run)
+//	synthesis-deps    convoy legs missing from synthesis depends_on
+//	long-prompt       a step prompt over MaxPromptChars
+//
+// Formulas are checked as written, so undefined-var is a warning: patrol
+// formulas use {{name}} placeholders the agent fills in at runtime.
+// Issues are ordered by severity, then step.
+func (f *Formula) Lint() []LintIssue {
+	var issues []LintIssue
+	add := func(severity, rule, step, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{Severity: severity, Rule: rule, Step: step, Message: fmt.Sprintf(format, args...)})
+	}
+
+	used := make(map[string]bool)
+	for _, text := range f.lintTexts() {
+		for _, m := range varRefRe.FindAllStringSubmatch(text.body, -1) {
+			name := m[1]
+			used[name] = true
+			if (name == "item" && text.forEach) || templateKeywords[name] {
+				continue
+			}
+			if !f.declaresVar(name) {
+				add(LintWarning, "undefined-var", text.step, "{{%s}} is not declared in [vars] or [inputs]", name)
+			}
+		}
+		if len(text.body) > MaxPromptChars {
+			add(LintWarning, "long-prompt", text.step, "%s is %d chars (over %d)", text.what, len(text.body), MaxPromptChars)
+		}
+	}
+	for _, step := range f.Steps {
+		for _, src := range []string{step.When, loopUntil(step)} {
+			if c, err := ParseCondition(src); err == nil && src != "" {
+				for _, name := range c.Vars() {
+					used[name] = true
+				}
+			}
+		}
+		if step.ForEach != "" {
+			used[step.ForEach] = true
+		}
+	}
+	for _, in := range f.Inputs {
+		for _, alt := range in.RequiredUnless {
+			used[alt] = true
+		}
+	}
+	for _, name := range sortedKeys(f.Vars) {
+		if !used[name] {
+			add(LintWarning, "unused-var", "", "var %q is never referenced", name)
+		}
+	}
+	for _, name := range sortedKeys(f.Inputs) {
+		if !used[name] {
+			add(LintWarning, "unused-var", "", "input %q is never referenced", name)
+		}
+	}
+
+	for _, id := range f.unreachableSteps() {
+		add(LintWarning, "unreachable-step", id, "can never run: its when condition is always false")
+	}
+
+	if f.Type == TypeConvoy && f.Synthesis != nil && len(f.Synthesis.DependsOn) > 0 {
+		for _, leg := range f.Legs {
+			if !containsString(f.Synthesis.DependsOn, leg.ID) {
+				add(LintWarning, "synthesis-deps", leg.ID, "synthesis depends_on omits this leg; its findings won't be synthesized")
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Severity != issues[j].Severity {
+			return issues[i].Severity == LintError
+		}
+		return issues[i].Step < issues[j].Step
+	})
+	return issues
+}
+
+// templateKeywords are Go template actions that look like {{name}}
+// references but belong to the agent's renderer.
+var templateKeywords = map[string]bool{"else": true, "end": true}
+
+// lintText is a piece of prompt text checked by Lint.
+type lintText struct {
+	step    string
+	what    string
+	body    string
+	forEach bool // {{item}} is defined
+}
+
+func (f *Formula) lintTexts() []lintText {
+	var texts []lintText
+	base := f.Prompts["base"]
+	for _, step := range f.Steps {
+		texts = append(texts,
+			lintText{step.ID, "title", step.Title, step.ForEach != ""},
+			lintText{step.ID, "description", step.Description, step.ForEach != ""})
+	}
+	for _, leg := range f.Legs {
+		texts = append(texts, lintText{step: leg.ID, what: "title", body: leg.Title})
+		prompt := leg.Description
+		if base != "" {
+			prompt += "\n\n" + base
+		}
+		texts = append(texts, lintText{step: leg.ID, what: "prompt (with base prompt)", body: prompt})
+	}
+	if f.Synthesis != nil {
+		texts = append(texts, lintText{step: SynthesisID, what: "description", body: f.Synthesis.Description})
+	}
+	for _, tmpl := range f.Template {
+		texts = append(texts,
+			lintText{step: tmpl.ID, what: "title", body: tmpl.Title},
+			lintText{step: tmpl.ID, what: "description", body: tmpl.Description})
+	}
+	for _, a := range f.Aspects {
+		texts = append(texts, lintText{step: a.ID, what: "description", body: a.Description})
+	}
+	return texts
+}
+
+func loopUntil(step Step) string {
+	if step.Loop == nil {
+		return ""
+	}
+	return step.Loop.Until
+}
+
+// unreachableSteps returns workflow steps whose own when condition is
+// constant-false. Steps that need them still run: a skipped step satisfies
+// its dependents.
+func (f *Formula) unreachableSteps() []string {
+	if f.Type != TypeWorkflow {
+		return nil
+	}
+	var out []string
+	for _, step := range f.Steps {
+		if step.When == "" {
+			continue
+		}
+		if c, err := ParseCondition(step.When); err == nil && len(c.Vars()) == 0 && len(c.Steps()) == 0 && !c.eval(&condEnv{}) {
+			out = append(out, step.ID)
+		}
+	}
+	return out
+}
+
+// HasErrors reports whether any issue is an error.
+func HasErrors(issues []LintIssue) bool {
+	for _, i := range issues {
+		if i.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+// LintSummary counts errors and warnings, e.g. "1 error, 2 warnings".
+func LintSummary(issues []LintIssue) string {
+	var errs, warns int
+	for _, i := range issues {
+		if i.Severity == LintError {
+			errs++
+		} else {
+			warns++
+		}
+	}
+	return fmt.Sprintf("%d %s, %d %s", errs, plural("error", errs), warns, plural("warning", warns))
+}
+
+func plural(word string, n int) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
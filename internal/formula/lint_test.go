@@ -0,0 +1,96 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+func lintRules(issues []LintIssue) string {
+	var rules []string
+	for _, i := range issues {
+		rules = append(rules, i.Severity+":"+i.Step+":"+i.Rule)
+	}
+	return strings.Join(rules, ",")
+}
+
+func TestLint_Vars(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "vars"
+[vars.feature]
+required = true
+[vars.spare]
+default = "x"
+[inputs.mode]
+type = "enum"
+enum = ["fast", "slow"]
+[[steps]]
+id = "build"
+title = "Build {{feature}} for {{owner}}"
+description = "{{if .ok}}fine{{else}}not{{end}}"
+[[steps]]
+id = "fast"
+needs = ["build"]
+when = "vars.mode == 'fast'"
+[[steps]]
+id = "each"
+for_each = "feature"
+description = "Check {{item}}"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	got := lintRules(f.Lint())
+	want := "warning::unused-var,warning:build:undefined-var"
+	if got != want {
+		t.Errorf("Lint = %s, want %s", got, want)
+	}
+	if issues := f.Lint(); !strings.Contains(issues[0].Message, `"spare"`) || !strings.Contains(issues[1].Message, "{{owner}}") {
+		t.Errorf("messages = %v", issues)
+	}
+}
+
+func TestLint_UnreachableSteps(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "dead"
+[[steps]]
+id = "never"
+when = "false"
+[[steps]]
+id = "after"
+needs = ["never"]
+[[steps]]
+id = "always"
+when = "!false"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	// after still runs once never is skipped
+	if got := lintRules(f.Lint()); got != "warning:never:unreachable-step" {
+		t.Errorf("Lint = %s", got)
+	}
+}
+
+func TestLint_ConvoyAndLongPrompt(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "review"
+type = "convoy"
+[[legs]]
+id = "a"
+[[legs]]
+id = "b"
+description = "` + strings.Repeat("x", MaxPromptChars+1) + `"
+[synthesis]
+depends_on = ["a"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	issues := f.Lint()
+	if got := lintRules(issues); got != "warning:b:long-prompt,warning:b:synthesis-deps" {
+		t.Errorf("Lint = %s", got)
+	}
+	if HasErrors(issues) || LintSummary(issues) != "0 errors, 2 warnings" {
+		t.Errorf("summary = %s", LintSummary(issues))
+	}
+}
@@ -3,6 +3,7 @@ package formula
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -46,8 +47,10 @@ func (f *Formula) inferType() {
 		f.Type = TypeConvoy
 	} else if len(f.Template) > 0 {
 		f.Type = TypeExpansion
-	} else if len(f.Aspects) > 0 {
+	} else if len(f.Aspects) > 0 || len(f.Advice) > 0 {
 		f.Type = TypeAspect
+	} else if f.Composes() {
+		f.Type = TypeWorkflow
 	}
 }
 
@@ -62,6 +65,14 @@ func (f *Formula) Validate() error {
 		return fmt.Errorf("invalid formula type %q (must be convoy, workflow, expansion, or aspect)", f.Type)
 	}
 
+	if err := f.validateInputs(); err != nil {
+		return err
+	}
+
+	if f.Composes() && f.Type != TypeWorkflow {
+		return fmt.Errorf("only workflow formulas can use extends, imports or compose")
+	}
+
 	// Type-specific validation
 	switch f.Type {
 	case TypeConvoy:
@@ -107,6 +118,11 @@ func (f *Formula) validateConvoy() error {
 }
 
 func (f *Formula) validateWorkflow() error {
+	if f.Composes() {
+		// Needs and conditions may refer to imported steps; the
+		// resolved formula is validated in full by Resolve.
+		return f.validateComposition()
+	}
 	if len(f.Steps) == 0 {
 		return fmt.Errorf("workflow formula requires at least one step")
 	}
@@ -120,6 +136,9 @@ func (f *Formula) validateWorkflow() error {
 		if seen[step.ID] {
 			return fmt.Errorf("duplicate step id: %s", step.ID)
 		}
+		if step.Override != "" {
+			return fmt.Errorf("step %q sets override but the formula imports nothing", step.ID)
+		}
 		seen[step.ID] = true
 	}
 
@@ -137,6 +156,12 @@ func (f *Formula) validateWorkflow() error {
 		return err
 	}
 
+	for i := range f.Steps {
+		if err := f.validateStepFlow(&f.Steps[i]); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -170,8 +195,19 @@ func (f *Formula) validateExpansion() error {
 }
 
 func (f *Formula) validateAspect() error {
-	if len(f.Aspects) == 0 {
-		return fmt.Errorf("aspect formula requires at least one aspect")
+	if len(f.Aspects) == 0 && len(f.Advice) == 0 {
+		return fmt.Errorf("aspect formula requires at least one aspect or advice")
+	}
+
+	for i, adv := range f.Advice {
+		if adv.Target == "" {
+			return fmt.Errorf("advice %d missing required target field", i+1)
+		}
+		for _, step := range append(adv.Around.Before, adv.Around.After...) {
+			if step.ID == "" {
+				return fmt.Errorf("advice for %q has a step missing required id field", adv.Target)
+			}
+		}
 	}
 
 	// Check aspect IDs are unique
@@ -189,46 +225,74 @@ func (f *Formula) validateAspect() error {
 	return nil
 }
 
-// checkCycles detects circular dependencies in steps.
+// checkCycles detects circular dependencies in steps. For a resolved
+// formula this covers needs that cross import boundaries; cycles among the
+// imports themselves are caught by checkImportCycles.
 func (f *Formula) checkCycles() error {
 	// Build adjacency list
 	deps := make(map[string][]string)
+	var ids []string
 	for _, step := range f.Steps {
+		ids = append(ids, step.ID)
 		deps[step.ID] = step.Needs
 	}
 
-	// DFS for cycle detection
+	cycle, err := findCycle(ids, func(id string) ([]string, error) {
+		return deps[id], nil
+	})
+	if err != nil {
+		return err
+	}
+	if cycle != nil {
+		return fmt.Errorf("cycle detected involving step: %s (%s)", cycle[0], strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findCycle runs a DFS from each root and returns the first cycle found
+// as a path that starts and ends with the same node, or nil.
+func findCycle(roots []string, edges func(id string) ([]string, error)) ([]string, error) {
 	visited := make(map[string]bool)
 	inStack := make(map[string]bool)
+	var stack []string
 
-	var visit func(id string) error
-	visit = func(id string) error {
+	var visit func(id string) ([]string, error)
+	visit = func(id string) ([]string, error) {
 		if inStack[id] {
-			return fmt.Errorf("cycle detected involving step: %s", id)
+			for i, s := range stack {
+				if s == id {
+					return append(append([]string{}, stack[i:]...), id), nil
+				}
+			}
 		}
 		if visited[id] {
-			return nil
+			return nil, nil
 		}
 		visited[id] = true
 		inStack[id] = true
+		stack = append(stack, id)
 
-		for _, dep := range deps[id] {
-			if err := visit(dep); err != nil {
-				return err
+		next, err := edges(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range next {
+			if cycle, err := visit(dep); cycle != nil || err != nil {
+				return cycle, err
 			}
 		}
 
+		stack = stack[:len(stack)-1]
 		inStack[id] = false
-		return nil
+		return nil, nil
 	}
 
-	for _, step := range f.Steps {
-		if err := visit(step.ID); err != nil {
-			return err
+	for _, id := range roots {
+		if cycle, err := visit(id); cycle != nil || err != nil {
+			return cycle, err
 		}
 	}
-
-	return nil
+	return nil, nil
 }
 
 // TopologicalSort returns steps in dependency order (dependencies before dependents).
@@ -324,6 +388,8 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 
 // ReadySteps returns steps that have no unmet dependencies.
 // completed is a set of step IDs that have been completed.
+// Only dependencies are considered; ReadyStepsFor also honours workflow
+// when conditions, retries and loops.
 func (f *Formula) ReadySteps(completed map[string]bool) []string {
 	var ready []string
 
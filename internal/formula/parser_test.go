@@ -353,3 +353,32 @@ title = "Leg 3"
 		t.Errorf("ReadySteps({leg1}) = %v, want 2 legs", ready)
 	}
 }
+
+func TestParse_AspectAdvice(t *testing.T) {
+	data := []byte(`
+formula = "security-audit"
+type = "aspect"
+version = 1
+
+[[advice]]
+target = "implement"
+[advice.around]
+[[advice.around.before]]
+id = "{step.id}-security-prescan"
+title = "Security prescan for {step.id}"
+
+[[pointcuts]]
+glob = "implement"
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(f.Advice) != 1 || f.Advice[0].Target != "implement" {
+		t.Fatalf("Advice = %+v, want one advice targeting implement", f.Advice)
+	}
+	if len(f.Advice[0].Around.Before) != 1 || len(f.Pointcuts) != 1 {
+		t.Errorf("Around = %+v, Pointcuts = %+v", f.Advice[0].Around, f.Pointcuts)
+	}
+}
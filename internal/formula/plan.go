@@ -0,0 +1,150 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SynthesisID is the plan ID of a convoy formula's synthesis step.
+const SynthesisID = "synthesis"
+
+// Target is the bead an expansion formula is applied to.
+type Target struct {
+	ID          string
+	Title       string
+	Description string
+}
+
+// PlannedStep is one unit of work created when a formula is run: a workflow
+// step, an expanded template, a convoy leg or synthesis, or an aspect.
+type PlannedStep struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Needs       []string `json:"needs,omitempty"`
+	Ready       bool     `json:"ready"`               // no unmet needs; dispatched immediately
+	Skipped     bool     `json:"skipped,omitempty"`   // when condition already false
+	Synthesis   bool     `json:"synthesis,omitempty"` // convoy synthesis step
+
+	// Workflow control flow, carried over from the step
+	When  string `json:"when,omitempty"`
+	Retry *Retry `json:"retry,omitempty"`
+	Loop  *Loop  `json:"loop,omitempty"`
+
+	// Failure policy, carried over from the step
+	Timeout     string `json:"timeout,omitempty"`
+	MaxAttempts int    `json:"max_attempts,omitempty"`
+	OnFailure   string `json:"on_failure,omitempty"`
+}
+
+// Plan returns the work a run of the formula creates, in dependency order
+// (see TopologicalSort), with the steps that are ready up front marked (see
+// ReadyStepsFor). Workflow for_each steps are fanned out over their list
+// var, and steps whose when condition is already false are marked skipped.
+// {{var}} references in titles and descriptions are replaced from vars.
+// Expansion formulas require a target, which fills in {target},
+// {target.title} and {target.description}.
+//
+// Aspect formulas that only define advice have nothing to run on their own;
+// they are woven into other formulas via composition.
+func (f *Formula) Plan(vars map[string]string, target *Target) ([]PlannedStep, error) {
+	if f.Type == TypeAspect && len(f.Aspects) == 0 {
+		return nil, fmt.Errorf("aspect formula %q only defines advice; compose it into a workflow (compose.aspects) instead of running it", f.Name)
+	}
+	if f.Type == TypeExpansion && target == nil {
+		return nil, fmt.Errorf("expansion formula %q requires a target bead", f.Name)
+	}
+
+	f, err := f.ExpandForEach(vars)
+	if err != nil {
+		return nil, err
+	}
+	order, err := f.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	state := &RunState{Vars: vars}
+	ready := make(map[string]bool)
+	for _, id := range f.ReadyStepsFor(state) {
+		ready[id] = true
+	}
+	var states map[string]StepState
+	if f.Type == TypeWorkflow {
+		states = f.StepStates(state)
+	}
+
+	expand := func(s string) string {
+		if target != nil {
+			s = expandTarget(s, target)
+		}
+		return ExpandVars(s, vars)
+	}
+
+	steps := make([]PlannedStep, 0, len(order)+1)
+	for _, id := range order {
+		step := PlannedStep{ID: id, Ready: ready[id]}
+		switch f.Type {
+		case TypeWorkflow:
+			s := f.GetStep(id)
+			step.Title, step.Description, step.Needs = s.Title, s.Description, s.Needs
+			step.When, step.Retry, step.Loop = s.When, s.Retry, s.Loop
+			step.Timeout, step.MaxAttempts, step.OnFailure = s.Timeout, s.MaxAttempts, s.OnFailure
+			step.Skipped = states[id] == StepSkipped
+		case TypeExpansion:
+			t := f.GetTemplate(id)
+			step.Title, step.Description = t.Title, t.Description
+			step.ID = expandTarget(id, target)
+			for _, need := range t.Needs {
+				step.Needs = append(step.Needs, expandTarget(need, target))
+			}
+		case TypeConvoy:
+			leg := f.GetLeg(id)
+			step.Title, step.Description = leg.Title, leg.Description
+			if base := f.Prompts["base"]; base != "" {
+				step.Description = fmt.Sprintf("%s\n\n---\nBase Prompt:\n%s", leg.Description, base)
+			}
+		case TypeAspect:
+			a := f.GetAspect(id)
+			step.Title, step.Description = a.Title, a.Description
+		}
+		if step.Title == "" {
+			step.Title = step.ID
+		}
+		step.Title = expand(step.Title)
+		step.Description = expand(step.Description)
+		steps = append(steps, step)
+	}
+
+	if f.Type == TypeConvoy && f.Synthesis != nil {
+		needs := f.Synthesis.DependsOn
+		if len(needs) == 0 {
+			needs = order // synthesis waits for every leg by default
+		}
+		desc := f.Synthesis.Description
+		if desc == "" {
+			desc = "Synthesize findings from all legs into unified output"
+		}
+		title := f.Synthesis.Title
+		if title == "" {
+			title = "Synthesis"
+		}
+		steps = append(steps, PlannedStep{
+			ID:          SynthesisID,
+			Title:       expand(title),
+			Description: expand(desc),
+			Needs:       needs,
+			Synthesis:   true,
+		})
+	}
+
+	return steps, nil
+}
+
+// expandTarget substitutes an expansion target into a template string.
+func expandTarget(s string, t *Target) string {
+	return strings.NewReplacer(
+		"{target.title}", t.Title,
+		"{target.description}", t.Description,
+		"{target}", t.ID,
+	).Replace(s)
+}
@@ -0,0 +1,165 @@
+package formula
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlan_Workflow(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "release"
+type = "workflow"
+[vars.version]
+required = true
+[[steps]]
+id = "bump"
+title = "Bump to {{version}}"
+[[steps]]
+id = "test"
+needs = ["bump"]
+[[steps]]
+id = "tag"
+title = "Tag {{version}}"
+needs = ["test"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	steps, err := f.Plan(map[string]string{"version": "1.2.0"}, nil)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(steps) != 3 || steps[0].ID != "bump" || steps[2].ID != "tag" {
+		t.Fatalf("Plan order = %+v", steps)
+	}
+	if !steps[0].Ready || steps[1].Ready || steps[2].Ready {
+		t.Errorf("only bump should be ready: %+v", steps)
+	}
+	if steps[0].Title != "Bump to 1.2.0" {
+		t.Errorf("Title = %q, want vars expanded", steps[0].Title)
+	}
+	if steps[1].Title != "test" {
+		t.Errorf("Title = %q, want ID fallback", steps[1].Title)
+	}
+}
+
+func TestPlan_Convoy(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "review"
+type = "convoy"
+[prompts]
+base = "Review {{.leg.id}}"
+[[legs]]
+id = "security"
+title = "Security"
+[[legs]]
+id = "perf"
+title = "Performance"
+[synthesis]
+title = "Synthesis"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	steps, err := f.Plan(nil, nil)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(steps) != 3 {
+		t.Fatalf("len(steps) = %d, want 2 legs + synthesis", len(steps))
+	}
+	if !steps[0].Ready || !steps[1].Ready {
+		t.Errorf("legs should be ready: %+v", steps[:2])
+	}
+	if !strings.Contains(steps[0].Description, "Review {{.leg.id}}") {
+		t.Errorf("leg description %q missing base prompt", steps[0].Description)
+	}
+	syn := steps[2]
+	if !syn.Synthesis || syn.Ready || len(syn.Needs) != 2 {
+		t.Errorf("synthesis = %+v, want blocked on both legs", syn)
+	}
+}
+
+func TestPlan_Expansion(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "rule-of-two"
+type = "expansion"
+[[template]]
+id = "{target}.draft"
+title = "Draft: {target.title}"
+[[template]]
+id = "{target}.refine"
+needs = ["{target}.draft"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if _, err := f.Plan(nil, nil); err == nil {
+		t.Error("Plan without target should fail")
+	}
+	steps, err := f.Plan(nil, &Target{ID: "gt-abc", Title: "Parser"})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if steps[0].ID != "gt-abc.draft" || steps[0].Title != "Draft: Parser" || !steps[0].Ready {
+		t.Errorf("first step = %+v", steps[0])
+	}
+	if steps[1].Needs[0] != "gt-abc.draft" || steps[1].Ready {
+		t.Errorf("second step = %+v", steps[1])
+	}
+}
+
+func TestPlan_AdviceOnlyAspect(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "audit"
+[[advice]]
+target = "implement"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if f.Type != TypeAspect {
+		t.Errorf("Type = %q, want aspect", f.Type)
+	}
+	if _, err := f.Plan(nil, nil); err == nil || !strings.Contains(err.Error(), "compose") {
+		t.Errorf("Plan error = %v, want compose hint", err)
+	}
+}
+
+func TestResolveVars(t *testing.T) {
+	f := &Formula{
+		Vars: map[string]Var{
+			"issue":  {Required: true},
+			"branch": {Default: "main"},
+		},
+		Inputs: map[string]Input{
+			"pr":    {RequiredUnless: []string{"files"}},
+			"files": {RequiredUnless: []string{"pr"}},
+		},
+	}
+
+	_, err := f.ResolveVars(nil)
+	if err == nil || !strings.Contains(err.Error(), "issue") || !strings.Contains(err.Error(), "pr") {
+		t.Errorf("ResolveVars(nil) error = %v, want issue and pr missing", err)
+	}
+
+	vars, err := f.ResolveVars(map[string]string{"issue": "gt-1", "files": "*.go", "extra": "x"})
+	if err != nil {
+		t.Fatalf("ResolveVars failed: %v", err)
+	}
+	if vars["branch"] != "main" || vars["extra"] != "x" {
+		t.Errorf("vars = %v, want default and passthrough", vars)
+	}
+}
+
+func TestExpandVars(t *testing.T) {
+	vars := map[string]string{"issue": "gt-1"}
+	got := ExpandVars("fix {{issue}} ({{ issue }}) {{.leg.id}} {{unknown}}", vars)
+	want := "fix gt-1 (gt-1) {{.leg.id}} {{unknown}}"
+	if got != want {
+		t.Errorf("ExpandVars = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Flow describes the step's when, retry and loop settings and its failure
+// policy, one per line.
+func (s PlannedStep) Flow() []string {
+	var lines []string
+	if s.When != "" {
+		lines = append(lines, "when: "+s.When)
+	}
+	if s.Retry != nil {
+		lines = append(lines, fmt.Sprintf("retry: up to %d attempts", s.Retry.Max))
+	}
+	if s.Loop != nil {
+		lines = append(lines, fmt.Sprintf("loop: up to %d iterations, until %s", s.Loop.Max, s.Loop.Until))
+	}
+	if s.Timeout != "" {
+		lines = append(lines, fmt.Sprintf("time limit: %s per attempt", s.Timeout))
+	}
+	switch {
+	case s.OnFailure == "retry":
+		lines = append(lines, fmt.Sprintf("on failure: retry, up to %d attempts, then escalate", s.MaxAttempts))
+	case s.OnFailure != "":
+		lines = append(lines, "on failure: "+s.OnFailure)
+	}
+	return lines
+}
+
+// ExecutionWaves simulates running planned steps: each wave holds the steps
+// that can start once every earlier wave has finished, in plan order.
+// Skipped steps never run and don't hold up the steps that need them.
+func ExecutionWaves(steps []PlannedStep) [][]string {
+	wave := make(map[string]int, len(steps))
+	var waves [][]string
+	for _, step := range steps {
+		if step.Skipped {
+			continue
+		}
+		w := 0
+		for _, need := range step.Needs {
+			if nw, ok := wave[need]; ok && nw+1 > w {
+				w = nw + 1
+			}
+		}
+		wave[step.ID] = w
+		for len(waves) <= w {
+			waves = append(waves, nil)
+		}
+		waves[w] = append(waves[w], step.ID)
+	}
+	return waves
+}
+
+// RenderPlan renders a plan as a stable text document: the formula and the
+// vars it was planned with, the simulated execution order, then every step
+// with its needs, control flow and description. It is the format of
+// `gt formula test` golden files, so it must not depend on map order.
+func (f *Formula) RenderPlan(vars map[string]string, steps []PlannedStep) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "formula: %s\n", f.Name)
+	fmt.Fprintf(&b, "type: %s\n", f.Type)
+	if len(vars) > 0 {
+		b.WriteString("vars:\n")
+		for _, name := range sortedKeys(vars) {
+			fmt.Fprintf(&b, "  %s = %s\n", name, vars[name])
+		}
+	}
+
+	b.WriteString("\nexecution order:\n")
+	for i, ids := range ExecutionWaves(steps) {
+		fmt.Fprintf(&b, "  %d. %s\n", i+1, strings.Join(ids, ", "))
+	}
+	for _, step := range steps {
+		if step.Skipped {
+			fmt.Fprintf(&b, "  -  %s (skipped)\n", step.ID)
+		}
+	}
+
+	for _, step := range steps {
+		fmt.Fprintf(&b, "\n=== %s: %s\n", step.ID, step.Title)
+		if len(step.Needs) > 0 {
+			fmt.Fprintf(&b, "needs: %s\n", strings.Join(step.Needs, ", "))
+		}
+		for _, line := range step.Flow() {
+			b.WriteString(line + "\n")
+		}
+		if step.Skipped {
+			b.WriteString("skipped: when is false\n")
+		}
+		if desc := strings.TrimRight(step.Description, "\n"); desc != "" {
+			b.WriteString("\n" + desc + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExecutionWaves(t *testing.T) {
+	steps := []PlannedStep{
+		{ID: "a"},
+		{ID: "b"},
+		{ID: "c", Needs: []string{"a"}},
+		{ID: "d", Needs: []string{"b"}, Skipped: true},
+		{ID: "e", Needs: []string{"c", "d"}},
+	}
+	got := fmt.Sprint(ExecutionWaves(steps))
+	if got != "[[a b] [c] [e]]" {
+		t.Errorf("ExecutionWaves = %s", got)
+	}
+}
+
+func TestRenderPlan(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "release"
+[vars.version]
+required = true
+[vars.notes]
+default = "no"
+[[steps]]
+id = "tag"
+title = "Tag {{version}}"
+description = "git tag v{{version}}"
+[[steps]]
+id = "notes"
+when = "vars.notes == 'yes'"
+[[steps]]
+id = "publish"
+needs = ["tag"]
+[steps.retry]
+max = 2
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	vars, err := f.ResolveVars(map[string]string{"version": "1.2.0"})
+	if err != nil {
+		t.Fatalf("ResolveVars failed: %v", err)
+	}
+	steps, err := f.Plan(vars, nil)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	got := f.RenderPlan(vars, steps)
+	want := `formula: release
+type: workflow
+vars:
+  notes = no
+  version = 1.2.0
+
+execution order:
+  1. tag
+  2. publish
+  -  notes (skipped)
+
+=== tag: Tag 1.2.0
+
+git tag v1.2.0
+
+=== notes: notes
+when: vars.notes == 'yes'
+skipped: when is false
+
+=== publish: publish
+needs: tag
+retry: up to 2 attempts
+`
+	if got != want {
+		t.Errorf("RenderPlan mismatch:\n%s\nwant:\n%s", got, want)
+	}
+	if again := f.RenderPlan(vars, steps); again != got || !strings.HasSuffix(got, "\n") {
+		t.Error("RenderPlan should be stable")
+	}
+}
@@ -0,0 +1,535 @@
+package formula
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Step override modes for steps that redefine an imported step.
+const (
+	OverrideExtend  = "extend"  // merge into the imported step
+	OverrideReplace = "replace" // replace the imported step
+)
+
+// Loader returns the formula with the given name, unresolved.
+type Loader func(name string) (*Formula, error)
+
+// DirLoader returns a Loader that looks for <name>.formula.toml in each
+// directory in turn.
+func DirLoader(dirs ...string) Loader {
+	return func(name string) (*Formula, error) {
+		for _, dir := range dirs {
+			p := filepath.Join(dir, name+".formula.toml")
+			if _, err := os.Stat(p); err == nil {
+				return ParseFile(p)
+			}
+		}
+		return nil, fmt.Errorf("formula %q not found", name)
+	}
+}
+
+// Composes reports whether the formula uses extends, imports or compose,
+// and so must be resolved before it is run.
+func (f *Formula) Composes() bool {
+	return len(f.Extends) > 0 || len(f.Imports) > 0 ||
+		(f.Compose != nil && (len(f.Compose.Aspects) > 0 || len(f.Compose.Expand) > 0))
+}
+
+// allImports returns extends (whole-formula imports) followed by imports.
+func (f *Formula) allImports() []Import {
+	imports := make([]Import, 0, len(f.Extends)+len(f.Imports))
+	for _, name := range f.Extends {
+		imports = append(imports, Import{Formula: name})
+	}
+	return append(imports, f.Imports...)
+}
+
+// dependencies returns the formulas this one loads while resolving.
+func (f *Formula) dependencies() []string {
+	var names []string
+	for _, imp := range f.allImports() {
+		names = append(names, imp.Formula)
+	}
+	if f.Compose != nil {
+		names = append(names, f.Compose.Aspects...)
+		for _, exp := range f.Compose.Expand {
+			names = append(names, exp.With)
+		}
+	}
+	return names
+}
+
+// validateComposition checks an unresolved composed workflow.
+func (f *Formula) validateComposition() error {
+	for _, imp := range f.allImports() {
+		if imp.Formula == "" {
+			return fmt.Errorf("import missing required formula field")
+		}
+		if imp.Formula == f.Name {
+			return fmt.Errorf("formula %q imports itself", f.Name)
+		}
+	}
+	if f.Compose != nil {
+		for _, exp := range f.Compose.Expand {
+			if exp.Target == "" || exp.With == "" {
+				return fmt.Errorf("compose.expand requires target and with")
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, step := range f.Steps {
+		if step.ID == "" {
+			return fmt.Errorf("step missing required id field")
+		}
+		if seen[step.ID] {
+			return fmt.Errorf("duplicate step id: %s", step.ID)
+		}
+		seen[step.ID] = true
+		switch step.Override {
+		case "", OverrideExtend, OverrideReplace:
+		default:
+			return fmt.Errorf("step %q has invalid override %q (must be extend or replace)", step.ID, step.Override)
+		}
+	}
+	return nil
+}
+
+// checkImportCycles rejects formulas that import themselves, directly or
+// through other formulas.
+func checkImportCycles(root string, load Loader) error {
+	cycle, err := findCycle([]string{root}, func(name string) ([]string, error) {
+		g, err := load(name)
+		if err != nil {
+			return nil, err
+		}
+		return g.dependencies(), nil
+	})
+	if err != nil {
+		return err
+	}
+	if cycle != nil {
+		return fmt.Errorf("import cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// Resolve returns the formula with its composition applied: the steps and
+// vars of extends and imports merged in, local steps extending or
+// replacing imported ones, and compose.expand and compose.aspects woven
+// into the steps. The result is a plain workflow, validated in full.
+// Formulas that don't compose are returned as is.
+func (f *Formula) Resolve(load Loader) (*Formula, error) {
+	if !f.Composes() {
+		return f, nil
+	}
+
+	cache := map[string]*Formula{f.Name: f}
+	get := func(name string) (*Formula, error) {
+		if g, ok := cache[name]; ok {
+			return g, nil
+		}
+		g, err := load(name)
+		if err != nil {
+			return nil, err
+		}
+		cache[name] = g
+		return g, nil
+	}
+	if err := checkImportCycles(f.Name, get); err != nil {
+		return nil, err
+	}
+
+	r := &resolver{get: get, resolved: make(map[string]*Formula)}
+	return r.resolve(f)
+}
+
+type resolver struct {
+	get      Loader
+	resolved map[string]*Formula
+}
+
+func (r *resolver) load(name string) (*Formula, error) {
+	if g, ok := r.resolved[name]; ok {
+		return g, nil
+	}
+	g, err := r.get(name)
+	if err != nil {
+		return nil, err
+	}
+	g, err = r.resolve(g)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", name, err)
+	}
+	r.resolved[name] = g
+	return g, nil
+}
+
+func (r *resolver) resolve(f *Formula) (*Formula, error) {
+	if !f.Composes() {
+		return f, nil
+	}
+
+	out := *f
+	out.Extends, out.Imports, out.Compose = nil, nil, nil
+	out.Steps = nil
+	out.Vars = make(map[string]Var)
+	out.Inputs = make(map[string]Input)
+	for name, v := range f.Vars {
+		out.Vars[name] = v
+	}
+	for name, in := range f.Inputs {
+		out.Inputs[name] = in
+	}
+
+	index := make(map[string]int)     // step ID -> position in out.Steps
+	origin := make(map[string]string) // step ID -> formula it came from
+	for _, imp := range f.allImports() {
+		g, err := r.load(imp.Formula)
+		if err != nil {
+			return nil, fmt.Errorf("import %s: %w", imp.Formula, err)
+		}
+		if g.Type != TypeWorkflow {
+			return nil, fmt.Errorf("import %s: only workflow formulas can be imported (got %s)", imp.Formula, g.Type)
+		}
+		steps, err := importedSteps(g, imp.Steps)
+		if err != nil {
+			return nil, fmt.Errorf("import %s: %w", imp.Formula, err)
+		}
+		for _, step := range steps {
+			if from, ok := origin[step.ID]; ok {
+				return nil, fmt.Errorf("step %q imported from both %s and %s", step.ID, from, imp.Formula)
+			}
+			index[step.ID] = len(out.Steps)
+			origin[step.ID] = imp.Formula
+			out.Steps = append(out.Steps, step)
+		}
+
+		// Local declarations win over imported ones
+		for name, v := range g.Vars {
+			if _, ok := out.Vars[name]; !ok {
+				out.Vars[name] = v
+			}
+		}
+		for name, in := range g.Inputs {
+			if _, ok := out.Inputs[name]; !ok {
+				out.Inputs[name] = in
+			}
+		}
+		for name, val := range imp.Vars {
+			if _, local := f.Vars[name]; local {
+				continue
+			}
+			v, ok := out.Vars[name]
+			if !ok {
+				return nil, fmt.Errorf("import %s overrides undeclared var: %s", imp.Formula, name)
+			}
+			v.Default, v.Required = val, false
+			out.Vars[name] = v
+		}
+	}
+
+	for _, step := range cloneSteps(f.Steps) {
+		i, imported := index[step.ID]
+		switch {
+		case !imported && step.Override != "":
+			return nil, fmt.Errorf("step %q sets override but no import defines it", step.ID)
+		case !imported:
+			index[step.ID] = len(out.Steps)
+			out.Steps = append(out.Steps, step)
+		case step.Override == OverrideReplace:
+			out.Steps[i] = step
+		case step.Override == OverrideExtend:
+			out.Steps[i] = extendStep(out.Steps[i], step)
+		default:
+			return nil, fmt.Errorf("step %q is already defined by import %s (set override = \"extend\" or \"replace\")", step.ID, origin[step.ID])
+		}
+	}
+	for i := range out.Steps {
+		out.Steps[i].Override = ""
+	}
+	if len(out.Inputs) == 0 {
+		out.Inputs = nil
+	}
+
+	if f.Compose != nil {
+		for _, exp := range f.Compose.Expand {
+			g, err := r.load(exp.With)
+			if err != nil {
+				return nil, fmt.Errorf("compose.expand %s: %w", exp.With, err)
+			}
+			if out.Steps, err = expandStep(out.Steps, exp.Target, g); err != nil {
+				return nil, err
+			}
+		}
+		for _, name := range f.Compose.Aspects {
+			g, err := r.load(name)
+			if err != nil {
+				return nil, fmt.Errorf("compose.aspects %s: %w", name, err)
+			}
+			if g.Type != TypeAspect || len(g.Advice) == 0 {
+				return nil, fmt.Errorf("compose.aspects %s: not an aspect formula with advice", name)
+			}
+			out.Steps = weaveAdvice(out.Steps, g)
+		}
+	}
+
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("resolved formula %s: %w", f.Name, err)
+	}
+	return &out, nil
+}
+
+// importedSteps returns copies of the named steps of g, or all of them.
+func importedSteps(g *Formula, names []string) ([]Step, error) {
+	if len(names) == 0 {
+		return cloneSteps(g.Steps), nil
+	}
+	want := make(map[string]bool, len(names))
+	for _, name := range names {
+		if g.GetStep(name) == nil {
+			return nil, fmt.Errorf("no step %q", name)
+		}
+		want[name] = true
+	}
+	var steps []Step
+	for _, step := range cloneSteps(g.Steps) {
+		if !want[step.ID] {
+			continue
+		}
+		for _, need := range step.Needs {
+			if !want[need] {
+				return nil, fmt.Errorf("step %q needs %q, which is not imported", step.ID, need)
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func cloneSteps(steps []Step) []Step {
+	out := make([]Step, len(steps))
+	for i, step := range steps {
+		out[i] = step
+		out[i].Needs = append([]string(nil), step.Needs...)
+	}
+	return out
+}
+
+// extendStep merges a local step into an imported one: set fields
+// override, needs are added and the description is appended.
+func extendStep(base, ext Step) Step {
+	if ext.Title != "" {
+		base.Title = ext.Title
+	}
+	if ext.Description != "" {
+		if base.Description != "" {
+			base.Description += "\n\n"
+		}
+		base.Description += ext.Description
+	}
+	for _, need := range ext.Needs {
+		if !containsString(base.Needs, need) {
+			base.Needs = append(base.Needs, need)
+		}
+	}
+	if ext.When != "" {
+		base.When = ext.When
+	}
+	if ext.ForEach != "" {
+		base.ForEach = ext.ForEach
+	}
+	if ext.Retry != nil {
+		base.Retry = ext.Retry
+	}
+	if ext.Loop != nil {
+		base.Loop = ext.Loop
+	}
+	if ext.Timeout != "" {
+		base.Timeout = ext.Timeout
+	}
+	if ext.MaxAttempts != 0 {
+		base.MaxAttempts = ext.MaxAttempts
+	}
+	if ext.OnFailure != "" {
+		base.OnFailure = ext.OnFailure
+	}
+	return base
+}
+
+// expandStep replaces the target step with the templates of expansion
+// formula g. Templates without needs inherit the target's needs, and steps
+// that needed the target need the templates nothing else depends on.
+func expandStep(steps []Step, targetID string, g *Formula) ([]Step, error) {
+	if g.Type != TypeExpansion {
+		return nil, fmt.Errorf("compose.expand %s: not an expansion formula", g.Name)
+	}
+	pos := -1
+	for i := range steps {
+		if steps[i].ID == targetID {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		return nil, fmt.Errorf("compose.expand: no step %q to expand", targetID)
+	}
+	target := steps[pos]
+	t := &Target{ID: target.ID, Title: target.Title, Description: target.Description}
+
+	var expanded []Step
+	needed := make(map[string]bool)
+	for _, tmpl := range g.Template {
+		step := Step{
+			ID:          expandTarget(tmpl.ID, t),
+			Title:       expandTarget(tmpl.Title, t),
+			Description: expandTarget(tmpl.Description, t),
+		}
+		for _, need := range tmpl.Needs {
+			step.Needs = append(step.Needs, expandTarget(need, t))
+			needed[expandTarget(need, t)] = true
+		}
+		if len(step.Needs) == 0 {
+			step.Needs = append([]string(nil), target.Needs...)
+		}
+		expanded = append(expanded, step)
+	}
+	var sinks []string
+	for _, step := range expanded {
+		if !needed[step.ID] {
+			sinks = append(sinks, step.ID)
+		}
+	}
+
+	out := make([]Step, 0, len(steps)+len(expanded)-1)
+	out = append(out, steps[:pos]...)
+	out = append(out, expanded...)
+	out = append(out, steps[pos+1:]...)
+	for i := range out {
+		out[i].Needs = replaceNeed(out[i].Needs, targetID, sinks)
+	}
+	return out, nil
+}
+
+// weaveAdvice inserts the before and after steps of an aspect formula's
+// advice around each matching step. Advice targets are globs over step
+// IDs; when the aspect declares pointcuts, a step must also match one.
+func weaveAdvice(steps []Step, aspect *Formula) []Step {
+	for _, adv := range aspect.Advice {
+		var targets []string
+		for _, step := range steps {
+			if globMatch(adv.Target, step.ID) && aspect.inPointcut(step.ID) {
+				targets = append(targets, step.ID)
+			}
+		}
+		for _, id := range targets {
+			steps = adviseStep(steps, id, adv)
+		}
+	}
+	return steps
+}
+
+func (f *Formula) inPointcut(id string) bool {
+	if len(f.Pointcuts) == 0 {
+		return true
+	}
+	for _, pc := range f.Pointcuts {
+		if globMatch(pc.Glob, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func globMatch(pattern, s string) bool {
+	ok, err := path.Match(pattern, s)
+	return err == nil && ok
+}
+
+// adviseStep chains advice before and after one step.
+func adviseStep(steps []Step, id string, adv Advice) []Step {
+	pos := -1
+	for i := range steps {
+		if steps[i].ID == id {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		return steps
+	}
+	target := steps[pos]
+	sub := strings.NewReplacer("{step.id}", target.ID, "{step.title}", target.Title)
+	advice := func(a AdviceStep, needs []string) Step {
+		return Step{
+			ID:          sub.Replace(a.ID),
+			Title:       sub.Replace(a.Title),
+			Description: sub.Replace(a.Description),
+			Needs:       needs,
+		}
+	}
+
+	var before []Step
+	needs := target.Needs
+	for _, a := range adv.Around.Before {
+		step := advice(a, needs)
+		before = append(before, step)
+		needs = []string{step.ID}
+	}
+	target.Needs = needs
+
+	var after []Step
+	last := target.ID
+	for _, a := range adv.Around.After {
+		step := advice(a, []string{last})
+		after = append(after, step)
+		last = step.ID
+	}
+
+	out := make([]Step, 0, len(steps)+len(before)+len(after))
+	out = append(out, cloneSteps(steps[:pos])...)
+	out = append(out, before...)
+	out = append(out, target)
+	out = append(out, after...)
+	out = append(out, cloneSteps(steps[pos+1:])...)
+
+	// Steps that needed the target now wait for its after-advice
+	if len(after) > 0 {
+		for i := range out {
+			if out[i].ID != after[0].ID {
+				out[i].Needs = replaceNeed(out[i].Needs, target.ID, []string{last})
+			}
+		}
+	}
+	return out
+}
+
+// replaceNeed swaps one need for a list of others.
+func replaceNeed(needs []string, old string, with []string) []string {
+	if !containsString(needs, old) {
+		return needs
+	}
+	var out []string
+	for _, need := range needs {
+		if need != old {
+			out = append(out, need)
+			continue
+		}
+		for _, w := range with {
+			if !containsString(out, w) {
+				out = append(out, w)
+			}
+		}
+	}
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
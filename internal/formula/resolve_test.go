@@ -0,0 +1,225 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// mapLoader parses formulas from a map of name to TOML.
+func mapLoader(t *testing.T, sources map[string]string) Loader {
+	t.Helper()
+	return func(name string) (*Formula, error) {
+		src, ok := sources[name]
+		if !ok {
+			return nil, fmt.Errorf("formula %q not found", name)
+		}
+		return Parse([]byte(src))
+	}
+}
+
+const baseFormula = `
+formula = "base"
+type = "workflow"
+[vars.feature]
+required = true
+[vars.branch]
+default = "main"
+[[steps]]
+id = "design"
+title = "Design {{feature}}"
+description = "Think first."
+[[steps]]
+id = "implement"
+needs = ["design"]
+[[steps]]
+id = "submit"
+needs = ["implement"]
+`
+
+func TestResolve_ImportsAndOverrides(t *testing.T) {
+	load := mapLoader(t, map[string]string{"base": baseFormula})
+	f, err := Parse([]byte(`
+formula = "child"
+[[imports]]
+formula = "base"
+[imports.vars]
+branch = "develop"
+
+[[steps]]
+id = "design"
+override = "extend"
+description = "Write it down."
+
+[[steps]]
+id = "submit"
+override = "replace"
+title = "Open PR"
+needs = ["review"]
+
+[[steps]]
+id = "review"
+needs = ["implement"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if f.Type != TypeWorkflow {
+		t.Errorf("Type = %q, want workflow inferred from imports", f.Type)
+	}
+
+	r, err := f.Resolve(load)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if r.Composes() || r.Name != "child" {
+		t.Errorf("resolved formula still composes or lost its name: %+v", r)
+	}
+	order, err := r.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "design,implement,review,submit" {
+		t.Errorf("order = %s", got)
+	}
+	if d := r.GetStep("design"); d.Title != "Design {{feature}}" || d.Description != "Think first.\n\nWrite it down." {
+		t.Errorf("extended design = %+v", d)
+	}
+	if s := r.GetStep("submit"); s.Title != "Open PR" || s.Needs[0] != "review" {
+		t.Errorf("replaced submit = %+v", s)
+	}
+	if v := r.Vars["branch"]; v.Default != "develop" {
+		t.Errorf("branch default = %q, want import override", v.Default)
+	}
+	if !r.Vars["feature"].Required {
+		t.Error("imported var feature should stay required")
+	}
+}
+
+func TestResolve_Fragments(t *testing.T) {
+	load := mapLoader(t, map[string]string{"base": baseFormula})
+
+	f, err := Parse([]byte(`
+formula = "frag"
+[[imports]]
+formula = "base"
+steps = ["design"]
+[[steps]]
+id = "build"
+needs = ["design"]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	r, err := f.Resolve(load)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(r.Steps) != 2 || r.Steps[0].ID != "design" {
+		t.Errorf("steps = %+v, want design fragment + build", r.Steps)
+	}
+
+	// A fragment whose needs aren't imported is rejected
+	f, _ = Parse([]byte(`
+formula = "frag"
+[[imports]]
+formula = "base"
+steps = ["implement"]
+`))
+	if _, err := f.Resolve(load); err == nil || !strings.Contains(err.Error(), "not imported") {
+		t.Errorf("Resolve error = %v, want needs-not-imported", err)
+	}
+}
+
+func TestResolve_Conflicts(t *testing.T) {
+	load := mapLoader(t, map[string]string{"base": baseFormula})
+	f, _ := Parse([]byte(`
+formula = "dup"
+extends = ["base"]
+[[steps]]
+id = "implement"
+`))
+	if _, err := f.Resolve(load); err == nil || !strings.Contains(err.Error(), "override") {
+		t.Errorf("Resolve error = %v, want override hint", err)
+	}
+
+	// Extending needs can close a cycle across the import boundary
+	f, _ = Parse([]byte(`
+formula = "loop"
+extends = ["base"]
+[[steps]]
+id = "design"
+override = "extend"
+needs = ["submit"]
+`))
+	if _, err := f.Resolve(load); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Resolve error = %v, want step cycle", err)
+	}
+}
+
+func TestResolve_ImportCycle(t *testing.T) {
+	load := mapLoader(t, map[string]string{
+		"a": "formula = \"a\"\nextends = [\"b\"]\n",
+		"b": "formula = \"b\"\n[[imports]]\nformula = \"c\"\n",
+		"c": "formula = \"c\"\nextends = [\"a\"]\n",
+	})
+	f, err := load("a")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	_, err = f.Resolve(load)
+	if err == nil || !strings.Contains(err.Error(), "import cycle: a -> b -> c -> a") {
+		t.Errorf("Resolve error = %v, want import cycle", err)
+	}
+}
+
+func TestResolve_Compose(t *testing.T) {
+	load := mapLoader(t, map[string]string{
+		"base": baseFormula,
+		"twice": `
+formula = "twice"
+type = "expansion"
+[[template]]
+id = "{target}.draft"
+title = "Draft: {target.title}"
+[[template]]
+id = "{target}.polish"
+needs = ["{target}.draft"]
+`,
+		"audit": `
+formula = "audit"
+type = "aspect"
+[[advice]]
+target = "impl*"
+[[advice.around.before]]
+id = "{step.id}-prescan"
+[[advice.around.after]]
+id = "{step.id}-postscan"
+`,
+	})
+
+	f, _ := Parse([]byte(`
+formula = "composed"
+extends = ["base"]
+[compose]
+aspects = ["audit"]
+[[compose.expand]]
+target = "design"
+with = "twice"
+`))
+	r, err := f.Resolve(load)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	order, err := r.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	want := "design.draft,design.polish,implement-prescan,implement,implement-postscan,submit"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+	if s := r.GetStep("submit"); s.Needs[0] != "implement-postscan" {
+		t.Errorf("submit needs = %v, want after-advice", s.Needs)
+	}
+}
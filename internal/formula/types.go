@@ -46,6 +46,36 @@ type Formula struct {
 
 	// Aspect-specific (similar to convoy but for analysis)
 	Aspects []Aspect `toml:"aspects"`
+
+	// Aspect advice: steps woven around matching steps of other formulas
+	Advice    []Advice   `toml:"advice"`
+	Pointcuts []Pointcut `toml:"pointcuts"`
+
+	// Composition, applied by Resolve
+	Extends []string `toml:"extends,omitempty"` // formulas whose steps and vars are inherited
+	Imports []Import `toml:"imports,omitempty"`
+	Compose *Compose `toml:"compose,omitempty"`
+}
+
+// Import brings the steps and vars of another workflow formula into this
+// one. Steps selects named steps (fragments) instead of all of them; Vars
+// overrides the defaults of imported vars.
+type Import struct {
+	Formula string            `toml:"formula"`
+	Steps   []string          `toml:"steps,omitempty"`
+	Vars    map[string]string `toml:"vars,omitempty"`
+}
+
+// Compose applies other formulas to the resolved steps.
+type Compose struct {
+	Aspects []string        `toml:"aspects,omitempty"` // aspect formulas whose advice is woven in
+	Expand  []ComposeExpand `toml:"expand,omitempty"`
+}
+
+// ComposeExpand replaces a step with the steps of an expansion formula.
+type ComposeExpand struct {
+	Target string `toml:"target"`
+	With   string `toml:"with"`
 }
 
 // Aspect represents a parallel analysis aspect in an aspect formula.
@@ -56,13 +86,41 @@ type Aspect struct {
 	Description string `toml:"description"`
 }
 
+// Advice inserts steps before and after a target step when an aspect
+// formula is composed into a workflow. Step IDs and titles may use
+// {step.id} to refer to the target.
+type Advice struct {
+	Target string       `toml:"target"`
+	Around AdviceAround `toml:"around"`
+}
+
+// AdviceAround holds the steps inserted around an advice target.
+type AdviceAround struct {
+	Before []AdviceStep `toml:"before"`
+	After  []AdviceStep `toml:"after"`
+}
+
+// AdviceStep is a step inserted by advice.
+type AdviceStep struct {
+	ID          string `toml:"id"`
+	Title       string `toml:"title"`
+	Description string `toml:"description"`
+}
+
+// Pointcut selects the steps an aspect formula applies to.
+type Pointcut struct {
+	Glob string `toml:"glob"`
+}
+
 // Input represents an input parameter for a formula.
 type Input struct {
-	Description    string   `toml:"description"`
-	Type           string   `toml:"type"`
-	Required       bool     `toml:"required"`
-	RequiredUnless []string `toml:"required_unless"`
-	Default        string   `toml:"default"`
+	Description    string    `toml:"description"`
+	Type           InputType `toml:"type"`
+	Required       bool      `toml:"required"`
+	RequiredUnless []string  `toml:"required_unless"`
+	Default        string    `toml:"default"`
+	Enum           []string  `toml:"enum,omitempty"` // allowed values for type enum
+	Env            string    `toml:"env,omitempty"`  // env var supplying the default
 }
 
 // Output configures where formula outputs are written.
@@ -93,6 +151,33 @@ type Step struct {
 	Title       string   `toml:"title"`
 	Description string   `toml:"description"`
 	Needs       []string `toml:"needs"`
+
+	// Control flow (see condition.go for the condition syntax)
+	When    string `toml:"when,omitempty"`     // run only if the condition holds; skipped otherwise
+	ForEach string `toml:"for_each,omitempty"` // fan out: one step per item of this list var
+	Retry   *Retry `toml:"retry,omitempty"`
+	Loop    *Loop  `toml:"loop,omitempty"`
+
+	// Failure policy, tracked on the step's bead and enforced by patrols
+	Timeout     string `toml:"timeout,omitempty"`      // time limit per attempt, e.g. "30m"
+	MaxAttempts int    `toml:"max_attempts,omitzero"` // attempts allowed with on_failure = "retry"
+	OnFailure   string `toml:"on_failure,omitempty"`   // retry, skip, escalate (default) or burn
+
+	// Override is "extend" or "replace" when the step redefines a step
+	// brought in by an import (see Resolve).
+	Override string `toml:"override,omitempty"`
+}
+
+// Retry re-runs a step that failed, up to Max attempts in total.
+type Retry struct {
+	Max int `toml:"max"`
+}
+
+// Loop re-runs a step until its Until condition holds, up to Max
+// iterations in total. A loop that runs out of iterations fails.
+type Loop struct {
+	Max   int    `toml:"max"`
+	Until string `toml:"until"`
 }
 
 // Template represents a template step in an expansion formula.
@@ -106,8 +191,8 @@ type Template struct {
 // Var represents a variable definition for formulas.
 type Var struct {
 	Description string `toml:"description"`
-	Required    bool   `toml:"required"`
-	Default     string `toml:"default"`
+	Required    bool   `toml:"required,omitempty"`
+	Default     string `toml:"default,omitempty"`
 }
 
 // IsValid returns true if the formula type is recognized.
@@ -0,0 +1,126 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// varRefRe matches {{name}} variable references. Go template actions such
+// as {{.leg.id}} or {{if ...}} are left alone for the agent's renderer.
+var varRefRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// ExpandVars replaces {{name}} references in text with their values.
+// References to names not in vars are left as is.
+func ExpandVars(text string, vars map[string]string) string {
+	if len(vars) == 0 || !strings.Contains(text, "{{") {
+		return text
+	}
+	return varRefRe.ReplaceAllStringFunc(text, func(ref string) string {
+		name := varRefRe.FindStringSubmatch(ref)[1]
+		if val, ok := vars[name]; ok {
+			return val
+		}
+		return ref
+	})
+}
+
+// ApplyDefaults returns provided merged with defaults for the formula's
+// [vars] and [inputs]. An input's env var, when set, takes precedence over
+// its declared default. Values for names the formula doesn't declare are
+// passed through.
+func (f *Formula) ApplyDefaults(provided map[string]string) map[string]string {
+	return f.applyDefaults(provided, true)
+}
+
+// applyDefaults implements ApplyDefaults; useEnv controls whether input
+// env vars are consulted.
+func (f *Formula) applyDefaults(provided map[string]string, useEnv bool) map[string]string {
+	resolved := make(map[string]string, len(provided))
+	for k, v := range provided {
+		resolved[k] = v
+	}
+	for name, v := range f.Vars {
+		if _, ok := resolved[name]; !ok && v.Default != "" {
+			resolved[name] = v.Default
+		}
+	}
+	for name, in := range f.Inputs {
+		if _, ok := resolved[name]; ok {
+			continue
+		}
+		if val := in.envDefault(); useEnv && val != "" {
+			resolved[name] = val
+		} else if in.Default != "" {
+			resolved[name] = in.Default
+		}
+	}
+	return resolved
+}
+
+// MissingVars returns the required vars and inputs with no value in vars,
+// sorted. Inputs with required_unless are missing only when none of their
+// alternatives is set either.
+func (f *Formula) MissingVars(vars map[string]string) []string {
+	var missing []string
+	for name, v := range f.Vars {
+		if _, ok := vars[name]; !ok && v.Required {
+			missing = append(missing, name)
+		}
+	}
+	for name, in := range f.Inputs {
+		if _, ok := vars[name]; ok {
+			continue
+		}
+		if in.Required {
+			missing = append(missing, name)
+			continue
+		}
+		if len(in.RequiredUnless) == 0 {
+			continue
+		}
+		satisfied := false
+		for _, alt := range in.RequiredUnless {
+			if _, ok := vars[alt]; ok {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// ResolveVars merges provided values with the defaults declared in the
+// formula's [vars] and [inputs] and checks that required ones are set.
+// Inputs with required_unless are satisfied by any of their alternatives.
+// Values for names the formula doesn't declare are passed through.
+func (f *Formula) ResolveVars(provided map[string]string) (map[string]string, error) {
+	return f.resolveVars(f.ApplyDefaults(provided))
+}
+
+// ResolveDeclaredVars is ResolveVars using only the defaults declared in
+// the formula: input env vars are ignored, so the result depends on the
+// formula and provided values alone. gt formula test uses it so golden
+// files don't change with the caller's environment.
+func (f *Formula) ResolveDeclaredVars(provided map[string]string) (map[string]string, error) {
+	return f.resolveVars(f.applyDefaults(provided, false))
+}
+
+// resolveVars checks that the required vars and inputs have values.
+func (f *Formula) resolveVars(resolved map[string]string) (map[string]string, error) {
+	missing := f.MissingVars(resolved)
+	if len(missing) == 0 {
+		return resolved, nil
+	}
+	for i, name := range missing {
+		if alts := f.Inputs[name].RequiredUnless; len(alts) > 0 && !f.Inputs[name].Required {
+			missing[i] = fmt.Sprintf("%s (or one of %s)", name, strings.Join(alts, ", "))
+		}
+	}
+	return nil, fmt.Errorf("missing required vars: %s", strings.Join(missing, "; "))
+}
@@ -0,0 +1,308 @@
+// Package graph renders step dependency graphs (molecules and formulas) as
+// Graphviz DOT, Mermaid, or an ASCII layout for terminals.
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// State is the execution state of a node. Formula graphs have no state.
+type State string
+
+// Node states, coloured in every output format.
+const (
+	StateNone       State = ""
+	StateDone       State = "done"
+	StateInProgress State = "in_progress"
+	StateReady      State = "ready"
+	StateBlocked    State = "blocked"
+	StateFailed     State = "failed"
+)
+
+// Formats accepted by Render.
+const (
+	FormatASCII   = "ascii"
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// Node is a step in the graph.
+type Node struct {
+	ID       string   `json:"id"`
+	Label    string   `json:"label"`
+	State    State    `json:"state,omitempty"`
+	Assignee string   `json:"assignee,omitempty"`
+	Needs    []string `json:"needs,omitempty"` // IDs of nodes this one depends on
+}
+
+// Graph is a named DAG of nodes, in display order.
+type Graph struct {
+	Name  string `json:"name"`
+	Nodes []Node `json:"nodes"`
+}
+
+// stateStyle holds how a state is drawn.
+type stateStyle struct {
+	symbol string
+	name   string
+	fill   string // DOT and Mermaid fill colour
+	term   lipgloss.Style
+}
+
+var stateStyles = map[State]stateStyle{
+	StateNone:       {"○", "", "#ffffff", lipgloss.NewStyle()},
+	StateDone:       {"✓", "done", "#b7e1b5", style.Success},
+	StateInProgress: {"●", "in progress", "#fff2a8", style.Warning},
+	StateReady:      {"○", "ready", "#bcd9f5", style.Info},
+	StateBlocked:    {"◌", "blocked", "#e0e0e0", style.Dim},
+	StateFailed:     {"✗", "failed", "#f5b7b1", style.Error},
+}
+
+// legendOrder is the order states appear in legends.
+var legendOrder = []State{StateDone, StateInProgress, StateReady, StateBlocked, StateFailed}
+
+// Render renders g in the given format.
+func Render(g *Graph, format string) (string, error) {
+	switch format {
+	case FormatASCII, "":
+		return g.ASCII(), nil
+	case FormatDOT:
+		return g.DOT(), nil
+	case FormatMermaid:
+		return g.Mermaid(), nil
+	}
+	return "", fmt.Errorf("unknown graph format %q (want ascii, dot or mermaid)", format)
+}
+
+// edges returns the (need, node) pairs between nodes in the graph. Needs
+// outside the graph are dropped.
+func (g *Graph) edges() [][2]string {
+	known := make(map[string]bool, len(g.Nodes))
+	for _, n := range g.Nodes {
+		known[n.ID] = true
+	}
+	var out [][2]string
+	for _, n := range g.Nodes {
+		for _, need := range n.Needs {
+			if known[need] {
+				out = append(out, [2]string{need, n.ID})
+			}
+		}
+	}
+	return out
+}
+
+// Layers groups nodes into waves: each node goes one layer below the
+// deepest node it needs. Nodes keep their display order within a layer.
+// Nodes on a cycle, which a valid molecule never has, go in a final layer.
+func (g *Graph) Layers() [][]Node {
+	byID := make(map[string]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		byID[n.ID] = i
+	}
+	depth := make(map[string]int, len(g.Nodes))
+	for pass := 0; pass < len(g.Nodes); pass++ {
+		progressed := false
+		for _, n := range g.Nodes {
+			if _, done := depth[n.ID]; done {
+				continue
+			}
+			d, ready := 0, true
+			for _, need := range n.Needs {
+				if _, ok := byID[need]; !ok {
+					continue
+				}
+				nd, ok := depth[need]
+				if !ok {
+					ready = false
+					break
+				}
+				if nd+1 > d {
+					d = nd + 1
+				}
+			}
+			if ready {
+				depth[n.ID] = d
+				progressed = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	var layers [][]Node
+	var stuck []Node
+	for _, n := range g.Nodes {
+		d, ok := depth[n.ID]
+		if !ok {
+			stuck = append(stuck, n)
+			continue
+		}
+		for len(layers) <= d {
+			layers = append(layers, nil)
+		}
+		layers[d] = append(layers[d], n)
+	}
+	if len(stuck) > 0 {
+		layers = append(layers, stuck)
+	}
+	return layers
+}
+
+// hasState reports whether any node has a state.
+func (g *Graph) hasState() bool {
+	for _, n := range g.Nodes {
+		if n.State != StateNone {
+			return true
+		}
+	}
+	return false
+}
+
+// DOT renders the graph in Graphviz DOT, for `dot -Tsvg`.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(g.Name))
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	for _, n := range g.Nodes {
+		label := n.Label
+		if label != n.ID {
+			label += "\n" + n.ID
+		}
+		if n.Assignee != "" {
+			label += "\n@" + n.Assignee
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%s];\n",
+			dotQuote(n.ID), dotQuote(label), dotQuote(stateStyles[n.State].fill))
+	}
+	for _, e := range g.edges() {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(e[0]), dotQuote(e[1]))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, for markdown.
+func (g *Graph) Mermaid() string {
+	// Bead IDs contain dots and dashes, so nodes get positional IDs
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, n := range g.Nodes {
+		label := mermaidEscape(n.Label)
+		if n.Label != n.ID {
+			label += "<br/><small>" + mermaidEscape(n.ID) + "</small>"
+		}
+		if n.Assignee != "" {
+			label += "<br/>@" + mermaidEscape(n.Assignee)
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]", ids[n.ID], label)
+		if n.State != StateNone {
+			fmt.Fprintf(&b, ":::%s", n.State)
+		}
+		b.WriteString("\n")
+	}
+	for _, e := range g.edges() {
+		fmt.Fprintf(&b, "  %s --> %s\n", ids[e[0]], ids[e[1]])
+	}
+	if g.hasState() {
+		for _, s := range legendOrder {
+			fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:#555\n", s, stateStyles[s].fill)
+		}
+	}
+	return b.String()
+}
+
+func mermaidEscape(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "<", "#lt;")
+	return strings.ReplaceAll(s, ">", "#gt;")
+}
+
+// maxASCIILabel is where long labels are cut in the ASCII layout.
+const maxASCIILabel = 48
+
+// ASCII renders the graph as waves of steps for a terminal: every step in
+// a wave can run once the waves above it are done. Each step lists what it
+// needs, so fan-in and fan-out read top to bottom.
+func (g *Graph) ASCII() string {
+	idWidth, labelWidth, assigneeWidth := 0, 0, 0
+	for _, n := range g.Nodes {
+		idWidth = max(idWidth, utf8.RuneCountInString(n.ID))
+		labelWidth = max(labelWidth, utf8.RuneCountInString(truncate(n.Label, maxASCIILabel)))
+		if n.Assignee != "" {
+			assigneeWidth = max(assigneeWidth, utf8.RuneCountInString(n.Assignee)+1)
+		}
+	}
+
+	var b strings.Builder
+	if g.Name != "" {
+		b.WriteString(style.Bold.Render(g.Name) + "\n\n")
+	}
+	layers := g.Layers()
+	for i, layer := range layers {
+		fmt.Fprintf(&b, "%s\n", style.Dim.Render(fmt.Sprintf("wave %d", i+1)))
+		for _, n := range layer {
+			ss := stateStyles[n.State]
+			line := fmt.Sprintf("  %s %s  %s", ss.term.Render(ss.symbol),
+				pad(n.ID, idWidth), pad(truncate(n.Label, maxASCIILabel), labelWidth))
+			if assigneeWidth > 0 {
+				assignee := ""
+				if n.Assignee != "" {
+					assignee = "@" + n.Assignee
+				}
+				line += "  " + pad(assignee, assigneeWidth)
+			}
+			if i > 0 && len(n.Needs) > 0 {
+				line += "  " + style.Dim.Render("← "+strings.Join(n.Needs, ", "))
+			}
+			b.WriteString(strings.TrimRight(line, " ") + "\n")
+		}
+		if i < len(layers)-1 {
+			b.WriteString("      │\n")
+		}
+	}
+
+	if g.hasState() {
+		var legend []string
+		for _, s := range legendOrder {
+			ss := stateStyles[s]
+			legend = append(legend, ss.term.Render(ss.symbol)+" "+ss.name)
+		}
+		b.WriteString("\n" + strings.Join(legend, "  ") + "\n")
+	}
+	return b.String()
+}
+
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+func truncate(s string, limit int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit-1]) + "…"
+}
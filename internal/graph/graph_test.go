@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func sampleGraph() *Graph {
+	return &Graph{
+		Name: "release (gt-r1)",
+		Nodes: []Node{
+			{ID: "gt-r1.1", Label: "Bump version", State: StateDone, Assignee: "gastown/toast"},
+			{ID: "gt-r1.2", Label: "Run \"tests\"", State: StateInProgress, Needs: []string{"gt-r1.1"}},
+			{ID: "gt-r1.3", Label: "Write changelog", State: StateReady, Needs: []string{"gt-r1.1"}},
+			{ID: "gt-r1.4", Label: "Tag", State: StateBlocked, Needs: []string{"gt-r1.2", "gt-r1.3", "gt-elsewhere"}},
+		},
+	}
+}
+
+func TestLayers(t *testing.T) {
+	var got []string
+	for _, layer := range sampleGraph().Layers() {
+		var ids []string
+		for _, n := range layer {
+			ids = append(ids, n.ID)
+		}
+		got = append(got, strings.Join(ids, " "))
+	}
+	want := "gt-r1.1 | gt-r1.2 gt-r1.3 | gt-r1.4"
+	if s := strings.Join(got, " | "); s != want {
+		t.Errorf("Layers = %s, want %s", s, want)
+	}
+}
+
+func TestLayers_Cycle(t *testing.T) {
+	g := &Graph{Nodes: []Node{
+		{ID: "a"},
+		{ID: "b", Needs: []string{"c"}},
+		{ID: "c", Needs: []string{"b"}},
+	}}
+	layers := g.Layers()
+	if len(layers) != 2 || len(layers[1]) != 2 {
+		t.Errorf("Layers = %v, want cycle nodes in a final layer", layers)
+	}
+}
+
+func TestDOT(t *testing.T) {
+	out := sampleGraph().DOT()
+	for _, want := range []string{
+		`digraph "release (gt-r1)" {`,
+		`"gt-r1.1" [label="Bump version\ngt-r1.1\n@gastown/toast", fillcolor="#b7e1b5"];`,
+		`label="Run \"tests\"\ngt-r1.2"`,
+		`"gt-r1.1" -> "gt-r1.2";`,
+		`"gt-r1.3" -> "gt-r1.4";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "gt-elsewhere") {
+		t.Errorf("DOT has an edge to a node outside the graph:\n%s", out)
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	out := sampleGraph().Mermaid()
+	for _, want := range []string{
+		"flowchart TD\n",
+		`n0["Bump version<br/><small>gt-r1.1</small><br/>@gastown/toast"]:::done`,
+		`n1["Run #quot;tests#quot;<br/><small>gt-r1.2</small>"]:::in_progress`,
+		"n2 --> n3",
+		"classDef failed fill:#f5b7b1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestASCII(t *testing.T) {
+	out := sampleGraph().ASCII()
+	for _, want := range []string{
+		"wave 1\n  ✓ gt-r1.1  Bump version     @gastown/toast\n",
+		"wave 3\n  ◌ gt-r1.4  Tag",
+		"← gt-r1.2, gt-r1.3, gt-elsewhere",
+		"✓ done  ● in progress  ○ ready  ◌ blocked  ✗ failed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ASCII missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	if _, err := Render(sampleGraph(), "svg"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
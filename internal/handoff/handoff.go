@@ -0,0 +1,205 @@
+// Package handoff provides structured handoff documents for session cycling.
+// gt handoff writes one before respawning an agent, and gt prime shows it to
+// the successor session as a checklist.
+package handoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Filename is the handoff document file name within the agent's runtime
+// directory.
+const Filename = "handoff.json"
+
+// Document is a structured handoff from one session to the next.
+type Document struct {
+	// Agent is the identity that wrote the handoff (e.g. gastown/crew/max).
+	Agent string `json:"agent"`
+
+	// Goal is what the session was working toward.
+	Goal string `json:"goal"`
+
+	// Done lists what was finished this session.
+	Done []string `json:"done,omitempty"`
+
+	// Next lists the actions the successor should take, in order.
+	Next []string `json:"next"`
+
+	// Questions lists open questions the successor should resolve.
+	Questions []string `json:"questions,omitempty"`
+
+	// Files lists files touched this session.
+	Files []string `json:"files,omitempty"`
+
+	// Commands lists commands the successor should re-run (tests, builds).
+	Commands []string `json:"commands,omitempty"`
+
+	// HookedBead is the bead on the agent's hook.
+	HookedBead string `json:"hooked_bead,omitempty"`
+
+	// Molecule is the molecule attached to the hooked bead.
+	Molecule string `json:"molecule,omitempty"`
+
+	// MoleculeStep is the molecule step in progress.
+	MoleculeStep string `json:"molecule_step,omitempty"`
+
+	// Notes contains free-form context.
+	Notes string `json:"notes,omitempty"`
+
+	// MailID is the handoff mail bead sent alongside the document.
+	MailID string `json:"mail_id,omitempty"`
+
+	// Timestamp is when the handoff was written.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Path returns the handoff document path for an agent's home directory.
+func Path(home string) string {
+	return filepath.Join(home, constants.DirRuntime, Filename)
+}
+
+// Read loads the handoff document from an agent's home directory.
+// Returns nil, nil if no handoff exists.
+func Read(home string) (*Document, error) {
+	data, err := os.ReadFile(Path(home)) //nolint:gosec // G304: path is constructed from trusted home
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading handoff: %w", err)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing handoff: %w", err)
+	}
+	return &doc, nil
+}
+
+// Write saves the handoff document to an agent's home directory.
+func Write(home string, doc *Document) error {
+	if doc.Timestamp.IsZero() {
+		doc.Timestamp = time.Now()
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling handoff: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(Path(home)), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	if err := os.WriteFile(Path(home), data, 0600); err != nil {
+		return fmt.Errorf("writing handoff: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the handoff document.
+func Remove(home string) error {
+	if err := os.Remove(Path(home)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing handoff: %w", err)
+	}
+	return nil
+}
+
+// Missing returns the required parts of the document that are absent:
+// a goal and at least one next action. Blank list items are reported too.
+func (d *Document) Missing() []string {
+	var missing []string
+	if strings.TrimSpace(d.Goal) == "" {
+		missing = append(missing, "goal")
+	}
+	if len(d.Next) == 0 {
+		missing = append(missing, "next actions")
+	}
+	lists := []struct {
+		name  string
+		items []string
+	}{
+		{"done", d.Done},
+		{"next actions", d.Next},
+		{"questions", d.Questions},
+		{"files", d.Files},
+		{"commands", d.Commands},
+	}
+	for _, l := range lists {
+		for _, item := range l.items {
+			if strings.TrimSpace(item) == "" {
+				missing = append(missing, "blank item in "+l.name)
+				break
+			}
+		}
+	}
+	return missing
+}
+
+// Validate returns an error naming what the document is missing.
+func (d *Document) Validate() error {
+	if missing := d.Missing(); len(missing) > 0 {
+		return fmt.Errorf("handoff incomplete: missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Age returns how long ago the handoff was written.
+func (d *Document) Age() time.Duration {
+	return time.Since(d.Timestamp)
+}
+
+// IsStale returns true if the handoff is older than the threshold.
+func (d *Document) IsStale(threshold time.Duration) bool {
+	return d.Age() > threshold
+}
+
+// Checklist renders the document as a markdown checklist for the successor
+// session: next actions, open questions and commands to re-run as unchecked
+// items, with the goal and what was done as context.
+func (d *Document) Checklist() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "**Goal:** %s\n", d.Goal)
+	if d.HookedBead != "" {
+		fmt.Fprintf(&sb, "**Hooked bead:** %s\n", d.HookedBead)
+	}
+	if d.MoleculeStep != "" {
+		if d.Molecule != "" {
+			fmt.Fprintf(&sb, "**Molecule step:** %s (%s)\n", d.MoleculeStep, d.Molecule)
+		} else {
+			fmt.Fprintf(&sb, "**Molecule step:** %s\n", d.MoleculeStep)
+		}
+	}
+
+	section := func(title, prefix string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n### %s\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&sb, "%s%s\n", prefix, item)
+		}
+	}
+	section("Done", "- [x] ", d.Done)
+	section("Next actions", "- [ ] ", d.Next)
+	section("Open questions", "- [ ] ", d.Questions)
+
+	if len(d.Commands) > 0 {
+		fmt.Fprintf(&sb, "\n### Re-run\n")
+		for _, c := range d.Commands {
+			fmt.Fprintf(&sb, "- [ ] `%s`\n", c)
+		}
+	}
+	section("Files touched", "- ", d.Files)
+
+	if d.Notes != "" {
+		fmt.Fprintf(&sb, "\n### Notes\n%s\n", d.Notes)
+	}
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package handoff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     Document
+		missing []string
+	}{
+		{"complete", Document{Goal: "Fix auth", Next: []string{"Add lock"}}, nil},
+		{"no goal", Document{Next: []string{"Add lock"}}, []string{"goal"}},
+		{"no next", Document{Goal: "Fix auth"}, []string{"next actions"}},
+		{"empty", Document{Goal: "  "}, []string{"goal", "next actions"}},
+		{"blank item", Document{Goal: "Fix auth", Next: []string{"Add lock"}, Questions: []string{""}}, []string{"blank item in questions"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.doc.Missing(); !reflect.DeepEqual(got, tt.missing) {
+				t.Errorf("Missing() = %v, want %v", got, tt.missing)
+			}
+			if err := tt.doc.Validate(); (err != nil) != (len(tt.missing) > 0) {
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestReadWriteRemove(t *testing.T) {
+	dir := t.TempDir()
+
+	doc, err := Read(dir)
+	if err != nil || doc != nil {
+		t.Fatalf("Read() with no handoff = %v, %v", doc, err)
+	}
+
+	want := &Document{Agent: "gastown/crew/max", Goal: "Fix auth", Next: []string{"Add lock"}, HookedBead: "gt-123"}
+	if err := Write(dir, want); err != nil {
+		t.Fatal(err)
+	}
+	if want.Timestamp.IsZero() {
+		t.Error("Write() should set the timestamp")
+	}
+	got, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Goal != want.Goal || got.HookedBead != want.HookedBead || !reflect.DeepEqual(got.Next, want.Next) {
+		t.Errorf("Read() = %+v, want %+v", got, want)
+	}
+
+	if err := Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(dir); err != nil {
+		t.Errorf("Remove() of a missing handoff = %v", err)
+	}
+}
+
+func TestChecklist(t *testing.T) {
+	doc := &Document{
+		Goal:         "Fix auth",
+		Done:         []string{"Found the race"},
+		Next:         []string{"Add lock", "Run tests"},
+		Questions:    []string{"Retry on 401?"},
+		Commands:     []string{"go test ./..."},
+		MoleculeStep: "gt-123.2",
+		Timestamp:    time.Now(),
+	}
+	out := doc.Checklist()
+	for _, want := range []string{
+		"**Goal:** Fix auth",
+		"- [x] Found the race",
+		"- [ ] Add lock\n- [ ] Run tests",
+		"- [ ] Retry on 401?",
+		"- [ ] `go test ./...`",
+		"**Molecule step:** gt-123.2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Checklist() missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Files touched") {
+		t.Error("Checklist() has an empty files section")
+	}
+}
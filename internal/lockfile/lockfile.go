@@ -0,0 +1,4 @@
+// Package lockfile provides exclusive advisory locks on files, used to
+// serialize read-modify-write updates to shared state files (mail receipts,
+// the mail search index, the dispatch queue) across gt processes.
+package lockfile
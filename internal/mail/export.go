@@ -0,0 +1,594 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat is an on-disk mail format for export/import.
+type ExportFormat string
+
+const (
+	// FormatMbox is a single mboxrd file (From-line separated, >From escaped).
+	FormatMbox ExportFormat = "mbox"
+
+	// FormatMaildir is a Maildir directory (cur/, new/, tmp/), one file per message.
+	FormatMaildir ExportFormat = "maildir"
+)
+
+// exportDomain is the mail domain used to turn agent addresses and message
+// IDs into RFC 5322 addresses and Message-IDs.
+const exportDomain = "gastown.local"
+
+// Gas Town headers carry metadata that doesn't survive the RFC 5322 mapping
+// losslessly. Import prefers these over the standard headers when present.
+const (
+	headerFrom     = "X-Gastown-From"
+	headerTo       = "X-Gastown-To"
+	headerCC       = "X-Gastown-Cc"
+	headerThread   = "X-Gastown-Thread"
+	headerPriority = "X-Gastown-Priority"
+	headerType     = "X-Gastown-Type"
+	headerReceipt  = "X-Gastown-Receipt"
+)
+
+// ParseExportFormat parses a format name.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(s)) {
+	case FormatMbox:
+		return FormatMbox, nil
+	case FormatMaildir:
+		return FormatMaildir, nil
+	}
+	return "", fmt.Errorf("unknown mail format %q (want mbox or maildir)", s)
+}
+
+// DetectExportFormat guesses the format of an existing path: directories
+// with a cur/ or new/ subdirectory are Maildirs, everything else is mbox.
+func DetectExportFormat(path string) ExportFormat {
+	for _, sub := range []string{"cur", "new"} {
+		if info, err := os.Stat(filepath.Join(path, sub)); err == nil && info.IsDir() {
+			return FormatMaildir
+		}
+	}
+	return FormatMbox
+}
+
+// agentEmail maps an agent address to an RFC 5322 mailbox, keeping the
+// agent address as the display name ("gastown/Toast" <gastown.Toast@gastown.local>).
+func agentEmail(address string) string {
+	local := strings.ReplaceAll(strings.Trim(address, "/"), "/", ".")
+	if local == "" {
+		local = "unknown"
+	}
+	return (&mail.Address{Name: address, Address: local + "@" + exportDomain}).String()
+}
+
+// messageIDHeader maps a message or thread ID to an RFC 5322 Message-ID.
+func messageIDHeader(id string) string {
+	return "<" + id + "@" + exportDomain + ">"
+}
+
+// parseMessageIDHeader reverses messageIDHeader. Foreign Message-IDs are
+// kept whole (without angle brackets).
+func parseMessageIDHeader(v string) string {
+	v = strings.TrimSpace(v)
+	v = strings.TrimSuffix(strings.TrimPrefix(v, "<"), ">")
+	return strings.TrimSuffix(v, "@"+exportDomain)
+}
+
+// parseAddressHeader extracts agent addresses from a From/To/Cc header.
+// Display names that look like agent addresses win; otherwise the email
+// address is used as-is.
+func parseAddressHeader(v string) []string {
+	if strings.TrimSpace(v) == "" {
+		return nil
+	}
+	list, err := mail.ParseAddressList(v)
+	if err != nil {
+		return []string{strings.TrimSpace(v)}
+	}
+	var addrs []string
+	for _, a := range list {
+		switch {
+		case a.Name != "":
+			addrs = append(addrs, a.Name)
+		case strings.HasSuffix(a.Address, "@"+exportDomain):
+			local := strings.TrimSuffix(a.Address, "@"+exportDomain)
+			addrs = append(addrs, strings.ReplaceAll(local, ".", "/"))
+		default:
+			addrs = append(addrs, a.Address)
+		}
+	}
+	return addrs
+}
+
+// beadsPriorityToXPriority maps a message priority to the X-Priority scale (1=highest, 5=lowest).
+func beadsPriorityToXPriority(p Priority) int {
+	switch p {
+	case PriorityUrgent:
+		return 1
+	case PriorityHigh:
+		return 2
+	case PriorityLow:
+		return 5
+	default:
+		return 3
+	}
+}
+
+// FormatRFC822 renders a message as an RFC 5322 email. ThreadID maps to
+// References (the thread root's Message-ID) and ReplyTo to In-Reply-To,
+// so mail clients thread conversations the same way gt mail does.
+func FormatRFC822(msg *Message) []byte {
+	var b bytes.Buffer
+	header := func(k, v string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+
+	ts := msg.Timestamp
+	if ts.IsZero() {
+		ts = timeNow()
+	}
+
+	header("Message-ID", messageIDHeader(msg.ID))
+	header("Date", ts.Format(time.RFC1123Z))
+	header("From", agentEmail(msg.From))
+	header("To", agentEmail(msg.To))
+	if len(msg.CC) > 0 {
+		var cc []string
+		for _, addr := range msg.CC {
+			cc = append(cc, agentEmail(addr))
+		}
+		header("Cc", strings.Join(cc, ", "))
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	if msg.ReplyTo != "" {
+		header("In-Reply-To", messageIDHeader(msg.ReplyTo))
+	}
+	var refs []string
+	if msg.ThreadID != "" {
+		refs = append(refs, messageIDHeader(msg.ThreadID))
+	}
+	if msg.ReplyTo != "" {
+		refs = append(refs, messageIDHeader(msg.ReplyTo))
+	}
+	if len(refs) > 0 {
+		header("References", strings.Join(refs, " "))
+	}
+	header("X-Priority", strconv.Itoa(beadsPriorityToXPriority(msg.Priority)))
+
+	header(headerFrom, msg.From)
+	header(headerTo, msg.To)
+	if len(msg.CC) > 0 {
+		header(headerCC, strings.Join(msg.CC, ", "))
+	}
+	if msg.ThreadID != "" {
+		header(headerThread, msg.ThreadID)
+	}
+	if msg.Priority != "" {
+		header(headerPriority, string(msg.Priority))
+	}
+	if msg.Type != "" {
+		header(headerType, string(msg.Type))
+	}
+	if msg.ReceiptID != "" {
+		header(headerReceipt, msg.ReceiptID)
+	}
+
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	body = strings.ReplaceAll(body, "\n", "\r\n")
+	b.WriteString(body)
+	if !strings.HasSuffix(body, "\r\n") {
+		b.WriteString("\r\n")
+	}
+	return b.Bytes()
+}
+
+// ParseRFC822 parses an email into a message. Gas Town headers are used
+// when present, so exported mail round-trips; foreign mail is mapped from
+// the standard headers (In-Reply-To → ReplyTo, References → ThreadID).
+func ParseRFC822(data []byte) (*Message, error) {
+	em, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing message: %w", err)
+	}
+	h := em.Header
+
+	msg := &Message{
+		ID:        parseMessageIDHeader(h.Get("Message-ID")),
+		ReplyTo:   parseMessageIDHeader(h.Get("In-Reply-To")),
+		ThreadID:  h.Get(headerThread),
+		ReceiptID: h.Get(headerReceipt),
+		Priority:  PriorityNormal,
+		Type:      TypeNotification,
+	}
+
+	dec := new(mime.WordDecoder)
+	msg.Subject = h.Get("Subject")
+	if s, err := dec.DecodeHeader(msg.Subject); err == nil {
+		msg.Subject = s
+	}
+
+	if t, err := h.Date(); err == nil {
+		msg.Timestamp = t
+	}
+
+	msg.From = h.Get(headerFrom)
+	if msg.From == "" {
+		if addrs := parseAddressHeader(h.Get("From")); len(addrs) > 0 {
+			msg.From = addrs[0]
+		}
+	}
+	msg.To = h.Get(headerTo)
+	if msg.To == "" {
+		if addrs := parseAddressHeader(h.Get("To")); len(addrs) > 0 {
+			msg.To = addrs[0]
+		}
+	}
+	if cc := h.Get(headerCC); cc != "" {
+		for _, addr := range strings.Split(cc, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				msg.CC = append(msg.CC, addr)
+			}
+		}
+	} else {
+		msg.CC = parseAddressHeader(h.Get("Cc"))
+	}
+
+	if msg.ThreadID == "" {
+		// The first reference is the thread root
+		if refs := strings.Fields(h.Get("References")); len(refs) > 0 {
+			msg.ThreadID = parseMessageIDHeader(refs[0])
+		} else if msg.ReplyTo != "" {
+			msg.ThreadID = msg.ReplyTo
+		}
+	}
+
+	if p := Priority(h.Get(headerPriority)); p != "" {
+		msg.Priority = p
+	} else {
+		switch strings.TrimSpace(strings.SplitN(h.Get("X-Priority"), " ", 2)[0]) {
+		case "1":
+			msg.Priority = PriorityUrgent
+		case "2":
+			msg.Priority = PriorityHigh
+		case "4", "5":
+			msg.Priority = PriorityLow
+		}
+	}
+	if t := MessageType(h.Get(headerType)); t != "" {
+		msg.Type = t
+	}
+
+	// mbox Status header / Maildir flags are applied by the caller
+	msg.Read = strings.Contains(h.Get("Status"), "R")
+
+	var body io.Reader = em.Body
+	switch strings.ToLower(strings.TrimSpace(h.Get("Content-Transfer-Encoding"))) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("reading message body: %w", err)
+	}
+	msg.Body = strings.TrimRight(strings.ReplaceAll(string(raw), "\r\n", "\n"), "\n")
+
+	if msg.ID == "" {
+		msg.ID = generateID()
+	}
+	return msg, nil
+}
+
+// WriteMbox writes messages to w in mboxrd format, oldest first.
+// Read messages get a "Status: RO" header so mail clients show them as read.
+func WriteMbox(w io.Writer, msgs []*Message) error {
+	bw := bufio.NewWriter(w)
+	for _, msg := range sortedForExport(msgs) {
+		ts := msg.Timestamp
+		if ts.IsZero() {
+			ts = timeNow()
+		}
+		sender := strings.ReplaceAll(strings.Trim(msg.From, "/"), "/", ".") + "@" + exportDomain
+		if _, err := fmt.Fprintf(bw, "From %s %s\n", sender, ts.UTC().Format(time.ANSIC)); err != nil {
+			return err
+		}
+
+		data := strings.ReplaceAll(string(FormatRFC822(msg)), "\r\n", "\n")
+		headers, body, _ := strings.Cut(data, "\n\n")
+		if msg.Read {
+			headers += "\nStatus: RO"
+		}
+		if _, err := bw.WriteString(headers + "\n\n"); err != nil {
+			return err
+		}
+		for _, line := range strings.SplitAfter(body, "\n") {
+			if isMboxFromLine(strings.TrimLeft(line, ">")) {
+				line = ">" + line
+			}
+			if _, err := bw.WriteString(line); err != nil {
+				return err
+			}
+		}
+		if _, err := bw.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadMbox reads messages from an mboxrd (or mboxo) stream.
+func ReadMbox(r io.Reader) ([]*Message, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var msgs []*Message
+	var cur []string
+	started := false
+	flush := func() error {
+		if !started {
+			return nil
+		}
+		// Drop the blank separator line before the next From line
+		for len(cur) > 0 && cur[len(cur)-1] == "" {
+			cur = cur[:len(cur)-1]
+		}
+		msg, err := ParseRFC822([]byte(strings.Join(cur, "\n") + "\n"))
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, msg)
+		cur = nil
+		return nil
+	}
+
+	prevBlank := true
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if prevBlank && isMboxFromLine(line) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			started = true
+			prevBlank = false
+			continue
+		}
+		if !started {
+			if strings.TrimSpace(line) != "" {
+				return nil, errors.New("not an mbox file (missing From line)")
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ">") && isMboxFromLine(strings.TrimLeft(line, ">")) {
+			line = line[1:]
+		}
+		cur = append(cur, line)
+		prevBlank = line == ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+func isMboxFromLine(line string) bool {
+	return strings.HasPrefix(line, "From ")
+}
+
+// WriteMaildir writes messages into a Maildir, creating cur/, new/ and tmp/.
+// Read messages go to cur/ with the S (seen) flag, unread ones to new/.
+// File names derive from the message ID, so re-exporting is idempotent.
+func WriteMaildir(dir string, msgs []*Message) error {
+	for _, sub := range []string{"cur", "new", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	for _, msg := range sortedForExport(msgs) {
+		ts := msg.Timestamp
+		if ts.IsZero() {
+			ts = timeNow()
+		}
+		base := fmt.Sprintf("%d.%s.gastown", ts.Unix(), maildirSafe(msg.ID))
+
+		tmp := filepath.Join(dir, "tmp", base)
+		if err := os.WriteFile(tmp, FormatRFC822(msg), 0644); err != nil { //nolint:gosec // G306: exported mail is meant to be readable by mail clients
+			return err
+		}
+
+		dest := filepath.Join(dir, "new", base)
+		if msg.Read {
+			dest = filepath.Join(dir, "cur", base+":2,S")
+		}
+		if err := os.Rename(tmp, dest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadMaildir reads all messages in a Maildir's cur/ and new/ directories.
+// Messages carrying the S (seen) flag are marked read.
+func ReadMaildir(dir string) ([]*Message, error) {
+	var msgs []*Message
+	for _, sub := range []string{"cur", "new"} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(dir, sub, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			msg, err := ParseRFC822(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if _, flags, ok := strings.Cut(entry.Name(), ":2,"); ok && strings.Contains(flags, "S") {
+				msg.Read = true
+			}
+			msgs = append(msgs, msg)
+		}
+	}
+	return sortedForExport(msgs), nil
+}
+
+// maildirSafe makes an ID safe for use in a Maildir file name.
+func maildirSafe(id string) string {
+	return strings.NewReplacer("/", "_", ":", "_").Replace(id)
+}
+
+// sortedForExport returns messages oldest first, as mail clients expect.
+func sortedForExport(msgs []*Message) []*Message {
+	sorted := make([]*Message, len(msgs))
+	copy(sorted, msgs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+	return sorted
+}
+
+// ImportOptions controls how Router.Import restores messages.
+type ImportOptions struct {
+	// Inbox restores unread messages as live beads messages in their
+	// recipients' inboxes (read messages are created and closed). The
+	// default restores everything into the archive, preserving IDs and
+	// timestamps.
+	Inbox bool
+
+	// To overrides the recipient of every imported message.
+	To string
+}
+
+// ImportResult reports what Router.Import did.
+type ImportResult struct {
+	Imported int
+	Skipped  int // Already present (archive mode only)
+}
+
+// Import restores messages exported with WriteMbox/WriteMaildir (or any
+// mbox/Maildir mail) into this town. Imported messages are indexed for
+// search. No notifications are sent.
+func (r *Router) Import(msgs []*Message, opts ImportOptions) (*ImportResult, error) {
+	beadsDir := r.resolveBeadsDir("")
+	result := &ImportResult{}
+
+	if opts.Inbox {
+		for _, msg := range msgs {
+			if opts.To != "" {
+				msg.To = opts.To
+			}
+			if err := r.importToInbox(beadsDir, msg); err != nil {
+				return result, fmt.Errorf("importing %s: %w", msg.ID, err)
+			}
+			r.indexMessage(msg, msg.To)
+			result.Imported++
+		}
+		return result, nil
+	}
+
+	archivePath := filepath.Join(beadsDir, "archive.jsonl")
+	existing, err := readArchiveFile(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, msg := range existing {
+		seen[msg.ID] = true
+	}
+
+	archive := NewMailboxWithBeadsDir("", filepath.Dir(beadsDir), beadsDir)
+	idx := r.SearchIndex()
+	for _, msg := range msgs {
+		if seen[msg.ID] {
+			result.Skipped++
+			continue
+		}
+		if opts.To != "" {
+			msg.To = opts.To
+		}
+		msg.Read = true
+		if err := archive.appendToArchive(msg); err != nil {
+			return result, fmt.Errorf("importing %s: %w", msg.ID, err)
+		}
+		seen[msg.ID] = true
+		_ = idx.Add(msg, msg.To) // non-fatal: index can be rebuilt
+		result.Imported++
+	}
+	return result, nil
+}
+
+// importToInbox recreates a message as a beads message, preserving
+// sender, threading and CC. The bead gets a new ID.
+func (r *Router) importToInbox(beadsDir string, msg *Message) error {
+	labels := []string{"from:" + msg.From}
+	if msg.ThreadID != "" {
+		labels = append(labels, "thread:"+msg.ThreadID)
+	}
+	if msg.ReplyTo != "" {
+		labels = append(labels, "reply-to:"+msg.ReplyTo)
+	}
+	if msg.Type != "" && msg.Type != TypeNotification {
+		labels = append(labels, "msg-type:"+string(msg.Type))
+	}
+	for _, cc := range msg.CC {
+		labels = append(labels, "cc:"+addressToIdentity(cc))
+	}
+
+	args := []string{"create", msg.Subject,
+		"--type", "message",
+		"--assignee", addressToIdentity(msg.To),
+		"-d", msg.Body,
+		"--priority", strconv.Itoa(PriorityToBeads(msg.Priority)),
+		"--labels", strings.Join(labels, ","),
+		"--actor", msg.From,
+	}
+	beadID, err := r.createMessageBead(beadsDir, args)
+	if err != nil {
+		return err
+	}
+	if beadID != "" {
+		msg.ID = beadID
+	}
+
+	if msg.Read && beadID != "" {
+		mb := NewMailboxWithBeadsDir(msg.To, filepath.Dir(beadsDir), beadsDir)
+		if err := mb.closeInDir(beadID, beadsDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
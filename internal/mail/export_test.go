@@ -0,0 +1,205 @@
+package mail
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exportTestMessages() []*Message {
+	t0 := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	return []*Message{
+		{
+			ID: "hq-1", From: "gastown/witness", To: "mayor/", CC: []string{"overseer", "gastown/crew/max"},
+			Subject: "Auth migration stalled", Body: "Toast is stuck.\nFrom the logs it looks like a lock.",
+			Timestamp: t0, Priority: PriorityHigh, Type: TypeTask, ThreadID: "thread-auth",
+		},
+		{
+			ID: "hq-2", From: "mayor/", To: "gastown/witness",
+			Subject: "Re: Auth migration stalled — ✓", Body: "Reassign to Nux.\n>From earlier: ok",
+			Timestamp: t0.Add(time.Hour), Priority: PriorityNormal, Type: TypeReply,
+			ThreadID: "thread-auth", ReplyTo: "hq-1", Read: true,
+		},
+	}
+}
+
+func assertRoundTrip(t *testing.T, got []*Message) {
+	t.Helper()
+	want := exportTestMessages()
+	if len(got) != len(want) {
+		t.Fatalf("got %d messages, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.ID != w.ID || g.From != w.From || g.To != w.To || g.Subject != w.Subject {
+			t.Errorf("msg %d headers = {%s %s %s %q}, want {%s %s %s %q}",
+				i, g.ID, g.From, g.To, g.Subject, w.ID, w.From, w.To, w.Subject)
+		}
+		if g.Body != w.Body {
+			t.Errorf("msg %d body = %q, want %q", i, g.Body, w.Body)
+		}
+		if !g.Timestamp.Equal(w.Timestamp) {
+			t.Errorf("msg %d timestamp = %v, want %v", i, g.Timestamp, w.Timestamp)
+		}
+		if g.ThreadID != w.ThreadID || g.ReplyTo != w.ReplyTo {
+			t.Errorf("msg %d threading = {%s %s}, want {%s %s}", i, g.ThreadID, g.ReplyTo, w.ThreadID, w.ReplyTo)
+		}
+		if strings.Join(g.CC, ",") != strings.Join(w.CC, ",") {
+			t.Errorf("msg %d CC = %v, want %v", i, g.CC, w.CC)
+		}
+		if g.Priority != w.Priority || g.Type != w.Type || g.Read != w.Read {
+			t.Errorf("msg %d = {%s %s read=%v}, want {%s %s read=%v}",
+				i, g.Priority, g.Type, g.Read, w.Priority, w.Type, w.Read)
+		}
+	}
+}
+
+func TestFormatRFC822Headers(t *testing.T) {
+	data := string(FormatRFC822(exportTestMessages()[1]))
+	for _, want := range []string{
+		"Message-ID: <hq-2@gastown.local>\r\n",
+		"In-Reply-To: <hq-1@gastown.local>\r\n",
+		"References: <thread-auth@gastown.local> <hq-1@gastown.local>\r\n",
+		`From: "mayor/" <mayor@gastown.local>` + "\r\n",
+		`To: "gastown/witness" <gastown.witness@gastown.local>` + "\r\n",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("missing header %q in:\n%s", want, data)
+		}
+	}
+}
+
+func TestMboxRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMbox(&buf, exportTestMessages()); err != nil {
+		t.Fatalf("WriteMbox: %v", err)
+	}
+	out := buf.String()
+	if strings.Count(out, "\nFrom ")+boolToInt(strings.HasPrefix(out, "From ")) != 2 {
+		t.Errorf("expected exactly 2 From separator lines, got:\n%s", out)
+	}
+	if !strings.Contains(out, "\n>From the logs") || !strings.Contains(out, "\n>>From earlier") {
+		t.Errorf("body From lines not escaped:\n%s", out)
+	}
+
+	msgs, err := ReadMbox(&buf)
+	if err != nil {
+		t.Fatalf("ReadMbox: %v", err)
+	}
+	assertRoundTrip(t, msgs)
+}
+
+func TestMaildirRoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Maildir")
+	if err := WriteMaildir(dir, exportTestMessages()); err != nil {
+		t.Fatalf("WriteMaildir: %v", err)
+	}
+	if DetectExportFormat(dir) != FormatMaildir {
+		t.Error("DetectExportFormat should detect the Maildir")
+	}
+
+	newEntries, _ := os.ReadDir(filepath.Join(dir, "new"))
+	curEntries, _ := os.ReadDir(filepath.Join(dir, "cur"))
+	if len(newEntries) != 1 || len(curEntries) != 1 {
+		t.Fatalf("new=%d cur=%d, want 1 unread and 1 read", len(newEntries), len(curEntries))
+	}
+	if !strings.HasSuffix(curEntries[0].Name(), ":2,S") {
+		t.Errorf("read message %q missing seen flag", curEntries[0].Name())
+	}
+
+	// Re-export overwrites rather than duplicating
+	if err := WriteMaildir(dir, exportTestMessages()); err != nil {
+		t.Fatalf("WriteMaildir (again): %v", err)
+	}
+
+	msgs, err := ReadMaildir(dir)
+	if err != nil {
+		t.Fatalf("ReadMaildir: %v", err)
+	}
+	assertRoundTrip(t, msgs)
+}
+
+func TestParseRFC822Foreign(t *testing.T) {
+	raw := "Message-ID: <abc@example.com>\r\n" +
+		"Date: Mon, 02 Mar 2026 10:00:00 +0000\r\n" +
+		"From: Jane <jane@example.com>\r\n" +
+		"To: gastown.witness@gastown.local\r\n" +
+		"Subject: =?utf-8?q?caf=C3=A9?=\r\n" +
+		"In-Reply-To: <root@example.com>\r\n" +
+		"References: <root@example.com>\r\n" +
+		"X-Priority: 1 (Highest)\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"hello =3D world\r\n"
+
+	msg, err := ParseRFC822([]byte(raw))
+	if err != nil {
+		t.Fatalf("ParseRFC822: %v", err)
+	}
+	if msg.ID != "abc@example.com" || msg.ReplyTo != "root@example.com" || msg.ThreadID != "root@example.com" {
+		t.Errorf("ids = {%s %s %s}", msg.ID, msg.ReplyTo, msg.ThreadID)
+	}
+	if msg.From != "Jane" || msg.To != "gastown/witness" {
+		t.Errorf("addresses = {%s %s}", msg.From, msg.To)
+	}
+	if msg.Subject != "café" || msg.Body != "hello = world" || msg.Priority != PriorityUrgent {
+		t.Errorf("subject=%q body=%q priority=%s", msg.Subject, msg.Body, msg.Priority)
+	}
+}
+
+func TestReadMboxRejectsNonMbox(t *testing.T) {
+	if _, err := ReadMbox(strings.NewReader("Subject: hi\n\nbody\n")); err == nil {
+		t.Error("ReadMbox should reject input without a From line")
+	}
+}
+
+func TestImportToArchive(t *testing.T) {
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	res, err := r.Import(exportTestMessages(), ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if res.Imported != 2 || res.Skipped != 0 {
+		t.Errorf("Import = %+v, want 2 imported", res)
+	}
+
+	// Importing again skips duplicates
+	res, err = r.Import(exportTestMessages(), ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import (again): %v", err)
+	}
+	if res.Imported != 0 || res.Skipped != 2 {
+		t.Errorf("re-Import = %+v, want 2 skipped", res)
+	}
+
+	archived, err := readArchiveFile(filepath.Join(beadsDir, "archive.jsonl"))
+	if err != nil || len(archived) != 2 {
+		t.Fatalf("archive has %d messages (err=%v), want 2", len(archived), err)
+	}
+	if archived[0].ThreadID != "thread-auth" || len(archived[0].CC) != 2 {
+		t.Errorf("archived message lost metadata: %+v", archived[0])
+	}
+
+	// Imported mail is searchable
+	q, _ := ParseIndexQuery("thread:thread-auth is:archived", time.Now())
+	results, err := r.SearchIndex().Search(q)
+	if err != nil || len(results) != 2 {
+		t.Errorf("search after import = %d results (err=%v), want 2", len(results), err)
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
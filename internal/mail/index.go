@@ -0,0 +1,799 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/lockfile"
+)
+
+// searchIndexVersion is bumped when the on-disk index format changes.
+// Indexes with a different version are treated as empty until rebuilt.
+const searchIndexVersion = 1
+
+// ErrIndexEmpty is returned when searching an index that has never been built.
+var ErrIndexEmpty = errors.New("search index is empty (run: gt mail search --reindex)")
+
+// IndexEntry is one message copy in the town-wide search index.
+type IndexEntry struct {
+	ID         string      `json:"id"`
+	ReceiptID  string      `json:"receipt_id,omitempty"`
+	Mailbox    string      `json:"mailbox"` // Recipient address this copy was delivered to
+	From       string      `json:"from"`
+	To         string      `json:"to"`
+	CC         []string    `json:"cc,omitempty"`
+	Subject    string      `json:"subject"`
+	Body       string      `json:"body"`
+	ThreadID   string      `json:"thread_id,omitempty"`
+	ReplyTo    string      `json:"reply_to,omitempty"`
+	Type       MessageType `json:"type,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+	Archived   bool        `json:"archived,omitempty"`
+	ArchivedAt *time.Time  `json:"archived_at,omitempty"`
+}
+
+// newIndexEntry builds an index entry from a delivered message.
+func newIndexEntry(msg *Message, mailbox string) *IndexEntry {
+	if mailbox == "" {
+		mailbox = msg.To
+	}
+	return &IndexEntry{
+		ID:        msg.ID,
+		ReceiptID: msg.ReceiptID,
+		Mailbox:   mailbox,
+		From:      msg.From,
+		To:        msg.To,
+		CC:        msg.CC,
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+		ThreadID:  msg.ThreadID,
+		ReplyTo:   msg.ReplyTo,
+		Type:      msg.Type,
+		Timestamp: msg.Timestamp,
+		Archived:  msg.Read,
+	}
+}
+
+// indexData is the persisted form of the search index: the indexed
+// messages plus an inverted index from term to message IDs.
+type indexData struct {
+	Version   int                    `json:"version"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Entries   map[string]*IndexEntry `json:"entries"`
+	Terms     map[string][]string    `json:"terms"`
+}
+
+func newIndexData() *indexData {
+	return &indexData{
+		Version: searchIndexVersion,
+		Entries: make(map[string]*IndexEntry),
+		Terms:   make(map[string][]string),
+	}
+}
+
+// put adds or replaces an entry and updates its postings.
+func (d *indexData) put(e *IndexEntry) {
+	if _, ok := d.Entries[e.ID]; ok {
+		d.remove(e.ID)
+	}
+	d.Entries[e.ID] = e
+	for _, term := range uniqueTerms(e.Subject + "\n" + e.Body) {
+		d.Terms[term] = insertSorted(d.Terms[term], e.ID)
+	}
+}
+
+// remove drops an entry and its postings.
+func (d *indexData) remove(id string) {
+	e, ok := d.Entries[id]
+	if !ok {
+		return
+	}
+	for _, term := range uniqueTerms(e.Subject + "\n" + e.Body) {
+		ids := d.Terms[term]
+		i := sort.SearchStrings(ids, id)
+		if i < len(ids) && ids[i] == id {
+			ids = append(ids[:i], ids[i+1:]...)
+		}
+		if len(ids) == 0 {
+			delete(d.Terms, term)
+		} else {
+			d.Terms[term] = ids
+		}
+	}
+	delete(d.Entries, id)
+}
+
+// SearchIndex is a town-wide full-text index over all mailboxes. It is
+// updated as mail is sent and archived so queries don't have to scan every
+// agent's inbox through bd.
+//
+// Sends and archives append one line to .beads/mail-index.log; nothing is
+// rewritten on the send path. Loading replays the log over the snapshot in
+// .beads/mail-index.json, and once the log passes compactLogBytes the next
+// update folds it into a new snapshot.
+type SearchIndex struct {
+	path string
+}
+
+// compactLogBytes is the log size at which updates compact the index.
+const compactLogBytes = 1 << 20
+
+// indexLogRecord is one line of the index log: an added entry, or an
+// archive state change.
+type indexLogRecord struct {
+	Entry      *IndexEntry `json:"entry,omitempty"`
+	ID         string      `json:"id,omitempty"`
+	Archived   bool        `json:"archived,omitempty"`
+	ArchivedAt *time.Time  `json:"archived_at,omitempty"`
+}
+
+// apply replays a log record onto the index.
+func (d *indexData) apply(rec *indexLogRecord) {
+	if rec.Entry != nil {
+		if rec.Entry.ID != "" {
+			d.put(rec.Entry)
+		}
+		return
+	}
+	if e, ok := d.Entries[rec.ID]; ok && e.Archived != rec.Archived {
+		e.Archived = rec.Archived
+		e.ArchivedAt = rec.ArchivedAt
+	}
+}
+
+// NewSearchIndex returns the search index for the given .beads directory.
+func NewSearchIndex(beadsDir string) *SearchIndex {
+	return &SearchIndex{path: filepath.Join(beadsDir, "mail-index.json")}
+}
+
+// Path returns the index snapshot file path.
+func (s *SearchIndex) Path() string {
+	return s.path
+}
+
+// logPath returns the path of the index log.
+func (s *SearchIndex) logPath() string {
+	return strings.TrimSuffix(s.path, ".json") + ".log"
+}
+
+// lock takes an exclusive lock on the index. Returns an unlock function.
+func (s *SearchIndex) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, err
+	}
+	return lockfile.Lock(s.path + ".lock")
+}
+
+// load reads the snapshot and replays the log over it. A missing or
+// outdated snapshot loads empty.
+func (s *SearchIndex) load() (*indexData, error) {
+	d, err := s.loadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.replayLog(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// loadSnapshot reads the compacted index.
+func (s *SearchIndex) loadSnapshot() (*indexData, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return newIndexData(), nil
+		}
+		return nil, err
+	}
+	var d indexData
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parsing search index: %w", err)
+	}
+	if d.Version != searchIndexVersion || d.Entries == nil {
+		return newIndexData(), nil
+	}
+	if d.Terms == nil {
+		d.Terms = make(map[string][]string)
+	}
+	return &d, nil
+}
+
+// replayLog applies the index log to d. Malformed lines are skipped.
+func (s *SearchIndex) replayLog(d *indexData) error {
+	file, err := os.Open(s.logPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var rec indexLogRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		d.apply(&rec)
+	}
+	return scanner.Err()
+}
+
+// save writes a new snapshot and clears the log it now includes.
+func (s *SearchIndex) save(d *indexData) error {
+	d.UpdatedAt = timeNow()
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: index is non-sensitive operational data
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	if err := os.Remove(s.logPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// appendLog appends a record to the index log under the lock, compacting
+// the index once the log has grown past compactLogBytes.
+func (s *SearchIndex) appendLog(rec *indexLogRecord) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.logPath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: index is non-sensitive operational data
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	info, err := f.Stat()
+	_ = f.Close()
+	if err != nil || info.Size() < compactLogBytes {
+		return err
+	}
+	return s.compact()
+}
+
+// compact folds the log into a new snapshot. Callers hold the lock.
+func (s *SearchIndex) compact() error {
+	d, err := s.load()
+	if err != nil {
+		return err
+	}
+	return s.save(d)
+}
+
+// Add indexes a delivered message copy. mailbox is the recipient address
+// the copy was delivered to (defaults to msg.To). Messages without an ID
+// are skipped; they are picked up by the next rebuild.
+func (s *SearchIndex) Add(msg *Message, mailbox string) error {
+	if msg.ID == "" {
+		return nil
+	}
+	return s.appendLog(&indexLogRecord{Entry: newIndexEntry(msg, mailbox)})
+}
+
+// SetArchived marks an indexed message as archived (or back in the inbox).
+// Unknown IDs are ignored.
+func (s *SearchIndex) SetArchived(id string, archived bool) error {
+	rec := &indexLogRecord{ID: id, Archived: archived}
+	if archived {
+		now := timeNow()
+		rec.ArchivedAt = &now
+	}
+	return s.appendLog(rec)
+}
+
+// Rebuild replaces the index contents with the given entries.
+func (s *SearchIndex) Rebuild(entries []*IndexEntry) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	d := newIndexData()
+	for _, e := range entries {
+		if e.ID != "" {
+			d.put(e)
+		}
+	}
+	return s.save(d)
+}
+
+// Len returns the number of indexed message copies.
+func (s *SearchIndex) Len() (int, error) {
+	d, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	return len(d.Entries), nil
+}
+
+// Backlog returns the number of indexed messages still in each mailbox's
+// inbox (not yet read or archived), keyed by mailbox address.
+func (s *SearchIndex) Backlog() (map[string]int, error) {
+	d, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	backlog := make(map[string]int)
+	for _, e := range d.Entries {
+		if !e.Archived {
+			backlog[e.Mailbox]++
+		}
+	}
+	return backlog, nil
+}
+
+// SearchResult is a search hit. Copies of the same logical message
+// (mailing list fan-out) are collapsed into one result listing every
+// recipient mailbox.
+type SearchResult struct {
+	*IndexEntry
+	Mailboxes []string `json:"mailboxes"`
+}
+
+// Search runs a query against the index, returning results newest first.
+func (s *SearchIndex) Search(q *IndexQuery) ([]*SearchResult, error) {
+	d, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if len(d.Entries) == 0 {
+		_, snapErr := os.Stat(s.path)
+		_, logErr := os.Stat(s.logPath())
+		if os.IsNotExist(snapErr) && os.IsNotExist(logErr) {
+			return nil, ErrIndexEmpty
+		}
+	}
+
+	candidates := d.candidates(q.Terms)
+
+	var hits []*IndexEntry
+	for id := range candidates {
+		e := d.Entries[id]
+		if e != nil && q.matches(e) {
+			hits = append(hits, e)
+		}
+	}
+
+	// Collapse fan-out copies sharing a receipt
+	var results []*SearchResult
+	byReceipt := make(map[string]*SearchResult)
+	sort.Slice(hits, func(i, j int) bool { return hits[i].ID < hits[j].ID })
+	for _, e := range hits {
+		if e.ReceiptID != "" {
+			if r, ok := byReceipt[e.ReceiptID]; ok {
+				r.Mailboxes = append(r.Mailboxes, e.Mailbox)
+				// Collapsed result is archived only when every copy is
+				r.Archived = r.Archived && e.Archived
+				continue
+			}
+		}
+		copied := *e
+		r := &SearchResult{IndexEntry: &copied, Mailboxes: []string{e.Mailbox}}
+		if e.ReceiptID != "" {
+			byReceipt[e.ReceiptID] = r
+		}
+		results = append(results, r)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
+
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+// candidates returns the IDs of entries containing every term (by prefix).
+// With no terms, every entry is a candidate.
+func (d *indexData) candidates(terms []string) map[string]bool {
+	if len(terms) == 0 {
+		all := make(map[string]bool, len(d.Entries))
+		for id := range d.Entries {
+			all[id] = true
+		}
+		return all
+	}
+
+	var result map[string]bool
+	for _, term := range terms {
+		matched := make(map[string]bool)
+		for indexed, ids := range d.Terms {
+			if !strings.HasPrefix(indexed, term) {
+				continue
+			}
+			for _, id := range ids {
+				if result == nil || result[id] {
+					matched[id] = true
+				}
+			}
+		}
+		result = matched
+		if len(result) == 0 {
+			break
+		}
+	}
+	return result
+}
+
+// IndexQuery is a parsed search query: free-text terms (all must match,
+// prefix matching) plus optional field filters.
+type IndexQuery struct {
+	Terms   []string  // Lowercased terms; each must prefix-match a word in subject/body
+	Phrases []string  // Quoted phrases that must appear verbatim (case-insensitive)
+	From    string    // from: sender substring
+	To      string    // to: recipient substring (mailbox, To or CC)
+	Thread  string    // thread: thread ID (prefix)
+	Before  time.Time // before: sent before this time
+	After   time.Time // after: sent at or after this time
+	Status  string    // is: "inbox" or "archived"; empty matches both
+	Limit   int       // Maximum results (0 = unlimited)
+}
+
+// ParseIndexQuery parses a search query string. Bare words and "quoted
+// phrases" are matched against subject and body; field filters narrow the
+// results:
+//
+//	from:<sender>     sender address contains value
+//	to:<recipient>    recipient mailbox, To or CC contains value
+//	thread:<id>       thread ID (prefix match)
+//	before:<when>     sent before date (2006-01-02, RFC3339, 7d, 12h, today, yesterday)
+//	after:<when>      sent on or after date (same formats)
+//	is:inbox|archived message state
+//
+// Relative times are resolved against now.
+func ParseIndexQuery(query string, now time.Time) (*IndexQuery, error) {
+	q := &IndexQuery{}
+	for _, tok := range splitQuery(query) {
+		if tok.quoted {
+			phrase := strings.ToLower(strings.TrimSpace(tok.text))
+			if phrase != "" {
+				q.Phrases = append(q.Phrases, phrase)
+				q.Terms = append(q.Terms, tokenize(phrase)...)
+			}
+			continue
+		}
+
+		field, value, hasField := strings.Cut(tok.text, ":")
+		if hasField && value != "" {
+			var err error
+			switch strings.ToLower(field) {
+			case "from":
+				q.From = strings.ToLower(value)
+				continue
+			case "to":
+				q.To = strings.ToLower(value)
+				continue
+			case "thread":
+				q.Thread = value
+				continue
+			case "before":
+				q.Before, err = parseSearchTime(value, now)
+			case "after", "since":
+				q.After, err = parseSearchTime(value, now)
+			case "is":
+				switch strings.ToLower(value) {
+				case "inbox", "open", "unread":
+					q.Status = "inbox"
+				case "archived", "closed", "read":
+					q.Status = "archived"
+				default:
+					return nil, fmt.Errorf("invalid is: filter %q (want inbox or archived)", value)
+				}
+				continue
+			default:
+				// Not a known field - treat as plain text (e.g. "HELP: stuck")
+				q.Terms = append(q.Terms, tokenize(tok.text)...)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: filter: %w", field, err)
+			}
+			continue
+		}
+
+		q.Terms = append(q.Terms, tokenize(tok.text)...)
+	}
+	return q, nil
+}
+
+// matches applies the field filters and phrases to an entry.
+func (q *IndexQuery) matches(e *IndexEntry) bool {
+	if q.From != "" && !strings.Contains(strings.ToLower(e.From), q.From) {
+		return false
+	}
+	if q.To != "" && !q.matchesRecipient(e) {
+		return false
+	}
+	if q.Thread != "" && !strings.HasPrefix(e.ThreadID, q.Thread) {
+		return false
+	}
+	if !q.Before.IsZero() && !e.Timestamp.Before(q.Before) {
+		return false
+	}
+	if !q.After.IsZero() && e.Timestamp.Before(q.After) {
+		return false
+	}
+	switch q.Status {
+	case "inbox":
+		if e.Archived {
+			return false
+		}
+	case "archived":
+		if !e.Archived {
+			return false
+		}
+	}
+	if len(q.Phrases) > 0 {
+		text := strings.ToLower(e.Subject + "\n" + e.Body)
+		for _, p := range q.Phrases {
+			if !strings.Contains(text, p) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (q *IndexQuery) matchesRecipient(e *IndexEntry) bool {
+	if strings.Contains(strings.ToLower(e.Mailbox), q.To) || strings.Contains(strings.ToLower(e.To), q.To) {
+		return true
+	}
+	for _, cc := range e.CC {
+		if strings.Contains(strings.ToLower(cc), q.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSearchTime parses an absolute date or a relative age ("7d", "2w", "12h").
+func parseSearchTime(value string, now time.Time) (time.Time, error) {
+	switch strings.ToLower(value) {
+	case "today":
+		y, m, d := now.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location()), nil
+	case "yesterday":
+		y, m, d := now.AddDate(0, 0, -1).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location()), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if len(value) >= 2 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && n >= 0 {
+			switch value[len(value)-1] {
+			case 'h':
+				return now.Add(-time.Duration(n) * time.Hour), nil
+			case 'd':
+				return now.AddDate(0, 0, -n), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*n), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time %q (use 2006-01-02, RFC3339, or 7d/12h/2w)", value)
+}
+
+type queryToken struct {
+	text   string
+	quoted bool
+}
+
+// splitQuery splits a query on whitespace, keeping "quoted phrases" together.
+func splitQuery(query string) []queryToken {
+	var tokens []queryToken
+	var cur strings.Builder
+	inQuote := false
+	flush := func(quoted bool) {
+		if cur.Len() > 0 || quoted {
+			tokens = append(tokens, queryToken{text: cur.String(), quoted: quoted})
+		}
+		cur.Reset()
+	}
+	for _, r := range query {
+		switch {
+		case r == '"':
+			if inQuote {
+				flush(true)
+			} else {
+				flush(false)
+			}
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			flush(false)
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush(inQuote)
+	return tokens
+}
+
+// tokenize splits text into lowercased words of two or more characters.
+func tokenize(text string) []string {
+	var terms []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) >= 2 {
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// uniqueTerms returns the distinct terms in text.
+func uniqueTerms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(text) {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// insertSorted inserts id into a sorted slice if not already present.
+func insertSorted(ids []string, id string) []string {
+	i := sort.SearchStrings(ids, id)
+	if i < len(ids) && ids[i] == id {
+		return ids
+	}
+	ids = append(ids, "")
+	copy(ids[i+1:], ids[i:])
+	ids[i] = id
+	return ids
+}
+
+// RebuildSearchIndex re-indexes every message in the town beads database,
+// open and closed, plus the archive file. Closed beads count as archived.
+// Returns the number of messages indexed.
+func (r *Router) RebuildSearchIndex() (int, error) {
+	msgs, err := r.ListAllMessages()
+	if err != nil {
+		return 0, err
+	}
+
+	var entries []*IndexEntry
+	for _, msg := range msgs {
+		entries = append(entries, newIndexEntry(msg, msg.To))
+	}
+
+	if err := r.SearchIndex().Rebuild(entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// ListAllMessages returns every message in town: all message beads, open
+// and closed, plus messages in the archive file that no longer exist as
+// beads. Closed and archived messages are marked Read.
+func (r *Router) ListAllMessages() ([]*Message, error) {
+	beadsDir := r.resolveBeadsDir("")
+
+	cmd := exec.Command("bd", "list", "--type", "message", "--all", "--limit=0", "--json") //nolint:gosec // G204: fixed args
+	cmd.Dir = filepath.Dir(beadsDir)
+	cmd.Env = append(cmd.Environ(), "BEADS_DIR="+beadsDir)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg != "" {
+			return nil, errors.New(errMsg)
+		}
+		return nil, err
+	}
+
+	var beadsMsgs []BeadsMessage
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 && string(out) != "null" {
+		if err := json.Unmarshal(out, &beadsMsgs); err != nil {
+			return nil, fmt.Errorf("parsing bd list output: %w", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var messages []*Message
+	for i := range beadsMsgs {
+		msg := beadsMsgs[i].ToMessage()
+		seen[msg.ID] = true
+		messages = append(messages, msg)
+	}
+
+	archived, err := readArchiveFile(filepath.Join(beadsDir, "archive.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range archived {
+		if msg.ID == "" || seen[msg.ID] {
+			continue
+		}
+		seen[msg.ID] = true
+		msg.Read = true
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// readArchiveFile reads messages from an archive JSONL file.
+func readArchiveFile(path string) ([]*Message, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var messages []*Message
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		var msg Message
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			continue // Skip malformed lines
+		}
+		messages = append(messages, &msg)
+	}
+	return messages, scanner.Err()
+}
+
+// SearchIndex returns the town-wide mail search index.
+func (r *Router) SearchIndex() *SearchIndex {
+	return NewSearchIndex(r.resolveBeadsDir(""))
+}
+
+// indexMessage adds a delivered message copy to the search index (best-effort).
+func (r *Router) indexMessage(msg *Message, mailbox string) {
+	_ = r.SearchIndex().Add(msg, mailbox) // non-fatal: index can be rebuilt
+}
@@ -0,0 +1,240 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func seedIndex(t *testing.T, idx *SearchIndex, now time.Time) {
+	t.Helper()
+	msgs := []struct {
+		msg     *Message
+		mailbox string
+	}{
+		{&Message{ID: "hq-1", From: "gastown/witness", To: "mayor/", Subject: "Auth migration stalled",
+			Body: "Polecat Toast is blocked on the authentication schema.", ThreadID: "thread-auth",
+			Timestamp: now.Add(-2 * 24 * time.Hour)}, ""},
+		{&Message{ID: "hq-2", From: "mayor/", To: "gastown/witness", Subject: "Re: Auth migration stalled",
+			Body: "Reassign to Nux.", ThreadID: "thread-auth", ReplyTo: "hq-1",
+			Timestamp: now.Add(-1 * 24 * time.Hour)}, ""},
+		{&Message{ID: "hq-3", From: "gastown/refinery", To: "mayor/", Subject: "Merge queue drained",
+			Body: "All clear.", Timestamp: now.Add(-20 * 24 * time.Hour)}, ""},
+		// Mailing list fan-out: two copies sharing a receipt
+		{&Message{ID: "hq-4", ReceiptID: "msg-list", From: "deacon/", To: "gastown/Toast", Subject: "Auth rollout tonight",
+			Body: "Heads up.", Timestamp: now.Add(-3 * time.Hour)}, "gastown/Toast"},
+		{&Message{ID: "hq-5", ReceiptID: "msg-list", From: "deacon/", To: "gastown/Nux", Subject: "Auth rollout tonight",
+			Body: "Heads up.", Timestamp: now.Add(-3 * time.Hour)}, "gastown/Nux"},
+	}
+	for _, m := range msgs {
+		if err := idx.Add(m.msg, m.mailbox); err != nil {
+			t.Fatalf("Add(%s): %v", m.msg.ID, err)
+		}
+	}
+}
+
+func searchIDs(t *testing.T, idx *SearchIndex, query string, now time.Time) []string {
+	t.Helper()
+	q, err := ParseIndexQuery(query, now)
+	if err != nil {
+		t.Fatalf("ParseIndexQuery(%q): %v", query, err)
+	}
+	results, err := idx.Search(q)
+	if err != nil {
+		t.Fatalf("Search(%q): %v", query, err)
+	}
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func equalIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearchIndexQueries(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	idx := NewSearchIndex(t.TempDir())
+	seedIndex(t, idx, now)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"auth migration", []string{"hq-2", "hq-1"}},
+		{"authent", []string{"hq-1"}}, // prefix match
+		{`"migration stalled"`, []string{"hq-2", "hq-1"}},
+		{`"stalled migration"`, nil},
+		{"auth from:witness", []string{"hq-1"}},
+		{"to:mayor", []string{"hq-1", "hq-3"}},
+		{"thread:thread-auth", []string{"hq-2", "hq-1"}},
+		{"before:2026-03-01", []string{"hq-3"}},
+		{"auth after:7d", []string{"hq-4", "hq-2", "hq-1"}},
+		{"auth after:7d before:yesterday", []string{"hq-1"}},
+		{"nonexistent", nil},
+		{"", []string{"hq-4", "hq-2", "hq-1", "hq-3"}},
+	}
+	for _, tt := range tests {
+		got := searchIDs(t, idx, tt.query, now)
+		if !equalIDs(got, tt.want) {
+			t.Errorf("search %q = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchIndexCollapsesFanOut(t *testing.T) {
+	now := time.Now()
+	idx := NewSearchIndex(t.TempDir())
+	seedIndex(t, idx, now)
+
+	q, _ := ParseIndexQuery("rollout", now)
+	results, err := idx.Search(q)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("len(results) = %d, want 1 collapsed result", len(results))
+	}
+	if got := results[0].Mailboxes; len(got) != 2 || got[0] != "gastown/Toast" || got[1] != "gastown/Nux" {
+		t.Errorf("Mailboxes = %v, want [gastown/Toast gastown/Nux]", got)
+	}
+}
+
+func TestSearchIndexArchived(t *testing.T) {
+	now := time.Now()
+	idx := NewSearchIndex(t.TempDir())
+	seedIndex(t, idx, now)
+
+	if err := idx.SetArchived("hq-1", true); err != nil {
+		t.Fatalf("SetArchived: %v", err)
+	}
+	if got := searchIDs(t, idx, "auth is:archived", now); !equalIDs(got, []string{"hq-1"}) {
+		t.Errorf("is:archived = %v, want [hq-1]", got)
+	}
+	if got := searchIDs(t, idx, "migration is:inbox", now); !equalIDs(got, []string{"hq-2"}) {
+		t.Errorf("is:inbox = %v, want [hq-2]", got)
+	}
+
+	// Reopened messages return to the inbox
+	if err := idx.SetArchived("hq-1", false); err != nil {
+		t.Fatalf("SetArchived: %v", err)
+	}
+	if got := searchIDs(t, idx, "is:archived", now); len(got) != 0 {
+		t.Errorf("is:archived after reopen = %v, want none", got)
+	}
+
+	// Unknown IDs are ignored
+	if err := idx.SetArchived("hq-missing", true); err != nil {
+		t.Errorf("SetArchived(missing) = %v, want nil", err)
+	}
+}
+
+func TestSearchIndexReAddReplacesTerms(t *testing.T) {
+	now := time.Now()
+	idx := NewSearchIndex(t.TempDir())
+	if err := idx.Add(&Message{ID: "hq-1", Subject: "alpha", Timestamp: now}, "mayor/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Add(&Message{ID: "hq-1", Subject: "beta", Timestamp: now}, "mayor/"); err != nil {
+		t.Fatal(err)
+	}
+	if got := searchIDs(t, idx, "alpha", now); len(got) != 0 {
+		t.Errorf("stale term still matches: %v", got)
+	}
+	if got := searchIDs(t, idx, "beta", now); !equalIDs(got, []string{"hq-1"}) {
+		t.Errorf("search beta = %v, want [hq-1]", got)
+	}
+	if n, _ := idx.Len(); n != 1 {
+		t.Errorf("Len = %d, want 1", n)
+	}
+}
+
+func TestSearchIndexEmptyAndRebuild(t *testing.T) {
+	idx := NewSearchIndex(t.TempDir())
+	if _, err := idx.Search(&IndexQuery{}); !errors.Is(err, ErrIndexEmpty) {
+		t.Errorf("Search on missing index err = %v, want ErrIndexEmpty", err)
+	}
+
+	entries := []*IndexEntry{
+		{ID: "hq-9", Subject: "rebuilt entry", Mailbox: "mayor/", Archived: true},
+		{ID: "", Subject: "skipped"},
+	}
+	if err := idx.Rebuild(entries); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if n, _ := idx.Len(); n != 1 {
+		t.Errorf("Len after rebuild = %d, want 1", n)
+	}
+	if got := searchIDs(t, idx, "rebuilt is:archived", time.Now()); !equalIDs(got, []string{"hq-9"}) {
+		t.Errorf("search after rebuild = %v, want [hq-9]", got)
+	}
+}
+
+func TestParseIndexQuery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	q, err := ParseIndexQuery(`HELP: "auth migration" from:Witness to:mayor thread:t-1 after:2w`, now)
+	if err != nil {
+		t.Fatalf("ParseIndexQuery: %v", err)
+	}
+	if !equalIDs(q.Terms, []string{"help", "auth", "migration"}) {
+		t.Errorf("Terms = %v", q.Terms)
+	}
+	if !equalIDs(q.Phrases, []string{"auth migration"}) {
+		t.Errorf("Phrases = %v", q.Phrases)
+	}
+	if q.From != "witness" || q.To != "mayor" || q.Thread != "t-1" {
+		t.Errorf("filters = from:%q to:%q thread:%q", q.From, q.To, q.Thread)
+	}
+	if want := now.AddDate(0, 0, -14); !q.After.Equal(want) {
+		t.Errorf("After = %v, want %v", q.After, want)
+	}
+
+	for _, bad := range []string{"before:soon", "is:maybe"} {
+		if _, err := ParseIndexQuery(bad, now); err == nil {
+			t.Errorf("ParseIndexQuery(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSearchIndexLogAndCompact(t *testing.T) {
+	now := time.Now()
+	idx := NewSearchIndex(t.TempDir())
+	seedIndex(t, idx, now)
+	if err := idx.SetArchived("hq-1", true); err != nil {
+		t.Fatalf("SetArchived: %v", err)
+	}
+
+	// Sends only append to the log; the snapshot isn't written
+	if _, err := os.Stat(idx.Path()); !os.IsNotExist(err) {
+		t.Errorf("snapshot written on send: %v", err)
+	}
+	before := searchIDs(t, idx, "is:archived", now)
+
+	unlock, err := idx.lock()
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	err = idx.compact()
+	unlock()
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if _, err := os.Stat(idx.logPath()); !os.IsNotExist(err) {
+		t.Errorf("log kept after compaction: %v", err)
+	}
+	if after := searchIDs(t, idx, "is:archived", now); !equalIDs(after, before) || !equalIDs(after, []string{"hq-1"}) {
+		t.Errorf("is:archived after compaction = %v, want %v", after, before)
+	}
+}
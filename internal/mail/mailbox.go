@@ -336,7 +336,41 @@ func (m *Mailbox) MarkRead(id string) error {
 
 func (m *Mailbox) markReadBeads(id string) error {
 	// Single DB - wisps and persistent messages in same store
-	return m.closeInDir(id, m.beadsDir)
+	if err := m.closeInDir(id, m.beadsDir); err != nil {
+		return err
+	}
+
+	// Closing the bead is the acknowledgement - record it on the receipt
+	_ = m.Receipts().MarkAcked(id, m.identity) // non-fatal: receipts are advisory
+	_ = m.SearchIndex().SetArchived(id, true)  // non-fatal: index can be rebuilt
+	return nil
+}
+
+// Receipts returns the receipt store backing this mailbox.
+// Legacy (JSONL) mailboxes keep receipts next to their inbox file.
+func (m *Mailbox) Receipts() *ReceiptStore {
+	if m.legacy {
+		return NewReceiptStore(filepath.Dir(m.path))
+	}
+	return NewReceiptStore(m.beadsDir)
+}
+
+// SearchIndex returns the search index covering this mailbox.
+// Legacy (JSONL) mailboxes keep their index next to their inbox file.
+func (m *Mailbox) SearchIndex() *SearchIndex {
+	if m.legacy {
+		return NewSearchIndex(filepath.Dir(m.path))
+	}
+	return NewSearchIndex(m.beadsDir)
+}
+
+// RecordRead records a read receipt for a message without acknowledging it.
+// Called when the message is displayed to its recipient.
+func (m *Mailbox) RecordRead(id string) error {
+	if m.legacy {
+		return nil
+	}
+	return m.Receipts().MarkRead(id, m.identity)
 }
 
 // closeInDir closes a message in a specific beads directory.
@@ -415,6 +449,7 @@ func (m *Mailbox) markUnreadBeads(id string) error {
 		return err
 	}
 
+	_ = m.SearchIndex().SetArchived(id, false) // non-fatal: index can be rebuilt
 	return nil
 }
 
@@ -484,7 +519,18 @@ func (m *Mailbox) Archive(id string) error {
 	}
 
 	// Delete from inbox
-	return m.Delete(id)
+	if err := m.Delete(id); err != nil {
+		return err
+	}
+
+	if m.legacy {
+		// Beads mailboxes update the index when the bead is closed
+		msg.Read = true
+		idx := m.SearchIndex()
+		_ = idx.Add(msg, msg.To)          // non-fatal: index can be rebuilt
+		_ = idx.SetArchived(msg.ID, true) // records ArchivedAt
+	}
+	return nil
 }
 
 // ArchivePath returns the path to the archive file.
@@ -521,6 +567,67 @@ func (m *Mailbox) appendToArchive(msg *Message) error {
 	return err
 }
 
+// ListAll returns every message addressed to this mailbox (as recipient
+// or CC): open, read/closed and archived. Used for export.
+func (m *Mailbox) ListAll() ([]*Message, error) {
+	var messages []*Message
+	seen := make(map[string]bool)
+	add := func(msgs []*Message) {
+		for _, msg := range msgs {
+			if !seen[msg.ID] {
+				seen[msg.ID] = true
+				messages = append(messages, msg)
+			}
+		}
+	}
+
+	inbox, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	add(inbox)
+
+	if !m.legacy {
+		// Closed beads are read/archived mail
+		for _, identity := range m.identityVariants() {
+			if msgs, err := m.queryMessages(m.beadsDir, "--assignee", identity, "closed"); err == nil {
+				add(msgs)
+			}
+			if msgs, err := m.queryMessages(m.beadsDir, "--label", "cc:"+identity, "closed"); err == nil {
+				add(msgs)
+			}
+		}
+	}
+
+	archived, err := m.ListArchived()
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range archived {
+		// The beads archive file is shared town-wide; keep only our mail
+		if !m.legacy && !m.isRecipient(msg) {
+			continue
+		}
+		msg.Read = true
+		add([]*Message{msg})
+	}
+
+	return messages, nil
+}
+
+// isRecipient reports whether msg was addressed (or CC'd) to this mailbox.
+func (m *Mailbox) isRecipient(msg *Message) bool {
+	if addressToIdentity(msg.To) == m.identity {
+		return true
+	}
+	for _, cc := range msg.CC {
+		if addressToIdentity(cc) == m.identity {
+			return true
+		}
+	}
+	return false
+}
+
 // ListArchived returns all messages in the archive file.
 func (m *Mailbox) ListArchived() ([]*Message, error) {
 	archivePath := m.ArchivePath()
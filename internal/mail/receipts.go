@@ -0,0 +1,474 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/lockfile"
+)
+
+// DefaultAckTimeout is how long recipients have to acknowledge a
+// --require-ack message before the daemon follows up.
+const DefaultAckTimeout = 30 * time.Minute
+
+// MaxAckRenotify is how many times the daemon re-notifies an unacknowledged
+// recipient before escalating to the overseer.
+const MaxAckRenotify = 2
+
+// ReceiptRetention is how long settled receipts are kept before pruning.
+const ReceiptRetention = 7 * 24 * time.Hour
+
+// ErrReceiptNotFound indicates no receipt exists for a message.
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// RecipientReceipt tracks delivery and read state for one recipient of a message.
+type RecipientReceipt struct {
+	// Address is the recipient address (e.g., "gastown/Toast", "queue:work").
+	Address string `json:"address"`
+
+	// MessageID is the bead ID of this recipient's copy.
+	// CC recipients share the bead of the primary recipient.
+	MessageID string `json:"message_id,omitempty"`
+
+	// CC is true if the recipient was CC'd rather than addressed directly.
+	CC bool `json:"cc,omitempty"`
+
+	// DeliveredAt is when the message bead was created for this recipient.
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+
+	// DeliveryError records why delivery failed, if it did.
+	DeliveryError string `json:"delivery_error,omitempty"`
+
+	// ReadAt is when the recipient first opened the message.
+	ReadAt *time.Time `json:"read_at,omitempty"`
+
+	// AckedAt is when the recipient acknowledged (closed) the message.
+	AckedAt *time.Time `json:"acked_at,omitempty"`
+
+	// NotifyCount is how many times the daemon re-notified this recipient.
+	NotifyCount int `json:"notify_count,omitempty"`
+
+	// LastNotifiedAt is when the daemon last re-notified this recipient.
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty"`
+}
+
+// State returns a short human-readable state for the recipient.
+func (rr *RecipientReceipt) State() string {
+	switch {
+	case rr.DeliveryError != "":
+		return "failed"
+	case rr.AckedAt != nil:
+		return "acked"
+	case rr.ReadAt != nil:
+		return "read"
+	case rr.DeliveredAt != nil:
+		return "delivered"
+	default:
+		return "pending"
+	}
+}
+
+// Receipt tracks delivery and read acknowledgement for a sent message
+// across all of its recipients (including list/group fan-out and CC).
+type Receipt struct {
+	ID         string              `json:"id"`
+	From       string              `json:"from"`
+	To         string              `json:"to"`
+	Subject    string              `json:"subject"`
+	SentAt     time.Time           `json:"sent_at"`
+	RequireAck bool                `json:"require_ack,omitempty"`
+	AckTimeout time.Duration       `json:"ack_timeout,omitempty"`
+	Recipients []*RecipientReceipt `json:"recipients"`
+
+	// EscalatedAt is set once the daemon has escalated unacknowledged recipients.
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+}
+
+// Recipient returns the receipt entry for an address, or nil.
+// Addresses are compared in normalized identity form.
+func (r *Receipt) Recipient(address string) *RecipientReceipt {
+	identity := addressToIdentity(address)
+	for _, rr := range r.Recipients {
+		if addressToIdentity(rr.Address) == identity {
+			return rr
+		}
+	}
+	return nil
+}
+
+// Settled returns true if nothing further is expected for this receipt:
+// either no acknowledgement was requested, every delivered recipient has
+// acknowledged, or the receipt was already escalated.
+func (r *Receipt) Settled() bool {
+	if !r.RequireAck || r.EscalatedAt != nil {
+		return true
+	}
+	for _, rr := range r.Recipients {
+		if rr.DeliveredAt != nil && rr.AckedAt == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ackTimeout returns the effective acknowledgement timeout.
+func (r *Receipt) ackTimeout() time.Duration {
+	if r.AckTimeout > 0 {
+		return r.AckTimeout
+	}
+	return DefaultAckTimeout
+}
+
+// AckFollowUpAction is what the daemon should do about an unacknowledged message.
+type AckFollowUpAction string
+
+const (
+	// AckRenotify re-sends the tmux notification to the recipient.
+	AckRenotify AckFollowUpAction = "renotify"
+
+	// AckEscalate escalates the unacknowledged message to the overseer.
+	AckEscalate AckFollowUpAction = "escalate"
+)
+
+// AckFollowUp describes a pending follow-up for an unacknowledged message.
+type AckFollowUp struct {
+	Action     AckFollowUpAction
+	Receipt    *Receipt
+	Recipients []*RecipientReceipt // Unacknowledged recipients
+}
+
+// FollowUp returns the follow-up due for this receipt at the given time, or nil.
+// Each recipient is re-notified once per timeout period, up to MaxAckRenotify
+// times. When a further period passes without acknowledgement, the whole
+// receipt is escalated once.
+func (r *Receipt) FollowUp(now time.Time) *AckFollowUp {
+	if r.Settled() {
+		return nil
+	}
+
+	timeout := r.ackTimeout()
+	var renotify, exhausted []*RecipientReceipt
+	for _, rr := range r.Recipients {
+		if rr.DeliveredAt == nil || rr.AckedAt != nil {
+			continue
+		}
+		due := r.SentAt.Add(time.Duration(rr.NotifyCount+1) * timeout)
+		if now.Before(due) {
+			continue
+		}
+		if rr.NotifyCount < MaxAckRenotify {
+			renotify = append(renotify, rr)
+		} else {
+			exhausted = append(exhausted, rr)
+		}
+	}
+
+	if len(exhausted) > 0 {
+		return &AckFollowUp{Action: AckEscalate, Receipt: r, Recipients: exhausted}
+	}
+	if len(renotify) > 0 {
+		return &AckFollowUp{Action: AckRenotify, Receipt: r, Recipients: renotify}
+	}
+	return nil
+}
+
+// ReceiptStore persists receipts as JSON files in .beads/receipts/.
+// Receipts are local operational state (like the merge queue) and are
+// pruned once settled.
+type ReceiptStore struct {
+	dir string
+}
+
+// NewReceiptStore creates a receipt store for the given .beads directory.
+func NewReceiptStore(beadsDir string) *ReceiptStore {
+	return &ReceiptStore{dir: filepath.Join(beadsDir, "receipts")}
+}
+
+// Dir returns the directory receipts are stored in.
+func (s *ReceiptStore) Dir() string {
+	return s.dir
+}
+
+func (s *ReceiptStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// lock takes an exclusive lock on the store so concurrent agents don't
+// lose each other's updates. Returns an unlock function.
+func (s *ReceiptStore) lock() (func(), error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, err
+	}
+	return lockfile.Lock(filepath.Join(s.dir, ".lock"))
+}
+
+// Get loads a receipt by its ID.
+func (s *ReceiptStore) Get(id string) (*Receipt, error) {
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrReceiptNotFound
+		}
+		return nil, err
+	}
+	var r Receipt
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing receipt %s: %w", id, err)
+	}
+	return &r, nil
+}
+
+// Find resolves a receipt ID or the bead ID of any recipient's copy.
+func (s *ReceiptStore) Find(id string) (*Receipt, error) {
+	if r, err := s.Get(id); err == nil {
+		return r, nil
+	} else if !errors.Is(err, ErrReceiptNotFound) {
+		return nil, err
+	}
+
+	receipts, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range receipts {
+		for _, rr := range r.Recipients {
+			if rr.MessageID == id {
+				return r, nil
+			}
+		}
+	}
+	return nil, ErrReceiptNotFound
+}
+
+// List returns all receipts, newest first.
+func (s *ReceiptStore) List() ([]*Receipt, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var receipts []*Receipt
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		r, err := s.Get(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			continue // Skip malformed files
+		}
+		receipts = append(receipts, r)
+	}
+
+	sort.Slice(receipts, func(i, j int) bool {
+		return receipts[i].SentAt.After(receipts[j].SentAt)
+	})
+	return receipts, nil
+}
+
+func (s *ReceiptStore) save(r *Receipt) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path(r.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: receipts are non-sensitive operational data
+		return err
+	}
+	return os.Rename(tmp, s.path(r.ID))
+}
+
+// update applies fn to a receipt under the store lock and saves it.
+func (s *ReceiptStore) update(id string, fn func(*Receipt) error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := fn(r); err != nil {
+		return err
+	}
+	return s.save(r)
+}
+
+// newReceipt builds an empty receipt for msg, addressed to msg.To.
+func newReceipt(msg *Message) *Receipt {
+	sentAt := msg.Timestamp
+	if sentAt.IsZero() {
+		sentAt = timeNow()
+	}
+	return &Receipt{
+		ID:         msg.ReceiptID,
+		From:       msg.From,
+		To:         msg.To,
+		Subject:    msg.Subject,
+		SentAt:     sentAt,
+		RequireAck: msg.RequireAck,
+		AckTimeout: msg.AckTimeout,
+	}
+}
+
+// Create creates the receipt for msg, addressed to msg.To, unless it
+// already exists. Fan-out sends create it before sending any copy so the
+// receipt names the list or group rather than its first member.
+func (s *ReceiptStore) Create(msg *Message) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := s.Get(msg.ReceiptID); !errors.Is(err, ErrReceiptNotFound) {
+		return err
+	}
+	return s.save(newReceipt(msg))
+}
+
+// RecordDelivery records delivery (or failure) of a message copy to one recipient.
+// The receipt is created from msg on first delivery if Create wasn't called.
+func (s *ReceiptStore) RecordDelivery(msg *Message, rr *RecipientReceipt) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r, err := s.Get(msg.ReceiptID)
+	if errors.Is(err, ErrReceiptNotFound) {
+		r = newReceipt(msg)
+	} else if err != nil {
+		return err
+	}
+
+	if existing := r.Recipient(rr.Address); existing != nil {
+		*existing = *rr
+	} else {
+		r.Recipients = append(r.Recipients, rr)
+	}
+	return s.save(r)
+}
+
+// markRecipient finds the receipt containing messageID for the given identity
+// and applies fn to the matching recipient entry. Messages without a receipt
+// are ignored.
+func (s *ReceiptStore) markRecipient(messageID, identity string, fn func(*RecipientReceipt)) error {
+	r, err := s.Find(messageID)
+	if errors.Is(err, ErrReceiptNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.update(r.ID, func(r *Receipt) error {
+		for _, rr := range r.Recipients {
+			if rr.MessageID == messageID && addressToIdentity(rr.Address) == identity {
+				fn(rr)
+				return nil
+			}
+		}
+		// Fall back to the primary copy when the reader's identity isn't on
+		// the receipt (e.g., a group member reading via a shared inbox).
+		for _, rr := range r.Recipients {
+			if rr.MessageID == messageID && !rr.CC {
+				fn(rr)
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// MarkRead records that identity opened the message with the given bead ID.
+func (s *ReceiptStore) MarkRead(messageID, identity string) error {
+	now := timeNow()
+	return s.markRecipient(messageID, identity, func(rr *RecipientReceipt) {
+		if rr.ReadAt == nil {
+			rr.ReadAt = &now
+		}
+	})
+}
+
+// MarkAcked records that identity acknowledged the message with the given bead ID.
+func (s *ReceiptStore) MarkAcked(messageID, identity string) error {
+	now := timeNow()
+	return s.markRecipient(messageID, identity, func(rr *RecipientReceipt) {
+		if rr.ReadAt == nil {
+			rr.ReadAt = &now
+		}
+		if rr.AckedAt == nil {
+			rr.AckedAt = &now
+		}
+	})
+}
+
+// RecordRenotify records that the daemon re-notified a recipient.
+func (s *ReceiptStore) RecordRenotify(receiptID, address string) error {
+	now := timeNow()
+	return s.update(receiptID, func(r *Receipt) error {
+		rr := r.Recipient(address)
+		if rr == nil {
+			return fmt.Errorf("recipient %s not on receipt %s", address, receiptID)
+		}
+		rr.NotifyCount++
+		rr.LastNotifiedAt = &now
+		return nil
+	})
+}
+
+// RecordEscalation records that the daemon escalated the receipt.
+func (s *ReceiptStore) RecordEscalation(receiptID string) error {
+	now := timeNow()
+	return s.update(receiptID, func(r *Receipt) error {
+		r.EscalatedAt = &now
+		return nil
+	})
+}
+
+// PendingFollowUps returns all follow-ups due at the given time.
+func (s *ReceiptStore) PendingFollowUps(now time.Time) ([]*AckFollowUp, error) {
+	receipts, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var due []*AckFollowUp
+	for _, r := range receipts {
+		if f := r.FollowUp(now); f != nil {
+			due = append(due, f)
+		}
+	}
+	return due, nil
+}
+
+// Prune removes settled receipts older than maxAge. Returns the number removed.
+func (s *ReceiptStore) Prune(maxAge time.Duration) (int, error) {
+	receipts, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := timeNow().Add(-maxAge)
+	pruned := 0
+	for _, r := range receipts {
+		if r.SentAt.After(cutoff) || !r.Settled() {
+			continue
+		}
+		if err := os.Remove(s.path(r.ID)); err != nil && !os.IsNotExist(err) {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
@@ -0,0 +1,260 @@
+package mail
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestReceipt(t *testing.T, store *ReceiptStore, requireAck bool, recipients ...string) *Message {
+	t.Helper()
+	msg := &Message{
+		From:       "gastown/witness",
+		To:         "list:oncall",
+		Subject:    "HELP: stuck",
+		Timestamp:  time.Now().Add(-time.Hour),
+		RequireAck: requireAck,
+		AckTimeout: 10 * time.Minute,
+		ReceiptID:  "msg-test",
+	}
+	for i, addr := range recipients {
+		delivered := msg.Timestamp
+		rr := &RecipientReceipt{
+			Address:     addr,
+			MessageID:   "hq-" + string(rune('a'+i)),
+			DeliveredAt: &delivered,
+		}
+		if err := store.RecordDelivery(msg, rr); err != nil {
+			t.Fatalf("RecordDelivery(%s): %v", addr, err)
+		}
+	}
+	return msg
+}
+
+func TestReceiptStoreFanOut(t *testing.T) {
+	store := NewReceiptStore(t.TempDir())
+	newTestReceipt(t, store, false, "mayor/", "gastown/Toast", "gastown/crew/max")
+
+	r, err := store.Get("msg-test")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(r.Recipients) != 3 {
+		t.Fatalf("len(Recipients) = %d, want 3", len(r.Recipients))
+	}
+	if r.To != "list:oncall" {
+		t.Errorf("To = %q, want list:oncall", r.To)
+	}
+
+	// Lookup by a recipient's bead ID resolves to the same receipt
+	found, err := store.Find("hq-b")
+	if err != nil {
+		t.Fatalf("Find by bead ID: %v", err)
+	}
+	if found.ID != "msg-test" {
+		t.Errorf("Find returned %q, want msg-test", found.ID)
+	}
+
+	if _, err := store.Find("hq-missing"); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("Find(missing) err = %v, want ErrReceiptNotFound", err)
+	}
+}
+
+func TestReceiptStoreMarkReadAndAcked(t *testing.T) {
+	store := NewReceiptStore(t.TempDir())
+	newTestReceipt(t, store, true, "mayor/", "gastown/Toast")
+
+	// Crew/polecat paths normalize to the same identity
+	if err := store.MarkRead("hq-b", addressToIdentity("gastown/polecats/Toast")); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	r, _ := store.Get("msg-test")
+	toast := r.Recipient("gastown/Toast")
+	if toast.State() != "read" {
+		t.Errorf("Toast state = %q, want read", toast.State())
+	}
+	if mayor := r.Recipient("mayor"); mayor.State() != "delivered" {
+		t.Errorf("mayor state = %q, want delivered", mayor.State())
+	}
+
+	if err := store.MarkAcked("hq-a", "mayor/"); err != nil {
+		t.Fatalf("MarkAcked: %v", err)
+	}
+	r, _ = store.Get("msg-test")
+	if mayor := r.Recipient("mayor/"); mayor.State() != "acked" || mayor.ReadAt == nil {
+		t.Errorf("mayor state = %q (ReadAt=%v), want acked with ReadAt set", mayor.State(), mayor.ReadAt)
+	}
+	if r.Settled() {
+		t.Error("receipt should not be settled while Toast hasn't acked")
+	}
+
+	if err := store.MarkAcked("hq-b", "gastown/Toast"); err != nil {
+		t.Fatalf("MarkAcked: %v", err)
+	}
+	r, _ = store.Get("msg-test")
+	if !r.Settled() {
+		t.Error("receipt should be settled once all recipients acked")
+	}
+
+	// Messages without receipts are ignored
+	if err := store.MarkAcked("hq-untracked", "mayor/"); err != nil {
+		t.Errorf("MarkAcked(untracked) = %v, want nil", err)
+	}
+}
+
+func TestReceiptStoreCCRecipient(t *testing.T) {
+	store := NewReceiptStore(t.TempDir())
+	msg := newTestReceipt(t, store, true, "gastown/refinery")
+	now := time.Now()
+	if err := store.RecordDelivery(msg, &RecipientReceipt{Address: "overseer", MessageID: "hq-a", CC: true, DeliveredAt: &now}); err != nil {
+		t.Fatalf("RecordDelivery(cc): %v", err)
+	}
+
+	if err := store.MarkRead("hq-a", "overseer"); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	r, _ := store.Get("msg-test")
+	if cc := r.Recipient("overseer"); cc.State() != "read" || !cc.CC {
+		t.Errorf("overseer state = %q cc=%v, want read cc", cc.State(), cc.CC)
+	}
+	if primary := r.Recipient("gastown/refinery"); primary.State() != "delivered" {
+		t.Errorf("refinery state = %q, want delivered", primary.State())
+	}
+}
+
+func TestReceiptFollowUp(t *testing.T) {
+	sent := time.Now().Add(-5 * time.Minute)
+	delivered := sent
+	r := &Receipt{
+		ID:         "msg-x",
+		SentAt:     sent,
+		RequireAck: true,
+		AckTimeout: 10 * time.Minute,
+		Recipients: []*RecipientReceipt{{Address: "mayor/", DeliveredAt: &delivered}},
+	}
+
+	if f := r.FollowUp(time.Now()); f != nil {
+		t.Fatalf("FollowUp before timeout = %v, want nil", f.Action)
+	}
+
+	// First timeout: renotify
+	f := r.FollowUp(sent.Add(11 * time.Minute))
+	if f == nil || f.Action != AckRenotify {
+		t.Fatalf("FollowUp after timeout = %v, want renotify", f)
+	}
+
+	// Already renotified once: next renotify due after second period
+	r.Recipients[0].NotifyCount = 1
+	if f := r.FollowUp(sent.Add(15 * time.Minute)); f != nil {
+		t.Errorf("FollowUp mid-period = %v, want nil", f.Action)
+	}
+
+	// Renotify budget exhausted: escalate
+	r.Recipients[0].NotifyCount = MaxAckRenotify
+	f = r.FollowUp(sent.Add(time.Duration(MaxAckRenotify+1) * 10 * time.Minute))
+	if f == nil || f.Action != AckEscalate {
+		t.Fatalf("FollowUp after renotify budget = %v, want escalate", f)
+	}
+
+	// Escalated receipts are settled
+	now := time.Now()
+	r.EscalatedAt = &now
+	if f := r.FollowUp(now.Add(24 * time.Hour)); f != nil {
+		t.Errorf("FollowUp after escalation = %v, want nil", f.Action)
+	}
+}
+
+func TestReceiptFollowUpNotRequired(t *testing.T) {
+	delivered := time.Now().Add(-24 * time.Hour)
+	r := &Receipt{
+		SentAt:     delivered,
+		Recipients: []*RecipientReceipt{{Address: "mayor/", DeliveredAt: &delivered}},
+	}
+	if f := r.FollowUp(time.Now()); f != nil {
+		t.Errorf("FollowUp without RequireAck = %v, want nil", f.Action)
+	}
+}
+
+func TestReceiptStoreRenotifyAndPrune(t *testing.T) {
+	store := NewReceiptStore(t.TempDir())
+	newTestReceipt(t, store, true, "mayor/")
+
+	due, err := store.PendingFollowUps(time.Now())
+	if err != nil {
+		t.Fatalf("PendingFollowUps: %v", err)
+	}
+	if len(due) != 1 || due[0].Action != AckRenotify {
+		t.Fatalf("PendingFollowUps = %d items, want 1 renotify", len(due))
+	}
+
+	if err := store.RecordRenotify("msg-test", "mayor/"); err != nil {
+		t.Fatalf("RecordRenotify: %v", err)
+	}
+	r, _ := store.Get("msg-test")
+	if rr := r.Recipient("mayor/"); rr.NotifyCount != 1 || rr.LastNotifiedAt == nil {
+		t.Errorf("NotifyCount = %d, want 1 with LastNotifiedAt set", rr.NotifyCount)
+	}
+
+	// Unsettled receipts are never pruned
+	if n, _ := store.Prune(0); n != 0 {
+		t.Errorf("Prune(unsettled) = %d, want 0", n)
+	}
+
+	if err := store.RecordEscalation("msg-test"); err != nil {
+		t.Fatalf("RecordEscalation: %v", err)
+	}
+	if n, _ := store.Prune(time.Hour * 24); n != 0 {
+		t.Errorf("Prune(recent) = %d, want 0", n)
+	}
+	if n, _ := store.Prune(0); n != 1 {
+		t.Errorf("Prune(settled) = %d, want 1", n)
+	}
+	if _, err := store.Get("msg-test"); !errors.Is(err, ErrReceiptNotFound) {
+		t.Errorf("Get after prune err = %v, want ErrReceiptNotFound", err)
+	}
+}
+
+func TestReceiptLabels(t *testing.T) {
+	if labels := receiptLabels(&Message{}); len(labels) != 0 {
+		t.Errorf("receiptLabels(empty) = %v, want none", labels)
+	}
+	labels := receiptLabels(&Message{ReceiptID: "msg-abc", RequireAck: true})
+	if len(labels) != 2 || labels[0] != "receipt:msg-abc" || labels[1] != "ack:required" {
+		t.Errorf("receiptLabels = %v", labels)
+	}
+
+	bm := BeadsMessage{ID: "hq-1", Labels: []string{"from:mayor/", "receipt:msg-abc", "ack:required"}}
+	msg := bm.ToMessage()
+	if msg.ReceiptID != "msg-abc" || !msg.RequireAck {
+		t.Errorf("ToMessage ReceiptID=%q RequireAck=%v", msg.ReceiptID, msg.RequireAck)
+	}
+}
+
+func TestReceiptStoreCreateKeepsFanOutAddress(t *testing.T) {
+	store := NewReceiptStore(t.TempDir())
+	msg := &Message{From: "mayor/", To: "@crew", Subject: "Standup", ReceiptID: "msg-group"}
+	if err := store.Create(msg); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Copies are delivered to members; the receipt stays addressed to the group
+	for i, member := range []string{"gastown/crew/max", "gastown/crew/joe"} {
+		copy := *msg
+		copy.To = member
+		now := time.Now()
+		if err := store.RecordDelivery(&copy, &RecipientReceipt{Address: member, MessageID: "hq-" + string(rune('a'+i)), DeliveredAt: &now}); err != nil {
+			t.Fatalf("RecordDelivery(%s): %v", member, err)
+		}
+	}
+	if err := store.Create(msg); err != nil {
+		t.Fatalf("Create again: %v", err)
+	}
+
+	r, err := store.Get("msg-group")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if r.To != "@crew" || len(r.Recipients) != 2 {
+		t.Errorf("receipt To = %q with %d recipients, want @crew with 2", r.To, len(r.Recipients))
+	}
+}
@@ -518,6 +518,15 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
 func (r *Router) Send(msg *Message) error {
+	// Assign a receipt ID once per logical message so every fan-out copy
+	// and CC recipient is tracked on the same receipt.
+	if msg.ReceiptID == "" {
+		msg.ReceiptID = generateID()
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = timeNow()
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -558,17 +567,26 @@ func (r *Router) sendToGroup(msg *Message) error {
 		return fmt.Errorf("no recipients found for group: %s", msg.To)
 	}
 
+	r.createFanOutReceipt(msg)
+
 	// Fan-out: send a copy to each recipient
 	var errs []string
+	firstID := ""
 	for _, recipient := range recipients {
 		// Create a copy of the message for this recipient
 		msgCopy := *msg
 		msgCopy.To = recipient
+		msgCopy.fanOutCopy = true
 
 		if err := r.sendToSingle(&msgCopy); err != nil {
 			errs = append(errs, fmt.Sprintf("%s: %v", recipient, err))
+			continue
+		}
+		if firstID == "" {
+			firstID = msgCopy.ID
 		}
 	}
+	r.recordFanOutCC(msg, firstID)
 
 	if len(errs) > 0 {
 		return fmt.Errorf("some group sends failed: %s", strings.Join(errs, "; "))
@@ -596,6 +614,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, receiptLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=<recipient> -d <body>
 	args := []string{"create", msg.Subject,
@@ -622,21 +641,24 @@ func (r *Router) sendToSingle(msg *Message) error {
 	}
 
 	beadsDir := r.resolveBeadsDir(msg.To)
-	cmd := exec.Command("bd", args...) //nolint:gosec // G204: bd is a trusted internal tool
-	cmd.Env = append(cmd.Environ(),
-		"BEADS_DIR="+beadsDir,
-	)
-	cmd.Dir = filepath.Dir(beadsDir) // Run in parent of .beads
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
+	beadID, err := r.createMessageBead(beadsDir, args)
+	if err != nil {
+		r.recordDelivery(msg, msg.To, "", err)
+		return err
+	}
+	if beadID != "" {
+		msg.ID = beadID
+	}
 
-	if err := cmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg != "" {
-			return errors.New(errMsg)
+	// Record delivery for the primary recipient and each CC recipient
+	// (CC recipients share the same bead). Fan-out copies leave CC to the
+	// list or group send, which records it once.
+	r.recordDelivery(msg, msg.To, beadID, nil)
+	r.indexMessage(msg, msg.To)
+	if !msg.fanOutCopy {
+		for _, cc := range msg.CC {
+			r.recordCCDelivery(msg, cc, beadID)
 		}
-		return fmt.Errorf("sending message: %w", err)
 	}
 
 	// Notify recipient if they have an active session (best-effort notification)
@@ -658,20 +680,28 @@ func (r *Router) sendToList(msg *Message) error {
 		return err
 	}
 
+	r.createFanOutReceipt(msg)
+
 	// Send to each recipient
 	var lastErr error
 	successCount := 0
+	firstID := ""
 	for _, recipient := range recipients {
 		// Create a copy of the message for this recipient
 		copy := *msg
 		copy.To = recipient
+		copy.fanOutCopy = true
 
 		if err := r.Send(&copy); err != nil {
 			lastErr = err
 			continue
 		}
+		if firstID == "" {
+			firstID = copy.ID
+		}
 		successCount++
 	}
+	r.recordFanOutCC(msg, firstID)
 
 	// If all sends failed, return the last error
 	if successCount == 0 && lastErr != nil {
@@ -718,6 +748,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, receiptLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=queue:<name> -d <body>
 	// Use queue:<name> as assignee so inbox queries can filter by queue
@@ -744,22 +775,16 @@ func (r *Router) sendToQueue(msg *Message) error {
 
 	// Queue messages go to town-level beads (shared location)
 	beadsDir := r.resolveBeadsDir("")
-	cmd := exec.Command("bd", args...) //nolint:gosec // G204: args are constructed internally, not from user input
-	cmd.Env = append(cmd.Environ(),
-		"BEADS_DIR="+beadsDir,
-	)
-	cmd.Dir = filepath.Dir(beadsDir) // Run in parent of .beads
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg != "" {
-			return errors.New(errMsg)
-		}
+	beadID, err := r.createMessageBead(beadsDir, args)
+	if err != nil {
+		r.recordDelivery(msg, msg.To, "", err)
 		return fmt.Errorf("sending to queue %s: %w", queueName, err)
 	}
+	if beadID != "" {
+		msg.ID = beadID
+	}
+	r.recordDelivery(msg, msg.To, beadID, nil)
+	r.indexMessage(msg, msg.To)
 
 	// No notification for queue messages - workers poll or check on their own schedule
 
@@ -801,6 +826,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, receiptLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=announce:<name> -d <body>
 	// Use announce:<name> as assignee so queries can filter by channel
@@ -827,22 +853,16 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 
 	// Announce messages go to town-level beads (shared location)
 	beadsDir := r.resolveBeadsDir("")
-	cmd := exec.Command("bd", args...) //nolint:gosec // G204: args are constructed internally, not from user input
-	cmd.Env = append(cmd.Environ(),
-		"BEADS_DIR="+beadsDir,
-	)
-	cmd.Dir = filepath.Dir(beadsDir) // Run in parent of .beads
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg != "" {
-			return errors.New(errMsg)
-		}
+	beadID, err := r.createMessageBead(beadsDir, args)
+	if err != nil {
+		r.recordDelivery(msg, msg.To, "", err)
 		return fmt.Errorf("sending to announce %s: %w", announceName, err)
 	}
+	if beadID != "" {
+		msg.ID = beadID
+	}
+	r.recordDelivery(msg, msg.To, beadID, nil)
+	r.indexMessage(msg, msg.To)
 
 	// No notification for announce messages - readers poll or check on their own schedule
 
@@ -915,6 +935,103 @@ func (r *Router) pruneAnnounce(announceName string, retainCount int) error {
 	return nil
 }
 
+// receiptLabels returns the labels linking a message copy to its receipt.
+func receiptLabels(msg *Message) []string {
+	var labels []string
+	if msg.ReceiptID != "" {
+		labels = append(labels, "receipt:"+msg.ReceiptID)
+	}
+	if msg.RequireAck {
+		labels = append(labels, "ack:required")
+	}
+	return labels
+}
+
+// createMessageBead runs bd create with the given args and returns the new bead ID.
+// The bead ID is best-effort: older bd versions may not emit JSON.
+func (r *Router) createMessageBead(beadsDir string, args []string) (string, error) {
+	args = append(args, "--json")
+	cmd := exec.Command("bd", args...) //nolint:gosec // G204: args are constructed internally, not from user input
+	cmd.Env = append(cmd.Environ(),
+		"BEADS_DIR="+beadsDir,
+	)
+	cmd.Dir = filepath.Dir(beadsDir) // Run in parent of .beads
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg != "" {
+			return "", errors.New(errMsg)
+		}
+		return "", fmt.Errorf("sending message: %w", err)
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(stdout.Bytes(), &created)
+	return created.ID, nil
+}
+
+// Receipts returns the receipt store for this router's town.
+func (r *Router) Receipts() *ReceiptStore {
+	return NewReceiptStore(r.resolveBeadsDir(""))
+}
+
+// recordDelivery records a delivery attempt on the message receipt (best-effort).
+func (r *Router) recordDelivery(msg *Message, address, beadID string, sendErr error) {
+	if msg.ReceiptID == "" {
+		return
+	}
+	rr := &RecipientReceipt{Address: address, MessageID: beadID}
+	if sendErr != nil {
+		rr.DeliveryError = sendErr.Error()
+	} else {
+		now := timeNow()
+		rr.DeliveredAt = &now
+	}
+	_ = r.Receipts().RecordDelivery(msg, rr) // non-fatal: receipts are advisory
+}
+
+// createFanOutReceipt creates the receipt of a list or group send,
+// addressed to the list or group, before any copy is delivered.
+func (r *Router) createFanOutReceipt(msg *Message) {
+	if msg.ReceiptID == "" || msg.fanOutCopy {
+		return
+	}
+	_ = r.Receipts().Create(msg) // non-fatal: receipts are advisory
+}
+
+// recordFanOutCC records CC delivery for a list or group send once,
+// against the first copy delivered.
+func (r *Router) recordFanOutCC(msg *Message, beadID string) {
+	if msg.fanOutCopy || beadID == "" {
+		return
+	}
+	for _, cc := range msg.CC {
+		r.recordCCDelivery(msg, cc, beadID)
+	}
+}
+
+// recordCCDelivery records delivery to a CC recipient sharing the primary bead.
+func (r *Router) recordCCDelivery(msg *Message, address, beadID string) {
+	if msg.ReceiptID == "" {
+		return
+	}
+	now := timeNow()
+	rr := &RecipientReceipt{Address: address, MessageID: beadID, CC: true, DeliveredAt: &now}
+	_ = r.Receipts().RecordDelivery(msg, rr) // non-fatal: receipts are advisory
+}
+
+// Renotify re-sends the tmux notification for an unacknowledged message.
+// Used by the daemon to follow up on --require-ack messages.
+func (r *Router) Renotify(address, from, subject string) error {
+	return r.notifyRecipient(&Message{To: address, From: from, Subject: "[ACK REQUIRED] " + subject})
+}
+
 // isSelfMail returns true if sender and recipient are the same identity.
 // Normalizes addresses by removing trailing slashes for comparison.
 func isSelfMail(from, to string) bool {
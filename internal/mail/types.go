@@ -28,7 +28,6 @@ const (
 // MessageType indicates the purpose of a message.
 type MessageType string
 
-
 const (
 	// TypeTask indicates a message requiring action from the recipient.
 	TypeTask MessageType = "task"
@@ -106,6 +105,22 @@ type Message struct {
 	// CC contains addresses that should receive a copy of this message.
 	// CC'd recipients see the message in their inbox but are not the primary recipient.
 	CC []string `json:"cc,omitempty"`
+
+	// RequireAck asks recipients to explicitly acknowledge the message.
+	// Unacknowledged messages are re-notified and then escalated by the daemon.
+	RequireAck bool `json:"require_ack,omitempty"`
+
+	// AckTimeout is how long recipients have to acknowledge before follow-up.
+	// Only used at send time; zero means DefaultAckTimeout.
+	AckTimeout time.Duration `json:"-"`
+
+	// ReceiptID links all copies of a message (list/group fan-out, CC) to a
+	// single delivery receipt. Assigned by Router.Send.
+	ReceiptID string `json:"receipt_id,omitempty"`
+
+	// fanOutCopy marks a per-recipient copy made by list or group fan-out.
+	// The receipt and CC deliveries are recorded once, for the original.
+	fanOutCopy bool
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -165,16 +180,18 @@ type BeadsMessage struct {
 	Priority    int       `json:"priority"`    // 0=urgent, 1=high, 2=normal, 3=low
 	Status      string    `json:"status"`      // open=unread, closed=read
 	CreatedAt   time.Time `json:"created_at"`
-	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X)
+	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, receipt:X, ack:required)
 	Pinned      bool      `json:"pinned,omitempty"`
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (filtered from JSONL export)
 
 	// Cached parsed values (populated by ParseLabels)
-	sender   string
-	threadID string
-	replyTo  string
-	msgType  string
-	cc       []string // CC recipients
+	sender     string
+	threadID   string
+	replyTo    string
+	msgType    string
+	cc         []string // CC recipients
+	receiptID  string
+	requireAck bool
 }
 
 // ParseLabels extracts metadata from the labels array.
@@ -190,6 +207,10 @@ func (bm *BeadsMessage) ParseLabels() {
 			bm.msgType = strings.TrimPrefix(label, "msg-type:")
 		} else if strings.HasPrefix(label, "cc:") {
 			bm.cc = append(bm.cc, strings.TrimPrefix(label, "cc:"))
+		} else if strings.HasPrefix(label, "receipt:") {
+			bm.receiptID = strings.TrimPrefix(label, "receipt:")
+		} else if label == "ack:required" {
+			bm.requireAck = true
 		}
 	}
 }
@@ -241,19 +262,21 @@ func (bm *BeadsMessage) ToMessage() *Message {
 	}
 
 	return &Message{
-		ID:        bm.ID,
-		From:      identityToAddress(bm.sender),
-		To:        identityToAddress(bm.Assignee),
-		Subject:   bm.Title,
-		Body:      bm.Description,
-		Timestamp: bm.CreatedAt,
-		Read:      bm.Status == "closed",
-		Priority:  priority,
-		Type:      msgType,
-		ThreadID:  bm.threadID,
-		ReplyTo:   bm.replyTo,
-		Wisp:      bm.Wisp,
-		CC:        ccAddrs,
+		ID:         bm.ID,
+		From:       identityToAddress(bm.sender),
+		To:         identityToAddress(bm.Assignee),
+		Subject:    bm.Title,
+		Body:       bm.Description,
+		Timestamp:  bm.CreatedAt,
+		Read:       bm.Status == "closed",
+		Priority:   priority,
+		Type:       msgType,
+		ThreadID:   bm.threadID,
+		ReplyTo:    bm.replyTo,
+		Wisp:       bm.Wisp,
+		CC:         ccAddrs,
+		RequireAck: bm.requireAck,
+		ReceiptID:  bm.receiptID,
 	}
 }
 
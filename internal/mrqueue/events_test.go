@@ -112,3 +112,32 @@ func splitLines(s string) []string {
 	}
 	return lines
 }
+
+func TestReadEvents(t *testing.T) {
+	beadsDir := t.TempDir()
+	logger := NewEventLogger(beadsDir)
+
+	events, err := logger.ReadEvents()
+	if err != nil || len(events) != 0 {
+		t.Fatalf("ReadEvents on missing log = %v, %v; want no events", events, err)
+	}
+
+	mr := &MR{ID: "mr-1", Branch: "polecat/nux", Target: "main", SourceIssue: "gt-abc", Rig: "gastown"}
+	if err := logger.LogMergeStarted(mr); err != nil {
+		t.Fatalf("LogMergeStarted: %v", err)
+	}
+	if err := logger.LogMerged(mr, "abc123"); err != nil {
+		t.Fatalf("LogMerged: %v", err)
+	}
+
+	events, err = logger.ReadEvents()
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("ReadEvents returned %d events, want 2", len(events))
+	}
+	if events[1].Type != EventMerged || events[1].SourceIssue != "gt-abc" || events[1].MergeCommit != "abc123" {
+		t.Errorf("second event = %+v, want the merged event", events[1])
+	}
+}
@@ -0,0 +1,223 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// defaultWebhookTemplate renders every escalation field as a flat JSON object.
+const defaultWebhookTemplate = `{"severity": {{json .Severity}}, "topic": {{json .Topic}}, ` +
+	`"message": {{json .Message}}, "from": {{json .From}}, "bead_id": {{json .BeadID}}, ` +
+	`"town": {{json .Town}}, "time": {{json .Time}}, "text": {{json .Title}}}`
+
+// webhookNotifier POSTs a templated JSON body to a URL.
+type webhookNotifier struct {
+	cfg  *config.NotifierConfig
+	tmpl *template.Template
+}
+
+func newWebhook(cfg *config.NotifierConfig) (*webhookNotifier, error) {
+	text := cfg.Template
+	if text == "" {
+		text = defaultWebhookTemplate
+	}
+	tmpl, err := template.New(cfg.DisplayName()).Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing webhook template: %w", err)
+	}
+	return &webhookNotifier{cfg: cfg, tmpl: tmpl}, nil
+}
+
+// Render renders the webhook body for an escalation and checks it is valid JSON.
+func (w *webhookNotifier) Render(e *Escalation) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := w.tmpl.Execute(&buf, e); err != nil {
+		return nil, fmt.Errorf("rendering webhook template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("webhook template rendered invalid JSON: %s", truncate(buf.String(), 200))
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, e *Escalation) error {
+	body, err := w.Render(e)
+	if err != nil {
+		return err
+	}
+	return postHTTP(ctx, w.cfg, bytes.NewReader(body), map[string]string{
+		"Content-Type": "application/json",
+	})
+}
+
+// ntfyNotifier publishes to an ntfy-style topic URL: the body is the
+// message text, with title/priority/tags as headers.
+type ntfyNotifier struct {
+	cfg *config.NotifierConfig
+}
+
+// ntfyPriority maps severity to ntfy's 1-5 priority scale.
+func ntfyPriority(severity string) string {
+	switch strings.ToUpper(severity) {
+	case SeverityCritical:
+		return "5"
+	case SeverityHigh:
+		return "4"
+	}
+	return "3"
+}
+
+func (n *ntfyNotifier) Notify(ctx context.Context, e *Escalation) error {
+	headers := map[string]string{
+		"Content-Type": "text/plain; charset=utf-8",
+		"Title":        e.Title(),
+		"Priority":     ntfyPriority(e.Severity),
+		"Tags":         "rotating_light," + strings.ToLower(e.Severity),
+	}
+	if n.cfg.TokenEnv != "" {
+		if token := os.Getenv(n.cfg.TokenEnv); token != "" {
+			headers["Authorization"] = "Bearer " + token
+		}
+	}
+	return postHTTP(ctx, n.cfg, strings.NewReader(e.Text()), headers)
+}
+
+// postHTTP POSTs body to cfg.URL with the given headers plus cfg.Headers,
+// treating any non-2xx response as a failure.
+func postHTTP(ctx context.Context, cfg *config.NotifierConfig, body io.Reader, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, expandEnv(v))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", cfg.URL, resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
+
+// smtpNotifier emails the escalation through an SMTP relay.
+type smtpNotifier struct {
+	cfg *config.NotifierConfig
+}
+
+// Message renders the RFC 5322 email for an escalation.
+func (s *smtpNotifier) Message(e *Escalation) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerText("Gas Town escalation "+e.Title()))
+	fmt.Fprintf(&b, "Date: %s\r\n", e.Time.Format(time.RFC1123Z))
+	if e.Severity == SeverityCritical {
+		b.WriteString("X-Priority: 1\r\n")
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(e.Text(), "\n", "\r\n"))
+	return b.Bytes()
+}
+
+// headerText makes text safe for a mail header value: line breaks, which
+// would start new headers, become spaces, and non-ASCII text is
+// Q-encoded.
+func headerText(text string) string {
+	text = strings.Join(strings.FieldsFunc(text, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+	return mime.QEncoding.Encode("utf-8", text)
+}
+
+func (s *smtpNotifier) Notify(ctx context.Context, e *Escalation) error {
+	port := s.cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, os.Getenv(s.cfg.PasswordEnv), s.cfg.Host)
+	}
+
+	// net/smtp has no context support; run it in the background and
+	// give up when the context expires.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, s.cfg.From, s.cfg.To, s.Message(e))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("smtp %s: %w", addr, ctx.Err())
+	}
+}
+
+// desktopNotifier shows a local desktop notification via notify-send.
+type desktopNotifier struct {
+	cfg *config.NotifierConfig
+}
+
+// Args returns the notify-send arguments for an escalation.
+func (d *desktopNotifier) Args(e *Escalation) []string {
+	urgency := "normal"
+	if e.Severity == SeverityCritical {
+		urgency = "critical"
+	}
+	return []string{"--app-name=Gas Town", "--urgency=" + urgency, e.Title(), truncate(e.Text(), 1000)}
+}
+
+func (d *desktopNotifier) Notify(ctx context.Context, e *Escalation) error {
+	command := d.cfg.Command
+	if command == "" {
+		command = "notify-send"
+	}
+	out, err := exec.CommandContext(ctx, command, d.Args(e)...).CombinedOutput() //nolint:gosec // G204: command is from town settings
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", command, err, msg)
+		}
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return nil
+}
+
+// truncate shortens s to at most n bytes, marking the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
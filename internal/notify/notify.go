@@ -0,0 +1,197 @@
+// Package notify delivers escalations to humans outside the terminal.
+//
+// Notifiers are configured in town settings (settings/config.json) and fire
+// for escalations at or above their minimum severity. Each delivery result
+// is logged as an event so failed bridges are visible in the feed.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Escalation severity levels, lowest to highest. They map to mail
+// priorities and indicate urgency for human attention.
+const (
+	// SeverityMedium (P2) - Standard escalations for human attention at convenience.
+	// Examples: unclear requirements, design decisions needed, non-blocking issues.
+	SeverityMedium = "MEDIUM"
+
+	// SeverityHigh (P1) - Important blockers that need human attention soon.
+	// Examples: unresolvable merge conflicts, critical blocking bugs, ambiguous requirements.
+	SeverityHigh = "HIGH"
+
+	// SeverityCritical (P0) - System-threatening issues requiring immediate human attention.
+	// Examples: data corruption, security breach, complete system failure.
+	SeverityCritical = "CRITICAL"
+)
+
+// DefaultMinSeverity is used when a notifier doesn't set min_severity.
+const DefaultMinSeverity = SeverityHigh
+
+// DefaultTimeout bounds a single delivery when a notifier doesn't set timeout.
+const DefaultTimeout = 10 * time.Second
+
+// Escalation is the information handed to every notifier.
+type Escalation struct {
+	Severity string    `json:"severity"`
+	Topic    string    `json:"topic"`
+	Message  string    `json:"message,omitempty"`
+	From     string    `json:"from"`
+	BeadID   string    `json:"bead_id,omitempty"`
+	Town     string    `json:"town,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Title returns a one-line summary ("[HIGH] topic").
+func (e *Escalation) Title() string {
+	return fmt.Sprintf("[%s] %s", e.Severity, e.Topic)
+}
+
+// Text returns a plain-text body describing the escalation.
+func (e *Escalation) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Severity: %s\n", e.Severity)
+	fmt.Fprintf(&b, "From: %s\n", e.From)
+	if e.Town != "" {
+		fmt.Fprintf(&b, "Town: %s\n", e.Town)
+	}
+	if e.BeadID != "" {
+		fmt.Fprintf(&b, "Bead: %s\n", e.BeadID)
+	}
+	fmt.Fprintf(&b, "Time: %s\n", e.Time.Format(time.RFC3339))
+	if e.Message != "" {
+		b.WriteString("\n")
+		b.WriteString(e.Message)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Notifier delivers an escalation to one outbound channel.
+type Notifier interface {
+	Notify(ctx context.Context, e *Escalation) error
+}
+
+// New builds the notifier described by cfg.
+func New(cfg *config.NotifierConfig) (Notifier, error) {
+	switch cfg.Type {
+	case config.NotifierWebhook:
+		return newWebhook(cfg)
+	case config.NotifierSMTP:
+		return &smtpNotifier{cfg: cfg}, nil
+	case config.NotifierDesktop:
+		return &desktopNotifier{cfg: cfg}, nil
+	case config.NotifierNtfy:
+		return &ntfyNotifier{cfg: cfg}, nil
+	}
+	return nil, fmt.Errorf("unknown notifier type %q", cfg.Type)
+}
+
+// severityRank orders severities; unknown severities rank lowest.
+func severityRank(s string) int {
+	switch strings.ToUpper(s) {
+	case SeverityCritical:
+		return 3
+	case SeverityHigh:
+		return 2
+	case SeverityMedium:
+		return 1
+	}
+	return 0
+}
+
+// Triggers reports whether cfg should fire for an escalation of the given severity.
+func Triggers(cfg *config.NotifierConfig, severity string) bool {
+	if cfg.Disabled {
+		return false
+	}
+	min := cfg.MinSeverity
+	if min == "" {
+		min = DefaultMinSeverity
+	}
+	return severityRank(severity) >= severityRank(min)
+}
+
+// Result is the outcome of one notifier delivery.
+type Result struct {
+	Name     string        `json:"name"`
+	Type     string        `json:"type"`
+	Err      error         `json:"-"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// OK reports whether the delivery succeeded.
+func (r *Result) OK() bool {
+	return r.Err == nil
+}
+
+// Dispatch delivers e to every notifier in cfgs that triggers for its
+// severity and returns one result per attempted notifier. Deliveries are
+// independent: one failing notifier doesn't stop the others.
+func Dispatch(ctx context.Context, cfgs []*config.NotifierConfig, e *Escalation) []*Result {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	var results []*Result
+	for _, cfg := range cfgs {
+		if cfg == nil || !Triggers(cfg, e.Severity) {
+			continue
+		}
+		results = append(results, deliver(ctx, cfg, e))
+	}
+	return results
+}
+
+// deliver runs a single notifier with its timeout.
+func deliver(ctx context.Context, cfg *config.NotifierConfig, e *Escalation) *Result {
+	r := &Result{Name: cfg.DisplayName(), Type: cfg.Type}
+	start := time.Now()
+
+	n, err := New(cfg)
+	if err == nil {
+		timeout := DefaultTimeout
+		if d, parseErr := time.ParseDuration(cfg.Timeout); parseErr == nil && d > 0 {
+			timeout = d
+		}
+		dctx, cancel := context.WithTimeout(ctx, timeout)
+		err = n.Notify(dctx, e)
+		cancel()
+	}
+
+	r.Duration = time.Since(start)
+	if err != nil {
+		r.Err = err
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// LogResults records each delivery result as an event. Failures are
+// feed-visible so a broken bridge gets noticed; successes are audit-only.
+func LogResults(actor string, e *Escalation, results []*Result) {
+	for _, r := range results {
+		payload := events.NotifyPayload(r.Name, r.Type, e.Severity, e.Topic, r.Error, r.Duration)
+		if e.BeadID != "" {
+			payload["bead"] = e.BeadID
+		}
+		if r.OK() {
+			_ = events.LogAudit(events.TypeEscalationNotified, actor, payload)
+		} else {
+			_ = events.LogFeed(events.TypeEscalationNotifyFailed, actor, payload)
+		}
+	}
+}
+
+// expandEnv expands $VAR and ${VAR} references in configured header values.
+func expandEnv(s string) string {
+	return os.Expand(s, os.Getenv)
+}
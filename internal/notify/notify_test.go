@@ -0,0 +1,337 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func testEscalation(severity string) *Escalation {
+	return &Escalation{
+		Severity: severity,
+		Topic:    `Merge "conflict" on main`,
+		Message:  "Refinery cannot rebase.",
+		From:     "gastown/refinery",
+		BeadID:   "hq-esc1",
+		Town:     "gt",
+		Time:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestTriggers(t *testing.T) {
+	tests := []struct {
+		min      string
+		severity string
+		want     bool
+	}{
+		{"", SeverityMedium, false}, // default HIGH
+		{"", SeverityHigh, true},
+		{"", SeverityCritical, true},
+		{"medium", SeverityMedium, true},
+		{"CRITICAL", SeverityHigh, false},
+		{"CRITICAL", SeverityCritical, true},
+	}
+	for _, tt := range tests {
+		cfg := &config.NotifierConfig{Type: config.NotifierDesktop, MinSeverity: tt.min}
+		if got := Triggers(cfg, tt.severity); got != tt.want {
+			t.Errorf("Triggers(min=%q, %s) = %v, want %v", tt.min, tt.severity, got, tt.want)
+		}
+	}
+
+	disabled := &config.NotifierConfig{Type: config.NotifierDesktop, MinSeverity: "MEDIUM", Disabled: true}
+	if Triggers(disabled, SeverityCritical) {
+		t.Error("disabled notifier should never trigger")
+	}
+}
+
+func TestWebhookTemplate(t *testing.T) {
+	var gotBody map[string]interface{}
+	var gotAuth, gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &gotBody); err != nil {
+			t.Errorf("webhook body is not JSON: %s", data)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("GT_TEST_HOOK_TOKEN", "s3cret")
+	cfg := &config.NotifierConfig{
+		Name:     "slack",
+		Type:     config.NotifierWebhook,
+		URL:      srv.URL,
+		Template: `{"text": {{json .Title}}, "bead": {{json .BeadID}}}`,
+		Headers:  map[string]string{"Authorization": "Bearer ${GT_TEST_HOOK_TOKEN}"},
+	}
+
+	results := Dispatch(context.Background(), []*config.NotifierConfig{cfg}, testEscalation(SeverityHigh))
+	if len(results) != 1 || !results[0].OK() {
+		t.Fatalf("Dispatch results = %+v", results)
+	}
+	if gotBody["text"] != `[HIGH] Merge "conflict" on main` || gotBody["bead"] != "hq-esc1" {
+		t.Errorf("webhook body = %v", gotBody)
+	}
+	if gotAuth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q, want env-expanded token", gotAuth)
+	}
+	if gotType != "application/json" {
+		t.Errorf("Content-Type = %q", gotType)
+	}
+}
+
+func TestWebhookDefaultTemplateAndErrors(t *testing.T) {
+	w, err := newWebhook(&config.NotifierConfig{Type: config.NotifierWebhook})
+	if err != nil {
+		t.Fatalf("newWebhook: %v", err)
+	}
+	body, err := w.Render(testEscalation(SeverityCritical))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	var got Escalation
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("default template is not JSON: %s", body)
+	}
+	if got.Severity != SeverityCritical || got.Topic != testEscalation("").Topic || got.From != "gastown/refinery" {
+		t.Errorf("default body = %+v", got)
+	}
+
+	// Templates rendering invalid JSON are rejected before sending
+	bad, err := newWebhook(&config.NotifierConfig{Type: config.NotifierWebhook, Template: `{"text": {{.Topic}}}`})
+	if err != nil {
+		t.Fatalf("newWebhook: %v", err)
+	}
+	if _, err := bad.Render(testEscalation(SeverityHigh)); err == nil {
+		t.Error("Render should reject invalid JSON")
+	}
+
+	// Non-2xx responses are failures
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	cfg := &config.NotifierConfig{Type: config.NotifierWebhook, URL: srv.URL, MinSeverity: "MEDIUM"}
+	results := Dispatch(context.Background(), []*config.NotifierConfig{cfg}, testEscalation(SeverityMedium))
+	if len(results) != 1 || results[0].OK() || !strings.Contains(results[0].Error, "403") {
+		t.Errorf("expected 403 failure, got %+v", results[0])
+	}
+}
+
+func TestNtfy(t *testing.T) {
+	var gotTitle, gotPriority, gotAuth, gotBody, gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotTitle = r.Header.Get("Title")
+		gotPriority = r.Header.Get("Priority")
+		gotAuth = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+	}))
+	defer srv.Close()
+
+	t.Setenv("GT_TEST_NTFY_TOKEN", "tk_abc")
+	cfg := &config.NotifierConfig{Type: config.NotifierNtfy, URL: srv.URL + "/my-town", TokenEnv: "GT_TEST_NTFY_TOKEN"}
+	results := Dispatch(context.Background(), []*config.NotifierConfig{cfg}, testEscalation(SeverityCritical))
+	if len(results) != 1 || !results[0].OK() {
+		t.Fatalf("Dispatch results = %+v", results)
+	}
+	if gotPath != "/my-town" || gotPriority != "5" || gotAuth != "Bearer tk_abc" {
+		t.Errorf("path=%q priority=%q auth=%q", gotPath, gotPriority, gotAuth)
+	}
+	if gotTitle != `[CRITICAL] Merge "conflict" on main` {
+		t.Errorf("Title = %q", gotTitle)
+	}
+	if !strings.Contains(gotBody, "Refinery cannot rebase.") || !strings.Contains(gotBody, "Bead: hq-esc1") {
+		t.Errorf("body = %q", gotBody)
+	}
+}
+
+// smtpStub is a minimal SMTP server that records one message.
+func smtpStub(t *testing.T) (host string, port int, received <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	ch := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 stub ESMTP")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					ch <- data.String()
+					reply("250 queued")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 stub")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, ch
+}
+
+func TestSMTP(t *testing.T) {
+	host, port, received := smtpStub(t)
+	cfg := &config.NotifierConfig{
+		Type: config.NotifierSMTP,
+		Host: host,
+		Port: port,
+		From: "gastown@example.com",
+		To:   []string{"oncall@example.com", "boss@example.com"},
+	}
+
+	results := Dispatch(context.Background(), []*config.NotifierConfig{cfg}, testEscalation(SeverityCritical))
+	if len(results) != 1 || !results[0].OK() {
+		t.Fatalf("Dispatch results = %+v", results[0])
+	}
+
+	select {
+	case msg := <-received:
+		for _, want := range []string{
+			"To: oncall@example.com, boss@example.com",
+			`Subject: Gas Town escalation [CRITICAL] Merge "conflict" on main`,
+			"X-Priority: 1",
+			"Refinery cannot rebase.",
+		} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("email missing %q:\n%s", want, msg)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP stub received no message")
+	}
+}
+
+func TestSMTPSubjectInjection(t *testing.T) {
+	s := &smtpNotifier{cfg: &config.NotifierConfig{From: "a@example.com", To: []string{"b@example.com"}}}
+	e := testEscalation(SeverityHigh)
+	e.Topic = "disk full\r\nBcc: attacker@example.com"
+
+	msg := string(s.Message(e))
+	headers, _, _ := strings.Cut(msg, "\r\n\r\n")
+	if strings.Contains(headers, "\r\nBcc:") {
+		t.Errorf("topic injected a header:\n%s", headers)
+	}
+	if !strings.Contains(headers, "Subject: Gas Town escalation [HIGH] disk full Bcc: attacker@example.com\r\n") {
+		t.Errorf("subject not flattened to one line:\n%s", headers)
+	}
+
+	e.Topic = "Café down"
+	if msg := string(s.Message(e)); !strings.Contains(msg, "Subject: =?utf-8?q?") {
+		t.Errorf("non-ASCII subject not encoded:\n%s", msg)
+	}
+}
+
+func TestSMTPTimeout(t *testing.T) {
+	// A listener that never speaks SMTP
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	cfg := &config.NotifierConfig{
+		Type: config.NotifierSMTP, Host: addr.IP.String(), Port: addr.Port,
+		From: "a@example.com", To: []string{"b@example.com"}, Timeout: "100ms",
+	}
+	results := Dispatch(context.Background(), []*config.NotifierConfig{cfg}, testEscalation(SeverityHigh))
+	if len(results) != 1 || results[0].OK() {
+		t.Fatalf("expected timeout failure, got %+v", results)
+	}
+}
+
+func TestDesktop(t *testing.T) {
+	// Fake notify-send that records its arguments
+	dir := t.TempDir()
+	out := filepath.Join(dir, "args")
+	script := filepath.Join(dir, "notify-send")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s\\n' \"$@\" > "+strconv.Quote(out)+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.NotifierConfig{Type: config.NotifierDesktop, Command: script}
+	results := Dispatch(context.Background(), []*config.NotifierConfig{cfg}, testEscalation(SeverityCritical))
+	if len(results) != 1 || !results[0].OK() {
+		t.Fatalf("Dispatch results = %+v", results[0])
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading args: %v", err)
+	}
+	if !strings.Contains(string(data), "--urgency=critical") || !strings.Contains(string(data), "[CRITICAL]") {
+		t.Errorf("notify-send args = %q", data)
+	}
+
+	missing := &config.NotifierConfig{Type: config.NotifierDesktop, Command: filepath.Join(dir, "missing")}
+	results = Dispatch(context.Background(), []*config.NotifierConfig{missing}, testEscalation(SeverityHigh))
+	if len(results) != 1 || results[0].OK() {
+		t.Errorf("missing command should fail, got %+v", results)
+	}
+}
+
+func TestDispatchSkipsBelowSeverity(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	cfgs := []*config.NotifierConfig{
+		{Type: config.NotifierWebhook, URL: srv.URL, MinSeverity: "CRITICAL"},
+		nil,
+	}
+	if results := Dispatch(context.Background(), cfgs, testEscalation(SeverityHigh)); len(results) != 0 {
+		t.Errorf("Dispatch = %d results, want 0", len(results))
+	}
+	if called {
+		t.Error("webhook below min severity should not be called")
+	}
+}
@@ -72,15 +72,26 @@ type EscalationData struct {
 }
 
 // HandoffData contains information for session handoff messages.
+// Goal through MoleculeStep come from a structured handoff document;
+// the rest are rendered when set.
 type HandoffData struct {
-	Role        string
-	CurrentWork string
-	Status      string
-	NextSteps   []string
-	Notes       string
-	PendingMail int
-	GitBranch   string
-	GitDirty    bool
+	Role         string
+	Goal         string
+	Done         []string
+	Next         []string
+	Questions    []string
+	Files        []string
+	Commands     []string
+	HookedBead   string
+	Molecule     string
+	MoleculeStep string
+	Notes        string
+	CurrentWork  string
+	Status       string
+	NextSteps    []string
+	PendingMail  int
+	GitBranch    string
+	GitDirty     bool
 }
 
 // New creates a new Templates instance.
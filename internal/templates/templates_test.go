@@ -149,6 +149,72 @@ func TestRenderMessage_Spawn(t *testing.T) {
 	}
 }
 
+func TestRenderMessage_Handoff(t *testing.T) {
+	tmpl, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data := HandoffData{
+		Role:         "gastown/crew/max",
+		Goal:         "Fix token refresh",
+		Done:         []string{"Found the race in refresh()"},
+		Next:         []string{"Add a lock around refresh()"},
+		Commands:     []string{"go test ./internal/auth/..."},
+		HookedBead:   "gt-123",
+		MoleculeStep: "gt-123.2",
+	}
+
+	output, err := tmpl.RenderMessage("handoff", data)
+	if err != nil {
+		t.Fatalf("RenderMessage() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Fix token refresh",
+		"- [x] Found the race in refresh()",
+		"- [ ] Add a lock around refresh()",
+		"- [ ] `go test ./internal/auth/...`",
+		"gt-123.2",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(output, "Open Questions") {
+		t.Error("output has an empty Open Questions section")
+	}
+	if strings.Contains(output, "Git branch") || strings.Contains(output, "Pending mail") {
+		t.Error("output renders unset session state")
+	}
+
+	// Session state fields still render when set
+	data = HandoffData{
+		Role:        "gastown/crew/max",
+		CurrentWork: "gt-123",
+		Status:      "tests failing",
+		NextSteps:   []string{"Fix the flaky test"},
+		PendingMail: 2,
+		GitBranch:   "fix/refresh",
+		GitDirty:    true,
+	}
+	output, err = tmpl.RenderMessage("handoff", data)
+	if err != nil {
+		t.Fatalf("RenderMessage() error = %v", err)
+	}
+	for _, want := range []string{
+		"**Working on**: gt-123",
+		"**Status**: tests failing",
+		"- [ ] Fix the flaky test",
+		"**Pending mail**: 2",
+		"**Git branch**: fix/refresh (⚠️ uncommitted changes)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
 func TestRenderMessage_Nudge(t *testing.T) {
 	tmpl, err := New()
 	if err != nil {
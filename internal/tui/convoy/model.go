@@ -15,6 +15,7 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/forecast"
 )
 
 // convoyIDPattern validates convoy IDs to prevent SQL injection.
@@ -25,9 +26,11 @@ const subprocessTimeout = 5 * time.Second
 
 // IssueItem represents a tracked issue within a convoy.
 type IssueItem struct {
-	ID     string
-	Title  string
-	Status string
+	ID       string
+	Title    string
+	Status   string
+	OpenedAt time.Time
+	ClosedAt time.Time // zero while open
 }
 
 // ConvoyItem represents a convoy with its tracked issues.
@@ -37,9 +40,13 @@ type ConvoyItem struct {
 	Status   string
 	Issues   []IssueItem
 	Progress string // e.g., "2/5"
+	Burndown string // sparkline of open issues since the convoy was created
 	Expanded bool
 }
 
+// burndownPoints is the width of the burndown sparkline.
+const burndownPoints = 16
+
 // Model is the bubbletea model for the convoy TUI.
 type Model struct {
 	convoys   []ConvoyItem
@@ -99,9 +106,10 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 	}
 
 	var rawConvoys []struct {
-		ID     string `json:"id"`
-		Title  string `json:"title"`
-		Status string `json:"status"`
+		ID        string `json:"id"`
+		Title     string `json:"title"`
+		Status    string `json:"status"`
+		CreatedAt string `json:"created_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &rawConvoys); err != nil {
 		return nil, fmt.Errorf("parsing convoy list: %w", err)
@@ -116,6 +124,7 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 			Status:   rc.Status,
 			Issues:   issues,
 			Progress: fmt.Sprintf("%d/%d", completed, total),
+			Burndown: burndownSparkline(rc.CreatedAt, issues),
 			Expanded: false,
 		})
 	}
@@ -123,6 +132,32 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 	return convoys, nil
 }
 
+// burndownSparkline renders open tracked issues over the convoy's life.
+func burndownSparkline(createdAt string, issues []IssueItem) string {
+	start := parseTime(createdAt)
+	now := time.Now()
+	if start.IsZero() || len(issues) == 0 {
+		return ""
+	}
+	spans := make([]forecast.Span, 0, len(issues))
+	for _, issue := range issues {
+		closed := issue.ClosedAt
+		if issue.Status == "closed" && closed.IsZero() {
+			closed = start // Closed, time unknown
+		}
+		spans = append(spans, forecast.Span{Opened: issue.OpenedAt, Closed: closed})
+	}
+	return forecast.Sparkline(forecast.Burndown(spans, start, now, burndownPoints))
+}
+
+// parseTime parses a bead timestamp, returning the zero time if unset.
+func parseTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
 // loadTrackedIssues loads issues tracked by a convoy.
 func loadTrackedIssues(townBeads, convoyID string) ([]IssueItem, int, int) {
 	// Validate convoy ID to prevent SQL injection
@@ -220,9 +255,11 @@ func getIssueDetailsBatch(townBeads string, issueIDs []string) map[string]IssueI
 	}
 
 	var issues []struct {
-		ID     string `json:"id"`
-		Title  string `json:"title"`
-		Status string `json:"status"`
+		ID        string `json:"id"`
+		Title     string `json:"title"`
+		Status    string `json:"status"`
+		CreatedAt string `json:"created_at"`
+		ClosedAt  string `json:"closed_at"`
 	}
 	if err := json.Unmarshal(stdout.Bytes(), &issues); err != nil {
 		return result
@@ -230,9 +267,11 @@ func getIssueDetailsBatch(townBeads string, issueIDs []string) map[string]IssueI
 
 	for _, issue := range issues {
 		result[issue.ID] = IssueItem{
-			ID:     issue.ID,
-			Title:  issue.Title,
-			Status: issue.Status,
+			ID:       issue.ID,
+			Title:    issue.Title,
+			Status:   issue.Status,
+			OpenedAt: parseTime(issue.CreatedAt),
+			ClosedAt: parseTime(issue.ClosedAt),
 		}
 	}
 
@@ -77,6 +77,9 @@ func (m Model) renderView() string {
 			c.Title,
 			progressStyle.Render(fmt.Sprintf("(%s)", c.Progress)),
 		)
+		if c.Burndown != "" {
+			line += " " + progressStyle.Render(c.Burndown)
+		}
 
 		if isSelected {
 			b.WriteString(selectedStyle.Render(line))